@@ -0,0 +1,161 @@
+package admin
+
+import (
+	"database/sql"
+	"errors"
+	"fst/backend/app/models"
+	"fst/backend/app/services"
+	"fst/backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailCampaignController 邮件群发管理控制器
+type EmailCampaignController struct {
+	email_svc *services.EmailService
+}
+
+func NewEmailCampaignController() *EmailCampaignController {
+	return &EmailCampaignController{email_svc: services.NewEmailService()}
+}
+
+// List 群发任务列表
+func (ctrl *EmailCampaignController) List(c *gin.Context) {
+	utils.SanitizeQueryParams(c)
+
+	var q models.EmailCampaignQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		utils.Fail(c, 400, "参数错误")
+		return
+	}
+
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = 20
+	}
+	if q.PageSize > 100 {
+		q.PageSize = 100
+	}
+
+	list, total, err := models.GetEmailCampaignList(&q)
+	if err != nil {
+		utils.Fail(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"list":      list,
+		"total":     total,
+		"page":      q.Page,
+		"page_size": q.PageSize,
+	})
+}
+
+// Detail 群发任务详情
+func (ctrl *EmailCampaignController) Detail(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "无效的 ID")
+		return
+	}
+
+	campaign, err := models.GetEmailCampaignByID(id)
+	if err != nil {
+		utils.Fail(c, 404, "任务不存在")
+		return
+	}
+
+	utils.Success(c, campaign)
+}
+
+// EmailCampaignPreviewRequest 收件人预览请求
+type EmailCampaignPreviewRequest struct {
+	Filter models.EmailCampaignFilter `json:"filter"`
+}
+
+// Preview 预览收件人数
+func (ctrl *EmailCampaignController) Preview(c *gin.Context) {
+	var req EmailCampaignPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.FailBind(c, err, &req)
+		return
+	}
+
+	count, err := ctrl.email_svc.PreviewCampaignRecipients(&req.Filter)
+	if err != nil {
+		utils.Fail(c, 500, "统计失败")
+		return
+	}
+
+	utils.Success(c, gin.H{"count": count})
+}
+
+// Create 创建群发任务
+func (ctrl *EmailCampaignController) Create(c *gin.Context) {
+	var req services.EmailCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.FailBind(c, err, &req)
+		return
+	}
+	req.Name = utils.Clean_XSS(req.Name)
+
+	campaign, err := ctrl.email_svc.CreateCampaign(&req, templateAuthor(c))
+	if errors.Is(err, services.ErrCampaignInvalid) {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
+	if err != nil {
+		utils.Fail(c, 500, "创建失败")
+		return
+	}
+
+	utils.Success(c, campaign)
+}
+
+// Pause 暂停群发
+func (ctrl *EmailCampaignController) Pause(c *gin.Context) {
+	ctrl.change(c, ctrl.email_svc.PauseCampaign)
+}
+
+// Resume 恢复群发
+func (ctrl *EmailCampaignController) Resume(c *gin.Context) {
+	ctrl.change(c, ctrl.email_svc.ResumeCampaign)
+}
+
+// Cancel 取消群发
+func (ctrl *EmailCampaignController) Cancel(c *gin.Context) {
+	ctrl.change(c, ctrl.email_svc.CancelCampaign)
+}
+
+// change 执行状态变更并返回最新的任务
+func (ctrl *EmailCampaignController) change(c *gin.Context, action func(uint64) error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "无效的 ID")
+		return
+	}
+
+	err = action(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.Fail(c, 404, "任务不存在")
+		return
+	}
+	if errors.Is(err, services.ErrCampaignState) {
+		utils.Fail(c, 409, err.Error())
+		return
+	}
+	if err != nil {
+		utils.Fail(c, 500, "操作失败")
+		return
+	}
+
+	campaign, err := models.GetEmailCampaignByID(id)
+	if err != nil {
+		utils.Fail(c, 500, "查询失败")
+		return
+	}
+	utils.Success(c, campaign)
+}
@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"fst/backend/app/models"
+	"fst/backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailSuppressionController 邮件抑制列表管理控制器
+type EmailSuppressionController struct{}
+
+func NewEmailSuppressionController() *EmailSuppressionController {
+	return &EmailSuppressionController{}
+}
+
+// List 抑制列表
+func (ctrl *EmailSuppressionController) List(c *gin.Context) {
+	utils.SanitizeQueryParams(c)
+
+	var q models.EmailSuppressionQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		utils.Fail(c, 400, "参数错误")
+		return
+	}
+
+	if q.Suppressed == 0 && c.Query("suppressed") == "" {
+		q.Suppressed = -1
+	}
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = 20
+	}
+	if q.PageSize > 100 {
+		q.PageSize = 100
+	}
+
+	list, total, err := models.GetEmailSuppressionList(&q)
+	if err != nil {
+		utils.Fail(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"list":      list,
+		"total":     total,
+		"page":      q.Page,
+		"page_size": q.PageSize,
+	})
+}
+
+// Delete 移除抑制记录
+func (ctrl *EmailSuppressionController) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "无效的 ID")
+		return
+	}
+
+	ok, err := models.DeleteEmailSuppression(id)
+	if err != nil {
+		utils.Fail(c, 500, "删除失败")
+		return
+	}
+	if !ok {
+		utils.Fail(c, 404, "记录不存在")
+		return
+	}
+
+	utils.Success(c, nil)
+}
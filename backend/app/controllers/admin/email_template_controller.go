@@ -1,12 +1,12 @@
 package admin
 
 import (
+	"errors"
 	"fst/backend/app/models"
 	"fst/backend/app/services"
 	"fst/backend/internal/db"
 	"fst/backend/utils"
 	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,14 +24,6 @@ func NewEmailTemplateController() *EmailTemplateController {
 }
 
 // List 获取邮件模板列表
-// @Summary 获取邮件模板列表
-// @Description 获取所有邮件模板列表
-// @Tags Admin-邮件模板
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/email-templates [get]
 func (ctrl *EmailTemplateController) List(c *gin.Context) {
 	// 查询所有模板
 	var templates []models.EmailTemplate
@@ -46,15 +38,6 @@ func (ctrl *EmailTemplateController) List(c *gin.Context) {
 }
 
 // Detail 获取邮件模板详情
-// @Summary 获取邮件模板详情
-// @Description 根据ID获取邮件模板详情
-// @Tags Admin-邮件模板
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "模板ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/email-templates/{id} [get]
 func (ctrl *EmailTemplateController) Detail(c *gin.Context) {
 	id_str := c.Param("id")
 	id, err := strconv.ParseUint(id_str, 10, 64)
@@ -82,16 +65,6 @@ type EmailTemplateUpdateRequest struct {
 }
 
 // Update 更新邮件模板
-// @Summary 更新邮件模板
-// @Description 更新邮件模板内容
-// @Tags Admin-邮件模板
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "模板ID"
-// @Param request body EmailTemplateUpdateRequest true "更新信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/email-templates/{id} [put]
 func (ctrl *EmailTemplateController) Update(c *gin.Context) {
 	id_str := c.Param("id")
 	id, err := strconv.ParseUint(id_str, 10, 64)
@@ -102,7 +75,7 @@ func (ctrl *EmailTemplateController) Update(c *gin.Context) {
 
 	var req EmailTemplateUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -112,45 +85,47 @@ func (ctrl *EmailTemplateController) Update(c *gin.Context) {
 	// Content 不需要过滤，因为是HTML邮件内容
 
 	// 检查模板是否存在
-	var existing models.EmailTemplate
-	check_query := "SELECT * FROM email_templates WHERE id = ?"
-	if err := db.DB.Get(&existing, check_query, id); err != nil {
+	existing, err := models.GetEmailTemplateByID(id)
+	if err != nil {
 		utils.Fail(c, 404, "Template not found")
 		return
 	}
 
-	// 更新模板
-	update_query := `UPDATE email_templates SET subject = ?, content = ?, description = ?, status = ? WHERE id = ?`
 	status := existing.Status
 	if req.Status != nil {
 		status = *req.Status
 	}
 
-	if _, err := db.DB.Exec(update_query, req.Subject, req.Content, req.Description, status, id); err != nil {
+	// 更新模板并记录版本
+	rev, err := ctrl.email_svc.UpdateTemplate(id, services.TemplateUpdate{
+		Subject:     req.Subject,
+		Content:     req.Content,
+		Description: req.Description,
+		Status:      status,
+	}, templateAuthor(c))
+	if err == services.ErrTemplateUnchanged {
+		utils.Success(c, gin.H{"message": "Template unchanged"})
+		return
+	}
+	if errors.Is(err, services.ErrTemplateInvalid) {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
+	if err != nil {
 		utils.Fail(c, 500, "Failed to update template")
 		return
 	}
 
-	utils.Success(c, gin.H{"message": "Template updated successfully"})
+	utils.Success(c, gin.H{"message": "Template updated successfully", "version": rev.Version})
 }
 
 // PreviewRequest 预览请求
 type EmailPreviewRequest struct {
-	Content string                 `json:"content" binding:"required"`
-	Vars    map[string]interface{} `json:"vars"`
+	Content string         `json:"content" binding:"required"`
+	Vars    map[string]any `json:"vars"`
 }
 
 // Preview 预览邮件模板
-// @Summary 预览邮件模板
-// @Description 预览邮件模板渲染效果
-// @Tags Admin-邮件模板
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "模板ID"
-// @Param request body EmailPreviewRequest true "预览参数"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/email-templates/{id}/preview [post]
 func (ctrl *EmailTemplateController) Preview(c *gin.Context) {
 	id_str := c.Param("id")
 	id, err := strconv.ParseUint(id_str, 10, 64)
@@ -161,7 +136,7 @@ func (ctrl *EmailTemplateController) Preview(c *gin.Context) {
 
 	var req EmailPreviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -173,42 +148,30 @@ func (ctrl *EmailTemplateController) Preview(c *gin.Context) {
 		return
 	}
 
-	// 渲染模板内容
-	content := template.Content
+	// 使用传入的内容进行预览，先按保存时的规则校验
 	if req.Content != "" {
-		content = req.Content // 使用传入的内容进行预览
+		template.Content = req.Content
 	}
-
-	// 渲染主题
-	subject := template.Subject
-
-	// 替换变量
-	for k, v := range req.Vars {
-		placeholder := "{" + k + "}"
-		content = strings.ReplaceAll(content, placeholder, toString(v))
-		subject = strings.ReplaceAll(subject, placeholder, toString(v))
+	if err := ctrl.email_svc.ValidateTemplate(&template); err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
 	}
 
-	// 使用 HTML 布局包装预览内容
-	wrapped := ctrl.email_svc.WrapHTMLLayout(subject, content)
+	rendered, err := ctrl.email_svc.RenderTemplate(&template, req.Vars)
+	if err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
 
 	utils.Success(c, gin.H{
-		"subject": subject,
-		"content": content,
-		"wrapped": wrapped,
+		"subject": rendered.Subject,
+		"content": rendered.Body,
+		"wrapped": rendered.HTML,
+		"text":    rendered.Text,
 	})
 }
 
 // Reset 重置邮件模板为默认
-// @Summary 重置邮件模板
-// @Description 重置邮件模板为系统默认模板
-// @Tags Admin-邮件模板
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "模板ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/email-templates/{id}/reset [post]
 func (ctrl *EmailTemplateController) Reset(c *gin.Context) {
 	id_str := c.Param("id")
 	id, err := strconv.ParseUint(id_str, 10, 64)
@@ -217,79 +180,128 @@ func (ctrl *EmailTemplateController) Reset(c *gin.Context) {
 		return
 	}
 
-	// 获取模板名称和语言
-	var template models.EmailTemplate
-	query := "SELECT * FROM email_templates WHERE id = ?"
-	if err := db.DB.Get(&template, query, id); err != nil {
+	if _, err := models.GetEmailTemplateByID(id); err != nil {
 		utils.Fail(c, 404, "Template not found")
 		return
 	}
 
-	// 默认模板内容
-	registerCodeZH := `<p style="margin:0 0 16px 0;">您好，感谢您的注册！请使用以下验证码完成验证：</p>` +
-		`<div style="text-align:center;margin:28px 0;">` +
-		`<div style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:32px;font-weight:700;letter-spacing:8px;padding:16px 40px;border-radius:12px;">{code}</div>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">⏱ 验证码有效期为 <strong>{expire_minutes} 分钟</strong>，请尽快使用。</p>` +
-		`<p style="margin:0;color:#a0a0b8;font-size:13px;">如果这不是您本人的操作，请忽略此邮件。请勿将验证码透露给任何人。</p>`
-
-	registerCodeEN := `<p style="margin:0 0 16px 0;">Hello! Thank you for signing up. Please use the following code to verify your account:</p>` +
-		`<div style="text-align:center;margin:28px 0;">` +
-		`<div style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:32px;font-weight:700;letter-spacing:8px;padding:16px 40px;border-radius:12px;">{code}</div>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">⏱ This code is valid for <strong>{expire_minutes} minutes</strong>.</p>` +
-		`<p style="margin:0;color:#a0a0b8;font-size:13px;">If you did not request this, please ignore this email. Never share your code with anyone.</p>`
-
-	resetPasswordZH := `<p style="margin:0 0 16px 0;">您好，我们收到了您的密码重置请求。请点击下方按钮重置密码：</p>` +
-		`<div style="text-align:center;margin:28px 0;">` +
-		`<a href="{link}" style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:16px;font-weight:600;text-decoration:none;padding:14px 48px;border-radius:10px;">重置密码</a>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">如果按钮无法点击，您也可以使用以下验证码：</p>` +
-		`<div style="text-align:center;margin:20px 0;">` +
-		`<div style="display:inline-block;background:#f0f2f5;font-size:28px;font-weight:700;letter-spacing:6px;padding:14px 36px;border-radius:10px;color:#1a1a2e;border:2px dashed #667eea;">{code}</div>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">⏱ 有效期为 <strong>15 分钟</strong>，请尽快操作。</p>` +
-		`<p style="margin:0;color:#a0a0b8;font-size:13px;">如果这不是您本人的操作，请忽略此邮件，您的密码不会被更改。</p>`
-
-	resetPasswordEN := `<p style="margin:0 0 16px 0;">Hello, we received a request to reset your password. Click the button below to proceed:</p>` +
-		`<div style="text-align:center;margin:28px 0;">` +
-		`<a href="{link}" style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:16px;font-weight:600;text-decoration:none;padding:14px 48px;border-radius:10px;">Reset Password</a>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">If the button doesn't work, you can also use this verification code:</p>` +
-		`<div style="text-align:center;margin:20px 0;">` +
-		`<div style="display:inline-block;background:#f0f2f5;font-size:28px;font-weight:700;letter-spacing:6px;padding:14px 36px;border-radius:10px;color:#1a1a2e;border:2px dashed #667eea;">{code}</div>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">⏱ Valid for <strong>15 minutes</strong>.</p>` +
-		`<p style="margin:0;color:#a0a0b8;font-size:13px;">If you did not request a password reset, please ignore this email. Your password will remain unchanged.</p>`
-
-	default_templates := map[string]map[string]struct {
-		Subject string
-		Content string
-	}{
-		"register_code": {
-			"zh-CN": {Subject: "【{app_name}】注册验证码", Content: registerCodeZH},
-			"en-US": {Subject: "[{app_name}] Registration Code", Content: registerCodeEN},
-		},
-		"reset_password": {
-			"zh-CN": {Subject: "【{app_name}】密码重置请求", Content: resetPasswordZH},
-			"en-US": {Subject: "[{app_name}] Password Reset Request", Content: resetPasswordEN},
-		},
-	}
-
-	// 获取默认模板
-	if templates, ok := default_templates[template.Name]; ok {
-		if default_tpl, ok := templates[template.Lang]; ok {
-			update_query := "UPDATE email_templates SET subject = ?, content = ? WHERE id = ?"
-			if _, err := db.DB.Exec(update_query, default_tpl.Subject, default_tpl.Content, id); err != nil {
-				utils.Fail(c, 500, "Failed to reset template")
-				return
-			}
-			utils.Success(c, gin.H{"message": "Template reset successfully"})
-			return
-		}
+	// 恢复内置默认内容并记录版本
+	rev, err := ctrl.email_svc.ResetTemplate(id, templateAuthor(c))
+	if err == services.ErrTemplateUnchanged {
+		utils.Success(c, gin.H{"message": "Template is already the default"})
+		return
+	}
+	if err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Template reset successfully", "version": rev.Version})
+}
+
+// Revisions 获取模板版本列表
+func (ctrl *EmailTemplateController) Revisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "Invalid template ID")
+		return
 	}
 
-	utils.Fail(c, 400, "No default template available for this template")
+	revisions, err := ctrl.email_svc.TemplateRevisions(id)
+	if err != nil {
+		utils.Fail(c, 500, "Failed to fetch revisions")
+		return
+	}
+	utils.Success(c, revisions)
+}
+
+// RevisionDetail 获取模板指定版本
+func (ctrl *EmailTemplateController) RevisionDetail(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "Invalid template ID")
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		utils.Fail(c, 400, "Invalid version")
+		return
+	}
+
+	rev, err := ctrl.email_svc.TemplateRevision(id, version)
+	if err != nil {
+		utils.Fail(c, 404, "Revision not found")
+		return
+	}
+	utils.Success(c, rev)
+}
+
+// EmailTemplateDiffQuery 版本对比参数
+type EmailTemplateDiffQuery struct {
+	From int `form:"from" binding:"required,min=1"` // 旧版本号
+	To   int `form:"to"`                            // 新版本号，不传则与当前内容对比
+}
+
+// Diff 对比模板版本
+func (ctrl *EmailTemplateController) Diff(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "Invalid template ID")
+		return
+	}
+	var query EmailTemplateDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.FailBind(c, err, &query)
+		return
+	}
+
+	diff, err := ctrl.email_svc.DiffTemplate(id, query.From, query.To)
+	if err != nil {
+		utils.Fail(c, 404, err.Error())
+		return
+	}
+	utils.Success(c, diff)
+}
+
+// EmailTemplateRollbackRequest 回滚请求
+type EmailTemplateRollbackRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
+
+// Rollback 回滚模板到指定版本
+func (ctrl *EmailTemplateController) Rollback(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "Invalid template ID")
+		return
+	}
+	var req EmailTemplateRollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.FailBind(c, err, &req)
+		return
+	}
+	if _, err := models.GetEmailTemplateByID(id); err != nil {
+		utils.Fail(c, 404, "Template not found")
+		return
+	}
+
+	rev, err := ctrl.email_svc.RollbackTemplate(id, req.Version, templateAuthor(c))
+	if err == services.ErrTemplateUnchanged {
+		utils.Success(c, gin.H{"message": "Template unchanged"})
+		return
+	}
+	if err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
+	utils.Success(c, gin.H{"message": "Template rolled back successfully", "version": rev.Version})
+}
+
+// templateAuthor 当前管理员，作为模板版本的修改人
+func templateAuthor(c *gin.Context) services.TemplateAuthor {
+	user_id, _ := c.Get("userID")
+	id, _ := user_id.(uint64)
+	return services.TemplateAuthor{ID: id, Name: c.GetString("username")}
 }
 
 // EmailSendTestRequest 发件测试请求
@@ -301,19 +313,10 @@ type EmailSendTestRequest struct {
 }
 
 // SendTest 发件测试
-// @Summary 发件测试
-// @Description 发送测试邮件，验证SMTP配置是否正常，支持选择模板发送
-// @Tags Admin-邮件模板
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body EmailSendTestRequest true "测试邮件参数"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/email-send-test [post]
 func (ctrl *EmailTemplateController) SendTest(c *gin.Context) {
 	var req EmailSendTestRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -328,24 +331,19 @@ func (ctrl *EmailTemplateController) SendTest(c *gin.Context) {
 			return
 		}
 
-		// 使用示例变量渲染
-		subject = tpl.Subject
-		content = tpl.Content
-
-		// 替换常见变量为示例值
-		example_vars := map[string]string{
-			"{app_name}":       "TestApp",
-			"{code}":           "888888",
-			"{expire_minutes}": "15",
-			"{link}":           "https://example.com/reset?token=test123",
-		}
-		for k, v := range example_vars {
-			subject = strings.ReplaceAll(subject, k, v)
-			content = strings.ReplaceAll(content, k, v)
+		// 使用示例变量渲染并套用布局
+		rendered, err := ctrl.email_svc.RenderTemplate(&tpl, map[string]any{
+			"app_name":       "TestApp",
+			"code":           "888888",
+			"expire_minutes": "15",
+			"link":           "https://example.com/reset?token=test123",
+		})
+		if err != nil {
+			utils.Fail(c, 400, err.Error())
+			return
 		}
-
-		// 包装 HTML 布局
-		content = ctrl.email_svc.WrapHTMLLayout(subject, content)
+		subject = rendered.Subject
+		content = rendered.HTML
 	} else {
 		// 自定义内容发送
 		subject = req.Subject
@@ -366,17 +364,3 @@ func (ctrl *EmailTemplateController) SendTest(c *gin.Context) {
 
 	utils.Success(c, gin.H{"message": "测试邮件已发送"})
 }
-
-// 辅助函数
-func toString(v interface{}) string {
-	switch val := v.(type) {
-	case string:
-		return val
-	case float64:
-		return strconv.FormatFloat(val, 'f', -1, 64)
-	case int:
-		return strconv.Itoa(val)
-	default:
-		return ""
-	}
-}
@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"errors"
+	"fst/backend/app/models"
+	"fst/backend/app/services"
+	"fst/backend/internal/openapi"
+	"fst/backend/internal/scheduler"
+	"fst/backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobController 定时任务管理控制器
+type JobController struct{}
+
+func NewJobController() *JobController {
+	return &JobController{}
+}
+
+// List 定时任务列表
+func (ctrl *JobController) List(c *gin.Context) {
+	jobs, err := services.ListJobs()
+	if err != nil {
+		utils.Fail(c, 500, "查询失败")
+		return
+	}
+	utils.Success(c, gin.H{"list": jobs})
+}
+
+// Runs 定时任务运行记录
+func (ctrl *JobController) Runs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	runs, total, err := models.GetScheduledJobRunList(c.Param("name"), page, pageSize)
+	if err != nil {
+		utils.Fail(c, 500, "查询失败")
+		return
+	}
+	utils.Success(c, gin.H{
+		"list":      runs,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// Pause 暂停定时任务
+func (ctrl *JobController) Pause(c *gin.Context) {
+	if err := services.GlobalScheduler.Pause(c.Param("name")); err != nil {
+		failJobError(c, err)
+		return
+	}
+	utils.SuccessMsg(c, "任务已暂停", nil)
+}
+
+// Resume 恢复定时任务
+func (ctrl *JobController) Resume(c *gin.Context) {
+	if err := services.GlobalScheduler.Resume(c.Param("name")); err != nil {
+		failJobError(c, err)
+		return
+	}
+	utils.SuccessMsg(c, "任务已恢复", nil)
+}
+
+// Trigger 手动触发定时任务
+func (ctrl *JobController) Trigger(c *gin.Context) {
+	if err := services.GlobalScheduler.Trigger(c.Param("name")); err != nil {
+		failJobError(c, err)
+		return
+	}
+	utils.SuccessMsg(c, "任务已触发", nil)
+}
+
+func failJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		utils.Fail(c, 404, "任务不存在")
+	case errors.Is(err, scheduler.ErrJobRunning), errors.Is(err, scheduler.ErrLeaseHeld):
+		utils.Fail(c, 409, "任务正在执行中")
+	default:
+		utils.Fail(c, 500, "操作失败: "+err.Error())
+	}
+}
+
+// RegisterRoutes 注册定时任务管理路由
+func (ctrl *JobController) RegisterRoutes(group *gin.RouterGroup) {
+	jobs := group.Group("/jobs")
+	{
+		jobs.GET("", ctrl.List)
+		openapi.Route(jobs, "GET", "", openapi.Op("获取定时任务列表", "Admin-定时任务").
+			Notes("返回所有定时任务的表达式、暂停状态、下次执行时间、执行实例与最近一次运行结果").Auth().Returns(nil))
+		jobs.GET("/:name/runs", ctrl.Runs)
+		openapi.Route(jobs, "GET", "/:name/runs", openapi.Op("获取定时任务运行记录", "Admin-定时任务").
+			Notes("分页获取指定任务的运行历史，包含执行实例、耗时与错误信息").Auth().Path("name", "string", "任务名称").Paged().Returns(nil))
+		jobs.POST("/:name/pause", ctrl.Pause)
+		openapi.Route(jobs, "POST", "/:name/pause", openapi.Op("暂停定时任务", "Admin-定时任务").
+			Notes("暂停后所有实例都不再定时执行该任务，手动触发不受影响").Auth().Path("name", "string", "任务名称").Returns(nil))
+		jobs.POST("/:name/resume", ctrl.Resume)
+		openapi.Route(jobs, "POST", "/:name/resume", openapi.Op("恢复定时任务", "Admin-定时任务").Auth().
+			Path("name", "string", "任务名称").Returns(nil))
+		jobs.POST("/:name/trigger", ctrl.Trigger)
+		openapi.Route(jobs, "POST", "/:name/trigger", openapi.Op("手动触发定时任务", "Admin-定时任务").
+			Notes("立即在当前实例异步执行一次任务，其他实例正在执行时返回错误").Auth().Path("name", "string", "任务名称").Returns(nil))
+	}
+}
@@ -17,16 +17,6 @@ func NewLogController() *LogController {
 }
 
 // List 日志列表
-// @Summary 获取操作日志列表
-// @Description 获取操作日志列表（分页），仅支持简单分页浏览
-// @Tags Admin-操作日志
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(20)
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/logs [get]
 func (c *LogController) List(ctx *gin.Context) {
 	utils.SanitizeQueryParams(ctx)
 	var query models.OperationLogQuery
@@ -102,15 +92,6 @@ func (c *LogController) List(ctx *gin.Context) {
 }
 
 // Clean 清理日志
-// @Summary 清理操作日志
-// @Description 清理指定时间之前的操作日志，用于控制日志数量
-// @Tags Admin-操作日志
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param body body map[string]int64 true "清理参数 {before_time: timestamp}"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/logs/clean [post]
 func (c *LogController) Clean(ctx *gin.Context) {
 	var req struct {
 		BeforeTime int64 `json:"before_time" binding:"required"`
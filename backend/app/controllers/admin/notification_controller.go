@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"errors"
+	"fst/backend/app/services"
+	"fst/backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController 站内通知管理控制器
+type NotificationController struct{}
+
+func NewNotificationController() *NotificationController {
+	return &NotificationController{}
+}
+
+// Broadcast 广播系统通知
+func (ctrl *NotificationController) Broadcast(c *gin.Context) {
+	var req services.NotificationBroadcast
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.FailBind(c, err, &req)
+		return
+	}
+
+	count, err := services.BroadcastNotification(&req)
+	if errors.Is(err, services.ErrNotificationEmpty) {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
+	if err != nil {
+		utils.Fail(c, 500, "发送失败")
+		return
+	}
+
+	utils.Success(c, gin.H{"count": count})
+}
@@ -3,6 +3,7 @@ package admin
 import (
 	"fst/backend/app/models"
 	"fst/backend/app/services"
+	"fst/backend/internal/openapi"
 	"fst/backend/utils"
 	"strconv"
 
@@ -30,17 +31,6 @@ type AdminCompleteOrderRequest struct {
 // ========================================
 
 // ListOrders 订单列表（管理端，支持筛选）
-// @Summary 管理端-支付订单列表
-// @Tags 管理端-支付
-// @Produce json
-// @Security BearerAuth
-// @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(20)
-// @Param status query int false "状态筛选（-1=全部）" default(-1)
-// @Param user_id query int false "用户ID筛选"
-// @Param keyword query string false "搜索关键词"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/payment/orders [get]
 func (ctrl *PaymentController) ListOrders(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
@@ -72,13 +62,6 @@ func (ctrl *PaymentController) ListOrders(c *gin.Context) {
 }
 
 // OrderDetail 订单详情
-// @Summary 管理端-订单详情
-// @Tags 管理端-支付
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "订单ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/payment/orders/{id} [get]
 func (ctrl *PaymentController) OrderDetail(c *gin.Context) {
 	orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
@@ -96,15 +79,6 @@ func (ctrl *PaymentController) OrderDetail(c *gin.Context) {
 }
 
 // CompleteOrder 手动补单
-// @Summary 管理端-手动补单
-// @Tags 管理端-支付
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "订单ID"
-// @Param body body AdminCompleteOrderRequest false "补单备注"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/payment/orders/{id}/complete [post]
 func (ctrl *PaymentController) CompleteOrder(c *gin.Context) {
 	orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
@@ -125,13 +99,6 @@ func (ctrl *PaymentController) CompleteOrder(c *gin.Context) {
 }
 
 // CancelOrder 取消订单
-// @Summary 管理端-取消订单
-// @Tags 管理端-支付
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "订单ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/payment/orders/{id}/cancel [post]
 func (ctrl *PaymentController) CancelOrder(c *gin.Context) {
 	orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
@@ -148,12 +115,6 @@ func (ctrl *PaymentController) CancelOrder(c *gin.Context) {
 }
 
 // GetStats 支付统计
-// @Summary 管理端-支付统计
-// @Tags 管理端-支付
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/payment/stats [get]
 func (ctrl *PaymentController) GetStats(c *gin.Context) {
 	stats, err := models.GetPaymentStats()
 	if err != nil {
@@ -165,13 +126,6 @@ func (ctrl *PaymentController) GetStats(c *gin.Context) {
 }
 
 // DeleteOrder 删除订单
-// @Summary 管理端-删除订单
-// @Tags 管理端-支付
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "订单ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/payment/orders/{id} [delete]
 func (ctrl *PaymentController) DeleteOrder(c *gin.Context) {
 	orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
@@ -294,11 +248,23 @@ func (ctrl *PaymentController) RegisterPaymentRoutes(group *gin.RouterGroup) {
 	{
 		// 订单管理
 		payment.GET("/orders", ctrl.ListOrders)
+		openapi.Route(payment, "GET", "/orders", openapi.Op("管理端-支付订单列表", "管理端-支付").Auth().Paged().
+			Query("status", "int", "状态筛选（-1=全部）").Default(-1).Query("user_id", "int", "用户ID筛选").
+			Query("keyword", "string", "搜索关键词").Returns(nil))
 		payment.GET("/orders/:id", ctrl.OrderDetail)
+		openapi.Route(payment, "GET", "/orders/:id", openapi.Op("管理端-订单详情", "管理端-支付").Auth().Path("id", "int", "订单ID").
+			Returns(nil))
 		payment.POST("/orders/:id/complete", ctrl.CompleteOrder)
+		openapi.Route(payment, "POST", "/orders/:id/complete", openapi.Op("管理端-手动补单", "管理端-支付").Auth().
+			Path("id", "int", "订单ID").Body(AdminCompleteOrderRequest{}, "补单备注").Returns(nil))
 		payment.POST("/orders/:id/cancel", ctrl.CancelOrder)
+		openapi.Route(payment, "POST", "/orders/:id/cancel", openapi.Op("管理端-取消订单", "管理端-支付").Auth().
+			Path("id", "int", "订单ID").Returns(nil))
 		payment.DELETE("/orders/:id", ctrl.DeleteOrder)
+		openapi.Route(payment, "DELETE", "/orders/:id", openapi.Op("管理端-删除订单", "管理端-支付").Auth().
+			Path("id", "int", "订单ID").Returns(nil))
 		payment.GET("/stats", ctrl.GetStats)
+		openapi.Route(payment, "GET", "/stats", openapi.Op("管理端-支付统计", "管理端-支付").Auth().Returns(nil))
 
 		// 支付通道管理
 		payment.POST("/gateways", ctrl.CreateGateway)
@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"errors"
+	"fst/backend/app/plugins"
+	"fst/backend/app/services"
+	"fst/backend/internal/openapi"
+	"fst/backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PluginController 插件管理控制器
+type PluginController struct{}
+
+func NewPluginController() *PluginController {
+	return &PluginController{}
+}
+
+// List 插件列表
+func (ctrl *PluginController) List(c *gin.Context) {
+	if services.GlobalPluginManager == nil {
+		utils.Success(c, gin.H{"list": []plugins.PluginInfo{}})
+		return
+	}
+	utils.Success(c, gin.H{"list": services.GlobalPluginManager.GetPluginInfos()})
+}
+
+// Enable 启用插件
+func (ctrl *PluginController) Enable(c *gin.Context) {
+	ctrl.apply(c, "插件已启用", func(m *plugins.Manager, name string) error { return m.Enable(name) })
+}
+
+// Disable 禁用插件
+func (ctrl *PluginController) Disable(c *gin.Context) {
+	ctrl.apply(c, "插件已禁用", func(m *plugins.Manager, name string) error { return m.Disable(name) })
+}
+
+// Reinit 重新初始化插件
+func (ctrl *PluginController) Reinit(c *gin.Context) {
+	ctrl.apply(c, "插件已重新初始化", func(m *plugins.Manager, name string) error { return m.Reinit(name) })
+}
+
+// GetConfig 获取插件配置
+func (ctrl *PluginController) GetConfig(c *gin.Context) {
+	mgr := services.GlobalPluginManager
+	if mgr == nil {
+		utils.Fail(c, 503, "插件系统未初始化")
+		return
+	}
+	view, err := mgr.GetConfigView(c.Param("name"))
+	if err != nil {
+		failPluginError(c, err)
+		return
+	}
+	utils.Success(c, view)
+}
+
+// UpdateConfig 更新插件配置
+func (ctrl *PluginController) UpdateConfig(c *gin.Context) {
+	mgr := services.GlobalPluginManager
+	if mgr == nil {
+		utils.Fail(c, 503, "插件系统未初始化")
+		return
+	}
+	var values map[string]interface{}
+	if err := c.ShouldBindJSON(&values); err != nil {
+		utils.Fail(c, 400, "请求体格式错误")
+		return
+	}
+	view, err := mgr.UpdateConfig(c.Param("name"), values)
+	if err != nil {
+		failPluginError(c, err)
+		return
+	}
+	utils.SuccessMsg(c, "配置已保存", view)
+}
+
+// LoadReport 插件加载报告
+func (ctrl *PluginController) LoadReport(c *gin.Context) {
+	if services.GlobalPluginManager == nil {
+		utils.Success(c, gin.H{"list": []plugins.LoadResult{}})
+		return
+	}
+	utils.Success(c, gin.H{"list": services.GlobalPluginManager.LoadReport()})
+}
+
+// Health 插件健康检查
+func (ctrl *PluginController) Health(c *gin.Context) {
+	mgr := services.GlobalPluginManager
+	if mgr == nil {
+		utils.Success(c, plugins.HealthReport{Status: plugins.HealthOK, Plugins: []plugins.PluginHealth{}})
+		return
+	}
+	utils.Success(c, mgr.CheckHealth(c.Request.Context()))
+}
+
+// Menus 插件管理端菜单
+func (ctrl *PluginController) Menus(c *gin.Context) {
+	mgr := services.GlobalPluginManager
+	if mgr == nil {
+		utils.Success(c, plugins.AdminMenus{Menus: []plugins.MenuItem{}, Permissions: []plugins.Permission{}})
+		return
+	}
+	utils.Success(c, mgr.GetAdminMenus())
+}
+
+func (ctrl *PluginController) apply(c *gin.Context, msg string, fn func(*plugins.Manager, string) error) {
+	mgr := services.GlobalPluginManager
+	if mgr == nil {
+		utils.Fail(c, 503, "插件系统未初始化")
+		return
+	}
+	if err := fn(mgr, c.Param("name")); err != nil {
+		failPluginError(c, err)
+		return
+	}
+	utils.SuccessMsg(c, msg, nil)
+}
+
+func failPluginError(c *gin.Context, err error) {
+	var configErr *plugins.ConfigError
+	switch {
+	case errors.Is(err, plugins.ErrPluginNotFound):
+		utils.Fail(c, 404, err.Error())
+	case errors.Is(err, plugins.ErrPluginDisabled), errors.Is(err, plugins.ErrPluginBusy):
+		utils.Fail(c, 409, err.Error())
+	case errors.Is(err, plugins.ErrNotConfigurable), errors.As(err, &configErr):
+		utils.Fail(c, 400, err.Error())
+	default:
+		utils.Fail(c, 500, "操作失败: "+err.Error())
+	}
+}
+
+// RegisterRoutes 注册插件管理路由
+func (ctrl *PluginController) RegisterRoutes(group *gin.RouterGroup) {
+	p := group.Group("/plugins")
+	{
+		p.GET("", ctrl.List)
+		openapi.Route(p, "GET", "", openapi.Op("获取插件列表", "Admin-插件管理").
+			Notes("按加载顺序返回所有插件的版本、依赖、状态（active / inactive / disabled / error / skipped）与加载错误").Auth().Returns(nil))
+		p.GET("/health", ctrl.Health)
+		openapi.Route(p, "GET", "/health", openapi.Op("插件健康检查", "Admin-插件管理").
+			Notes("并发调用已启用插件的健康检查（HealthChecker），加载失败的插件记为 down；任一插件异常时汇总状态为 degraded").Auth().Returns(nil))
+		p.GET("/load-report", ctrl.LoadReport)
+		openapi.Route(p, "GET", "/load-report", openapi.Op("获取插件加载报告", "Admin-插件管理").
+			Notes("返回启动时每个插件的加载结果（loaded / failed / skipped / disabled）及失败或跳过的原因，如依赖版本不满足、依赖加载失败").Auth().Returns(nil))
+		p.GET("/menus", ctrl.Menus)
+		openapi.Route(p, "GET", "/menus", openapi.Op("获取插件管理端菜单", "Admin-插件管理").
+			Notes("返回已启用插件声明的管理端菜单项与权限（AdminMenu），名称与权限带插件名前缀").Auth().Returns(nil))
+		p.POST("/:name/enable", ctrl.Enable)
+		openapi.Route(p, "POST", "/:name/enable", openapi.Op("启用插件", "Admin-插件管理").
+			Notes("启用插件并持久化状态，未加载的插件会立即初始化，重启后保持启用").Auth().Path("name", "string", "插件名称").Returns(nil))
+		p.POST("/:name/disable", ctrl.Disable)
+		openapi.Route(p, "POST", "/:name/disable", openapi.Op("禁用插件", "Admin-插件管理").
+			Notes("禁用插件并持久化状态，插件路由立即返回 404，重启后保持禁用").Auth().Path("name", "string", "插件名称").Returns(nil))
+		p.POST("/:name/reinit", ctrl.Reinit)
+		openapi.Route(p, "POST", "/:name/reinit", openapi.Op("重新初始化插件", "Admin-插件管理").
+			Notes("关闭插件后重新执行配置、初始化与数据库迁移，可用于修复加载失败的插件").Auth().Path("name", "string", "插件名称").Returns(nil))
+		p.GET("/:name/config", ctrl.GetConfig)
+		openapi.Route(p, "GET", "/:name/config", openapi.Op("获取插件配置", "Admin-插件管理").
+			Notes("返回插件声明的配置项（类型、默认值、是否敏感）与当前值，敏感项以掩码显示").Auth().Path("name", "string", "插件名称").Returns(nil))
+		p.PUT("/:name/config", ctrl.UpdateConfig)
+		openapi.Route(p, "PUT", "/:name/config", openapi.Op("更新插件配置", "Admin-插件管理").
+			Notes("按声明校验后保存，插件已启用时立即生效无需重启；未提交的项保持原值，null 恢复默认值，敏感项提交掩码表示不修改").Auth().Path("name", "string", "插件名称").
+			Body(map[string]interface{}{}, "配置项").Returns(nil))
+	}
+}
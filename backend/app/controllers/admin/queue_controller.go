@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"database/sql"
+	"errors"
+	"fst/backend/app/models"
+	"fst/backend/app/services"
+	"fst/backend/internal/openapi"
+	"fst/backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueueController 后台任务队列管理控制器
+type QueueController struct{}
+
+func NewQueueController() *QueueController {
+	return &QueueController{}
+}
+
+// Jobs 队列任务列表
+func (ctrl *QueueController) Jobs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	jobs, total, err := models.GetQueueJobList(c.Query("queue"), c.Query("status"), c.Query("type"), page, pageSize)
+	if err != nil {
+		utils.Fail(c, 500, "查询失败")
+		return
+	}
+	utils.Success(c, gin.H{
+		"list":      jobs,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// Job 队列任务详情
+func (ctrl *QueueController) Job(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "无效的任务ID")
+		return
+	}
+	job, err := models.GetQueueJobByID(id)
+	if err == sql.ErrNoRows {
+		utils.Fail(c, 404, "任务不存在")
+		return
+	}
+	if err != nil {
+		utils.Fail(c, 500, "查询失败")
+		return
+	}
+	utils.Success(c, job)
+}
+
+// Retry 重试死信任务
+func (ctrl *QueueController) Retry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "无效的任务ID")
+		return
+	}
+	err = services.RetryQueueJob(id)
+	switch {
+	case err == nil:
+		utils.SuccessMsg(c, "任务已重新入队", nil)
+	case errors.Is(err, sql.ErrNoRows):
+		utils.Fail(c, 404, "任务不存在")
+	case errors.Is(err, services.ErrQueueJobNotDead):
+		utils.Fail(c, 409, "只能重试死信任务")
+	default:
+		utils.Fail(c, 500, "操作失败: "+err.Error())
+	}
+}
+
+// Stats 队列统计
+func (ctrl *QueueController) Stats(c *gin.Context) {
+	stats, err := models.GetQueueJobStats()
+	if err != nil {
+		utils.Fail(c, 500, "查询失败")
+		return
+	}
+	utils.Success(c, gin.H{"list": stats})
+}
+
+// RegisterRoutes 注册任务队列管理路由
+func (ctrl *QueueController) RegisterRoutes(group *gin.RouterGroup) {
+	q := group.Group("/queue")
+	{
+		q.GET("/stats", ctrl.Stats)
+		openapi.Route(q, "GET", "/stats", openapi.Op("获取队列统计", "Admin-任务队列").Notes("按队列与状态统计任务数量").Auth().Returns(nil))
+		q.GET("/jobs", ctrl.Jobs)
+		openapi.Route(q, "GET", "/jobs", openapi.Op("获取队列任务列表", "Admin-任务队列").
+			Notes("分页获取后台队列任务，可按队列、状态（pending/running/done/dead）与任务类型筛选").Auth().Query("queue", "string", "队列名称").
+			Query("status", "string", "任务状态").Query("type", "string", "任务类型").Paged().Returns(nil))
+		q.GET("/jobs/:id", ctrl.Job)
+		openapi.Route(q, "GET", "/jobs/:id", openapi.Op("获取队列任务详情", "Admin-任务队列").Notes("返回任务参数、执行次数与最近一次错误").Auth().
+			Path("id", "int", "任务ID").Returns(nil))
+		q.POST("/jobs/:id/retry", ctrl.Retry)
+		openapi.Route(q, "POST", "/jobs/:id/retry", openapi.Op("重试死信任务", "Admin-任务队列").
+			Notes("将重试耗尽的任务重置为待执行，执行次数重新计算").Auth().Path("id", "int", "任务ID").Returns(nil))
+	}
+}
@@ -6,11 +6,16 @@ import (
 	"fst/backend/app/services"
 	"fst/backend/internal/config"
 	"fst/backend/internal/db"
+	"fst/backend/internal/events"
+	"fst/backend/internal/lifecycle"
+	"fst/backend/internal/openapi"
 	"fst/backend/utils"
+	"log"
 	"net"
 	"os"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -89,26 +94,11 @@ var serverMonitorStartedAt = time.Now()
 
 const sensitiveSettingMaskedValue = "********"
 
-var sensitiveSettingKeys = map[string]struct{}{
-	"geetest_captcha_key": {},
-	"smtp_password":       {},
-	"sms_access_key":      {},
-	"sms_secret_key":      {},
-}
-
 // ========================================
 // 控制器方法
 // ========================================
 
 // List 获取所有配置
-// @Summary 获取所有系统配置
-// @Description 获取所有系统配置，按分类分组
-// @Tags Admin-系统配置
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response{data=SettingsListResponse}
-// @Router /api/v1/admin/settings [get]
 func (ctrl *SettingsController) List(c *gin.Context) {
 	settings, err := models.GetAllSettings()
 	if err != nil {
@@ -150,15 +140,6 @@ func (ctrl *SettingsController) List(c *gin.Context) {
 }
 
 // GetByCategory 获取指定分类的配置
-// @Summary 获取指定分类的配置
-// @Description 获取指定分类下的所有配置项
-// @Tags Admin-系统配置
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param category path string true "分类名称"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/settings/category/{category} [get]
 func (ctrl *SettingsController) GetByCategory(c *gin.Context) {
 	category := c.Param("category")
 	if category == "" {
@@ -190,15 +171,6 @@ func (ctrl *SettingsController) GetByCategory(c *gin.Context) {
 }
 
 // Get 获取单个配置
-// @Summary 获取单个配置
-// @Description 根据键名获取配置详情
-// @Tags Admin-系统配置
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param key path string true "配置键名"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/settings/{key} [get]
 func (ctrl *SettingsController) Get(c *gin.Context) {
 	key := c.Param("key")
 	if key == "" {
@@ -227,16 +199,6 @@ func (ctrl *SettingsController) Get(c *gin.Context) {
 }
 
 // Update 更新单个配置值
-// @Summary 更新单个配置值
-// @Description 更新指定配置项的值
-// @Tags Admin-系统配置
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param key path string true "配置键名"
-// @Param request body UpdateSettingRequest true "配置值"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/settings/{key} [put]
 func (ctrl *SettingsController) Update(c *gin.Context) {
 	key := c.Param("key")
 	if key == "" {
@@ -259,7 +221,7 @@ func (ctrl *SettingsController) Update(c *gin.Context) {
 
 	var req UpdateSettingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -269,6 +231,10 @@ func (ctrl *SettingsController) Update(c *gin.Context) {
 		utils.Fail(c, 400, "Invalid value type for "+setting.Type)
 		return
 	}
+	if err := ctrl.validateSettingContent(key, req.Value); err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
 
 	// 更新配置
 	if err := models.UpdateSetting(key, resolvedValue); err != nil {
@@ -276,22 +242,12 @@ func (ctrl *SettingsController) Update(c *gin.Context) {
 		return
 	}
 
-	ctrl.refreshRuntimeConfig()
+	ctrl.refreshRuntimeConfig(key)
 
 	utils.Success(c, gin.H{"message": "Setting updated successfully"})
 }
 
 // UpdateMeta 更新配置元数据
-// @Summary 更新配置元数据
-// @Description 更新配置项的完整信息（包括值和元数据）
-// @Tags Admin-系统配置
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param key path string true "配置键名"
-// @Param request body UpdateSettingMetaRequest true "配置信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/settings/{key}/meta [put]
 func (ctrl *SettingsController) UpdateMeta(c *gin.Context) {
 	key := c.Param("key")
 	if key == "" {
@@ -308,7 +264,7 @@ func (ctrl *SettingsController) UpdateMeta(c *gin.Context) {
 
 	var req UpdateSettingMetaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -324,6 +280,10 @@ func (ctrl *SettingsController) UpdateMeta(c *gin.Context) {
 		utils.Fail(c, 400, "Invalid value type for "+req.Type)
 		return
 	}
+	if err := ctrl.validateSettingContent(key, req.Value); err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
 
 	// 构建更新对象
 	setting := &models.SystemSetting{
@@ -343,25 +303,16 @@ func (ctrl *SettingsController) UpdateMeta(c *gin.Context) {
 		return
 	}
 
-	ctrl.refreshRuntimeConfig()
+	ctrl.refreshRuntimeConfig(key)
 
 	utils.Success(c, gin.H{"message": "Setting updated successfully"})
 }
 
 // BatchUpdate 批量更新配置
-// @Summary 批量更新配置
-// @Description 批量更新多个配置项的值
-// @Tags Admin-系统配置
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body BatchUpdateSettingsRequest true "配置键值对"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/settings/batch [put]
 func (ctrl *SettingsController) BatchUpdate(c *gin.Context) {
 	var req BatchUpdateSettingsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -383,6 +334,10 @@ func (ctrl *SettingsController) BatchUpdate(c *gin.Context) {
 			utils.Fail(c, 400, "Invalid value type for "+key)
 			return
 		}
+		if err := ctrl.validateSettingContent(key, req.Settings[key]); err != nil {
+			utils.Fail(c, 400, key+": "+err.Error())
+			return
+		}
 		resolvedSettings[key] = resolvedValue
 	}
 
@@ -392,25 +347,21 @@ func (ctrl *SettingsController) BatchUpdate(c *gin.Context) {
 		return
 	}
 
-	ctrl.refreshRuntimeConfig()
+	keys := make([]string, 0, len(resolvedSettings))
+	for key := range resolvedSettings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	ctrl.refreshRuntimeConfig(keys...)
 
 	utils.Success(c, gin.H{"message": "Settings updated successfully"})
 }
 
 // Create 创建新配置
-// @Summary 创建新配置
-// @Description 创建一个新的自定义配置项
-// @Tags Admin-系统配置
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body CreateSettingRequest true "配置信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/settings [post]
 func (ctrl *SettingsController) Create(c *gin.Context) {
 	var req CreateSettingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -451,6 +402,10 @@ func (ctrl *SettingsController) Create(c *gin.Context) {
 		utils.Fail(c, 400, "Invalid value type for "+req.Type)
 		return
 	}
+	if err := ctrl.validateSettingContent(req.Key, req.Value); err != nil {
+		utils.Fail(c, 400, err.Error())
+		return
+	}
 
 	// 验证分类
 	if !ctrl.isValidCategory(req.Category) {
@@ -476,7 +431,7 @@ func (ctrl *SettingsController) Create(c *gin.Context) {
 		return
 	}
 
-	ctrl.refreshRuntimeConfig()
+	ctrl.refreshRuntimeConfig(req.Key)
 
 	utils.Success(c, gin.H{
 		"message": "Setting created successfully",
@@ -485,15 +440,6 @@ func (ctrl *SettingsController) Create(c *gin.Context) {
 }
 
 // Delete 删除配置
-// @Summary 删除配置
-// @Description 删除指定的自定义配置项
-// @Tags Admin-系统配置
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param key path string true "配置键名"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/settings/{key} [delete]
 func (ctrl *SettingsController) Delete(c *gin.Context) {
 	key := c.Param("key")
 	if key == "" {
@@ -519,7 +465,7 @@ func (ctrl *SettingsController) Delete(c *gin.Context) {
 		return
 	}
 
-	ctrl.refreshRuntimeConfig()
+	ctrl.refreshRuntimeConfig(key)
 
 	utils.Success(c, gin.H{"message": "Setting deleted successfully"})
 }
@@ -571,6 +517,14 @@ func (ctrl *SettingsController) validateSettingValue(value, typ string) bool {
 	}
 }
 
+// validateSettingContent 校验受限配置项的取值（如邮件投递方式、DKIM 私钥），提交掩码表示不修改，不再校验
+func (ctrl *SettingsController) validateSettingContent(key, value string) error {
+	if isSensitiveSettingKey(key) && value == sensitiveSettingMaskedValue {
+		return nil
+	}
+	return config.ValidateSetting(key, value)
+}
+
 func (ctrl *SettingsController) resolveSettingValueForAdmin(setting models.SystemSetting) interface{} {
 	switch setting.Key {
 	case "geetest_enabled":
@@ -578,7 +532,7 @@ func (ctrl *SettingsController) resolveSettingValueForAdmin(setting models.Syste
 	case "geetest_captcha_id":
 		val := strings.TrimSpace(setting.Value)
 		if val == "" {
-			val = strings.TrimSpace(config.GlobalConfig.GeetestID)
+			val = strings.TrimSpace(config.Get().GeetestID)
 		}
 		return val
 	case "geetest_captcha_key":
@@ -586,13 +540,13 @@ func (ctrl *SettingsController) resolveSettingValueForAdmin(setting models.Syste
 	case "smtp_host":
 		val := strings.TrimSpace(setting.Value)
 		if val == "" {
-			val = strings.TrimSpace(config.GlobalConfig.SMTPHost)
+			val = strings.TrimSpace(config.Get().SMTPHost)
 		}
 		return val
 	case "smtp_port":
 		val := strings.TrimSpace(setting.Value)
 		if val == "" {
-			if port, err := strconv.Atoi(strings.TrimSpace(config.GlobalConfig.SMTPPort)); err == nil && port > 0 {
+			if port, err := strconv.Atoi(strings.TrimSpace(config.Get().SMTPPort)); err == nil && port > 0 {
 				return port
 			}
 			return setting.GetTypedValue()
@@ -604,50 +558,88 @@ func (ctrl *SettingsController) resolveSettingValueForAdmin(setting models.Syste
 	case "smtp_username":
 		val := strings.TrimSpace(setting.Value)
 		if val == "" {
-			val = strings.TrimSpace(config.GlobalConfig.SMTPUser)
+			val = strings.TrimSpace(config.Get().SMTPUser)
 		}
 		return val
-	case "smtp_password":
+	case "smtp_password", "dkim_private_key", "mail_http_token", "mail_webhook_token":
 		return ctrl.maskSensitiveSettingValue(ctrl.resolveCurrentSensitiveSettingValue(setting))
+	case "mail_transport":
+		val := strings.TrimSpace(setting.Value)
+		if val == "" {
+			val = config.Get().MailTransport
+		}
+		return val
+	case "smtp_pool_size":
+		if strings.TrimSpace(setting.Value) == "" {
+			if config.Get().SMTPPoolSize > 0 {
+				return config.Get().SMTPPoolSize
+			}
+		}
+		return setting.GetTypedValue()
 	case "smtp_ssl":
 		if strings.TrimSpace(setting.Value) == "" {
-			return config.GlobalConfig.SMTPSSL
+			return config.Get().SMTPSSL
 		}
 		return setting.GetTypedValue()
 	case "system_email_name":
 		val := strings.TrimSpace(setting.Value)
 		if val == "" {
-			val = strings.TrimSpace(config.GlobalConfig.SystemEmailName)
+			val = strings.TrimSpace(config.Get().SystemEmailName)
 		}
 		return val
 	case "jwt_access_expire":
 		if strings.TrimSpace(setting.Value) == "" {
-			if config.GlobalConfig.JWTAccessExpire > 0 {
-				return config.GlobalConfig.JWTAccessExpire
+			if config.Get().JWTAccessExpire > 0 {
+				return config.Get().JWTAccessExpire
 			}
 		}
 		return setting.GetTypedValue()
 	case "jwt_refresh_expire":
 		if strings.TrimSpace(setting.Value) == "" {
-			if config.GlobalConfig.JWTRefreshExpire > 0 {
-				return config.GlobalConfig.JWTRefreshExpire
+			if config.Get().JWTRefreshExpire > 0 {
+				return config.Get().JWTRefreshExpire
 			}
 		}
 		return setting.GetTypedValue()
 	case "login_max_failure":
 		if strings.TrimSpace(setting.Value) == "" {
-			if config.GlobalConfig.LoginMaxFailureCount > 0 {
-				return config.GlobalConfig.LoginMaxFailureCount
+			if config.Get().LoginMaxFailureCount > 0 {
+				return config.Get().LoginMaxFailureCount
 			}
 		}
 		return setting.GetTypedValue()
 	case "login_lock_duration":
 		if strings.TrimSpace(setting.Value) == "" {
-			if config.GlobalConfig.LoginLockDurationMinutes > 0 {
-				return config.GlobalConfig.LoginLockDurationMinutes
+			if config.Get().LoginLockDurationMinutes > 0 {
+				return config.Get().LoginLockDurationMinutes
 			}
 		}
 		return setting.GetTypedValue()
+	case "rate_limit_auth_rate":
+		if strings.TrimSpace(setting.Value) == "" {
+			return config.Get().AuthRateLimitRate
+		}
+		return setting.GetTypedValue()
+	case "rate_limit_auth_burst":
+		if strings.TrimSpace(setting.Value) == "" {
+			return config.Get().AuthRateLimitBurst
+		}
+		return setting.GetTypedValue()
+	case "sms_phone_hourly_limit":
+		if strings.TrimSpace(setting.Value) == "" {
+			return config.SMSPhoneHourlyLimit.Get()
+		}
+		return setting.GetTypedValue()
+	case "sms_phone_daily_limit":
+		if strings.TrimSpace(setting.Value) == "" {
+			return config.SMSPhoneDailyLimit.Get()
+		}
+		return setting.GetTypedValue()
+	case "sms_ip_daily_limit":
+		if strings.TrimSpace(setting.Value) == "" {
+			return config.SMSIPDailyLimit.Get()
+		}
+		return setting.GetTypedValue()
 	case "email_verify_enabled":
 		return services.GetGlobalVerifyConfig().EmailEnabled
 	case "sms_verify_enabled":
@@ -655,7 +647,7 @@ func (ctrl *SettingsController) resolveSettingValueForAdmin(setting models.Syste
 	case "sms_provider":
 		val := strings.TrimSpace(setting.Value)
 		if val == "" {
-			val = config.GlobalConfig.SMSProvider
+			val = config.Get().SMSProvider
 		}
 		if val == "" {
 			val = "console"
@@ -668,19 +660,31 @@ func (ctrl *SettingsController) resolveSettingValueForAdmin(setting models.Syste
 	case "sms_sign_name":
 		val := strings.TrimSpace(setting.Value)
 		if val == "" {
-			val = config.GlobalConfig.SMSSignName
+			val = config.Get().SMSSignName
 		}
 		return val
 	case "sms_template_code":
 		val := strings.TrimSpace(setting.Value)
 		if val == "" {
-			val = config.GlobalConfig.SMSTemplateCode
+			val = config.Get().SMSTemplateCode
 		}
 		return val
 	case "sms_region":
 		val := strings.TrimSpace(setting.Value)
 		if val == "" {
-			val = config.GlobalConfig.SMSRegion
+			val = config.Get().SMSRegion
+		}
+		return val
+	case "sms_templates":
+		val := strings.TrimSpace(setting.Value)
+		if val == "" {
+			val = config.Get().SMSTemplates
+		}
+		return val
+	case "sms_app_id":
+		val := strings.TrimSpace(setting.Value)
+		if val == "" {
+			val = config.Get().SMSAppID
 		}
 		return val
 	default:
@@ -689,8 +693,7 @@ func (ctrl *SettingsController) resolveSettingValueForAdmin(setting models.Syste
 }
 
 func isSensitiveSettingKey(key string) bool {
-	_, ok := sensitiveSettingKeys[key]
-	return ok
+	return models.IsSecretSettingKey(key)
 }
 
 func (ctrl *SettingsController) maskSensitiveSettingValue(value string) string {
@@ -707,13 +710,19 @@ func (ctrl *SettingsController) resolveCurrentSensitiveSettingValue(setting mode
 
 	switch setting.Key {
 	case "geetest_captcha_key":
-		return strings.TrimSpace(config.GlobalConfig.GeetestKey)
+		return strings.TrimSpace(config.Get().GeetestKey)
 	case "smtp_password":
-		return config.GlobalConfig.SMTPPass
+		return config.Get().SMTPPass
+	case "dkim_private_key":
+		return config.Get().DKIMPrivateKey
+	case "mail_http_token":
+		return config.Get().MailHTTPToken
+	case "mail_webhook_token":
+		return config.Get().MailWebhookToken
 	case "sms_access_key":
-		return strings.TrimSpace(config.GlobalConfig.SMSAccessKey)
+		return strings.TrimSpace(config.Get().SMSAccessKey)
 	case "sms_secret_key":
-		return strings.TrimSpace(config.GlobalConfig.SMSSecretKey)
+		return strings.TrimSpace(config.Get().SMSSecretKey)
 	default:
 		return setting.Value
 	}
@@ -726,117 +735,29 @@ func (ctrl *SettingsController) normalizeSettingValueForWrite(setting models.Sys
 	return value
 }
 
-func parseBoolSettingValue(v string, fallback bool) bool {
-	v = strings.ToLower(strings.TrimSpace(v))
-	if v == "" {
-		return fallback
-	}
-	if v == "1" || v == "true" {
-		return true
-	}
-	if v == "0" || v == "false" {
-		return false
-	}
-	return fallback
-}
-
-func parsePositiveIntSetting(v string, fallback int) int {
-	v = strings.TrimSpace(v)
-	if v == "" {
-		return fallback
-	}
-	num, err := strconv.Atoi(v)
-	if err != nil || num <= 0 {
-		return fallback
+// refreshRuntimeConfig 重新加载 system_settings 并立即应用到运行时配置
+// SMTP、短信、JWT 有效期、限流与 CORS 等订阅者会收到变更通知，并发布 settings.updated 事件
+func (ctrl *SettingsController) refreshRuntimeConfig(keys ...string) {
+	if err := services.ReloadRuntimeConfig(); err != nil {
+		log.Printf("[Settings] Reload runtime config failed: %v", err)
 	}
-	return num
+	events.SettingsUpdated.Emit(context.Background(), events.SettingsUpdatedEvent{Keys: keys})
 }
 
-func (ctrl *SettingsController) refreshRuntimeConfig() {
-	if services.GlobalSettingsService != nil {
-		services.GlobalSettingsService.InvalidateCache()
-	}
-
-	geetest := services.GetGlobalGeetestRuntimeConfig()
-	config.GlobalConfig.GeetestEnabled = geetest.Enabled
-	config.GlobalConfig.GeetestID = geetest.CaptchaID
-	config.GlobalConfig.GeetestKey = geetest.CaptchaKey
-
-	keys := []string{
-		"smtp_host",
-		"smtp_port",
-		"smtp_username",
-		"smtp_password",
-		"smtp_ssl",
-		"system_email_name",
-		"jwt_access_expire",
-		"jwt_refresh_expire",
-		"login_max_failure",
-		"login_lock_duration",
-	}
-	settingMap, err := models.GetSettingsMap(keys)
-	if err != nil {
+// ReloadConfig 重新读取 .env 文件、环境变量与 system_settings 并应用
+func (ctrl *SettingsController) ReloadConfig(c *gin.Context) {
+	if err := config.Reload(); err != nil {
+		utils.Fail(c, 500, "Failed to reload config: "+err.Error())
 		return
 	}
-
-	if v, ok := settingMap["smtp_host"]; ok {
-		config.GlobalConfig.SMTPHost = strings.TrimSpace(v)
-	}
-	if v, ok := settingMap["smtp_port"]; ok {
-		config.GlobalConfig.SMTPPort = strings.TrimSpace(v)
-	}
-	if v, ok := settingMap["smtp_username"]; ok {
-		config.GlobalConfig.SMTPUser = strings.TrimSpace(v)
-	}
-	if v, ok := settingMap["smtp_password"]; ok {
-		config.GlobalConfig.SMTPPass = v
-	}
-	if v, ok := settingMap["smtp_ssl"]; ok {
-		config.GlobalConfig.SMTPSSL = parseBoolSettingValue(v, config.GlobalConfig.SMTPSSL)
-	}
-	if v, ok := settingMap["system_email_name"]; ok {
-		config.GlobalConfig.SystemEmailName = strings.TrimSpace(v)
-	}
-	if v, ok := settingMap["jwt_access_expire"]; ok {
-		config.GlobalConfig.JWTAccessExpire = parsePositiveIntSetting(v, config.GlobalConfig.JWTAccessExpire)
-	}
-	if v, ok := settingMap["jwt_refresh_expire"]; ok {
-		config.GlobalConfig.JWTRefreshExpire = parsePositiveIntSetting(v, config.GlobalConfig.JWTRefreshExpire)
-	}
-	if v, ok := settingMap["login_max_failure"]; ok {
-		config.GlobalConfig.LoginMaxFailureCount = parsePositiveIntSetting(v, config.GlobalConfig.LoginMaxFailureCount)
-	}
-	if v, ok := settingMap["login_lock_duration"]; ok {
-		config.GlobalConfig.LoginLockDurationMinutes = parsePositiveIntSetting(v, config.GlobalConfig.LoginLockDurationMinutes)
-	}
-
-	// 同步邮箱/短信验证开关
-	verifyConfig := services.GetGlobalVerifyConfig()
-	config.GlobalConfig.EmailVerifyEnabled = verifyConfig.EmailEnabled
-	config.GlobalConfig.SMSVerifyEnabled = verifyConfig.SMSEnabled
-
-	// 同步短信服务配置并更新 SMS Provider
-	smsConfig := services.GetGlobalSMSRuntimeConfig()
-	config.GlobalConfig.SMSProvider = smsConfig.Provider
-	config.GlobalConfig.SMSAccessKey = smsConfig.AccessKey
-	config.GlobalConfig.SMSSecretKey = smsConfig.SecretKey
-	config.GlobalConfig.SMSSignName = smsConfig.SignName
-	config.GlobalConfig.SMSTemplateCode = smsConfig.TemplateCode
-	config.GlobalConfig.SMSRegion = smsConfig.Region
-
-	if services.GlobalSMSService != nil {
-		services.GlobalSMSService.SetConfig(services.SMSConfig{
-			Provider:     smsConfig.Provider,
-			AccessKey:    smsConfig.AccessKey,
-			SecretKey:    smsConfig.SecretKey,
-			SignName:     smsConfig.SignName,
-			TemplateCode: smsConfig.TemplateCode,
-			Region:       smsConfig.Region,
-		})
+	if err := services.ReloadRuntimeConfig(); err != nil {
+		utils.Fail(c, 500, "Failed to reload settings: "+err.Error())
+		return
 	}
+	utils.Success(c, gin.H{"message": "Config reloaded successfully"})
 }
 
-// RestartBackend restarts backend process after response is flushed.
+// RestartBackend gracefully stops the backend process so the supervisor can restart it.
 func (ctrl *SettingsController) RestartBackend(c *gin.Context) {
 	if config.IsProductionMode() {
 		utils.Fail(c, 403, "生产环境已禁用该功能")
@@ -844,10 +765,8 @@ func (ctrl *SettingsController) RestartBackend(c *gin.Context) {
 	}
 
 	utils.Success(c, gin.H{"message": "Backend restart requested"})
-	go func() {
-		time.Sleep(500 * time.Millisecond)
-		os.Exit(0)
-	}()
+	// 响应写出后触发优雅关闭，由进程守护负责重新拉起
+	lifecycle.RequestShutdown()
 }
 
 // GetServerMonitoringStatus 返回当前项目服务端运行监控快照。
@@ -919,9 +838,9 @@ func (ctrl *SettingsController) GetServerMonitoringStatus(c *gin.Context) {
 		"generated_at":   now.Format(time.RFC3339),
 		"uptime_seconds": int64(now.Sub(serverMonitorStartedAt).Seconds()),
 		"app": gin.H{
-			"name":       config.GlobalConfig.AppName,
-			"mode":       config.GlobalConfig.AppMode,
-			"port":       config.GlobalConfig.Port,
+			"name":       config.Get().AppName,
+			"mode":       config.Get().AppMode,
+			"port":       config.Get().Port,
 			"go_version": runtime.Version(),
 		},
 		"metrics": gin.H{
@@ -1023,10 +942,10 @@ func firstNonEmpty(values ...string) string {
 func (ctrl *SettingsController) buildSMTPStatus() gin.H {
 	settingMap, _ := models.GetSettingsMap([]string{"smtp_host", "smtp_port", "smtp_username", "smtp_password"})
 
-	host := firstNonEmpty(settingMap["smtp_host"], config.GlobalConfig.SMTPHost)
-	port := firstNonEmpty(settingMap["smtp_port"], config.GlobalConfig.SMTPPort)
-	username := firstNonEmpty(settingMap["smtp_username"], config.GlobalConfig.SMTPUser)
-	password := firstNonEmpty(settingMap["smtp_password"], config.GlobalConfig.SMTPPass)
+	host := firstNonEmpty(settingMap["smtp_host"], config.Get().SMTPHost)
+	port := firstNonEmpty(settingMap["smtp_port"], config.Get().SMTPPort)
+	username := firstNonEmpty(settingMap["smtp_username"], config.Get().SMTPUser)
+	password := firstNonEmpty(settingMap["smtp_password"], config.Get().SMTPPass)
 
 	configured := host != "" && port != "" && username != "" && password != ""
 	if !configured {
@@ -1067,14 +986,33 @@ func (ctrl *SettingsController) RegisterRoutes(group *gin.RouterGroup) {
 	settings := group.Group("/settings")
 	{
 		settings.GET("", ctrl.List)
+		openapi.Route(settings, "GET", "", openapi.Op("获取所有系统配置", "Admin-系统配置").Notes("获取所有系统配置，按分类分组").Auth().
+			Returns(SettingsListResponse{}))
 		settings.GET("/category/:category", ctrl.GetByCategory)
+		openapi.Route(settings, "GET", "/category/:category", openapi.Op("获取指定分类的配置", "Admin-系统配置").
+			Notes("获取指定分类下的所有配置项").Auth().Path("category", "string", "分类名称").Returns(nil))
 		settings.GET("/server-monitoring", ctrl.GetServerMonitoringStatus)
 		settings.POST("", ctrl.Create)
+		openapi.Route(settings, "POST", "", openapi.Op("创建新配置", "Admin-系统配置").Notes("创建一个新的自定义配置项").Auth().
+			Body(CreateSettingRequest{}, "配置信息").Returns(nil))
 		settings.POST("/restart-backend", ctrl.RestartBackend)
+		settings.POST("/reload-config", ctrl.ReloadConfig)
+		openapi.Route(settings, "POST", "/reload-config", openapi.Op("重新加载运行时配置", "Admin-系统配置").
+			Notes("重新读取配置文件、环境变量与数据库配置，变更立即生效无需重启").Auth().Returns(nil))
 		settings.PUT("/batch", ctrl.BatchUpdate)
+		openapi.Route(settings, "PUT", "/batch", openapi.Op("批量更新配置", "Admin-系统配置").Notes("批量更新多个配置项的值").Auth().
+			Body(BatchUpdateSettingsRequest{}, "配置键值对").Returns(nil))
 		settings.GET("/:key", ctrl.Get)
+		openapi.Route(settings, "GET", "/:key", openapi.Op("获取单个配置", "Admin-系统配置").Notes("根据键名获取配置详情").Auth().
+			Path("key", "string", "配置键名").Returns(nil))
 		settings.PUT("/:key", ctrl.Update)
+		openapi.Route(settings, "PUT", "/:key", openapi.Op("更新单个配置值", "Admin-系统配置").Notes("更新指定配置项的值").Auth().
+			Path("key", "string", "配置键名").Body(UpdateSettingRequest{}, "配置值").Returns(nil))
 		settings.PUT("/:key/meta", ctrl.UpdateMeta)
+		openapi.Route(settings, "PUT", "/:key/meta", openapi.Op("更新配置元数据", "Admin-系统配置").Notes("更新配置项的完整信息（包括值和元数据）").
+			Auth().Path("key", "string", "配置键名").Body(UpdateSettingMetaRequest{}, "配置信息").Returns(nil))
 		settings.DELETE("/:key", ctrl.Delete)
+		openapi.Route(settings, "DELETE", "/:key", openapi.Op("删除配置", "Admin-系统配置").Notes("删除指定的自定义配置项").Auth().
+			Path("key", "string", "配置键名").Returns(nil))
 	}
 }
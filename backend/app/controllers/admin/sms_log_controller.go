@@ -0,0 +1,89 @@
+package admin
+
+import (
+	"fst/backend/app/models"
+	"fst/backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SMSLogController 短信发送记录管理控制器
+type SMSLogController struct{}
+
+func NewSMSLogController() *SMSLogController {
+	return &SMSLogController{}
+}
+
+// List 短信日志列表
+func (ctrl *SMSLogController) List(c *gin.Context) {
+	utils.SanitizeQueryParams(c)
+
+	var q models.SMSLogQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		utils.Fail(c, 400, "参数错误")
+		return
+	}
+
+	if q.Status == 0 && c.Query("status") == "" {
+		q.Status = -1
+	}
+
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = 20
+	}
+	if q.PageSize > 100 {
+		q.PageSize = 100
+	}
+
+	logs, total, err := models.GetSMSLogList(&q)
+	if err != nil {
+		utils.Fail(c, 500, "查询失败")
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"list":      logs,
+		"total":     total,
+		"page":      q.Page,
+		"page_size": q.PageSize,
+	})
+}
+
+// Clean 清理短信日志
+func (ctrl *SMSLogController) Clean(c *gin.Context) {
+	var req struct {
+		Before string `json:"before" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Fail(c, 400, "参数错误：请提供 before 日期")
+		return
+	}
+
+	affected, err := models.DeleteSMSLogsBefore(req.Before)
+	if err != nil {
+		utils.Fail(c, 500, "清理失败")
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"affected": affected,
+	})
+}
+
+// Stats 短信日志统计
+func (ctrl *SMSLogController) Stats(c *gin.Context) {
+	total, success, fail, err := models.GetSMSLogStats()
+	if err != nil {
+		utils.Fail(c, 500, "统计失败")
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"total":   total,
+		"success": success,
+		"fail":    fail,
+	})
+}
@@ -23,18 +23,6 @@ func NewUserController() *UserController {
 }
 
 // List 用户列表
-// @Summary 获取用户列表
-// @Description 获取所有用户列表（分页）
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(20)
-// @Param keyword query string false "搜索关键词"
-// @Param status query int false "状态"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users [get]
 func (c *UserController) List(ctx *gin.Context) {
 	utils.SanitizeQueryParams(ctx)
 	var query services.UserListQuery
@@ -53,15 +41,6 @@ func (c *UserController) List(ctx *gin.Context) {
 }
 
 // Detail 用户详情
-// @Summary 获取用户详情
-// @Description 根据ID获取用户详情
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "用户ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users/{id} [get]
 func (c *UserController) Detail(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -81,15 +60,6 @@ func (c *UserController) Detail(ctx *gin.Context) {
 }
 
 // Create 创建用户
-// @Summary 创建用户
-// @Description 管理员创建新用户
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param body body services.UserCreateRequest true "用户信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users [post]
 func (c *UserController) Create(ctx *gin.Context) {
 	var req services.UserCreateRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -115,16 +85,6 @@ func (c *UserController) Create(ctx *gin.Context) {
 }
 
 // Update 更新用户
-// @Summary 更新用户
-// @Description 更新用户信息
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "用户ID"
-// @Param body body services.UserUpdateRequest true "用户信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users/{id} [put]
 func (c *UserController) Update(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -148,15 +108,6 @@ func (c *UserController) Update(ctx *gin.Context) {
 }
 
 // Delete 删除用户
-// @Summary 删除用户
-// @Description 删除指定用户
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "用户ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users/{id} [delete]
 func (c *UserController) Delete(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -173,16 +124,6 @@ func (c *UserController) Delete(ctx *gin.Context) {
 }
 
 // UpdateStatus 更新用户状态
-// @Summary 更新用户状态
-// @Description 启用/禁用用户
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "用户ID"
-// @Param body body map[string]int true "状态 {status: 0|1}"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users/{id}/status [put]
 func (c *UserController) UpdateStatus(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -207,16 +148,6 @@ func (c *UserController) UpdateStatus(ctx *gin.Context) {
 }
 
 // ResetPassword 重置用户密码
-// @Summary 重置用户密码
-// @Description 管理员重置用户密码
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "用户ID"
-// @Param body body map[string]string true "新密码 {password: \"xxx\"}"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users/{id}/password [put]
 func (c *UserController) ResetPassword(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -247,15 +178,6 @@ func (c *UserController) ResetPassword(ctx *gin.Context) {
 }
 
 // BatchGetSimpleInfo 批量获取用户简要信息
-// @Summary 批量获取用户简要信息
-// @Description 根据用户ID列表批量获取用户简要信息（用户名、昵称等），用于日志等场景显示用户名
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param body body map[string][]uint64 true "用户ID列表 {ids: [1, 2, 3]}"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users/batch-simple [post]
 func (c *UserController) BatchGetSimpleInfo(ctx *gin.Context) {
 	var req struct {
 		IDs []uint64 `json:"ids"`
@@ -287,15 +209,6 @@ func (c *UserController) BatchGetSimpleInfo(ctx *gin.Context) {
 }
 
 // LoginToUser 管理员登录指定用户（生成该用户的 JWT token）
-// @Summary 管理员登录指定用户
-// @Description 管理员可以生成任意用户的 JWT token 进行调试
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "用户ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users/{id}/login-as [post]
 func (c *UserController) LoginToUser(ctx *gin.Context) {
 	if config.IsProductionMode() {
 		utils.Fail(ctx, 403, "生产环境已禁用该功能")
@@ -314,8 +227,8 @@ func (c *UserController) LoginToUser(ctx *gin.Context) {
 		return
 	}
 
-	accessTTL := time.Duration(config.GlobalConfig.JWTAccessExpire) * time.Second
-	refreshTTL := time.Duration(config.GlobalConfig.JWTRefreshExpire) * time.Second
+	accessTTL := time.Duration(config.Get().JWTAccessExpire) * time.Second
+	refreshTTL := time.Duration(config.Get().JWTRefreshExpire) * time.Second
 	token, err := utils.GenerateTokenForGuardWithTTL(user.ID, user.Role, utils.UserAuthGuard, accessTTL)
 	if err != nil {
 		utils.Fail(ctx, 500, "生成 token 失败")
@@ -338,6 +251,7 @@ func (c *UserController) LoginToUser(ctx *gin.Context) {
 		utils.Fail(ctx, 500, "创建登录会话失败")
 		return
 	}
+	services.PushSessionRevoked(user.ID, utils.UserAuthGuard)
 
 	utils.Success(ctx, gin.H{
 		"user":             user,
@@ -349,15 +263,6 @@ func (c *UserController) LoginToUser(ctx *gin.Context) {
 }
 
 // ResetApiKey 管理员重置指定用户的 API Key
-// @Summary 重置用户 API Key
-// @Description 管理员重置指定用户的 API 密钥
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "用户ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users/{id}/reset-apikey [post]
 func (c *UserController) ResetApiKey(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
@@ -377,15 +282,6 @@ func (c *UserController) ResetApiKey(ctx *gin.Context) {
 }
 
 // LookupUser 按标识查找用户（ID/用户名/邮箱）
-// @Summary 按标识查找用户
-// @Description 通过 ID、用户名或邮箱查找用户
-// @Tags Admin-用户管理
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param keyword query string true "用户标识（ID/用户名/邮箱）"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/admin/users/lookup [get]
 func (c *UserController) LookupUser(ctx *gin.Context) {
 	keyword := utils.Clean_XSS(ctx.DefaultQuery("keyword", ""))
 	if keyword == "" {
@@ -52,6 +52,7 @@ func (ctrl *UserMoneyScoreController) ChangeMoney(c *gin.Context) {
 		utils.Fail(c, 400, err.Error())
 		return
 	}
+	services.NotifyAdminBalanceChange(userID, logEntry.Money, logEntry.After, logEntry.Memo)
 
 	utils.Success(c, gin.H{"message": "余额变更成功", "log": logEntry})
 }
@@ -79,6 +80,9 @@ func (ctrl *UserMoneyScoreController) SetMoney(c *gin.Context) {
 		utils.Fail(c, 400, err.Error())
 		return
 	}
+	if logEntry != nil && logEntry.Money != 0 {
+		services.NotifyAdminBalanceChange(userID, logEntry.Money, logEntry.After, logEntry.Memo)
+	}
 
 	utils.Success(c, gin.H{"message": "余额设置成功", "log": logEntry})
 }
@@ -163,6 +167,9 @@ func (ctrl *UserMoneyScoreController) OperateMoney(c *gin.Context) {
 		utils.Fail(c, 400, err.Error())
 		return
 	}
+	if result.AfterMoney != result.BeforeMoney {
+		services.NotifyAdminBalanceChange(userID, result.AfterMoney-result.BeforeMoney, result.AfterMoney, utils.Clean_XSS(req.Memo))
+	}
 
 	utils.Success(c, gin.H{"message": "余额组合操作成功", "result": result})
 }
@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
 	"fst/backend/app/models"
 	"fst/backend/app/services"
 	"fst/backend/internal/config"
 	crypto_rand "crypto/rand"
 	"fst/backend/internal/db"
+	"fst/backend/internal/lifecycle"
 	"fst/backend/utils"
 	"math/big"
 	"math/rand"
@@ -95,7 +97,7 @@ type ResetPasswordConfirmRequest struct {
 func (ctrl *AuthController) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -146,7 +148,7 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 		}
 	}
 
-	consumed, err := models.ConsumeVerificationCode(req.Email, req.Code, "register")
+	consumed, err := models.ConsumeVerificationCode(models.CodeChannelEmail, req.Email, req.Code, "register")
 	if err != nil || !consumed {
 		utils.Fail(c, 400, "Invalid or expired verification code")
 		return
@@ -175,7 +177,7 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 		return
 	}
 
-	if err := models.DeleteVerificationCodesByEmail(req.Email, "register"); err != nil && isNonProductionMode() {
+	if err := models.DiscardVerificationCodes(models.CodeChannelEmail, req.Email, "register"); err != nil && isNonProductionMode() {
 		fmt.Printf("[REGISTER-DEBUG] cleanup verification codes failed: %v\n", err)
 	}
 
@@ -186,7 +188,7 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 	var req SendCodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -202,8 +204,8 @@ func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 	code := generateSecureCodeLegacy()
 
 	// 存储验证码到数据库，有效期可配置（分钟）
-	expiresAt := time.Now().Add(time.Duration(config.GlobalConfig.RegisterCodeExpireMinutes) * time.Minute)
-	err := models.CreateVerificationCode(req.Email, code, "register", expiresAt)
+	expiresAt := time.Now().Add(time.Duration(config.Get().RegisterCodeExpireMinutes) * time.Minute)
+	err := models.CreateVerificationCode(models.CodeChannelEmail, req.Email, code, "register", c.ClientIP(), expiresAt)
 	if err != nil {
 		fmt.Printf("[ERROR] Failed to save verification code: %v\n", err)
 		utils.Fail(c, 500, "Failed to generate verification code")
@@ -215,26 +217,26 @@ func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 
 	tpl, err := models.GetEmailTemplate("register_code", lang)
 	var subject, body string
-	expireMinStr := fmt.Sprintf("%d", config.GlobalConfig.RegisterCodeExpireMinutes)
+	expireMinStr := fmt.Sprintf("%d", config.Get().RegisterCodeExpireMinutes)
 
 	if err == nil && tpl != nil {
-		subject = strings.ReplaceAll(tpl.Subject, "{app_name}", config.GlobalConfig.AppName)
+		subject = strings.ReplaceAll(tpl.Subject, "{app_name}", config.Get().AppName)
 		body = strings.ReplaceAll(tpl.Content, "{code}", code)
-		body = strings.ReplaceAll(body, "{app_name}", config.GlobalConfig.AppName)
+		body = strings.ReplaceAll(body, "{app_name}", config.Get().AppName)
 		body = strings.ReplaceAll(body, "{expire_minutes}", expireMinStr)
 	} else {
 		// 降级使用默认硬编码内容
 		if lang == "zh-CN" {
-			subject = fmt.Sprintf("【%s】注册验证码", config.GlobalConfig.AppName)
+			subject = fmt.Sprintf("【%s】注册验证码", config.Get().AppName)
 			body = fmt.Sprintf("您的验证码是：%s，有效期%s分钟。", code, expireMinStr)
 		} else {
-			subject = fmt.Sprintf("[%s] Registration Code", config.GlobalConfig.AppName)
+			subject = fmt.Sprintf("[%s] Registration Code", config.Get().AppName)
 			body = fmt.Sprintf("Your code is: %s, valid for %s minutes.", code, expireMinStr)
 		}
 	}
 
 	// 如果配置了SMTP，发送真实邮件
-	if config.GlobalConfig.SMTPHost != "" {
+	if config.Get().SMTPHost != "" {
 		err := utils.SendEmail(utils.EmailMessage{
 			To:      req.Email,
 			Subject: subject,
@@ -249,9 +251,10 @@ func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 			errMsg = err.Error()
 		}
 		// 异步记录日志
-		go func(email, subj, content string, st int, em string) {
-			_ = models.CreateEmailLog(email, subj, content, "register_code", st, em)
-		}(req.Email, subject, body, status, errMsg)
+		email := req.Email
+		lifecycle.Go("email-log", func(context.Context) {
+			_ = models.CreateEmailLog(email, subject, body, "register_code", status, errMsg)
+		})
 
 		if err != nil {
 			// 如果发送失败，但在开发环境，我们可以返回验证码方便调试
@@ -285,7 +288,7 @@ func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 func (ctrl *AuthController) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -342,7 +345,7 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 		// 锁定已过期，清除锁定状态
 		_, _ = db.DB.Exec("UPDATE users SET lock_until = NULL WHERE id = ?", user.ID)
 	}
-	if int(user.LoginFailure) >= config.GlobalConfig.LoginMaxFailureCount {
+	if int(user.LoginFailure) >= config.Get().LoginMaxFailureCount {
 		// 失败次数达到阈值，但锁定时间已过期，允许尝试（如果密码错误会重新锁定）
 		// 这里不阻止，让密码验证来决定
 	}
@@ -355,7 +358,7 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 	// 验证密码
 	if !utils.CheckPasswordHash(req.Password, user.Password) {
 		// 密码错误，增加失败计数（如果达到阈值会自动锁定）
-		_ = models.IncrementLoginFailure(user.ID, config.GlobalConfig.LoginMaxFailureCount, config.GlobalConfig.LoginLockDurationMinutes)
+		_ = models.IncrementLoginFailure(user.ID, config.Get().LoginMaxFailureCount, config.Get().LoginLockDurationMinutes)
 		if isNonProductionMode() {
 			pwdPrefix := ""
 			pwdLen := len(user.Password)
@@ -390,8 +393,8 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 		// 不阻止登录，只记录错误
 	}
 
-	accessTTL := time.Duration(config.GlobalConfig.JWTAccessExpire) * time.Second
-	refreshTTL := time.Duration(config.GlobalConfig.JWTRefreshExpire) * time.Second
+	accessTTL := time.Duration(config.Get().JWTAccessExpire) * time.Second
+	refreshTTL := time.Duration(config.Get().JWTRefreshExpire) * time.Second
 	nowUnix := time.Now().Unix()
 
 	accessToken, err := utils.GenerateTokenWithTTL(user.ID, user.Role, accessTTL)
@@ -423,7 +426,7 @@ type RefreshTokenRequest struct {
 func (ctrl *AuthController) UpdateToken(c *gin.Context) {
 	var req RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -433,8 +436,8 @@ func (ctrl *AuthController) UpdateToken(c *gin.Context) {
 		return
 	}
 
-	accessTTL := time.Duration(config.GlobalConfig.JWTAccessExpire) * time.Second
-	refreshTTL := time.Duration(config.GlobalConfig.JWTRefreshExpire) * time.Second
+	accessTTL := time.Duration(config.Get().JWTAccessExpire) * time.Second
+	refreshTTL := time.Duration(config.Get().JWTRefreshExpire) * time.Second
 	nowUnix := time.Now().Unix()
 
 	user, err := models.GetUserByID(claims.UserID)
@@ -467,7 +470,7 @@ func (ctrl *AuthController) GetUserRoutes(c *gin.Context) {
 func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 	var req ResetEmailRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -488,7 +491,7 @@ func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 
 	// 存储验证码到数据库，15分钟有效期（重置密码链接需要更长时间）
 	expiresAt := time.Now().Add(15 * time.Minute)
-	err = models.CreateVerificationCode(user.Email, code, "reset_password", expiresAt)
+	err = models.CreateVerificationCode(models.CodeChannelEmail, user.Email, code, "reset_password", c.ClientIP(), expiresAt)
 	if err != nil {
 		fmt.Printf("[ERROR] Failed to save reset code: %v\n", err)
 		// 即使失败也返回成功，避免邮箱枚举攻击
@@ -518,16 +521,16 @@ func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 	tpl, err := models.GetEmailTemplate("reset_password", lang)
 	var subject, body string
 	if err == nil && tpl != nil {
-		subject = strings.ReplaceAll(tpl.Subject, "{app_name}", config.GlobalConfig.AppName)
+		subject = strings.ReplaceAll(tpl.Subject, "{app_name}", config.Get().AppName)
 		body = strings.ReplaceAll(tpl.Content, "{code}", code)
 		body = strings.ReplaceAll(body, "{link}", resetLink)
-		body = strings.ReplaceAll(body, "{app_name}", config.GlobalConfig.AppName)
+		body = strings.ReplaceAll(body, "{app_name}", config.Get().AppName)
 	} else {
-		subject = fmt.Sprintf("【%s】密码重置请求", config.GlobalConfig.AppName)
+		subject = fmt.Sprintf("【%s】密码重置请求", config.Get().AppName)
 		body = fmt.Sprintf("请点击以下链接重置密码：<br><a href=\"%s\">%s</a><br>或者使用验证码：%s<br>有效期15分钟。", resetLink, resetLink, code)
 	}
 
-	if config.GlobalConfig.SMTPHost != "" {
+	if config.Get().SMTPHost != "" {
 		err := utils.SendEmail(utils.EmailMessage{
 			To:      user.Email,
 			Subject: subject,
@@ -542,9 +545,10 @@ func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 			errMsg = err.Error()
 		}
 		// 异步记录日志
-		go func(email, subj, content string, st int, em string) {
-			_ = models.CreateEmailLog(email, subj, content, "reset_password", st, em)
-		}(user.Email, subject, body, status, errMsg)
+		email := user.Email
+		lifecycle.Go("email-log", func(context.Context) {
+			_ = models.CreateEmailLog(email, subject, body, "reset_password", status, errMsg)
+		})
 
 		if err != nil {
 			fmt.Printf("[ERROR] Failed to send email: %v\n", err)
@@ -570,7 +574,7 @@ func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 func (ctrl *AuthController) ResetPasswordConfirm(c *gin.Context) {
 	var req ResetPasswordConfirmRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -579,14 +583,14 @@ func (ctrl *AuthController) ResetPasswordConfirm(c *gin.Context) {
 	req.Code = utils.Clean_XSS(req.Code)
 	// 密码不需要过滤（会被哈希处理）
 
-	consumed, err := models.ConsumeVerificationCode(req.Email, req.Code, "reset_password")
+	consumed, err := models.ConsumeVerificationCode(models.CodeChannelEmail, req.Email, req.Code, "reset_password")
 	if err != nil || !consumed {
 		utils.Fail(c, 400, "Invalid or expired reset token")
 		return
 	}
 
 	// 重置成功后：清理该邮箱所有重置密码验证码
-	_ = models.DeleteVerificationCodesByEmail(req.Email, "reset_password")
+	_ = models.DiscardVerificationCodes(models.CodeChannelEmail, req.Email, "reset_password")
 
 	user, err := models.GetUserByEmail(req.Email)
 	if err != nil {
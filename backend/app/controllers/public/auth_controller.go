@@ -8,7 +8,9 @@ import (
 	"fst/backend/app/models"
 	"fst/backend/app/services"
 	"fst/backend/internal/config"
+	"fst/backend/internal/events"
 	"fst/backend/internal/middleware"
+	"fst/backend/internal/openapi"
 	"fst/backend/utils"
 	"math/big"
 	"math/rand"
@@ -93,6 +95,9 @@ func getLangFromRequest(c *gin.Context, reqLang string) string {
 	return lang
 }
 
+// usernamePattern 用户名格式：3-50 位字母、数字或下划线
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]{3,50}$`)
+
 func isNonProductionMode() bool {
 	return !config.IsProductionMode()
 }
@@ -120,21 +125,10 @@ func registrationAllowed() bool {
 // ========================================
 
 // Login 用户登录
-// @Summary 用户登录
-// @Description 用户登录并获取 Token
-// @Tags Public-认证
-// @Accept json
-// @Produce json
-// @Param request body LoginRequest true "登录信息"
-// @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.Response
-// @Failure 401 {object} utils.Response
-// @Failure 403 {object} utils.Response
-// @Router /api/v1/public/login [post]
 func (ctrl *AuthController) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -171,16 +165,7 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 	}
 
 	// 获取客户端IP
-	clientIP := c.ClientIP()
-	if clientIP == "" {
-		clientIP = c.GetHeader("X-Forwarded-For")
-		if clientIP == "" {
-			clientIP = c.GetHeader("X-Real-IP")
-		}
-	}
-	if clientIP == "" {
-		clientIP = "unknown"
-	}
+	clientIP := resolveClientIP(c)
 
 	// 调用服务层登录
 	authGuard := req.AuthGuard
@@ -196,7 +181,14 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	// 记录登录会话
+	if !startLoginSession(c, result, authGuard, clientIP) {
+		return
+	}
+	utils.Success(c, result)
+}
+
+// startLoginSession 记录登录会话与登录设备，失败时已写入错误响应
+func startLoginSession(c *gin.Context, result *services.LoginResult, authGuard, clientIP string) bool {
 	userAgent := c.GetHeader("User-Agent")
 	device := parseDevice(userAgent)
 	accessTokenHash := hashToken(result.AccessToken)
@@ -206,10 +198,27 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 			fmt.Printf("[LOGIN-DEBUG] create session failed: %v\n", err)
 		}
 		utils.Fail(c, 500, "Failed to create login session")
-		return
+		return false
 	}
+	// 新会话替换了该 guard 下的旧会话，旧会话的实时推送连接随之断开
+	services.PushSessionRevoked(result.ID, authGuard)
+	services.RecordLoginDevice(result.ID, device, clientIP)
+	return true
+}
 
-	utils.Success(c, result)
+// resolveClientIP 获取客户端IP
+func resolveClientIP(c *gin.Context) string {
+	clientIP := c.ClientIP()
+	if clientIP == "" {
+		clientIP = c.GetHeader("X-Forwarded-For")
+		if clientIP == "" {
+			clientIP = c.GetHeader("X-Real-IP")
+		}
+	}
+	if clientIP == "" {
+		clientIP = "unknown"
+	}
+	return clientIP
 }
 
 // hashToken 对 token 进行 SHA256 哈希
@@ -242,20 +251,10 @@ func parseDevice(ua string) string {
 }
 
 // Register 注册新用户
-// @Summary 用户注册
-// @Description 注册一个新用户
-// @Tags Public-认证
-// @Accept json
-// @Produce json
-// @Param request body RegisterRequest true "注册信息"
-// @Success 200 {object} utils.Response
-// @Failure 400 {object} utils.Response
-// @Failure 500 {object} utils.Response
-// @Router /api/v1/public/register [post]
 func (ctrl *AuthController) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -270,8 +269,7 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 	}
 
 	// 验证用户名格式
-	usernameRegex := regexp.MustCompile(`^[a-zA-Z0-9_]{3,50}$`)
-	if !usernameRegex.MatchString(req.Username) {
+	if !usernamePattern.MatchString(req.Username) {
 		utils.Fail(c, 400, "Username must be 3-50 characters long and contain only letters, numbers, and underscores")
 		return
 	}
@@ -306,7 +304,17 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 		}
 	}
 
-	consumed, err := models.ConsumeVerificationCode(req.Email, req.Code, "register")
+	// 注册前钩子：插件可拒绝注册（在消耗验证码之前执行）
+	if err := events.UserBeforeRegister.Publish(c.Request.Context(), events.UserRegistration{
+		Username: req.Username,
+		Email:    req.Email,
+		IP:       c.ClientIP(),
+	}); err != nil {
+		utils.Fail(c, 403, events.Message(err))
+		return
+	}
+
+	consumed, err := models.ConsumeVerificationCode(models.CodeChannelEmail, req.Email, req.Code, "register")
 	if err != nil || !consumed {
 		utils.Fail(c, 400, "Invalid or expired verification code")
 		return
@@ -330,7 +338,7 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 		return
 	}
 
-	if err := models.DeleteVerificationCodesByEmail(req.Email, "register"); err != nil && isNonProductionMode() {
+	if err := models.DiscardVerificationCodes(models.CodeChannelEmail, req.Email, "register"); err != nil && isNonProductionMode() {
 		fmt.Printf("[REGISTER-DEBUG] cleanup verification codes failed: %v\n", err)
 	}
 
@@ -338,18 +346,10 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 }
 
 // SendRegisterCode 发送注册验证码
-// @Summary 发送注册验证码
-// @Description 发送注册验证码到邮箱
-// @Tags Public-认证
-// @Accept json
-// @Produce json
-// @Param request body SendCodeRequest true "邮箱信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/public/send-register-code [post]
 func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 	var req SendCodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -360,7 +360,7 @@ func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 		utils.Fail(c, 403, "Registration is disabled")
 		return
 	}
-	hasRecentCode, err := models.HasRecentVerificationCode(req.Email, "register", time.Now().Add(-time.Minute))
+	hasRecentCode, err := models.HasRecentVerificationCode(models.CodeChannelEmail, req.Email, "register", time.Now().Add(-time.Minute))
 	if err != nil {
 		utils.Fail(c, 500, "Failed to check verification cooldown")
 		return
@@ -374,9 +374,9 @@ func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 	code := generateSecureCode()
 
 	// 存储验证码
-	expireMinutes := config.GlobalConfig.RegisterCodeExpireMinutes
+	expireMinutes := config.Get().RegisterCodeExpireMinutes
 	expiresAt := time.Now().Add(time.Duration(expireMinutes) * time.Minute)
-	if err := models.CreateVerificationCode(req.Email, code, "register", expiresAt); err != nil {
+	if err := models.CreateVerificationCode(models.CodeChannelEmail, req.Email, code, "register", c.ClientIP(), expiresAt); err != nil {
 		utils.Fail(c, 500, "Failed to generate verification code")
 		return
 	}
@@ -396,7 +396,7 @@ func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 	}
 
 	// 发送验证码邮件
-	vars := map[string]string{
+	vars := map[string]any{
 		"code":           code,
 		"expire_minutes": fmt.Sprintf("%d", expireMinutes),
 	}
@@ -413,18 +413,10 @@ func (ctrl *AuthController) SendRegisterCode(c *gin.Context) {
 }
 
 // SendResetEmail 发送重置密码邮件
-// @Summary 发送重置密码邮件
-// @Description 发送重置密码验证码到邮箱
-// @Tags Public-认证
-// @Accept json
-// @Produce json
-// @Param request body ResetEmailRequest true "邮箱信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/public/forgot-password [post]
 func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 	var req ResetEmailRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -439,7 +431,7 @@ func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 		utils.Success(c, gin.H{"message": "If the email exists, a reset code has been sent"})
 		return
 	}
-	hasRecentCode, err := models.HasRecentVerificationCode(user.Email, "reset_password", time.Now().Add(-time.Minute))
+	hasRecentCode, err := models.HasRecentVerificationCode(models.CodeChannelEmail, user.Email, "reset_password", time.Now().Add(-time.Minute))
 	if err != nil || hasRecentCode {
 		utils.Success(c, gin.H{"message": "If the email exists, a reset code has been sent"})
 		return
@@ -450,7 +442,7 @@ func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 
 	// 存储验证码
 	expiresAt := time.Now().Add(15 * time.Minute)
-	if err := models.CreateVerificationCode(user.Email, code, "reset_password", expiresAt); err != nil {
+	if err := models.CreateVerificationCode(models.CodeChannelEmail, user.Email, code, "reset_password", c.ClientIP(), expiresAt); err != nil {
 		utils.Success(c, gin.H{"message": "If the email exists, a reset code has been sent"})
 		return
 	}
@@ -486,7 +478,7 @@ func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 	}
 
 	// 发送邮件
-	vars := map[string]string{
+	vars := map[string]any{
 		"code": code,
 		"link": resetLink,
 	}
@@ -500,18 +492,10 @@ func (ctrl *AuthController) SendResetEmail(c *gin.Context) {
 }
 
 // ResetPasswordConfirm 确认重置密码
-// @Summary 确认重置密码
-// @Description 使用验证码重置密码
-// @Tags Public-认证
-// @Accept json
-// @Produce json
-// @Param request body ResetPasswordConfirmRequest true "重置信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/public/reset-password [post]
 func (ctrl *AuthController) ResetPasswordConfirm(c *gin.Context) {
 	var req ResetPasswordConfirmRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -519,12 +503,12 @@ func (ctrl *AuthController) ResetPasswordConfirm(c *gin.Context) {
 	req.Email = utils.Clean_XSS(req.Email)
 	req.Code = utils.Clean_XSS(req.Code)
 
-	consumed, err := models.ConsumeVerificationCode(req.Email, req.Code, "reset_password")
+	consumed, err := models.ConsumeVerificationCode(models.CodeChannelEmail, req.Email, req.Code, "reset_password")
 	if err != nil || !consumed {
 		utils.Fail(c, 400, "Invalid or expired reset token")
 		return
 	}
-	_ = models.DeleteVerificationCodesByEmail(req.Email, "reset_password")
+	_ = models.DiscardVerificationCodes(models.CodeChannelEmail, req.Email, "reset_password")
 
 	// 获取用户
 	user, err := models.GetUserByEmail(req.Email)
@@ -543,32 +527,15 @@ func (ctrl *AuthController) ResetPasswordConfirm(c *gin.Context) {
 }
 
 // UpdateToken 刷新Token
-// @Summary 刷新Token
-// @Description 使用refresh token获取新的access token
-// @Tags Public-认证
-// @Accept json
-// @Produce json
-// @Param request body RefreshTokenRequest true "刷新令牌"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/public/refresh-token [post]
 func (ctrl *AuthController) UpdateToken(c *gin.Context) {
 	var req RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 	req.AuthGuard = utils.Clean_XSS(req.AuthGuard)
 
-	clientIP := c.ClientIP()
-	if clientIP == "" {
-		clientIP = c.GetHeader("X-Forwarded-For")
-		if clientIP == "" {
-			clientIP = c.GetHeader("X-Real-IP")
-		}
-	}
-	if clientIP == "" {
-		clientIP = "unknown"
-	}
+	clientIP := resolveClientIP(c)
 
 	userAgent := c.GetHeader("User-Agent")
 	device := parseDevice(userAgent)
@@ -593,11 +560,38 @@ func (ctrl *AuthController) RegisterRoutes(group *gin.RouterGroup) {
 	authGroup.Use(middleware.StrictRateLimitMiddleware())
 	{
 		authGroup.POST("/login", ctrl.Login)
+		openapi.Route(authGroup, "POST", "/login", openapi.Op("用户登录", "Public-认证").Notes("用户登录并获取 Token").
+			Body(LoginRequest{}, "登录信息").Returns(nil).Fails(400, 401, 403))
 		authGroup.POST("/register", ctrl.Register)
+		openapi.Route(authGroup, "POST", "/register", openapi.Op("用户注册", "Public-认证").Notes("注册一个新用户").
+			Body(RegisterRequest{}, "注册信息").Returns(nil).Fails(400, 500))
 		authGroup.POST("/send-register-code", ctrl.SendRegisterCode)
+		openapi.Route(authGroup, "POST", "/send-register-code", openapi.Op("发送注册验证码", "Public-认证").Notes("发送注册验证码到邮箱").
+			Body(SendCodeRequest{}, "邮箱信息").Returns(nil))
 		authGroup.POST("/forgot-password", ctrl.SendResetEmail)
+		openapi.Route(authGroup, "POST", "/forgot-password", openapi.Op("发送重置密码邮件", "Public-认证").Notes("发送重置密码验证码到邮箱").
+			Body(ResetEmailRequest{}, "邮箱信息").Returns(nil))
 		authGroup.POST("/reset-password", ctrl.ResetPasswordConfirm)
+		openapi.Route(authGroup, "POST", "/reset-password", openapi.Op("确认重置密码", "Public-认证").Notes("使用验证码重置密码").
+			Body(ResetPasswordConfirmRequest{}, "重置信息").Returns(nil))
 		authGroup.POST("/refresh-token", ctrl.UpdateToken)
+		openapi.Route(authGroup, "POST", "/refresh-token", openapi.Op("刷新Token", "Public-认证").
+			Notes("使用refresh token获取新的access token").Body(RefreshTokenRequest{}, "刷新令牌").Returns(nil))
+		authGroup.POST("/sms/send-code", ctrl.SendSMSCode)
+		openapi.Route(authGroup, "POST", "/sms/send-code", openapi.Op("发送短信验证码", "Public-认证").
+			Notes("发送注册、登录或找回密码的短信验证码。同一手机号 60 秒内只能发送一次，并受手机号每小时/每天、IP 每天的发送配额限制；登录与找回密码对未注册的手机号同样返回成功").
+			Body(SendSMSCodeRequest{}, "手机号与用途").Returns(nil).Fails(400, 403, 429))
+		authGroup.POST("/register/phone", ctrl.RegisterByPhone)
+		openapi.Route(authGroup, "POST", "/register/phone", openapi.Op("手机号注册", "Public-认证").
+			Notes("使用手机号和短信验证码注册，注册后邮箱为空，可在个人中心绑定").Body(PhoneRegisterRequest{}, "注册信息").Returns(nil).Fails(400, 403))
+		authGroup.POST("/login/sms", ctrl.LoginBySMS)
+		openapi.Route(authGroup, "POST", "/login/sms", openapi.Op("短信验证码登录", "Public-认证").
+			Notes("使用手机号和短信验证码登录并获取 Token，验证码错误计入登录失败次数").Body(SMSLoginRequest{}, "登录信息").Returns(nil).
+			Fails(400, 401, 403))
+		authGroup.POST("/reset-password/sms", ctrl.ResetPasswordBySMS)
+		openapi.Route(authGroup, "POST", "/reset-password/sms", openapi.Op("短信验证码重置密码", "Public-认证").
+			Notes("使用手机号和短信验证码重置密码，验证码错误计入登录失败次数，账户锁定期间不能重置").Body(SMSResetPasswordRequest{}, "重置信息").Returns(nil).
+			Fails(400, 403))
 	}
 }
 
@@ -0,0 +1,83 @@
+package public
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fst/backend/app/services"
+	"fst/backend/internal/config"
+	"fst/backend/internal/mailer"
+	"fst/backend/internal/openapi"
+	"fst/backend/utils"
+	"io"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// emailWebhookMaxBody 回调请求体上限
+const emailWebhookMaxBody = 1 << 20
+
+// EmailWebhookController 邮件服务商退信 / 投诉回调控制器（公共接口，以令牌校验）
+type EmailWebhookController struct{}
+
+// NewEmailWebhookController 创建退信回调控制器
+func NewEmailWebhookController() *EmailWebhookController {
+	return &EmailWebhookController{}
+}
+
+// EmailWebhookResult 回调处理结果
+type EmailWebhookResult struct {
+	Processed int `json:"processed"` // 处理的退信 / 投诉事件数
+}
+
+// Receive 接收退信 / 投诉回调
+func (ctrl *EmailWebhookController) Receive(c *gin.Context) {
+	// 服务商按 HTTP 状态码判断是否重试，不带 X-API-Version 时也要返回真实状态码
+	c.Set(utils.APIVersionKey, 2)
+
+	expected := config.Get().MailWebhookToken
+	if expected == "" {
+		utils.Fail(c, 403, "退信回调未启用")
+		return
+	}
+	token := c.Query("token")
+	if token == "" {
+		token = c.GetHeader("X-Webhook-Token")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		utils.Fail(c, 401, "回调令牌无效")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, emailWebhookMaxBody))
+	if err != nil {
+		utils.Fail(c, 400, "读取请求失败")
+		return
+	}
+
+	provider := c.Param("provider")
+	processed, err := services.HandleEmailFeedback(provider, body)
+	if err != nil {
+		log.Printf("[Email Webhook] %s 回调处理失败: %v", provider, err)
+		switch {
+		case errors.Is(err, mailer.ErrUnknownFeedbackProvider):
+			utils.Fail(c, 404, "不支持的服务商")
+		case errors.Is(err, services.ErrEmailFeedbackInvalid):
+			utils.Fail(c, 400, "回调内容无效")
+		default:
+			utils.Fail(c, 500, "回调处理失败")
+		}
+		return
+	}
+
+	utils.Success(c, EmailWebhookResult{Processed: processed})
+}
+
+// RegisterRoutes 注册退信回调路由
+func (ctrl *EmailWebhookController) RegisterRoutes(group *gin.RouterGroup) {
+	group.POST("/email/webhook/:provider", ctrl.Receive)
+	openapi.Route(group, "POST", "/email/webhook/:provider", openapi.Op("邮件退信与投诉回调", "Public-邮件").
+		Notes("接收邮件服务商推送的退信、投诉事件并更新抑制列表。provider 为 generic、ses、sendgrid、mailgun、postmark；令牌（系统设置 mail_webhook_token）通过 token 查询参数或 X-Webhook-Token 请求头传递，未配置令牌时接口不可用").
+		Path("provider", "string", "服务商").Query("token", "string", "回调令牌").Returns(EmailWebhookResult{}).
+		Fails(400, 401, 403, 404, 500))
+}
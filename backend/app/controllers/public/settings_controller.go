@@ -4,6 +4,7 @@ import (
 	"fst/backend/app/models"
 	"fst/backend/app/services"
 	"fst/backend/internal/config"
+	"fst/backend/internal/openapi"
 	"fst/backend/utils"
 	"strings"
 
@@ -41,12 +42,6 @@ type AppConfigResponse struct {
 }
 
 // GetAppConfig 获取应用配置
-// @Summary 获取应用配置
-// @Description 获取前端应用需要的公开配置信息
-// @Tags Public-配置
-// @Produce json
-// @Success 200 {object} utils.Response{data=AppConfigResponse}
-// @Router /api/v1/public/app-config [get]
 func (ctrl *SettingsController) GetAppConfig(c *gin.Context) {
 	// 尝试从缓存服务获取
 	if services.GlobalSettingsService != nil {
@@ -115,13 +110,13 @@ func buildAppConfigResponse(settings []models.SystemSetting) *AppConfigResponse
 	if v, ok := configMap["default_lang"]; ok {
 		response.DefaultLang = v
 	}
-	enabled := config.GlobalConfig.GeetestEnabled
-	if v, ok := configMap["geetest_enabled"]; ok {
+	enabled := config.Get().GeetestEnabled
+	if v, ok := configMap["geetest_enabled"]; ok && strings.TrimSpace(v) != "" {
 		v = strings.TrimSpace(v)
 		enabled = v == "true" || v == "1" || strings.EqualFold(v, "true")
 	}
 
-	captchaID := strings.TrimSpace(config.GlobalConfig.GeetestID)
+	captchaID := strings.TrimSpace(config.Get().GeetestID)
 	if v, ok := configMap["geetest_captcha_id"]; ok {
 		v = strings.TrimSpace(v)
 		if v != "" {
@@ -129,7 +124,7 @@ func buildAppConfigResponse(settings []models.SystemSetting) *AppConfigResponse
 		}
 	}
 
-	captchaKey := strings.TrimSpace(config.GlobalConfig.GeetestKey)
+	captchaKey := strings.TrimSpace(config.Get().GeetestKey)
 	if v, ok := configMap["geetest_captcha_key"]; ok {
 		v = strings.TrimSpace(v)
 		if v != "" {
@@ -146,4 +141,6 @@ func buildAppConfigResponse(settings []models.SystemSetting) *AppConfigResponse
 // RegisterRoutes 注册公共配置路由
 func (ctrl *SettingsController) RegisterRoutes(group *gin.RouterGroup) {
 	group.GET("/app-config", ctrl.GetAppConfig)
+	openapi.Route(group, "GET", "/app-config", openapi.Op("获取应用配置", "Public-配置").Notes("获取前端应用需要的公开配置信息").
+		Returns(AppConfigResponse{}))
 }
@@ -0,0 +1,272 @@
+package public
+
+import (
+	"fmt"
+	"fst/backend/app/models"
+	"fst/backend/app/services"
+	"fst/backend/internal/events"
+	"fst/backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ========================================
+// 短信验证码：手机号注册 / 验证码登录 / 找回密码
+// ========================================
+
+type SendSMSCodeRequest struct {
+	Phone   string `json:"phone" binding:"required"`
+	Purpose string `json:"purpose" binding:"required,oneof=register login reset_password"`
+}
+
+type PhoneRegisterRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Phone    string `json:"phone" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type SMSLoginRequest struct {
+	Phone     string `json:"phone" binding:"required"`
+	Code      string `json:"code" binding:"required"`
+	AuthGuard string `json:"authGuard"`
+}
+
+type SMSResetPasswordRequest struct {
+	Phone       string `json:"phone" binding:"required"`
+	Code        string `json:"code" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// smsVerifyEnabled 短信验证码功能是否开启
+func smsVerifyEnabled() bool {
+	return services.GetGlobalVerifyConfig().SMSEnabled
+}
+
+// geetestPassed 极验验证（未启用时直接通过）
+func geetestPassed(c *gin.Context) bool {
+	geetestConfig := services.GetGlobalGeetestRuntimeConfig()
+	if !geetestConfig.Enabled {
+		return true
+	}
+	geetestReq := utils.GeetestValidateRequest{
+		LotNumber:     c.GetHeader("X-Geetest-Lot-Number"),
+		CaptchaOutput: c.GetHeader("X-Geetest-Captcha-Output"),
+		PassToken:     c.GetHeader("X-Geetest-Pass-Token"),
+		GenTime:       c.GetHeader("X-Geetest-Gen-Time"),
+		CaptchaID:     c.GetHeader("X-Geetest-Captcha-Id"),
+	}
+	valid, err := utils.ValidateGeetest(geetestConfig.CaptchaID, geetestConfig.CaptchaKey, geetestReq)
+	return err == nil && valid
+}
+
+// SendSMSCode 发送短信验证码
+func (ctrl *AuthController) SendSMSCode(c *gin.Context) {
+	var req SendSMSCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.FailBind(c, err, &req)
+		return
+	}
+
+	phone, ok := utils.NormalizeMobile(utils.Clean_XSS(req.Phone))
+	if !ok {
+		utils.Fail(c, 400, "Invalid phone number")
+		return
+	}
+	if !smsVerifyEnabled() {
+		utils.Fail(c, 403, "SMS verification is disabled")
+		return
+	}
+	if !geetestPassed(c) {
+		utils.Fail(c, 403, "Captcha validation failed")
+		return
+	}
+
+	switch req.Purpose {
+	case services.SMSPurposeRegister:
+		if !registrationAllowed() {
+			utils.Fail(c, 403, "Registration is disabled")
+			return
+		}
+		if _, err := models.GetUserByMobile(phone); err == nil {
+			utils.Fail(c, 400, "Phone number already in use")
+			return
+		}
+	default:
+		// 安全考虑：未注册的手机号也返回成功，且不实际发送
+		if _, err := models.GetUserByMobile(phone); err != nil {
+			utils.Success(c, gin.H{"message": "Verification code sent"})
+			return
+		}
+	}
+
+	if svc_err := services.GlobalSMSService.SendVerifyCode(phone, req.Purpose, c.ClientIP()); svc_err != nil {
+		utils.Fail(c, svc_err.Code, svc_err.Message)
+		return
+	}
+	utils.Success(c, gin.H{"message": "Verification code sent"})
+}
+
+// RegisterByPhone 手机号注册
+func (ctrl *AuthController) RegisterByPhone(c *gin.Context) {
+	var req PhoneRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.FailBind(c, err, &req)
+		return
+	}
+
+	// 过滤用户输入
+	req.Username = utils.Clean_XSS(req.Username)
+	req.Code = utils.Clean_XSS(req.Code)
+	phone, ok := utils.NormalizeMobile(utils.Clean_XSS(req.Phone))
+	if !ok {
+		utils.Fail(c, 400, "Invalid phone number")
+		return
+	}
+
+	if !registrationAllowed() {
+		utils.Fail(c, 403, "Registration is disabled")
+		return
+	}
+	if !smsVerifyEnabled() {
+		utils.Fail(c, 403, "SMS verification is disabled")
+		return
+	}
+
+	// 验证用户名格式
+	if !usernamePattern.MatchString(req.Username) {
+		utils.Fail(c, 400, "Username must be 3-50 characters long and contain only letters, numbers, and underscores")
+		return
+	}
+
+	// 检查用户名是否存在
+	if _, err := models.GetUserByUsername(req.Username); err == nil {
+		utils.Fail(c, 400, "Username already exists")
+		return
+	}
+
+	// 检查手机号是否存在
+	if _, err := models.GetUserByMobile(phone); err == nil {
+		utils.Fail(c, 400, "Phone number already in use")
+		return
+	}
+
+	if !geetestPassed(c) {
+		utils.Fail(c, 403, "Captcha validation failed")
+		return
+	}
+
+	// 注册前钩子：插件可拒绝注册（在消耗验证码之前执行）
+	if err := events.UserBeforeRegister.Publish(c.Request.Context(), events.UserRegistration{
+		Username: req.Username,
+		Mobile:   phone,
+		IP:       c.ClientIP(),
+	}); err != nil {
+		utils.Fail(c, 403, events.Message(err))
+		return
+	}
+
+	if !services.ConsumeSMSCode(phone, req.Code, services.SMSPurposeRegister) {
+		utils.Fail(c, 400, "Invalid or expired verification code")
+		return
+	}
+
+	// 创建用户
+	user := &models.User{
+		Username: req.Username,
+		Password: req.Password, // 服务层会进行哈希
+		Mobile:   phone,
+		Role:     "user",
+		Status:   1,
+	}
+
+	if err := ctrl.auth_svc.Register(user); err != nil {
+		if isNonProductionMode() {
+			utils.Fail(c, 500, fmt.Sprintf("Failed to create user: %v", err))
+			return
+		}
+		utils.Fail(c, 500, "Failed to create user")
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "User registered successfully"})
+}
+
+// LoginBySMS 短信验证码登录
+func (ctrl *AuthController) LoginBySMS(c *gin.Context) {
+	var req SMSLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.FailBind(c, err, &req)
+		return
+	}
+
+	// 过滤用户输入
+	req.Code = utils.Clean_XSS(req.Code)
+	req.AuthGuard = utils.Clean_XSS(req.AuthGuard)
+	phone, ok := utils.NormalizeMobile(utils.Clean_XSS(req.Phone))
+	if !ok {
+		utils.Fail(c, 400, "Invalid phone number")
+		return
+	}
+
+	if !smsVerifyEnabled() {
+		utils.Fail(c, 403, "SMS verification is disabled")
+		return
+	}
+	if !geetestPassed(c) {
+		utils.Fail(c, 403, "Captcha validation failed")
+		return
+	}
+
+	clientIP := resolveClientIP(c)
+	authGuard := req.AuthGuard
+	if authGuard == "" {
+		authGuard = utils.UserAuthGuard
+	}
+	result, err := ctrl.auth_svc.LoginBySMS(phone, req.Code, authGuard, clientIP)
+	if err != nil {
+		if isNonProductionMode() {
+			fmt.Printf("[LOGIN-DEBUG] %v\n", err)
+		}
+		utils.Fail(c, err.Code, err.Message)
+		return
+	}
+
+	if !startLoginSession(c, result, authGuard, clientIP) {
+		return
+	}
+	utils.Success(c, result)
+}
+
+// ResetPasswordBySMS 短信验证码重置密码
+func (ctrl *AuthController) ResetPasswordBySMS(c *gin.Context) {
+	var req SMSResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.FailBind(c, err, &req)
+		return
+	}
+
+	// 过滤用户输入
+	req.Code = utils.Clean_XSS(req.Code)
+	phone, ok := utils.NormalizeMobile(utils.Clean_XSS(req.Phone))
+	if !ok {
+		utils.Fail(c, 400, "Invalid phone number")
+		return
+	}
+
+	if !smsVerifyEnabled() {
+		utils.Fail(c, 403, "SMS verification is disabled")
+		return
+	}
+	if !geetestPassed(c) {
+		utils.Fail(c, 403, "Captcha validation failed")
+		return
+	}
+
+	if svc_err := ctrl.auth_svc.ResetPasswordBySMS(phone, req.Code, req.NewPassword); svc_err != nil {
+		utils.Fail(c, svc_err.Code, svc_err.Message)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "Password reset successfully"})
+}
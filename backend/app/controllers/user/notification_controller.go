@@ -0,0 +1,173 @@
+package user
+
+import (
+	"fst/backend/app/models"
+	"fst/backend/internal/openapi"
+	"fst/backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController 用户站内通知控制器（需要登录）
+type NotificationController struct{}
+
+// NewNotificationController 创建站内通知控制器
+func NewNotificationController() *NotificationController {
+	return &NotificationController{}
+}
+
+// List 获取当前用户的通知列表
+func (ctrl *NotificationController) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Fail(c, 401, "用户未登录")
+		return
+	}
+	uid := userID.(uint64)
+
+	q := models.NotificationQuery{
+		Type: c.Query("type"),
+		Lang: c.Query("lang"),
+	}
+	q.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	q.PageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	q.IsRead, _ = strconv.Atoi(c.DefaultQuery("is_read", "-1"))
+
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 || q.PageSize > 100 {
+		q.PageSize = 20
+	}
+
+	list, total, err := models.GetUserNotifications(uid, &q)
+	if err != nil {
+		utils.Fail(c, 500, "获取通知列表失败")
+		return
+	}
+	if q.Lang != "" {
+		for i := range list {
+			list[i].Title = utils.ParseMemo(list[i].Title, q.Lang)
+			list[i].Content = utils.ParseMemo(list[i].Content, q.Lang)
+		}
+	}
+
+	utils.Success(c, gin.H{
+		"list":      list,
+		"total":     total,
+		"page":      q.Page,
+		"page_size": q.PageSize,
+	})
+}
+
+// UnreadCount 获取未读通知数
+func (ctrl *NotificationController) UnreadCount(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Fail(c, 401, "用户未登录")
+		return
+	}
+
+	count, err := models.CountUnreadNotifications(userID.(uint64))
+	if err != nil {
+		utils.Fail(c, 500, "获取未读数失败")
+		return
+	}
+
+	utils.Success(c, gin.H{"count": count})
+}
+
+// MarkRead 标记一条通知为已读
+func (ctrl *NotificationController) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Fail(c, 401, "用户未登录")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "无效的通知ID")
+		return
+	}
+
+	found, err := models.MarkNotificationRead(userID.(uint64), id)
+	if err != nil {
+		utils.Fail(c, 500, "操作失败")
+		return
+	}
+	if !found {
+		utils.Fail(c, 404, "通知不存在")
+		return
+	}
+
+	utils.Success(c, nil)
+}
+
+// MarkAllRead 将全部通知标记为已读
+func (ctrl *NotificationController) MarkAllRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Fail(c, 401, "用户未登录")
+		return
+	}
+
+	affected, err := models.MarkAllNotificationsRead(userID.(uint64))
+	if err != nil {
+		utils.Fail(c, 500, "操作失败")
+		return
+	}
+
+	utils.Success(c, gin.H{"affected": affected})
+}
+
+// Delete 删除一条通知
+func (ctrl *NotificationController) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Fail(c, 401, "用户未登录")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.Fail(c, 400, "无效的通知ID")
+		return
+	}
+
+	found, err := models.DeleteNotification(userID.(uint64), id)
+	if err != nil {
+		utils.Fail(c, 500, "删除失败")
+		return
+	}
+	if !found {
+		utils.Fail(c, 404, "通知不存在")
+		return
+	}
+
+	utils.Success(c, nil)
+}
+
+// RegisterRoutes 注册用户站内通知路由
+func (ctrl *NotificationController) RegisterRoutes(group *gin.RouterGroup) {
+	notifications := group.Group("/notifications")
+	{
+		notifications.GET("", ctrl.List)
+		openapi.Route(notifications, "GET", "", openapi.Op("获取我的站内通知列表", "站内通知").
+			Notes("标题与内容为多语言 JSON（同余额日志备注），传 lang 时由服务端按语言取出纯文本").Auth().Paged().
+			Query("type", "string", "类型: system, recharge, balance, login, password").
+			Query("is_read", "int", "已读筛选（-1=全部, 0=未读, 1=已读）").Default(-1).Query("lang", "string", "语言，如 zhCN、enUS").
+			Returns(nil))
+		notifications.GET("/unread-count", ctrl.UnreadCount)
+		openapi.Route(notifications, "GET", "/unread-count", openapi.Op("获取未读通知数", "站内通知").Auth().Returns(nil))
+		notifications.POST("/read-all", ctrl.MarkAllRead)
+		openapi.Route(notifications, "POST", "/read-all", openapi.Op("全部标记已读", "站内通知").Auth().Returns(nil))
+		notifications.POST("/:id/read", ctrl.MarkRead)
+		openapi.Route(notifications, "POST", "/:id/read", openapi.Op("标记通知已读", "站内通知").Auth().
+			Path("id", "int", "通知ID").Returns(nil))
+		notifications.DELETE("/:id", ctrl.Delete)
+		openapi.Route(notifications, "DELETE", "/:id", openapi.Op("删除通知", "站内通知").Auth().Path("id", "int", "通知ID").
+			Returns(nil))
+	}
+}
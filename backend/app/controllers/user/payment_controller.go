@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"fst/backend/app/models"
 	"fst/backend/app/services"
+	"fst/backend/internal/openapi"
 	"fst/backend/utils"
 	"strconv"
 	"strings"
@@ -34,14 +35,6 @@ type CreateOrderRequest struct {
 // ========================================
 
 // CreateOrder 创建充值订单
-// @Summary 创建充值订单
-// @Tags 支付
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param body body CreateOrderRequest true "订单信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/payment/create [post]
 func (ctrl *PaymentController) CreateOrder(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -96,15 +89,6 @@ func (ctrl *PaymentController) CreateOrder(c *gin.Context) {
 }
 
 // GetOrders 获取当前用户的订单列表
-// @Summary 获取我的充值订单列表
-// @Tags 支付
-// @Produce json
-// @Security BearerAuth
-// @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(20)
-// @Param status query int false "状态筛选（-1=全部）" default(-1)
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/payment/orders [get]
 func (ctrl *PaymentController) GetOrders(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -134,13 +118,6 @@ func (ctrl *PaymentController) GetOrders(c *gin.Context) {
 }
 
 // GetOrderDetail 获取订单详情（仅限自己的订单）
-// @Summary 获取订单详情
-// @Tags 支付
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "订单ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/payment/orders/{id} [get]
 func (ctrl *PaymentController) GetOrderDetail(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -171,13 +148,6 @@ func (ctrl *PaymentController) GetOrderDetail(c *gin.Context) {
 }
 
 // CheckOrderStatus 轮询订单支付状态
-// @Summary 检查订单状态
-// @Tags 支付
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "订单ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/payment/orders/{id}/status [get]
 func (ctrl *PaymentController) CheckOrderStatus(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -211,12 +181,6 @@ func (ctrl *PaymentController) CheckOrderStatus(c *gin.Context) {
 }
 
 // GetPayGateways 获取可用支付通道列表
-// @Summary 获取可用支付通道列表
-// @Tags 支付
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/payment/gateways [get]
 func (ctrl *PaymentController) GetPayGateways(c *gin.Context) {
 	gateways, err := services.GetPayGatewayListForUser()
 	if err != nil {
@@ -238,9 +202,18 @@ func (ctrl *PaymentController) RegisterRoutes(group *gin.RouterGroup) {
 	payment := group.Group("/payment")
 	{
 		payment.POST("/create", ctrl.CreateOrder)
+		openapi.Route(payment, "POST", "/create", openapi.Op("创建充值订单", "支付").Auth().Body(CreateOrderRequest{}, "订单信息").
+			Returns(nil))
 		payment.GET("/orders", ctrl.GetOrders)
+		openapi.Route(payment, "GET", "/orders", openapi.Op("获取我的充值订单列表", "支付").Auth().Paged().
+			Query("status", "int", "状态筛选（-1=全部）").Default(-1).Returns(nil))
 		payment.GET("/orders/:id", ctrl.GetOrderDetail)
+		openapi.Route(payment, "GET", "/orders/:id", openapi.Op("获取订单详情", "支付").Auth().Path("id", "int", "订单ID").
+			Returns(nil))
 		payment.GET("/orders/:id/status", ctrl.CheckOrderStatus)
+		openapi.Route(payment, "GET", "/orders/:id/status", openapi.Op("检查订单状态", "支付").Auth().
+			Path("id", "int", "订单ID").Returns(nil))
 		payment.GET("/gateways", ctrl.GetPayGateways)
+		openapi.Route(payment, "GET", "/gateways", openapi.Op("获取可用支付通道列表", "支付").Auth().Returns(nil))
 	}
 }
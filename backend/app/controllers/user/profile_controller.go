@@ -7,8 +7,8 @@ import (
 	"fmt"
 	"fst/backend/app/models"
 	"fst/backend/app/services"
-	"fst/backend/internal/config"
 	"fst/backend/internal/middleware"
+	"fst/backend/internal/openapi"
 	"fst/backend/utils"
 	"math/big"
 	"math/rand"
@@ -59,14 +59,6 @@ type ChangePasswordRequest struct {
 // ========================================
 
 // GetProfile 获取个人信息
-// @Summary 获取个人信息
-// @Description 获取当前登录用户的个人信息
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/profile [get]
 func (ctrl *ProfileController) GetProfile(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -132,15 +124,6 @@ func (ctrl *ProfileController) GetApiKey(c *gin.Context) {
 }
 
 // UpdateProfile 更新个人信息
-// @Summary 更新个人信息
-// @Description 更新当前登录用户的个人信息
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body UpdateProfileRequest true "更新信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/profile [put]
 func (ctrl *ProfileController) UpdateProfile(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -150,7 +133,7 @@ func (ctrl *ProfileController) UpdateProfile(c *gin.Context) {
 
 	var req UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -214,15 +197,6 @@ func (ctrl *ProfileController) UpdateProfile(c *gin.Context) {
 }
 
 // ChangePassword 修改密码
-// @Summary 修改密码
-// @Description 修改当前登录用户的密码
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body ChangePasswordRequest true "密码信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/password [put]
 func (ctrl *ProfileController) ChangePassword(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -232,7 +206,7 @@ func (ctrl *ProfileController) ChangePassword(c *gin.Context) {
 
 	var req ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -283,14 +257,6 @@ type DeactivateAccountRequest struct {
 // ========================================
 
 // GetSettings 获取用户设置
-// @Summary 获取用户设置
-// @Description 获取当前用户的个人设置
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/settings [get]
 func (ctrl *ProfileController) GetSettings(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -332,15 +298,6 @@ func (ctrl *ProfileController) GetSettings(c *gin.Context) {
 }
 
 // UpdateSettings 更新用户设置
-// @Summary 更新用户设置
-// @Description 更新当前用户的个人设置（语言、主题、通知偏好等）
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body UpdateSettingsRequest true "设置信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/settings [put]
 func (ctrl *ProfileController) UpdateSettings(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -350,7 +307,7 @@ func (ctrl *ProfileController) UpdateSettings(c *gin.Context) {
 
 	var req UpdateSettingsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -398,15 +355,6 @@ func (ctrl *ProfileController) UpdateSettings(c *gin.Context) {
 // ========================================
 
 // UpdateAvatar 更新头像
-// @Summary 更新头像
-// @Description 更新当前用户的头像URL
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body map[string]string true "头像URL {avatar: \"url\"}"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/avatar [put]
 func (ctrl *ProfileController) UpdateAvatar(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -418,7 +366,7 @@ func (ctrl *ProfileController) UpdateAvatar(c *gin.Context) {
 		Avatar string `json:"avatar" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -453,14 +401,6 @@ func (ctrl *ProfileController) UpdateAvatar(c *gin.Context) {
 // ========================================
 
 // GetUserStats 获取用户统计
-// @Summary 获取用户统计
-// @Description 获取当前用户的统计数据
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/stats [get]
 func (ctrl *ProfileController) GetUserStats(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -494,15 +434,6 @@ func (ctrl *ProfileController) GetUserStats(c *gin.Context) {
 // ========================================
 
 // SendEmailChangeCode 发送修改邮箱验证码
-// @Summary 发送修改邮箱验证码
-// @Description 发送验证码到新邮箱以验证邮箱变更
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body SendEmailCodeRequest true "新邮箱"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/email/send-code [post]
 func (ctrl *ProfileController) SendEmailChangeCode(c *gin.Context) {
 	// 极验验证
 	if !validateGeetestFromRequest(c) {
@@ -518,7 +449,7 @@ func (ctrl *ProfileController) SendEmailChangeCode(c *gin.Context) {
 
 	var req SendEmailCodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -531,7 +462,7 @@ func (ctrl *ProfileController) SendEmailChangeCode(c *gin.Context) {
 		utils.Fail(c, 400, "Email already in use")
 		return
 	}
-	hasRecentCode, err := models.HasRecentVerificationCode(req.NewEmail, "change_email", time.Now().Add(-time.Minute))
+	hasRecentCode, err := models.HasRecentVerificationCode(models.CodeChannelEmail, req.NewEmail, "change_email", time.Now().Add(-time.Minute))
 	if err != nil {
 		utils.Fail(c, 500, "Failed to check verification cooldown")
 		return
@@ -560,7 +491,7 @@ func (ctrl *ProfileController) SendEmailChangeCode(c *gin.Context) {
 
 	// 存储验证码（类型为 change_email）
 	expires_at := time.Now().Add(15 * time.Minute)
-	if err := models.CreateVerificationCode(req.NewEmail, code, "change_email", expires_at); err != nil {
+	if err := models.CreateVerificationCode(models.CodeChannelEmail, req.NewEmail, code, "change_email", c.ClientIP(), expires_at); err != nil {
 		utils.Fail(c, 500, "Failed to generate verification code")
 		return
 	}
@@ -573,7 +504,7 @@ func (ctrl *ProfileController) SendEmailChangeCode(c *gin.Context) {
 	}
 
 	lang := getLangFromRequest(c, req.Lang)
-	vars := map[string]string{
+	vars := map[string]any{
 		"code":           code,
 		"expire_minutes": "15",
 	}
@@ -589,15 +520,6 @@ func (ctrl *ProfileController) SendEmailChangeCode(c *gin.Context) {
 }
 
 // VerifyEmailChange 验证并修改邮箱
-// @Summary 验证并修改邮箱
-// @Description 使用验证码确认邮箱变更
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body VerifyEmailChangeRequest true "验证信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/email/verify [post]
 func (ctrl *ProfileController) VerifyEmailChange(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -607,19 +529,19 @@ func (ctrl *ProfileController) VerifyEmailChange(c *gin.Context) {
 
 	var req VerifyEmailChangeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
 	req.NewEmail = utils.Clean_XSS(req.NewEmail)
 	req.Code = utils.Clean_XSS(req.Code)
 
-	consumed, err := models.ConsumeVerificationCode(req.NewEmail, req.Code, "change_email")
+	consumed, err := models.ConsumeVerificationCode(models.CodeChannelEmail, req.NewEmail, req.Code, "change_email")
 	if err != nil || !consumed {
 		utils.Fail(c, 400, "Invalid or expired verification code")
 		return
 	}
-	_ = models.DeleteVerificationCodesByEmail(req.NewEmail, "change_email")
+	_ = models.DiscardVerificationCodes(models.CodeChannelEmail, req.NewEmail, "change_email")
 
 	// 更新邮箱
 	update_req := &services.UserUpdateRequest{
@@ -639,15 +561,6 @@ func (ctrl *ProfileController) VerifyEmailChange(c *gin.Context) {
 // ========================================
 
 // SendPhoneChangeCode 发送修改手机号验证码
-// @Summary 发送修改手机号验证码
-// @Description 发送验证码到新手机号（占位实现，需对接短信服务）
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body SendPhoneCodeRequest true "新手机号"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/phone/send-code [post]
 func (ctrl *ProfileController) SendPhoneChangeCode(c *gin.Context) {
 	// 极验验证
 	if !validateGeetestFromRequest(c) {
@@ -663,28 +576,22 @@ func (ctrl *ProfileController) SendPhoneChangeCode(c *gin.Context) {
 
 	var req SendPhoneCodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
-	req.NewMobile = utils.Clean_XSS(req.NewMobile)
+	mobile, ok := utils.NormalizeMobile(utils.Clean_XSS(req.NewMobile))
+	if !ok {
+		utils.Fail(c, 400, "Invalid phone number")
+		return
+	}
+	req.NewMobile = mobile
 	uid := user_id.(uint64)
 
 	// 检查手机号是否已被使用
-	if req.NewMobile != "" {
-		existing, _ := models.GetUserByMobile(req.NewMobile)
-		if existing != nil && existing.ID != uid {
-			utils.Fail(c, 400, "Phone number already in use")
-			return
-		}
-	}
-	hasRecentCode, err := models.HasRecentVerificationCode(req.NewMobile, "change_phone", time.Now().Add(-time.Minute))
-	if err != nil {
-		utils.Fail(c, 500, "Failed to check verification cooldown")
-		return
-	}
-	if hasRecentCode {
-		utils.Fail(c, 429, "Please wait before requesting another verification code")
+	existing, _ := models.GetUserByMobile(req.NewMobile)
+	if existing != nil && existing.ID != uid {
+		utils.Fail(c, 400, "Phone number already in use")
 		return
 	}
 
@@ -702,32 +609,9 @@ func (ctrl *ProfileController) SendPhoneChangeCode(c *gin.Context) {
 		return
 	}
 
-	// 生成验证码
-	code := generateCode()
-
-	// 存储验证码
-	expires_at := time.Now().Add(10 * time.Minute)
-	if err := models.CreateVerificationCode(req.NewMobile, code, "change_phone", expires_at); err != nil {
-		utils.Fail(c, 500, "Failed to generate verification code")
-		return
-	}
-
-	// 通过 SMS 服务发送验证码
-	if services.GlobalSMSService == nil {
-		_ = models.DeleteVerificationCodesByEmail(req.NewMobile, "change_phone")
-		utils.Fail(c, 500, "SMS service unavailable")
-		return
-	}
-	providerName := services.GlobalSMSService.GetProviderName()
-	if providerName == "none" || (providerName != "console" && !services.GlobalSMSService.IsConfigured()) || (providerName == "console" && config.IsProductionMode()) {
-		_ = models.DeleteVerificationCodesByEmail(req.NewMobile, "change_phone")
-		utils.Fail(c, 500, "SMS service not configured")
-		return
-	}
-	if err := services.GlobalSMSService.SendCode(req.NewMobile, code, 10); err != nil {
-		fmt.Printf("[SMS] Failed to send code to %s via %s: %v\n", req.NewMobile, providerName, err)
-		_ = models.DeleteVerificationCodesByEmail(req.NewMobile, "change_phone")
-		utils.Fail(c, 500, "Failed to send verification code")
+	// 生成、存储并发送验证码（含发送间隔与配额检查）
+	if svc_err := services.GlobalSMSService.SendVerifyCode(req.NewMobile, services.SMSPurposeChangePhone, c.ClientIP()); svc_err != nil {
+		utils.Fail(c, svc_err.Code, svc_err.Message)
 		return
 	}
 
@@ -735,15 +619,6 @@ func (ctrl *ProfileController) SendPhoneChangeCode(c *gin.Context) {
 }
 
 // VerifyPhoneChange 验证并修改手机号
-// @Summary 验证并修改手机号
-// @Description 使用验证码确认手机号变更
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body VerifyPhoneChangeRequest true "验证信息"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/phone/verify [post]
 func (ctrl *ProfileController) VerifyPhoneChange(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -753,19 +628,22 @@ func (ctrl *ProfileController) VerifyPhoneChange(c *gin.Context) {
 
 	var req VerifyPhoneChangeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
-	req.NewMobile = utils.Clean_XSS(req.NewMobile)
 	req.Code = utils.Clean_XSS(req.Code)
+	mobile, ok := utils.NormalizeMobile(utils.Clean_XSS(req.NewMobile))
+	if !ok {
+		utils.Fail(c, 400, "Invalid phone number")
+		return
+	}
+	req.NewMobile = mobile
 
-	consumed, err := models.ConsumeVerificationCode(req.NewMobile, req.Code, "change_phone")
-	if err != nil || !consumed {
+	if !services.ConsumeSMSCode(req.NewMobile, req.Code, services.SMSPurposeChangePhone) {
 		utils.Fail(c, 400, "Invalid or expired verification code")
 		return
 	}
-	_ = models.DeleteVerificationCodesByEmail(req.NewMobile, "change_phone")
 
 	// 更新手机号
 	update_req := &services.UserUpdateRequest{
@@ -785,15 +663,6 @@ func (ctrl *ProfileController) VerifyPhoneChange(c *gin.Context) {
 // ========================================
 
 // DeactivateAccount 注销账号
-// @Summary 注销账号
-// @Description 用户主动注销（软删除）自己的账号
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param request body DeactivateAccountRequest true "密码确认"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/deactivate [post]
 func (ctrl *ProfileController) DeactivateAccount(c *gin.Context) {
 	// 检查系统设置是否允许注销账号
 	allowDeleteAccount := false
@@ -822,7 +691,7 @@ func (ctrl *ProfileController) DeactivateAccount(c *gin.Context) {
 
 	var req DeactivateAccountRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.Fail(c, 400, err.Error())
+		utils.FailBind(c, err, &req)
 		return
 	}
 
@@ -854,14 +723,6 @@ func (ctrl *ProfileController) DeactivateAccount(c *gin.Context) {
 // ========================================
 
 // GetSessions 获取用户登录会话列表
-// @Summary 获取登录会话
-// @Description 获取当前用户的所有活跃登录会话
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/sessions [get]
 func (ctrl *ProfileController) GetSessions(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -885,15 +746,6 @@ func (ctrl *ProfileController) GetSessions(c *gin.Context) {
 }
 
 // RevokeSession 踢出指定会话
-// @Summary 踢出会话
-// @Description 撤销指定的登录会话
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Param id path int true "会话ID"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/sessions/:id [delete]
 func (ctrl *ProfileController) RevokeSession(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -917,19 +769,12 @@ func (ctrl *ProfileController) RevokeSession(c *gin.Context) {
 		utils.Fail(c, 500, "Failed to revoke session")
 		return
 	}
+	services.PushSessionRevoked(user_id.(uint64), guardStr)
 
 	utils.Success(c, gin.H{"message": "Session revoked successfully"})
 }
 
 // RevokeAllSessions 踢出所有其他会话
-// @Summary 踢出所有其他会话
-// @Description 撤销当前会话以外的所有登录会话
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/sessions/revoke-all [post]
 func (ctrl *ProfileController) RevokeAllSessions(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -955,6 +800,7 @@ func (ctrl *ProfileController) RevokeAllSessions(c *gin.Context) {
 		utils.Fail(c, 500, "Failed to revoke sessions")
 		return
 	}
+	services.PushSessionRevoked(user_id.(uint64), guardStr)
 
 	utils.Success(c, gin.H{"message": "All other sessions revoked"})
 }
@@ -964,14 +810,6 @@ func (ctrl *ProfileController) RevokeAllSessions(c *gin.Context) {
 // ========================================
 
 // ResetApiKey 重置API密钥
-// @Summary 重置API密钥
-// @Description 重置当前用户的API密钥
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/resetapikey [post]
 func (ctrl *ProfileController) ResetApiKey(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -1055,15 +893,6 @@ func validateGeetestFromRequest(c *gin.Context) bool {
 // ========================================
 
 // GetMoneyLogs 获取当前用户的余额变动日志
-// @Summary 获取我的余额变动日志
-// @Tags 用户中心
-// @Produce json
-// @Security BearerAuth
-// @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(20)
-// @Param keyword query string false "搜索关键词"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/money-logs [get]
 func (ctrl *ProfileController) GetMoneyLogs(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -1092,15 +921,6 @@ func (ctrl *ProfileController) GetMoneyLogs(c *gin.Context) {
 }
 
 // GetScoreLogs 获取当前用户的积分变动日志
-// @Summary 获取我的积分变动日志
-// @Tags 用户中心
-// @Produce json
-// @Security BearerAuth
-// @Param page query int false "页码" default(1)
-// @Param page_size query int false "每页数量" default(20)
-// @Param keyword query string false "搜索关键词"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/score-logs [get]
 func (ctrl *ProfileController) GetScoreLogs(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -1133,14 +953,6 @@ func (ctrl *ProfileController) GetScoreLogs(c *gin.Context) {
 // ========================================
 
 // GetDashboard 获取用户仪表盘数据
-// @Summary 获取用户仪表盘
-// @Description 返回用户统计概览、公告、快捷操作入口
-// @Tags 用户中心
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {object} utils.Response
-// @Router /api/v1/user/dashboard [get]
 func (ctrl *ProfileController) GetDashboard(c *gin.Context) {
 	user_id, exists := c.Get("userID")
 	if !exists {
@@ -1191,46 +1003,80 @@ func (ctrl *ProfileController) GetDashboard(c *gin.Context) {
 func (ctrl *ProfileController) RegisterRoutes(group *gin.RouterGroup) {
 	// 个人信息
 	group.GET("/profile", ctrl.GetProfile)
+	openapi.Route(group, "GET", "/profile", openapi.Op("获取个人信息", "用户中心").Notes("获取当前登录用户的个人信息").Auth().Returns(nil))
 	group.PUT("/profile", ctrl.UpdateProfile)
+	openapi.Route(group, "PUT", "/profile", openapi.Op("更新个人信息", "用户中心").Notes("更新当前登录用户的个人信息").Auth().
+		Body(UpdateProfileRequest{}, "更新信息").Returns(nil))
 	group.GET("/apikey", ctrl.GetApiKey)
 
 	// 密码
 	group.PUT("/password", ctrl.ChangePassword)
+	openapi.Route(group, "PUT", "/password", openapi.Op("修改密码", "用户中心").Notes("修改当前登录用户的密码").Auth().
+		Body(ChangePasswordRequest{}, "密码信息").Returns(nil))
 
 	// 设置
 	group.GET("/settings", ctrl.GetSettings)
+	openapi.Route(group, "GET", "/settings", openapi.Op("获取用户设置", "用户中心").Notes("获取当前用户的个人设置").Auth().Returns(nil))
 	group.PUT("/settings", ctrl.UpdateSettings)
+	openapi.Route(group, "PUT", "/settings", openapi.Op("更新用户设置", "用户中心").Notes("更新当前用户的个人设置（语言、主题、通知偏好等）").Auth().
+		Body(UpdateSettingsRequest{}, "设置信息").Returns(nil))
 
 	// 头像
 	group.PUT("/avatar", ctrl.UpdateAvatar)
+	openapi.Route(group, "PUT", "/avatar", openapi.Op("更新头像", "用户中心").Notes("更新当前用户的头像URL").Auth().
+		Body(map[string]string{}, "头像URL {avatar: \"url\"}").Returns(nil))
 
 	// 统计
 	group.GET("/stats", ctrl.GetUserStats)
+	openapi.Route(group, "GET", "/stats", openapi.Op("获取用户统计", "用户中心").Notes("获取当前用户的统计数据").Auth().Returns(nil))
 
 	verificationGroup := group.Group("")
 	verificationGroup.Use(middleware.UserRateLimitMiddleware(1, 3))
 	verificationGroup.POST("/email/send-code", ctrl.SendEmailChangeCode)
+	openapi.Route(verificationGroup, "POST", "/email/send-code", openapi.Op("发送修改邮箱验证码", "用户中心").
+		Notes("发送验证码到新邮箱以验证邮箱变更").Auth().Body(SendEmailCodeRequest{}, "新邮箱").Returns(nil))
 	verificationGroup.POST("/email/verify", ctrl.VerifyEmailChange)
+	openapi.Route(verificationGroup, "POST", "/email/verify", openapi.Op("验证并修改邮箱", "用户中心").Notes("使用验证码确认邮箱变更").
+		Auth().Body(VerifyEmailChangeRequest{}, "验证信息").Returns(nil))
 	verificationGroup.POST("/phone/send-code", ctrl.SendPhoneChangeCode)
+	openapi.Route(verificationGroup, "POST", "/phone/send-code", openapi.Op("发送修改手机号验证码", "用户中心").
+		Notes("发送验证码到新手机号（受发送间隔与发送配额限制）").Auth().Body(SendPhoneCodeRequest{}, "新手机号").Returns(nil))
 	verificationGroup.POST("/phone/verify", ctrl.VerifyPhoneChange)
+	openapi.Route(verificationGroup, "POST", "/phone/verify", openapi.Op("验证并修改手机号", "用户中心").Notes("使用验证码确认手机号变更").
+		Auth().Body(VerifyPhoneChangeRequest{}, "验证信息").Returns(nil))
 
 	// 账号注销
 	group.POST("/deactivate", ctrl.DeactivateAccount)
+	openapi.Route(group, "POST", "/deactivate", openapi.Op("注销账号", "用户中心").Notes("用户主动注销（软删除）自己的账号").Auth().
+		Body(DeactivateAccountRequest{}, "密码确认").Returns(nil))
 
 	// 会话管理
 	group.GET("/sessions", ctrl.GetSessions)
+	openapi.Route(group, "GET", "/sessions", openapi.Op("获取登录会话", "用户中心").Notes("获取当前用户的所有活跃登录会话").Auth().Returns(nil))
 	group.DELETE("/sessions/:id", ctrl.RevokeSession)
+	openapi.Route(group, "DELETE", "/sessions/:id", openapi.Op("踢出会话", "用户中心").Notes("撤销指定的登录会话").Auth().
+		Path("id", "int", "会话ID").Returns(nil))
 	group.POST("/sessions/revoke-all", ctrl.RevokeAllSessions)
+	openapi.Route(group, "POST", "/sessions/revoke-all", openapi.Op("踢出所有其他会话", "用户中心").Notes("撤销当前会话以外的所有登录会话").
+		Auth().Returns(nil))
 
 	// API Key
 	group.POST("/resetapikey", ctrl.ResetApiKey)
+	openapi.Route(group, "POST", "/resetapikey", openapi.Op("重置API密钥", "用户中心").Notes("重置当前用户的API密钥").Auth().
+		Returns(nil))
 
 	// 余额/积分日志
 	group.GET("/money-logs", ctrl.GetMoneyLogs)
+	openapi.Route(group, "GET", "/money-logs", openapi.Op("获取我的余额变动日志", "用户中心").Auth().Paged().
+		Query("keyword", "string", "搜索关键词").Returns(nil))
 	group.GET("/score-logs", ctrl.GetScoreLogs)
+	openapi.Route(group, "GET", "/score-logs", openapi.Op("获取我的积分变动日志", "用户中心").Auth().Paged().
+		Query("keyword", "string", "搜索关键词").Returns(nil))
 
 	// 仪表盘
 	group.GET("/dashboard", ctrl.GetDashboard)
+	openapi.Route(group, "GET", "/dashboard", openapi.Op("获取用户仪表盘", "用户中心").Notes("返回用户统计概览、公告、快捷操作入口").Auth().
+		Returns(nil))
 }
 
 // ========================================
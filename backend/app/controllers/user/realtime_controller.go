@@ -0,0 +1,108 @@
+package user
+
+import (
+	"encoding/json"
+	"fst/backend/app/models"
+	"fst/backend/app/services"
+	"fst/backend/internal/openapi"
+	"fst/backend/internal/realtime"
+	"fst/backend/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// realtimeHeartbeat 心跳间隔，同时定期复查会话是否仍然有效
+const realtimeHeartbeat = 25 * time.Second
+
+// RealtimeController 实时推送控制器（需要登录）
+type RealtimeController struct{}
+
+// NewRealtimeController 创建实时推送控制器
+func NewRealtimeController() *RealtimeController {
+	return &RealtimeController{}
+}
+
+// Stream 建立实时推送连接（Server-Sent Events）
+func (ctrl *RealtimeController) Stream(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.Fail(c, 401, "用户未登录")
+		return
+	}
+	uid := userID.(uint64)
+	auth_guard := c.GetString("authGuard")
+	token_hash := c.GetString("tokenHash")
+
+	sub, err := services.SubscribeRealtime(uid)
+	if err != nil {
+		utils.Fail(c, 503, "实时推送暂不可用")
+		return
+	}
+	defer services.GlobalRealtime.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭 Nginx 缓冲
+	c.SSEvent(services.RealtimeReady, gin.H{"user_id": uid})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(realtimeHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-sub.Done():
+			return
+		case msg := <-sub.C():
+			if msg.Event == services.RealtimeSessionRevoked {
+				// 同一用户的其他会话变化也会收到，只有本连接的会话失效时才断开
+				if !revokedFor(msg, auth_guard) || sessionActive(uid, auth_guard, token_hash) {
+					continue
+				}
+				c.SSEvent(msg.Event, string(msg.Data))
+				c.Writer.Flush()
+				return
+			}
+			c.SSEvent(msg.Event, string(msg.Data))
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if !sessionActive(uid, auth_guard, token_hash) {
+				c.SSEvent(services.RealtimeSessionRevoked, gin.H{"auth_guard": auth_guard})
+				c.Writer.Flush()
+				return
+			}
+			c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// revokedFor 会话撤销消息是否针对该 guard
+func revokedFor(msg *realtime.Message, auth_guard string) bool {
+	var data struct {
+		AuthGuard string `json:"auth_guard"`
+	}
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		return true
+	}
+	return data.AuthGuard == "" || data.AuthGuard == auth_guard
+}
+
+// sessionActive 会话是否仍然有效，查询失败时视为有效，等待下次复查
+func sessionActive(user_id uint64, auth_guard, token_hash string) bool {
+	active, err := models.IsUserSessionActive(user_id, auth_guard, token_hash)
+	return err != nil || active
+}
+
+// RegisterRoutes 注册实时推送路由
+func (ctrl *RealtimeController) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/stream", ctrl.Stream)
+	openapi.Route(group, "GET", "/stream", openapi.Op("实时推送（SSE）", "实时推送").
+		Notes("以 text/event-stream 持续推送当前用户的事件：payment.paid、notification.new、settings.updated、session.revoked。").
+		Notes("鉴权与其他用户接口相同（Authorization 头），浏览器原生 EventSource 无法携带请求头，需使用 fetch 读取流。").
+		Notes("本连接的会话失效（被踢出、其他设备登录或令牌已刷新）时推送 session.revoked 并关闭连接，客户端应调用普通接口确认登录状态（失效则退出登录）后重连。").
+		Notes("连接断开后客户端应重连并重新拉取最新状态。").Auth().Produces("text/event-stream", "事件流"))
+}
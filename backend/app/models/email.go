@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"fst/backend/internal/db"
 	"time"
 )
@@ -12,7 +13,8 @@ type EmailLog struct {
 	Subject      string    `db:"subject" json:"subject"`
 	Content      string    `db:"content" json:"content"`
 	TemplateName string    `db:"template_name" json:"template_name"`
-	Status       uint8     `db:"status" json:"status"`
+	CampaignID   uint64    `db:"campaign_id" json:"campaign_id"` // 群发任务 ID，0 表示非群发
+	Status       uint8     `db:"status" json:"status"`           // 见 EmailLogFailed 等
 	ErrorMsg     string    `db:"error_msg" json:"error_msg"`
 	CreatedAt    time.Time `db:"created_at" json:"created_at"`
 }
@@ -32,10 +34,22 @@ type EmailTemplate struct {
 	UpdatedAt   string `db:"updated_at" json:"updated_at"`
 }
 
+// 邮件日志状态
+const (
+	EmailLogFailed  = 0 // 发送失败
+	EmailLogSuccess = 1 // 发送成功
+	EmailLogSkipped = 2 // 收件人被抑制或关闭了邮件通知，未发送
+)
+
 // CreateEmailLog 记录邮件发送日志
 func CreateEmailLog(to, subject, content, tplName string, status int, errorMsg string) error {
-	query := `INSERT INTO email_logs (to_email, subject, content, template_name, status, error_msg) VALUES (?, ?, ?, ?, ?, ?)`
-	_, err := db.DB.Exec(query, to, subject, content, tplName, status, errorMsg)
+	return CreateCampaignEmailLog(0, to, subject, content, tplName, status, errorMsg)
+}
+
+// CreateCampaignEmailLog 记录群发任务中一个收件人的发送日志；campaignID 为 0 时即普通日志
+func CreateCampaignEmailLog(campaignID uint64, to, subject, content, tplName string, status int, errorMsg string) error {
+	query := `INSERT INTO email_logs (to_email, subject, content, template_name, campaign_id, status, error_msg) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.DB.Exec(query, to, subject, content, tplName, campaignID, status, errorMsg)
 	return err
 }
 
@@ -45,7 +59,8 @@ type EmailLogQuery struct {
 	PageSize     int    `form:"page_size" json:"page_size"`
 	ToEmail      string `form:"to_email" json:"to_email"`
 	TemplateName string `form:"template_name" json:"template_name"`
-	Status       int    `form:"status" json:"status"` // -1=全部, 0=失败, 1=成功
+	CampaignID   uint64 `form:"campaign_id" json:"campaign_id"`
+	Status       int    `form:"status" json:"status"` // -1=全部, 0=失败, 1=成功, 2=跳过
 	StartTime    string `form:"start_time" json:"start_time"`
 	EndTime      string `form:"end_time" json:"end_time"`
 }
@@ -66,6 +81,10 @@ func GetEmailLogList(q *EmailLogQuery) ([]EmailLog, int64, error) {
 		where += " AND template_name = ?"
 		args = append(args, q.TemplateName)
 	}
+	if q.CampaignID > 0 {
+		where += " AND campaign_id = ?"
+		args = append(args, q.CampaignID)
+	}
 	if q.Status >= 0 {
 		where += " AND status = ?"
 		args = append(args, q.Status)
@@ -92,7 +111,7 @@ func GetEmailLogList(q *EmailLogQuery) ([]EmailLog, int64, error) {
 	}
 	offset := (q.Page - 1) * q.PageSize
 
-	list_sql := "SELECT id, to_email, subject, template_name, status, error_msg, created_at FROM email_logs " +
+	list_sql := "SELECT id, to_email, subject, template_name, campaign_id, status, error_msg, created_at FROM email_logs " +
 		where + " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?"
 	args = append(args, q.PageSize, offset)
 
@@ -124,7 +143,7 @@ func DeleteEmailLogsBefore(before string) (int64, error) {
 }
 
 // GetEmailLogStats 邮件日志统计
-func GetEmailLogStats() (total int64, success int64, fail int64, err error) {
+func GetEmailLogStats() (total int64, success int64, fail int64, skipped int64, err error) {
 	err = db.DB.Get(&total, "SELECT COUNT(*) FROM email_logs")
 	if err != nil {
 		return
@@ -133,7 +152,11 @@ func GetEmailLogStats() (total int64, success int64, fail int64, err error) {
 	if err != nil {
 		return
 	}
-	fail = total - success
+	err = db.DB.Get(&skipped, "SELECT COUNT(*) FROM email_logs WHERE status = 2")
+	if err != nil {
+		return
+	}
+	fail = total - success - skipped
 	return
 }
 
@@ -172,108 +195,31 @@ func GetEmailTemplate(name, lang string) (*EmailTemplate, error) {
 	return &tpl, nil
 }
 
-// UpdateEmailTemplateContent 更新模板内容
-func UpdateEmailTemplateContent(name, lang, content string) error {
-	query := `UPDATE email_templates SET content = ? WHERE name = ? AND lang = ?`
-	_, err := db.DB.Exec(query, content, name, lang)
+// UpdateEmailTemplate 更新模板的可编辑字段
+func UpdateEmailTemplate(id uint64, subject, content, description string, status uint8) error {
+	query := `UPDATE email_templates SET subject = ?, content = ?, description = ?, status = ? WHERE id = ?`
+	_, err := db.DB.Exec(query, subject, content, description, status, id)
 	return err
 }
 
-// InitEmailTemplates 初始化默认邮件模板
-func InitEmailTemplates() {
-	// 注册验证码模板
-	registerCodeZH := `<p style="margin:0 0 16px 0;">您好，感谢您的注册！请使用以下验证码完成验证：</p>` +
-		`<div style="text-align:center;margin:28px 0;">` +
-		`<div style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:32px;font-weight:700;letter-spacing:8px;padding:16px 40px;border-radius:12px;">{code}</div>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">⏱ 验证码有效期为 <strong>{expire_minutes} 分钟</strong>，请尽快使用。</p>` +
-		`<p style="margin:0;color:#a0a0b8;font-size:13px;">如果这不是您本人的操作，请忽略此邮件。请勿将验证码透露给任何人。</p>`
-
-	registerCodeEN := `<p style="margin:0 0 16px 0;">Hello! Thank you for signing up. Please use the following code to verify your account:</p>` +
-		`<div style="text-align:center;margin:28px 0;">` +
-		`<div style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:32px;font-weight:700;letter-spacing:8px;padding:16px 40px;border-radius:12px;">{code}</div>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">⏱ This code is valid for <strong>{expire_minutes} minutes</strong>.</p>` +
-		`<p style="margin:0;color:#a0a0b8;font-size:13px;">If you did not request this, please ignore this email. Never share your code with anyone.</p>`
-
-	if !CheckTemplateExists("register_code", "zh-CN") {
-		CreateEmailTemplate(&EmailTemplate{
-			Name:        "register_code",
-			Lang:        "zh-CN",
-			Title:       "注册验证码",
-			Subject:     "【{app_name}】注册验证码",
-			Content:     registerCodeZH,
-			Description: "用户注册时发送的验证码",
-			Variables:   "code, app_name, expire_minutes",
-			Status:      1,
-		})
-	} else {
-		_ = UpdateEmailTemplateContent("register_code", "zh-CN", registerCodeZH)
-	}
-	if !CheckTemplateExists("register_code", "en-US") {
-		CreateEmailTemplate(&EmailTemplate{
-			Name:        "register_code",
-			Lang:        "en-US",
-			Title:       "Registration Code",
-			Subject:     "[{app_name}] Registration Code",
-			Content:     registerCodeEN,
-			Description: "Verification code for user registration",
-			Variables:   "code, app_name, expire_minutes",
-			Status:      1,
-		})
-	} else {
-		_ = UpdateEmailTemplateContent("register_code", "en-US", registerCodeEN)
+// GetEmailTemplateByID 根据 ID 获取模板（不限状态）
+func GetEmailTemplateByID(id uint64) (*EmailTemplate, error) {
+	var tpl EmailTemplate
+	if err := db.DB.Get(&tpl, "SELECT * FROM email_templates WHERE id = ?", id); err != nil {
+		return nil, err
 	}
+	return &tpl, nil
+}
 
-	// 密码重置模板
-	resetPasswordZH := `<p style="margin:0 0 16px 0;">您好，我们收到了您的密码重置请求。请点击下方按钮重置密码：</p>` +
-		`<div style="text-align:center;margin:28px 0;">` +
-		`<a href="{link}" style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:16px;font-weight:600;text-decoration:none;padding:14px 48px;border-radius:10px;">重置密码</a>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">如果按钮无法点击，您也可以使用以下验证码：</p>` +
-		`<div style="text-align:center;margin:20px 0;">` +
-		`<div style="display:inline-block;background:#f0f2f5;font-size:28px;font-weight:700;letter-spacing:6px;padding:14px 36px;border-radius:10px;color:#1a1a2e;border:2px dashed #667eea;">{code}</div>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">⏱ 有效期为 <strong>15 分钟</strong>，请尽快操作。</p>` +
-		`<p style="margin:0;color:#a0a0b8;font-size:13px;">如果这不是您本人的操作，请忽略此邮件，您的密码不会被更改。</p>`
-
-	resetPasswordEN := `<p style="margin:0 0 16px 0;">Hello, we received a request to reset your password. Click the button below to proceed:</p>` +
-		`<div style="text-align:center;margin:28px 0;">` +
-		`<a href="{link}" style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:16px;font-weight:600;text-decoration:none;padding:14px 48px;border-radius:10px;">Reset Password</a>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">If the button doesn't work, you can also use this verification code:</p>` +
-		`<div style="text-align:center;margin:20px 0;">` +
-		`<div style="display:inline-block;background:#f0f2f5;font-size:28px;font-weight:700;letter-spacing:6px;padding:14px 36px;border-radius:10px;color:#1a1a2e;border:2px dashed #667eea;">{code}</div>` +
-		`</div>` +
-		`<p style="margin:0 0 8px 0;">⏱ Valid for <strong>15 minutes</strong>.</p>` +
-		`<p style="margin:0;color:#a0a0b8;font-size:13px;">If you did not request a password reset, please ignore this email. Your password will remain unchanged.</p>`
-
-	if !CheckTemplateExists("reset_password", "zh-CN") {
-		CreateEmailTemplate(&EmailTemplate{
-			Name:        "reset_password",
-			Lang:        "zh-CN",
-			Title:       "密码重置",
-			Subject:     "【{app_name}】密码重置请求",
-			Content:     resetPasswordZH,
-			Description: "用户重置密码时发送的链接和验证码",
-			Variables:   "link, code, app_name",
-			Status:      1,
-		})
-	} else {
-		_ = UpdateEmailTemplateContent("reset_password", "zh-CN", resetPasswordZH)
+// GetEmailTemplateByNameLang 根据名称与语言获取模板（不限状态），不存在时返回 nil
+func GetEmailTemplateByNameLang(name, lang string) (*EmailTemplate, error) {
+	var tpl EmailTemplate
+	err := db.DB.Get(&tpl, "SELECT * FROM email_templates WHERE name = ? AND lang = ?", name, lang)
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-	if !CheckTemplateExists("reset_password", "en-US") {
-		CreateEmailTemplate(&EmailTemplate{
-			Name:        "reset_password",
-			Lang:        "en-US",
-			Title:       "Password Reset",
-			Subject:     "[{app_name}] Password Reset Request",
-			Content:     resetPasswordEN,
-			Description: "Link and code for password reset",
-			Variables:   "link, code, app_name",
-			Status:      1,
-		})
-	} else {
-		_ = UpdateEmailTemplateContent("reset_password", "en-US", resetPasswordEN)
+	if err != nil {
+		return nil, err
 	}
+	return &tpl, nil
 }
@@ -0,0 +1,315 @@
+package models
+
+import (
+	"encoding/json"
+	"fst/backend/internal/db"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// 群发任务状态
+const (
+	EmailCampaignScheduled = "scheduled" // 等待计划时间
+	EmailCampaignRunning   = "running"   // 发送中
+	EmailCampaignPaused    = "paused"    // 已暂停，可恢复
+	EmailCampaignCompleted = "completed" // 全部收件人已处理
+	EmailCampaignCancelled = "cancelled" // 已取消
+)
+
+// EmailCampaign 邮件群发任务
+//
+// 收件人按用户 ID 升序处理：开始时记录 max_user_id，之后注册的用户不在本次群发范围内；
+// last_user_id 为已处理到的用户 ID，暂停后从这里继续。run_id 在每次恢复时递增，旧的队列任务据此退出。
+type EmailCampaign struct {
+	ID            uint64          `db:"id" json:"id"`
+	Name          string          `db:"name" json:"name"`
+	TemplateName  string          `db:"template_name" json:"template_name"`
+	Lang          string          `db:"lang" json:"lang"` // 用户语言没有对应模板时使用的语言
+	Vars          json.RawMessage `db:"vars" json:"vars"`
+	Filter        json.RawMessage `db:"filter" json:"filter"` // EmailCampaignFilter
+	Status        string          `db:"status" json:"status"`
+	ScheduledAt   int64           `db:"scheduled_at" json:"scheduled_at"`
+	RatePerMinute int             `db:"rate_per_minute" json:"rate_per_minute"` // 每分钟最多发送数，0=不限
+	RunID         int             `db:"run_id" json:"-"`
+	LastUserID    uint64          `db:"last_user_id" json:"last_user_id"`
+	MaxUserID     uint64          `db:"max_user_id" json:"max_user_id"`
+	Total         int64           `db:"total" json:"total"`
+	Sent          int64           `db:"sent" json:"sent"`
+	Failed        int64           `db:"failed" json:"failed"`
+	Skipped       int64           `db:"skipped" json:"skipped"`
+	CreatedBy     uint64          `db:"created_by" json:"created_by"`
+	CreatedByName string          `db:"created_by_name" json:"created_by_name"`
+	StartTime     int64           `db:"start_time" json:"start_time"`
+	FinishTime    int64           `db:"finish_time" json:"finish_time"`
+	CreateTime    int64           `db:"create_time" json:"create_time"`
+	UpdateTime    int64           `db:"update_time" json:"update_time"`
+}
+
+// EmailCampaignFilter 群发收件人筛选条件，空值表示不限；只包含未删除且填写了邮箱的用户
+type EmailCampaignFilter struct {
+	Status         *uint8   `json:"status,omitempty"`          // 用户状态：1=启用，0=禁用
+	Levels         []uint64 `json:"levels,omitempty"`          // 用户等级
+	GroupIDs       []uint64 `json:"group_ids,omitempty"`       // 用户分组
+	Role           string   `json:"role,omitempty"`            // user / admin
+	RegisteredFrom int64    `json:"registered_from,omitempty"` // 注册时间起（时间戳，含）
+	RegisteredTo   int64    `json:"registered_to,omitempty"`   // 注册时间止（时间戳，含）
+}
+
+// CampaignRecipient 群发收件人
+type CampaignRecipient struct {
+	ID       uint64 `db:"id"`
+	Username string `db:"username"`
+	Nickname string `db:"nickname"`
+	Email    string `db:"email"`
+	Language string `db:"language"`
+}
+
+// InitEmailCampaignsTable 初始化邮件群发任务表
+func InitEmailCampaignsTable() {
+	if db.CheckTableExists("email_campaigns") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS email_campaigns (
+		id              BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		name            VARCHAR(100) NOT NULL COMMENT '任务名称',
+		template_name   VARCHAR(100) NOT NULL COMMENT '模板标识',
+		lang            VARCHAR(20)  NOT NULL DEFAULT 'zh-CN' COMMENT '默认语言',
+		vars            TEXT         NOT NULL COMMENT '模板变量(JSON)',
+		filter          TEXT         NOT NULL COMMENT '收件人筛选条件(JSON)',
+		status          VARCHAR(20)  NOT NULL DEFAULT 'scheduled' COMMENT '状态:scheduled/running/paused/completed/cancelled',
+		scheduled_at    BIGINT       NOT NULL DEFAULT 0 COMMENT '计划发送时间',
+		rate_per_minute INT          NOT NULL DEFAULT 0 COMMENT '每分钟最多发送数:0=不限',
+		run_id          INT          NOT NULL DEFAULT 1 COMMENT '运行序号,每次恢复递增',
+		last_user_id    BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '已处理到的用户ID',
+		max_user_id     BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '开始时的最大用户ID',
+		total           INT          NOT NULL DEFAULT 0 COMMENT '收件人数',
+		sent            INT          NOT NULL DEFAULT 0 COMMENT '发送成功数',
+		failed          INT          NOT NULL DEFAULT 0 COMMENT '发送失败数',
+		skipped         INT          NOT NULL DEFAULT 0 COMMENT '跳过数(被抑制或关闭通知)',
+		created_by      BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建人ID',
+		created_by_name VARCHAR(100) NOT NULL DEFAULT '' COMMENT '创建人',
+		start_time      BIGINT       NOT NULL DEFAULT 0 COMMENT '开始时间',
+		finish_time     BIGINT       NOT NULL DEFAULT 0 COMMENT '结束时间',
+		create_time     BIGINT       NOT NULL DEFAULT 0 COMMENT '创建时间',
+		update_time     BIGINT       NOT NULL DEFAULT 0 COMMENT '更新时间',
+		KEY idx_status (status, create_time)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='邮件群发任务表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create email_campaigns table: %v", err)
+	} else {
+		log.Println("[Init] Created email_campaigns table")
+	}
+}
+
+// where 生成用户筛选条件；maxUserID 为 0 时不限制用户 ID 上限
+func (f *EmailCampaignFilter) where(afterUserID, maxUserID uint64) (string, []interface{}, error) {
+	where := "WHERE delete_time IS NULL AND email != '' AND id > ?"
+	args := []interface{}{afterUserID}
+	if maxUserID > 0 {
+		where += " AND id <= ?"
+		args = append(args, maxUserID)
+	}
+	if f.Status != nil {
+		where += " AND status = ?"
+		args = append(args, *f.Status)
+	}
+	if len(f.Levels) > 0 {
+		where += " AND level IN (?)"
+		args = append(args, f.Levels)
+	}
+	if len(f.GroupIDs) > 0 {
+		where += " AND group_id IN (?)"
+		args = append(args, f.GroupIDs)
+	}
+	if f.Role != "" {
+		where += " AND role = ?"
+		args = append(args, f.Role)
+	}
+	if f.RegisteredFrom > 0 {
+		where += " AND COALESCE(create_time, join_time) >= ?"
+		args = append(args, f.RegisteredFrom)
+	}
+	if f.RegisteredTo > 0 {
+		where += " AND COALESCE(create_time, join_time) <= ?"
+		args = append(args, f.RegisteredTo)
+	}
+	return sqlx.In(where, args...)
+}
+
+// CountCampaignRecipients 统计符合条件的收件人数
+func CountCampaignRecipients(f *EmailCampaignFilter, maxUserID uint64) (int64, error) {
+	where, args, err := f.where(0, maxUserID)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	err = db.DB.Get(&total, "SELECT COUNT(*) FROM users "+where, args...)
+	return total, err
+}
+
+// GetCampaignRecipients 按用户 ID 升序取 afterUserID 之后的一批收件人
+func GetCampaignRecipients(f *EmailCampaignFilter, afterUserID, maxUserID uint64, limit int) ([]CampaignRecipient, error) {
+	where, args, err := f.where(afterUserID, maxUserID)
+	if err != nil {
+		return nil, err
+	}
+	var list []CampaignRecipient
+	args = append(args, limit)
+	err = db.DB.Select(&list, "SELECT id, username, nickname, email, language FROM users "+where+" ORDER BY id LIMIT ?", args...)
+	return list, err
+}
+
+// GetMaxUserID 当前最大的用户 ID
+func GetMaxUserID() (uint64, error) {
+	var id uint64
+	err := db.DB.Get(&id, "SELECT COALESCE(MAX(id), 0) FROM users")
+	return id, err
+}
+
+// CreateEmailCampaign 创建群发任务
+func CreateEmailCampaign(c *EmailCampaign) error {
+	now := time.Now().Unix()
+	c.CreateTime, c.UpdateTime = now, now
+	if c.Status == "" {
+		c.Status = EmailCampaignScheduled
+	}
+	if c.RunID == 0 {
+		c.RunID = 1
+	}
+	result, err := db.DB.NamedExec(`INSERT INTO email_campaigns
+		(name, template_name, lang, vars, filter, status, scheduled_at, rate_per_minute, run_id, total,
+		 created_by, created_by_name, create_time, update_time)
+		VALUES (:name, :template_name, :lang, :vars, :filter, :status, :scheduled_at, :rate_per_minute, :run_id, :total,
+		 :created_by, :created_by_name, :create_time, :update_time)`, c)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	c.ID = uint64(id)
+	return nil
+}
+
+// GetEmailCampaignByID 获取群发任务
+func GetEmailCampaignByID(id uint64) (*EmailCampaign, error) {
+	var c EmailCampaign
+	if err := db.DB.Get(&c, "SELECT * FROM email_campaigns WHERE id = ?", id); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// EmailCampaignQuery 群发任务查询参数
+type EmailCampaignQuery struct {
+	Page     int    `form:"page" json:"page"`
+	PageSize int    `form:"page_size" json:"page_size"`
+	Name     string `form:"name" json:"name"`
+	Status   string `form:"status" json:"status"`
+}
+
+// GetEmailCampaignList 分页查询群发任务
+func GetEmailCampaignList(q *EmailCampaignQuery) ([]EmailCampaign, int64, error) {
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	if q.Name != "" {
+		where += " AND name LIKE ?"
+		args = append(args, "%"+strings.TrimSpace(q.Name)+"%")
+	}
+	if q.Status != "" {
+		where += " AND status = ?"
+		args = append(args, q.Status)
+	}
+
+	var total int64
+	if err := db.DB.Get(&total, "SELECT COUNT(*) FROM email_campaigns "+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	list := []EmailCampaign{}
+	args = append(args, q.PageSize, (q.Page-1)*q.PageSize)
+	err := db.DB.Select(&list, "SELECT * FROM email_campaigns "+where+" ORDER BY id DESC LIMIT ? OFFSET ?", args...)
+	return list, total, err
+}
+
+// StartEmailCampaign 计划时间到达，记录收件人范围并进入发送中；任务状态或运行序号已变化时返回 false
+func StartEmailCampaign(id uint64, runID int, maxUserID uint64, total int64) (bool, error) {
+	now := time.Now().Unix()
+	result, err := db.DB.Exec(`UPDATE email_campaigns SET status = ?, max_user_id = ?, total = ?, start_time = ?, update_time = ?
+		WHERE id = ? AND run_id = ? AND status = ?`,
+		EmailCampaignRunning, maxUserID, total, now, now, id, runID, EmailCampaignScheduled)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// IsEmailCampaignActive 任务是否仍在发送中且未被恢复为新的运行序号
+func IsEmailCampaignActive(id uint64, runID int) (bool, error) {
+	var n int
+	err := db.DB.Get(&n, "SELECT COUNT(*) FROM email_campaigns WHERE id = ? AND run_id = ? AND status = ?", id, runID, EmailCampaignRunning)
+	return n > 0, err
+}
+
+// AdvanceEmailCampaign 记录一个收件人的处理结果并前移进度；result 为 EmailLogSuccess / EmailLogFailed / EmailLogSkipped
+func AdvanceEmailCampaign(id, userID uint64, result int) error {
+	column := "failed"
+	switch result {
+	case EmailLogSuccess:
+		column = "sent"
+	case EmailLogSkipped:
+		column = "skipped"
+	}
+	_, err := db.DB.Exec("UPDATE email_campaigns SET last_user_id = ?, "+column+" = "+column+" + 1, update_time = ? WHERE id = ? AND last_user_id < ?",
+		userID, time.Now().Unix(), id, userID)
+	return err
+}
+
+// FinishEmailCampaign 全部收件人处理完毕
+func FinishEmailCampaign(id uint64, runID int) (bool, error) {
+	now := time.Now().Unix()
+	result, err := db.DB.Exec("UPDATE email_campaigns SET status = ?, finish_time = ?, update_time = ? WHERE id = ? AND run_id = ? AND status = ?",
+		EmailCampaignCompleted, now, now, id, runID, EmailCampaignRunning)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// PauseEmailCampaign 暂停等待中或发送中的任务
+func PauseEmailCampaign(id uint64) (bool, error) {
+	result, err := db.DB.Exec("UPDATE email_campaigns SET status = ?, update_time = ? WHERE id = ? AND status IN (?, ?)",
+		EmailCampaignPaused, time.Now().Unix(), id, EmailCampaignScheduled, EmailCampaignRunning)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// ResumeEmailCampaign 恢复已暂停的任务：已开始的回到发送中，未开始的回到等待中，并递增运行序号
+func ResumeEmailCampaign(id uint64) (bool, error) {
+	result, err := db.DB.Exec(`UPDATE email_campaigns SET status = IF(start_time > 0, ?, ?), run_id = run_id + 1, update_time = ?
+		WHERE id = ? AND status = ?`,
+		EmailCampaignRunning, EmailCampaignScheduled, time.Now().Unix(), id, EmailCampaignPaused)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// CancelEmailCampaign 取消未结束的任务，已发送的邮件不受影响
+func CancelEmailCampaign(id uint64) (bool, error) {
+	now := time.Now().Unix()
+	result, err := db.DB.Exec("UPDATE email_campaigns SET status = ?, finish_time = ?, update_time = ? WHERE id = ? AND status IN (?, ?, ?)",
+		EmailCampaignCancelled, now, now, id, EmailCampaignScheduled, EmailCampaignRunning, EmailCampaignPaused)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestEmailCampaignFilterWhere 测试群发筛选条件生成的 SQL 与参数
+func TestEmailCampaignFilterWhere(t *testing.T) {
+	status := uint8(1)
+	tests := []struct {
+		name      string
+		filter    EmailCampaignFilter
+		after     uint64
+		max       uint64
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "不限条件",
+			wantWhere: "WHERE delete_time IS NULL AND email != '' AND id > ?",
+			wantArgs:  []interface{}{uint64(0)},
+		},
+		{
+			name:      "限制用户 ID 范围",
+			after:     10,
+			max:       20,
+			wantWhere: "WHERE delete_time IS NULL AND email != '' AND id > ? AND id <= ?",
+			wantArgs:  []interface{}{uint64(10), uint64(20)},
+		},
+		{
+			name: "全部条件",
+			filter: EmailCampaignFilter{
+				Status:         &status,
+				Levels:         []uint64{1, 2},
+				GroupIDs:       []uint64{3},
+				Role:           "user",
+				RegisteredFrom: 100,
+				RegisteredTo:   200,
+			},
+			wantWhere: "WHERE delete_time IS NULL AND email != '' AND id > ? AND status = ? AND level IN (?, ?) AND group_id IN (?)" +
+				" AND role = ? AND COALESCE(create_time, join_time) >= ? AND COALESCE(create_time, join_time) <= ?",
+			wantArgs: []interface{}{uint64(0), uint8(1), uint64(1), uint64(2), uint64(3), "user", int64(100), int64(200)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args, err := tt.filter.where(tt.after, tt.max)
+			if err != nil {
+				t.Fatalf("where() error = %v", err)
+			}
+			if where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
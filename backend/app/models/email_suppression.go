@@ -0,0 +1,182 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fst/backend/internal/db"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// 抑制原因
+const (
+	SuppressionHardBounce = "hard_bounce" // 永久退信
+	SuppressionSoftBounce = "soft_bounce" // 临时退信累计达到 SoftBounceSuppressLimit 次
+	SuppressionComplaint  = "complaint"   // 垃圾邮件投诉
+)
+
+// SoftBounceSuppressLimit 临时退信累计次数达到此值后抑制该地址
+const SoftBounceSuppressLimit = 3
+
+// EmailSuppression 邮件抑制记录，每个地址一条；Suppressed 为 true 时不再向该地址发送邮件
+type EmailSuppression struct {
+	ID          uint64 `db:"id" json:"id"`
+	Email       string `db:"email" json:"email"`
+	Reason      string `db:"reason" json:"reason"`
+	Source      string `db:"source" json:"source"` // 回调的服务商
+	Detail      string `db:"detail" json:"detail"` // 最近一次的诊断信息
+	BounceCount int    `db:"bounce_count" json:"bounce_count"`
+	Suppressed  bool   `db:"suppressed" json:"suppressed"`
+	CreateTime  int64  `db:"create_time" json:"create_time"`
+	UpdateTime  int64  `db:"update_time" json:"update_time"`
+}
+
+// InitEmailSuppressionsTable 初始化邮件抑制表
+func InitEmailSuppressionsTable() {
+	if db.CheckTableExists("email_suppressions") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS email_suppressions (
+		id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		email        VARCHAR(255) NOT NULL COMMENT '邮箱地址（小写）',
+		reason       VARCHAR(20)  NOT NULL DEFAULT '' COMMENT '原因:hard_bounce/soft_bounce/complaint',
+		source       VARCHAR(50)  NOT NULL DEFAULT '' COMMENT '来源服务商',
+		detail       VARCHAR(500) NOT NULL DEFAULT '' COMMENT '最近一次的诊断信息',
+		bounce_count INT          NOT NULL DEFAULT 0 COMMENT '退信与投诉累计次数',
+		suppressed   TINYINT(1)   NOT NULL DEFAULT 0 COMMENT '是否抑制:1=不再发送,0=仅记录',
+		create_time  BIGINT       NOT NULL DEFAULT 0 COMMENT '创建时间',
+		update_time  BIGINT       NOT NULL DEFAULT 0 COMMENT '更新时间',
+		UNIQUE KEY idx_email (email),
+		KEY idx_suppressed (suppressed, update_time)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='邮件抑制表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create email_suppressions table: %v", err)
+	} else {
+		log.Println("[Init] Created email_suppressions table")
+	}
+}
+
+// NormalizeSuppressionEmail 抑制记录使用的地址形式（去空白、小写）
+func NormalizeSuppressionEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// RecordEmailFeedback 记录一次退信或投诉：永久退信与投诉立即抑制，临时退信累计达到上限后抑制
+// 已抑制的地址再收到临时退信时保留原有原因
+func RecordEmailFeedback(email, reason, source, detail string) (*EmailSuppression, error) {
+	email = NormalizeSuppressionEmail(email)
+	if len(detail) > 500 {
+		detail = detail[:500]
+	}
+	now := time.Now().Unix()
+
+	tx, err := db.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var s EmailSuppression
+	err = tx.Get(&s, "SELECT * FROM email_suppressions WHERE email = ? FOR UPDATE", email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		s = EmailSuppression{Email: email, CreateTime: now}
+	case err != nil:
+		return nil, err
+	}
+
+	s.BounceCount++
+	s.Source, s.Detail, s.UpdateTime = source, detail, now
+	if reason != SuppressionSoftBounce || !s.Suppressed {
+		s.Reason = reason
+	}
+	if reason != SuppressionSoftBounce || s.BounceCount >= SoftBounceSuppressLimit {
+		s.Suppressed = true
+	}
+
+	if s.ID == 0 {
+		result, err := tx.NamedExec(`INSERT INTO email_suppressions
+			(email, reason, source, detail, bounce_count, suppressed, create_time, update_time)
+			VALUES (:email, :reason, :source, :detail, :bounce_count, :suppressed, :create_time, :update_time)`, &s)
+		if err != nil {
+			return nil, err
+		}
+		id, _ := result.LastInsertId()
+		s.ID = uint64(id)
+	} else {
+		_, err := tx.NamedExec(`UPDATE email_suppressions SET reason = :reason, source = :source, detail = :detail,
+			bounce_count = :bounce_count, suppressed = :suppressed, update_time = :update_time WHERE id = :id`, &s)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &s, tx.Commit()
+}
+
+// GetSuppressedEmails 返回给定地址中已被抑制的地址（小写）
+func GetSuppressedEmails(emails []string) ([]string, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	normalized := make([]string, len(emails))
+	for i, email := range emails {
+		normalized[i] = NormalizeSuppressionEmail(email)
+	}
+	query, args, err := sqlx.In("SELECT email FROM email_suppressions WHERE suppressed = 1 AND email IN (?)", normalized)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	err = db.DB.Select(&list, query, args...)
+	return list, err
+}
+
+// EmailSuppressionQuery 抑制记录查询参数
+type EmailSuppressionQuery struct {
+	Page       int    `form:"page" json:"page"`
+	PageSize   int    `form:"page_size" json:"page_size"`
+	Email      string `form:"email" json:"email"`
+	Reason     string `form:"reason" json:"reason"`
+	Suppressed int    `form:"suppressed" json:"suppressed"` // -1=全部, 0=仅记录, 1=已抑制
+}
+
+// GetEmailSuppressionList 分页查询抑制记录
+func GetEmailSuppressionList(q *EmailSuppressionQuery) ([]EmailSuppression, int64, error) {
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	if q.Email != "" {
+		where += " AND email LIKE ?"
+		args = append(args, "%"+NormalizeSuppressionEmail(q.Email)+"%")
+	}
+	if q.Reason != "" {
+		where += " AND reason = ?"
+		args = append(args, q.Reason)
+	}
+	if q.Suppressed >= 0 {
+		where += " AND suppressed = ?"
+		args = append(args, q.Suppressed)
+	}
+
+	var total int64
+	if err := db.DB.Get(&total, "SELECT COUNT(*) FROM email_suppressions "+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	list := []EmailSuppression{}
+	args = append(args, q.PageSize, (q.Page-1)*q.PageSize)
+	err := db.DB.Select(&list, "SELECT * FROM email_suppressions "+where+" ORDER BY update_time DESC, id DESC LIMIT ? OFFSET ?", args...)
+	return list, total, err
+}
+
+// DeleteEmailSuppression 删除抑制记录，该地址恢复发送且退信次数清零
+func DeleteEmailSuppression(id uint64) (bool, error) {
+	result, err := db.DB.Exec("DELETE FROM email_suppressions WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
@@ -0,0 +1,288 @@
+package models
+
+import (
+	"fst/backend/internal/db"
+	"log"
+	"time"
+)
+
+// ========================================
+// 内置邮件模板
+//
+// 内置默认内容单独保存在 email_template_defaults 表中，启动时只在模板未被管理员修改过
+// （当前内容与上次应用的默认内容一致）时才更新为新的默认内容，修改过的模板保持不变，
+// 可通过「重置」手动应用新的默认内容。
+// ========================================
+
+// 内置模板内容
+var (
+	registerCodeZH = `<p style="margin:0 0 16px 0;">您好，感谢您的注册！请使用以下验证码完成验证：</p>` +
+		`{{template "code" .code}}` +
+		`<p style="margin:0 0 8px 0;">⏱ 验证码有效期为 <strong>{{.expire_minutes}} 分钟</strong>，请尽快使用。</p>` +
+		`<p style="margin:0;color:#a0a0b8;font-size:13px;">如果这不是您本人的操作，请忽略此邮件。请勿将验证码透露给任何人。</p>`
+
+	registerCodeEN = `<p style="margin:0 0 16px 0;">Hello! Thank you for signing up. Please use the following code to verify your account:</p>` +
+		`{{template "code" .code}}` +
+		`<p style="margin:0 0 8px 0;">⏱ This code is valid for <strong>{{.expire_minutes}} minutes</strong>.</p>` +
+		`<p style="margin:0;color:#a0a0b8;font-size:13px;">If you did not request this, please ignore this email. Never share your code with anyone.</p>`
+
+	resetPasswordZH = `<p style="margin:0 0 16px 0;">您好，我们收到了您的密码重置请求。请点击下方按钮重置密码：</p>` +
+		`{{template "button" (dict "url" .link "text" "重置密码")}}` +
+		`<p style="margin:0 0 8px 0;">如果按钮无法点击，您也可以使用以下验证码：</p>` +
+		`<div style="text-align:center;margin:20px 0;">` +
+		`<div style="display:inline-block;background:#f0f2f5;font-size:28px;font-weight:700;letter-spacing:6px;padding:14px 36px;border-radius:10px;color:#1a1a2e;border:2px dashed #667eea;">{{.code}}</div>` +
+		`</div>` +
+		`<p style="margin:0 0 8px 0;">⏱ 有效期为 <strong>15 分钟</strong>，请尽快操作。</p>` +
+		`<p style="margin:0;color:#a0a0b8;font-size:13px;">如果这不是您本人的操作，请忽略此邮件，您的密码不会被更改。</p>`
+
+	resetPasswordEN = `<p style="margin:0 0 16px 0;">Hello, we received a request to reset your password. Click the button below to proceed:</p>` +
+		`{{template "button" (dict "url" .link "text" "Reset Password")}}` +
+		`<p style="margin:0 0 8px 0;">If the button doesn't work, you can also use this verification code:</p>` +
+		`<div style="text-align:center;margin:20px 0;">` +
+		`<div style="display:inline-block;background:#f0f2f5;font-size:28px;font-weight:700;letter-spacing:6px;padding:14px 36px;border-radius:10px;color:#1a1a2e;border:2px dashed #667eea;">{{.code}}</div>` +
+		`</div>` +
+		`<p style="margin:0 0 8px 0;">⏱ Valid for <strong>15 minutes</strong>.</p>` +
+		`<p style="margin:0;color:#a0a0b8;font-size:13px;">If you did not request a password reset, please ignore this email. Your password will remain unchanged.</p>`
+)
+
+// builtinEmailTemplates 内置模板
+var builtinEmailTemplates = []EmailTemplate{
+	{
+		Name:        "register_code",
+		Lang:        "zh-CN",
+		Title:       "注册验证码",
+		Subject:     "【{{.app_name}}】注册验证码",
+		Content:     registerCodeZH,
+		Description: "用户注册时发送的验证码",
+		Variables:   "code, app_name, expire_minutes",
+		Status:      1,
+	},
+	{
+		Name:        "register_code",
+		Lang:        "en-US",
+		Title:       "Registration Code",
+		Subject:     "[{{.app_name}}] Registration Code",
+		Content:     registerCodeEN,
+		Description: "Verification code for user registration",
+		Variables:   "code, app_name, expire_minutes",
+		Status:      1,
+	},
+	{
+		Name:        "reset_password",
+		Lang:        "zh-CN",
+		Title:       "密码重置",
+		Subject:     "【{{.app_name}}】密码重置请求",
+		Content:     resetPasswordZH,
+		Description: "用户重置密码时发送的链接和验证码",
+		Variables:   "link, code, app_name",
+		Status:      1,
+	},
+	{
+		Name:        "reset_password",
+		Lang:        "en-US",
+		Title:       "Password Reset",
+		Subject:     "[{{.app_name}}] Password Reset Request",
+		Content:     resetPasswordEN,
+		Description: "Link and code for password reset",
+		Variables:   "link, code, app_name",
+		Status:      1,
+	},
+}
+
+// legacyBuiltinEmailTemplates 改用 html/template 之前（{变量} 占位符）的内置模板内容
+// 旧版本没有 email_template_defaults 记录，且每次启动都会用这些内容覆盖内置模板，
+// 升级时以它们作为上次应用的默认内容，未被修改过的模板可以直接更新为新的默认内容
+var (
+	legacyRegisterCodeZH = `<p style="margin:0 0 16px 0;">您好，感谢您的注册！请使用以下验证码完成验证：</p>` +
+		`<div style="text-align:center;margin:28px 0;">` +
+		`<div style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:32px;font-weight:700;letter-spacing:8px;padding:16px 40px;border-radius:12px;">{code}</div>` +
+		`</div>` +
+		`<p style="margin:0 0 8px 0;">⏱ 验证码有效期为 <strong>{expire_minutes} 分钟</strong>，请尽快使用。</p>` +
+		`<p style="margin:0;color:#a0a0b8;font-size:13px;">如果这不是您本人的操作，请忽略此邮件。请勿将验证码透露给任何人。</p>`
+
+	legacyRegisterCodeEN = `<p style="margin:0 0 16px 0;">Hello! Thank you for signing up. Please use the following code to verify your account:</p>` +
+		`<div style="text-align:center;margin:28px 0;">` +
+		`<div style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:32px;font-weight:700;letter-spacing:8px;padding:16px 40px;border-radius:12px;">{code}</div>` +
+		`</div>` +
+		`<p style="margin:0 0 8px 0;">⏱ This code is valid for <strong>{expire_minutes} minutes</strong>.</p>` +
+		`<p style="margin:0;color:#a0a0b8;font-size:13px;">If you did not request this, please ignore this email. Never share your code with anyone.</p>`
+
+	legacyResetPasswordZH = `<p style="margin:0 0 16px 0;">您好，我们收到了您的密码重置请求。请点击下方按钮重置密码：</p>` +
+		`<div style="text-align:center;margin:28px 0;">` +
+		`<a href="{link}" style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:16px;font-weight:600;text-decoration:none;padding:14px 48px;border-radius:10px;">重置密码</a>` +
+		`</div>` +
+		`<p style="margin:0 0 8px 0;">如果按钮无法点击，您也可以使用以下验证码：</p>` +
+		`<div style="text-align:center;margin:20px 0;">` +
+		`<div style="display:inline-block;background:#f0f2f5;font-size:28px;font-weight:700;letter-spacing:6px;padding:14px 36px;border-radius:10px;color:#1a1a2e;border:2px dashed #667eea;">{code}</div>` +
+		`</div>` +
+		`<p style="margin:0 0 8px 0;">⏱ 有效期为 <strong>15 分钟</strong>，请尽快操作。</p>` +
+		`<p style="margin:0;color:#a0a0b8;font-size:13px;">如果这不是您本人的操作，请忽略此邮件，您的密码不会被更改。</p>`
+
+	legacyResetPasswordEN = `<p style="margin:0 0 16px 0;">Hello, we received a request to reset your password. Click the button below to proceed:</p>` +
+		`<div style="text-align:center;margin:28px 0;">` +
+		`<a href="{link}" style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:16px;font-weight:600;text-decoration:none;padding:14px 48px;border-radius:10px;">Reset Password</a>` +
+		`</div>` +
+		`<p style="margin:0 0 8px 0;">If the button doesn't work, you can also use this verification code:</p>` +
+		`<div style="text-align:center;margin:20px 0;">` +
+		`<div style="display:inline-block;background:#f0f2f5;font-size:28px;font-weight:700;letter-spacing:6px;padding:14px 36px;border-radius:10px;color:#1a1a2e;border:2px dashed #667eea;">{code}</div>` +
+		`</div>` +
+		`<p style="margin:0 0 8px 0;">⏱ Valid for <strong>15 minutes</strong>.</p>` +
+		`<p style="margin:0;color:#a0a0b8;font-size:13px;">If you did not request a password reset, please ignore this email. Your password will remain unchanged.</p>`
+)
+
+var legacyBuiltinEmailTemplates = []EmailTemplateDefault{
+	{Name: "register_code", Lang: "zh-CN", Subject: "【{app_name}】注册验证码", Content: legacyRegisterCodeZH},
+	{Name: "register_code", Lang: "en-US", Subject: "[{app_name}] Registration Code", Content: legacyRegisterCodeEN},
+	{Name: "reset_password", Lang: "zh-CN", Subject: "【{app_name}】密码重置请求", Content: legacyResetPasswordZH},
+	{Name: "reset_password", Lang: "en-US", Subject: "[{app_name}] Password Reset Request", Content: legacyResetPasswordEN},
+}
+
+// legacyBuiltinEmailTemplate 获取旧版本的内置模板内容，不存在时返回 nil
+func legacyBuiltinEmailTemplate(name, lang string) *EmailTemplateDefault {
+	for i := range legacyBuiltinEmailTemplates {
+		if legacyBuiltinEmailTemplates[i].Name == name && legacyBuiltinEmailTemplates[i].Lang == lang {
+			tpl := legacyBuiltinEmailTemplates[i]
+			return &tpl
+		}
+	}
+	return nil
+}
+
+// GetBuiltinEmailTemplate 获取内置模板，不存在时返回 nil
+func GetBuiltinEmailTemplate(name, lang string) *EmailTemplate {
+	for i := range builtinEmailTemplates {
+		if builtinEmailTemplates[i].Name == name && builtinEmailTemplates[i].Lang == lang {
+			tpl := builtinEmailTemplates[i]
+			return &tpl
+		}
+	}
+	return nil
+}
+
+// EmailTemplateDefault 上次应用的内置模板内容
+type EmailTemplateDefault struct {
+	Name       string `db:"name" json:"name"`
+	Lang       string `db:"lang" json:"lang"`
+	Subject    string `db:"subject" json:"subject"`
+	Content    string `db:"content" json:"content"`
+	UpdateTime int64  `db:"update_time" json:"update_time"`
+}
+
+// InitEmailTemplates 初始化邮件模板：建表，创建缺少的内置模板，并更新未被修改过的内置模板
+func InitEmailTemplates() {
+	initEmailTemplateDefaultsTable()
+	InitEmailTemplateRevisionsTable()
+
+	for _, builtin := range builtinEmailTemplates {
+		if err := applyBuiltinEmailTemplate(builtin); err != nil {
+			log.Printf("[Email] 初始化内置模板 %s (%s) 失败: %v", builtin.Name, builtin.Lang, err)
+		}
+	}
+}
+
+func initEmailTemplateDefaultsTable() {
+	if db.CheckTableExists("email_template_defaults") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS email_template_defaults (
+		name        VARCHAR(100) NOT NULL COMMENT '模板标识',
+		lang        VARCHAR(20)  NOT NULL COMMENT '语言',
+		subject     VARCHAR(255) NOT NULL DEFAULT '' COMMENT '默认主题',
+		content     TEXT NOT NULL COMMENT '默认内容',
+		update_time BIGINT       NOT NULL DEFAULT 0 COMMENT '更新时间',
+		PRIMARY KEY (name, lang)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='内置邮件模板默认内容表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create email_template_defaults table: %v", err)
+	} else {
+		log.Println("[Init] Created email_template_defaults table")
+	}
+}
+
+// builtinAction 内置模板的处理方式
+type builtinAction int
+
+const (
+	builtinKeep    builtinAction = iota // 保持不变（已是最新默认内容）
+	builtinApply                        // 未被修改过，更新为新的默认内容
+	builtinSkipped                      // 已被管理员修改，保留管理员的版本
+)
+
+// decideBuiltinAction 判断已存在的内置模板如何处理；previous 为上次应用的默认内容。
+// 从旧版本升级时没有记录，previous 为旧版本的内置内容（见 legacyBuiltinEmailTemplates）；
+// 仍为 nil 时无从比较，与内置内容不同即视为被修改过
+func decideBuiltinAction(current *EmailTemplate, previous *EmailTemplateDefault, builtin EmailTemplate) builtinAction {
+	if current.Subject == builtin.Subject && current.Content == builtin.Content {
+		return builtinKeep
+	}
+	if previous != nil && current.Subject == previous.Subject && current.Content == previous.Content {
+		return builtinApply
+	}
+	return builtinSkipped
+}
+
+func applyBuiltinEmailTemplate(builtin EmailTemplate) error {
+	current, err := GetEmailTemplateByNameLang(builtin.Name, builtin.Lang)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		if err := CreateEmailTemplate(&builtin); err != nil {
+			return err
+		}
+		if current, err = GetEmailTemplateByNameLang(builtin.Name, builtin.Lang); err != nil || current == nil {
+			return err
+		}
+		if _, err := CreateEmailTemplateRevision(NewEmailTemplateRevision(current, EmailRevisionBuiltin, 0, "system")); err != nil {
+			return err
+		}
+		return saveEmailTemplateDefault(builtin)
+	}
+
+	// 保证已有模板至少有一个版本，后续修改可以对比与回滚
+	if err := EnsureEmailTemplateBaseline(current); err != nil {
+		return err
+	}
+
+	previous, err := getEmailTemplateDefault(builtin.Name, builtin.Lang)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		previous = legacyBuiltinEmailTemplate(builtin.Name, builtin.Lang)
+	}
+	switch decideBuiltinAction(current, previous, builtin) {
+	case builtinApply:
+		if err := UpdateEmailTemplate(current.ID, builtin.Subject, builtin.Content, current.Description, current.Status); err != nil {
+			return err
+		}
+		current.Subject, current.Content = builtin.Subject, builtin.Content
+		if _, err := CreateEmailTemplateRevision(NewEmailTemplateRevision(current, EmailRevisionBuiltin, 0, "system")); err != nil {
+			return err
+		}
+		log.Printf("[Email] 内置模板 %s (%s) 已更新为新的默认内容", builtin.Name, builtin.Lang)
+	case builtinSkipped:
+		if previous == nil || previous.Subject != builtin.Subject || previous.Content != builtin.Content {
+			log.Printf("[Email] 模板 %s (%s) 已被修改，保留当前内容（可通过重置应用新的默认内容）", builtin.Name, builtin.Lang)
+		}
+	}
+	return saveEmailTemplateDefault(builtin)
+}
+
+func getEmailTemplateDefault(name, lang string) (*EmailTemplateDefault, error) {
+	var items []EmailTemplateDefault
+	err := db.DB.Select(&items, "SELECT name, lang, subject, content, update_time FROM email_template_defaults WHERE name = ? AND lang = ?", name, lang)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+func saveEmailTemplateDefault(tpl EmailTemplate) error {
+	_, err := db.DB.Exec(`
+		INSERT INTO email_template_defaults (name, lang, subject, content, update_time) VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE subject = VALUES(subject), content = VALUES(content), update_time = VALUES(update_time)`,
+		tpl.Name, tpl.Lang, tpl.Subject, tpl.Content, time.Now().Unix())
+	return err
+}
@@ -0,0 +1,65 @@
+package models
+
+import "testing"
+
+// TestDecideBuiltinAction 测试内置模板只在未被修改时才更新
+func TestDecideBuiltinAction(t *testing.T) {
+	builtin := EmailTemplate{Subject: "新主题", Content: "新内容"}
+	previous := &EmailTemplateDefault{Subject: "旧主题", Content: "旧内容"}
+
+	tests := []struct {
+		name     string
+		current  EmailTemplate
+		previous *EmailTemplateDefault
+		want     builtinAction
+	}{
+		{"已是最新默认内容", EmailTemplate{Subject: "新主题", Content: "新内容"}, previous, builtinKeep},
+		{"未修改过，应用新默认内容", EmailTemplate{Subject: "旧主题", Content: "旧内容"}, previous, builtinApply},
+		{"内容被修改，保留", EmailTemplate{Subject: "旧主题", Content: "管理员内容"}, previous, builtinSkipped},
+		{"主题被修改，保留", EmailTemplate{Subject: "管理员主题", Content: "旧内容"}, previous, builtinSkipped},
+		{"旧版本升级，内容一致", EmailTemplate{Subject: "新主题", Content: "新内容"}, nil, builtinKeep},
+		{"旧版本升级，内容被修改", EmailTemplate{Subject: "新主题", Content: "管理员内容"}, nil, builtinSkipped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := tt.current
+			if got := decideBuiltinAction(&current, tt.previous, builtin); got != tt.want {
+				t.Errorf("decideBuiltinAction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetBuiltinEmailTemplate 测试获取内置模板返回副本
+func TestGetBuiltinEmailTemplate(t *testing.T) {
+	tpl := GetBuiltinEmailTemplate("register_code", "zh-CN")
+	if tpl == nil || tpl.Content != registerCodeZH {
+		t.Fatalf("GetBuiltinEmailTemplate() = %v", tpl)
+	}
+	tpl.Content = "changed"
+	if GetBuiltinEmailTemplate("register_code", "zh-CN").Content != registerCodeZH {
+		t.Error("修改返回值不应影响内置模板")
+	}
+	if GetBuiltinEmailTemplate("missing", "zh-CN") != nil {
+		t.Error("不存在的模板应返回 nil")
+	}
+}
+
+// TestLegacyBuiltinUpgrade 测试从旧版本升级时，未修改的内置模板按旧版本内容识别并更新
+func TestLegacyBuiltinUpgrade(t *testing.T) {
+	builtin := *GetBuiltinEmailTemplate("register_code", "zh-CN")
+	legacy := legacyBuiltinEmailTemplate("register_code", "zh-CN")
+	if legacy == nil {
+		t.Fatal("legacyBuiltinEmailTemplate() = nil")
+	}
+
+	unchanged := EmailTemplate{Subject: legacy.Subject, Content: legacy.Content}
+	if got := decideBuiltinAction(&unchanged, legacy, builtin); got != builtinApply {
+		t.Errorf("未修改的旧版本模板 = %v, want builtinApply", got)
+	}
+	modified := EmailTemplate{Subject: legacy.Subject, Content: legacy.Content + "<p>管理员追加</p>"}
+	if got := decideBuiltinAction(&modified, legacy, builtin); got != builtinSkipped {
+		t.Errorf("修改过的旧版本模板 = %v, want builtinSkipped", got)
+	}
+}
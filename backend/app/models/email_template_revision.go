@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fst/backend/internal/db"
+	"log"
+	"time"
+)
+
+// 模板版本来源
+const (
+	EmailRevisionInitial  = "initial"  // 启用版本记录前已有的内容
+	EmailRevisionBuiltin  = "builtin"  // 应用内置默认内容
+	EmailRevisionEdit     = "edit"     // 管理员编辑
+	EmailRevisionReset    = "reset"    // 重置为内置默认内容
+	EmailRevisionRollback = "rollback" // 回滚到历史版本
+)
+
+// EmailTemplateRevision 邮件模板版本，每次修改模板都会新增一个版本
+type EmailTemplateRevision struct {
+	ID          uint64 `db:"id" json:"id"`
+	TemplateID  uint64 `db:"template_id" json:"template_id"`
+	Version     int    `db:"version" json:"version"`
+	Subject     string `db:"subject" json:"subject"`
+	Content     string `db:"content" json:"content,omitempty"`
+	Description string `db:"description" json:"description"`
+	Status      uint8  `db:"status" json:"status"`
+	Source      string `db:"source" json:"source"`
+	SourceRef   int    `db:"source_ref" json:"source_ref"` // 回滚时为来源版本号
+	AuthorID    uint64 `db:"author_id" json:"author_id"`   // 0 表示系统
+	AuthorName  string `db:"author_name" json:"author_name"`
+	CreateTime  int64  `db:"create_time" json:"create_time"`
+}
+
+// InitEmailTemplateRevisionsTable 初始化邮件模板版本表
+func InitEmailTemplateRevisionsTable() {
+	if db.CheckTableExists("email_template_revisions") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS email_template_revisions (
+		id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		template_id BIGINT UNSIGNED  NOT NULL COMMENT '模板ID',
+		version     INT              NOT NULL COMMENT '版本号，按模板递增',
+		subject     VARCHAR(255)     NOT NULL DEFAULT '' COMMENT '邮件主题',
+		content     TEXT NOT NULL COMMENT '邮件内容',
+		description VARCHAR(255)     NOT NULL DEFAULT '' COMMENT '描述',
+		status      TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '状态:1=启用,0=禁用',
+		source      VARCHAR(20)      NOT NULL DEFAULT '' COMMENT '来源:initial/builtin/edit/reset/rollback',
+		source_ref  INT              NOT NULL DEFAULT 0 COMMENT '回滚来源版本号',
+		author_id   BIGINT UNSIGNED  NOT NULL DEFAULT 0 COMMENT '修改人ID，0=系统',
+		author_name VARCHAR(100)     NOT NULL DEFAULT '' COMMENT '修改人',
+		create_time BIGINT           NOT NULL DEFAULT 0 COMMENT '创建时间',
+		UNIQUE KEY idx_template_version (template_id, version)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='邮件模板版本表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create email_template_revisions table: %v", err)
+	} else {
+		log.Println("[Init] Created email_template_revisions table")
+	}
+}
+
+// NewEmailTemplateRevision 以模板当前内容生成版本记录
+func NewEmailTemplateRevision(tpl *EmailTemplate, source string, authorID uint64, authorName string) *EmailTemplateRevision {
+	return &EmailTemplateRevision{
+		TemplateID:  tpl.ID,
+		Subject:     tpl.Subject,
+		Content:     tpl.Content,
+		Description: tpl.Description,
+		Status:      tpl.Status,
+		Source:      source,
+		AuthorID:    authorID,
+		AuthorName:  authorName,
+	}
+}
+
+// CreateEmailTemplateRevision 新增版本，版本号为该模板当前最大版本号 + 1
+func CreateEmailTemplateRevision(rev *EmailTemplateRevision) (*EmailTemplateRevision, error) {
+	rev.CreateTime = time.Now().Unix()
+	result, err := db.DB.Exec(`
+		INSERT INTO email_template_revisions
+			(template_id, version, subject, content, description, status, source, source_ref, author_id, author_name, create_time)
+		SELECT ?, COALESCE(MAX(version), 0) + 1, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		FROM email_template_revisions WHERE template_id = ?`,
+		rev.TemplateID, rev.Subject, rev.Content, rev.Description, rev.Status, rev.Source, rev.SourceRef,
+		rev.AuthorID, rev.AuthorName, rev.CreateTime, rev.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := result.LastInsertId()
+	rev.ID = uint64(id)
+	if err := db.DB.Get(&rev.Version, "SELECT version FROM email_template_revisions WHERE id = ?", rev.ID); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// EnsureEmailTemplateBaseline 模板还没有版本时，把当前内容记录为第一个版本
+func EnsureEmailTemplateBaseline(tpl *EmailTemplate) error {
+	var count int
+	if err := db.DB.Get(&count, "SELECT COUNT(*) FROM email_template_revisions WHERE template_id = ?", tpl.ID); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err := CreateEmailTemplateRevision(NewEmailTemplateRevision(tpl, EmailRevisionInitial, 0, "system"))
+	return err
+}
+
+// GetEmailTemplateRevisions 模板的版本列表（不含内容），新版本在前
+func GetEmailTemplateRevisions(templateID uint64) ([]EmailTemplateRevision, error) {
+	revisions := []EmailTemplateRevision{}
+	err := db.DB.Select(&revisions, `
+		SELECT id, template_id, version, subject, '' AS content, description, status, source, source_ref, author_id, author_name, create_time
+		FROM email_template_revisions WHERE template_id = ? ORDER BY version DESC`, templateID)
+	return revisions, err
+}
+
+// GetEmailTemplateRevision 获取模板的指定版本
+func GetEmailTemplateRevision(templateID uint64, version int) (*EmailTemplateRevision, error) {
+	var rev EmailTemplateRevision
+	err := db.DB.Get(&rev, "SELECT * FROM email_template_revisions WHERE template_id = ? AND version = ?", templateID, version)
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
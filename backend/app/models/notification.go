@@ -0,0 +1,171 @@
+package models
+
+import (
+	"fst/backend/internal/db"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// 站内通知类型
+const (
+	NotificationSystem   = "system"   // 管理员广播
+	NotificationRecharge = "recharge" // 充值到账
+	NotificationBalance  = "balance"  // 管理员调整余额
+	NotificationLogin    = "login"    // 新设备登录
+	NotificationPassword = "password" // 密码已修改
+)
+
+// Notification 站内通知
+//
+// Title 与 Content 与余额日志的备注相同：多语言时为 {"zhCN":"...","enUS":"..."} JSON 字符串（见 utils.BuildMemo），
+// 否则为纯文本，由 utils.ParseMemo 或前端 parseMemo 按语言取出。
+type Notification struct {
+	ID         uint64 `db:"id" json:"id"`
+	UserID     uint64 `db:"user_id" json:"user_id"`
+	Type       string `db:"type" json:"type"`
+	Title      string `db:"title" json:"title"`
+	Content    string `db:"content" json:"content"`
+	Link       string `db:"link" json:"link"`
+	IsRead     bool   `db:"is_read" json:"is_read"`
+	ReadTime   int64  `db:"read_time" json:"read_time"`
+	CreateTime int64  `db:"create_time" json:"create_time"`
+}
+
+// InitNotificationsTable 初始化站内通知表
+func InitNotificationsTable() {
+	if db.CheckTableExists("notifications") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS notifications (
+		id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		user_id     BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+		type        VARCHAR(50)  NOT NULL DEFAULT 'system' COMMENT '类型:system/recharge/balance/login/password',
+		title       TEXT         NOT NULL COMMENT '标题(多语言JSON或纯文本)',
+		content     TEXT         NOT NULL COMMENT '内容(多语言JSON或纯文本)',
+		link        VARCHAR(255) NOT NULL DEFAULT '' COMMENT '跳转链接',
+		is_read     TINYINT(1)   NOT NULL DEFAULT 0 COMMENT '是否已读',
+		read_time   BIGINT       NOT NULL DEFAULT 0 COMMENT '阅读时间',
+		create_time BIGINT       NOT NULL DEFAULT 0 COMMENT '创建时间',
+		KEY idx_user_read (user_id, is_read, id),
+		KEY idx_create_time (create_time)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='站内通知表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create notifications table: %v", err)
+	} else {
+		log.Println("[Init] Created notifications table")
+	}
+}
+
+// CreateNotification 创建一条通知
+func CreateNotification(n *Notification) error {
+	n.CreateTime = time.Now().Unix()
+	result, err := db.DB.NamedExec(`INSERT INTO notifications (user_id, type, title, content, link, create_time)
+		VALUES (:user_id, :type, :title, :content, :link, :create_time)`, n)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	n.ID = uint64(id)
+	return nil
+}
+
+// BroadcastNotification 向指定用户（为空时为全部未删除用户）各写入一条通知，返回写入条数
+func BroadcastNotification(userIDs []uint64, typ, title, content, link string) (int64, error) {
+	query := `INSERT INTO notifications (user_id, type, title, content, link, create_time)
+		SELECT id, ?, ?, ?, ?, ? FROM users WHERE delete_time IS NULL`
+	args := []interface{}{typ, title, content, link, time.Now().Unix()}
+	if len(userIDs) > 0 {
+		var err error
+		query, args, err = sqlx.In(query+" AND id IN (?)", append(args, userIDs)...)
+		if err != nil {
+			return 0, err
+		}
+	}
+	result, err := db.DB.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// NotificationQuery 通知查询参数
+type NotificationQuery struct {
+	Page     int    `form:"page" json:"page"`
+	PageSize int    `form:"page_size" json:"page_size"`
+	Type     string `form:"type" json:"type"`
+	IsRead   int    `form:"is_read" json:"is_read"` // -1=全部, 0=未读, 1=已读
+	Lang     string `form:"lang" json:"lang"`       // 非空时按该语言返回标题与内容，如 zhCN、enUS
+}
+
+// GetUserNotifications 分页查询用户的通知，按时间倒序
+func GetUserNotifications(userID uint64, q *NotificationQuery) ([]Notification, int64, error) {
+	where := "WHERE user_id = ?"
+	args := []interface{}{userID}
+	if q.Type != "" {
+		where += " AND type = ?"
+		args = append(args, q.Type)
+	}
+	if q.IsRead >= 0 {
+		where += " AND is_read = ?"
+		args = append(args, q.IsRead)
+	}
+
+	var total int64
+	if err := db.DB.Get(&total, "SELECT COUNT(*) FROM notifications "+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	list := []Notification{}
+	args = append(args, q.PageSize, (q.Page-1)*q.PageSize)
+	err := db.DB.Select(&list, "SELECT * FROM notifications "+where+" ORDER BY id DESC LIMIT ? OFFSET ?", args...)
+	return list, total, err
+}
+
+// CountUnreadNotifications 用户的未读通知数
+func CountUnreadNotifications(userID uint64) (int64, error) {
+	var count int64
+	err := db.DB.Get(&count, "SELECT COUNT(*) FROM notifications WHERE user_id = ? AND is_read = 0", userID)
+	return count, err
+}
+
+// MarkNotificationRead 将用户的一条通知标记为已读，通知不存在时返回 false
+func MarkNotificationRead(userID, id uint64) (bool, error) {
+	if _, err := db.DB.Exec("UPDATE notifications SET is_read = 1, read_time = ? WHERE id = ? AND user_id = ? AND is_read = 0",
+		time.Now().Unix(), id, userID); err != nil {
+		return false, err
+	}
+	var n int
+	err := db.DB.Get(&n, "SELECT COUNT(*) FROM notifications WHERE id = ? AND user_id = ?", id, userID)
+	return n > 0, err
+}
+
+// MarkAllNotificationsRead 将用户的全部未读通知标记为已读，返回标记条数
+func MarkAllNotificationsRead(userID uint64) (int64, error) {
+	result, err := db.DB.Exec("UPDATE notifications SET is_read = 1, read_time = ? WHERE user_id = ? AND is_read = 0",
+		time.Now().Unix(), userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteNotification 删除用户的一条通知
+func DeleteNotification(userID, id uint64) (bool, error) {
+	result, err := db.DB.Exec("DELETE FROM notifications WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// DeleteReadNotificationsBefore 删除指定时间之前的已读通知
+func DeleteReadNotificationsBefore(before int64) (int64, error) {
+	result, err := db.DB.Exec("DELETE FROM notifications WHERE is_read = 1 AND create_time < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
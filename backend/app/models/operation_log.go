@@ -119,8 +119,10 @@ func CreateOperationLog(log *OperationLog) error {
 			  VALUES (:user_id, :username, :module, :action, :method, :path, :ip,
 			  :user_agent, :request_body, :response_body, :status_code, :duration, :create_time)`
 
-	now := time.Now().Unix()
-	log.CreateTime = &now
+	if log.CreateTime == nil {
+		now := time.Now().Unix()
+		log.CreateTime = &now
+	}
 
 	result, err := db.DB.NamedExec(query, log)
 	if err != nil {
@@ -1,7 +1,9 @@
 package models
 
 import (
+	"fmt"
 	"fst/backend/internal/db"
+	"fst/backend/internal/secrets"
 	"log"
 	"time"
 )
@@ -39,6 +41,8 @@ type PayGateway struct {
 	NotifyURL   string  `db:"notify_url" json:"notify_url"`     // 自定义回调地址（留空用全局）
 	CreateTime  int64   `db:"create_time" json:"create_time"`
 	UpdateTime  int64   `db:"update_time" json:"update_time"`
+
+	sealedKey string // 无法解密的商户密钥密文，仅用于更新时原样写回
 }
 
 // InitPayGatewaysTable 初始化支付通道表
@@ -83,6 +87,10 @@ func InitPayGatewaysTable() {
 
 // CreatePayGateway 创建支付通道
 func CreatePayGateway(gw *PayGateway) error {
+	key, err := secrets.Encrypt(gw.Key)
+	if err != nil {
+		return fmt.Errorf("encrypt gateway key: %w", err)
+	}
 	now := time.Now().Unix()
 	gw.CreateTime = now
 	gw.UpdateTime = now
@@ -90,7 +98,7 @@ func CreatePayGateway(gw *PayGateway) error {
 	result, err := db.DB.Exec(
 		"INSERT INTO pay_gateways (name, type, pay_type, description, status, api_url, pid, `key`, logo_url, sort_order, min_amount, max_amount, fee_rate, fee_mode, min_level, notify_url, create_time, update_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		gw.Name, gw.Type, gw.PayType, gw.Description, gw.Status,
-		gw.ApiURL, gw.PID, gw.Key, gw.LogoURL, gw.SortOrder,
+		gw.ApiURL, gw.PID, key, gw.LogoURL, gw.SortOrder,
 		gw.MinAmount, gw.MaxAmount, gw.FeeRate, gw.FeeMode,
 		gw.MinLevel, gw.NotifyURL, gw.CreateTime, gw.UpdateTime,
 	)
@@ -109,16 +117,25 @@ func GetPayGatewayByID(id uint64) (*PayGateway, error) {
 	if err != nil {
 		return nil, err
 	}
+	gw.decryptKey()
 	return &gw, nil
 }
 
 // UpdatePayGateway 更新支付通道
 func UpdatePayGateway(gw *PayGateway) error {
+	key, err := secrets.Encrypt(gw.Key)
+	if err != nil {
+		return fmt.Errorf("encrypt gateway key: %w", err)
+	}
+	if key == "" && gw.sealedKey != "" {
+		// 密钥未能解密且本次未重新设置，保留原密文
+		key = gw.sealedKey
+	}
 	gw.UpdateTime = time.Now().Unix()
-	_, err := db.DB.Exec(
+	_, err = db.DB.Exec(
 		"UPDATE pay_gateways SET name=?, type=?, pay_type=?, description=?, status=?, api_url=?, pid=?, `key`=?, logo_url=?, sort_order=?, min_amount=?, max_amount=?, fee_rate=?, fee_mode=?, min_level=?, notify_url=?, update_time=? WHERE id=?",
 		gw.Name, gw.Type, gw.PayType, gw.Description, gw.Status,
-		gw.ApiURL, gw.PID, gw.Key, gw.LogoURL, gw.SortOrder,
+		gw.ApiURL, gw.PID, key, gw.LogoURL, gw.SortOrder,
 		gw.MinAmount, gw.MaxAmount, gw.FeeRate, gw.FeeMode,
 		gw.MinLevel, gw.NotifyURL, gw.UpdateTime, gw.ID,
 	)
@@ -165,6 +182,9 @@ func GetPayGatewayList(page, pageSize int, keyword string, onlyEnabled bool) ([]
 	if err != nil {
 		return nil, 0, err
 	}
+	for i := range gateways {
+		gateways[i].decryptKey()
+	}
 
 	return gateways, total, nil
 }
@@ -176,5 +196,80 @@ func GetEnabledPayGateways() ([]PayGateway, error) {
 	if err != nil {
 		return nil, err
 	}
+	for i := range gateways {
+		gateways[i].decryptKey()
+	}
 	return gateways, nil
 }
+
+// decryptKey 解密商户密钥；解密失败时清空 Key，密文仅保存在 sealedKey 中，
+// 避免密文被当作商户密钥参与签名，也避免未修改密钥的更新把密文覆盖为空
+func (gw *PayGateway) decryptKey() {
+	if !secrets.IsEncrypted(gw.Key) {
+		return
+	}
+	plain, err := secrets.Decrypt(gw.Key)
+	if err != nil {
+		log.Printf("[PayGateway] Failed to decrypt key of gateway %d: %v", gw.ID, err)
+		gw.sealedKey = gw.Key
+		gw.Key = ""
+		return
+	}
+	gw.Key = plain
+}
+
+// FindPlaintextPayGatewayKeys 返回商户密钥仍以明文存储的通道ID
+func FindPlaintextPayGatewayKeys() ([]uint64, error) {
+	var rows []struct {
+		ID  uint64 `db:"id"`
+		Key string `db:"key"`
+	}
+	if err := db.DB.Select(&rows, "SELECT id, `key` FROM pay_gateways"); err != nil {
+		return nil, err
+	}
+	var ids []uint64
+	for _, r := range rows {
+		if r.Key != "" && !secrets.IsEncrypted(r.Key) {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids, nil
+}
+
+// ReencryptPayGatewayKeys 使用当前主密钥重新加密所有商户密钥（含明文），返回更新的行数
+func ReencryptPayGatewayKeys() (int, error) {
+	tx, err := db.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var rows []struct {
+		ID  uint64 `db:"id"`
+		Key string `db:"key"`
+	}
+	if err := tx.Select(&rows, "SELECT id, `key` FROM pay_gateways FOR UPDATE"); err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, r := range rows {
+		if r.Key == "" || secrets.IsCurrent(r.Key) {
+			continue
+		}
+		plain, err := secrets.Decrypt(r.Key)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt key of gateway %d: %w", r.ID, err)
+		}
+		enc, err := secrets.Encrypt(plain)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE pay_gateways SET `key` = ? WHERE id = ?", enc, r.ID); err != nil {
+			return 0, err
+		}
+		updated++
+	}
+
+	return updated, tx.Commit()
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"database/sql"
+	"fst/backend/internal/db"
+	"log"
+	"time"
+)
+
+// PluginState 插件启用状态（未记录的插件默认启用）
+type PluginState struct {
+	Name       string `db:"name" json:"name"`
+	Enabled    bool   `db:"enabled" json:"enabled"`
+	UpdateTime int64  `db:"update_time" json:"update_time"`
+}
+
+// InitPluginStatesTable 初始化插件状态表
+func InitPluginStatesTable() {
+	if db.CheckTableExists("plugin_states") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS plugin_states (
+		name        VARCHAR(100)     NOT NULL PRIMARY KEY COMMENT '插件名称',
+		enabled     TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '是否启用',
+		update_time BIGINT           NOT NULL DEFAULT 0 COMMENT '更新时间'
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='插件状态表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create plugin_states table: %v", err)
+	} else {
+		log.Println("[Init] Created plugin_states table")
+	}
+}
+
+// GetDisabledPluginNames 获取被禁用的插件名称
+func GetDisabledPluginNames() ([]string, error) {
+	var names []string
+	err := db.DB.Select(&names, "SELECT name FROM plugin_states WHERE enabled = 0")
+	return names, err
+}
+
+// SetPluginEnabled 保存插件启用状态
+func SetPluginEnabled(name string, enabled bool) error {
+	_, err := db.DB.Exec(`
+		INSERT INTO plugin_states (name, enabled, update_time) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), update_time = VALUES(update_time)`,
+		name, enabled, time.Now().Unix())
+	return err
+}
+
+// InitPluginConfigsTable 初始化插件配置表
+func InitPluginConfigsTable() {
+	if db.CheckTableExists("plugin_configs") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS plugin_configs (
+		name        VARCHAR(100) NOT NULL PRIMARY KEY COMMENT '插件名称',
+		config      TEXT COMMENT '配置(JSON)，敏感项加密存储',
+		update_time BIGINT       NOT NULL DEFAULT 0 COMMENT '更新时间'
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='插件配置表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create plugin_configs table: %v", err)
+	} else {
+		log.Println("[Init] Created plugin_configs table")
+	}
+}
+
+// GetPluginConfig 获取插件配置 JSON，未保存时返回空字符串
+func GetPluginConfig(name string) (string, error) {
+	var config string
+	err := db.DB.Get(&config, "SELECT COALESCE(config, '') FROM plugin_configs WHERE name = ?", name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return config, err
+}
+
+// SavePluginConfig 保存插件配置 JSON
+func SavePluginConfig(name, config string) error {
+	_, err := db.DB.Exec(`
+		INSERT INTO plugin_configs (name, config, update_time) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE config = VALUES(config), update_time = VALUES(update_time)`,
+		name, config, time.Now().Unix())
+	return err
+}
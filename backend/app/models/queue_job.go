@@ -0,0 +1,213 @@
+package models
+
+import (
+	"database/sql"
+	"fst/backend/internal/db"
+	"log"
+	"time"
+)
+
+// QueueJob 后台队列任务
+type QueueJob struct {
+	ID          uint64 `db:"id" json:"id"`
+	Queue       string `db:"queue" json:"queue"`
+	JobType     string `db:"job_type" json:"job_type"`
+	Payload     string `db:"payload" json:"payload"`
+	Status      string `db:"status" json:"status"` // pending / running / done / dead
+	Attempts    int    `db:"attempts" json:"attempts"`
+	MaxAttempts int    `db:"max_attempts" json:"max_attempts"`
+	LastError   string `db:"last_error" json:"last_error"`
+	RunAt       int64  `db:"run_at" json:"run_at"`             // 计划执行时间
+	LockedBy    string `db:"locked_by" json:"locked_by"`       // 执行实例
+	LockedUntil int64  `db:"locked_until" json:"locked_until"` // 锁定到期时间
+	CreateTime  int64  `db:"create_time" json:"create_time"`
+	UpdateTime  int64  `db:"update_time" json:"update_time"`
+	FinishTime  int64  `db:"finish_time" json:"finish_time"`
+}
+
+// QueueJobStat 队列状态统计
+type QueueJobStat struct {
+	Queue  string `db:"queue" json:"queue"`
+	Status string `db:"status" json:"status"`
+	Count  int64  `db:"count" json:"count"`
+}
+
+const queueJobColumns = "id, queue, job_type, payload, status, attempts, max_attempts, COALESCE(last_error, '') AS last_error, run_at, locked_by, locked_until, create_time, update_time, finish_time"
+
+// InitQueueJobsTable 初始化队列任务表
+func InitQueueJobsTable() {
+	if db.CheckTableExists("queue_jobs") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS queue_jobs (
+		id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		queue        VARCHAR(50)  NOT NULL DEFAULT '' COMMENT '队列名称',
+		job_type     VARCHAR(100) NOT NULL DEFAULT '' COMMENT '任务类型',
+		payload      MEDIUMTEXT COMMENT '任务参数(JSON)',
+		status       VARCHAR(20)  NOT NULL DEFAULT 'pending' COMMENT '状态',
+		attempts     INT          NOT NULL DEFAULT 0 COMMENT '已执行次数',
+		max_attempts INT          NOT NULL DEFAULT 0 COMMENT '最大执行次数',
+		last_error   TEXT COMMENT '最近一次错误',
+		run_at       BIGINT       NOT NULL DEFAULT 0 COMMENT '计划执行时间',
+		locked_by    VARCHAR(100) NOT NULL DEFAULT '' COMMENT '执行实例',
+		locked_until BIGINT       NOT NULL DEFAULT 0 COMMENT '锁定到期时间',
+		create_time  BIGINT       NOT NULL DEFAULT 0 COMMENT '创建时间',
+		update_time  BIGINT       NOT NULL DEFAULT 0 COMMENT '更新时间',
+		finish_time  BIGINT       NOT NULL DEFAULT 0 COMMENT '完成时间',
+		INDEX idx_queue_status_run (queue, status, run_at),
+		INDEX idx_status_finish (status, finish_time)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='后台队列任务表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create queue_jobs table: %v", err)
+	} else {
+		log.Println("[Init] Created queue_jobs table")
+	}
+}
+
+// CreateQueueJob 写入队列任务
+func CreateQueueJob(job *QueueJob) error {
+	now := time.Now().Unix()
+	job.CreateTime = now
+	job.UpdateTime = now
+	result, err := db.DB.Exec(`
+		INSERT INTO queue_jobs (queue, job_type, payload, status, attempts, max_attempts, run_at, create_time, update_time)
+		VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?)`,
+		job.Queue, job.JobType, job.Payload, job.Status, job.MaxAttempts, job.RunAt, now, now)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	job.ID = uint64(id)
+	return nil
+}
+
+// ClaimQueueJob 领取一个可执行任务，无任务时返回 nil
+// 先查询候选任务，再通过条件更新抢占，抢占失败（被其他实例领取）时继续尝试下一个
+func ClaimQueueJob(queue, owner string, now, lockUntil int64) (*QueueJob, error) {
+	for i := 0; i < 3; i++ {
+		var id uint64
+		err := db.DB.Get(&id, `
+			SELECT id FROM queue_jobs
+			WHERE queue = ? AND ((status = 'pending' AND run_at <= ?) OR (status = 'running' AND locked_until < ?))
+			ORDER BY run_at, id LIMIT 1`, queue, now, now)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := db.DB.Exec(`
+			UPDATE queue_jobs SET status = 'running', attempts = attempts + 1, locked_by = ?, locked_until = ?, update_time = ?
+			WHERE id = ? AND ((status = 'pending' AND run_at <= ?) OR (status = 'running' AND locked_until < ?))`,
+			owner, lockUntil, now, id, now, now)
+		if err != nil {
+			return nil, err
+		}
+		if affected, _ := result.RowsAffected(); affected != 1 {
+			continue
+		}
+		return GetQueueJobByID(id)
+	}
+	return nil, nil
+}
+
+// FinishQueueJob 更新任务结果并释放锁，返回是否成功
+// 仅当任务仍为 running 且由 owner 持有锁时更新，锁过期后被其他实例重新领取的任务不受影响
+func FinishQueueJob(id uint64, owner, status string, runAt int64, errMsg string) (bool, error) {
+	now := time.Now().Unix()
+	var finishTime int64
+	if status == "done" || status == "dead" {
+		finishTime = now
+	}
+	query := "UPDATE queue_jobs SET status = ?, last_error = ?, locked_by = '', locked_until = 0, finish_time = ?, update_time = ?"
+	args := []interface{}{status, errMsg, finishTime, now}
+	if runAt > 0 {
+		query += ", run_at = ?"
+		args = append(args, runAt)
+	}
+	query += " WHERE id = ? AND locked_by = ? AND status = 'running'"
+	args = append(args, id, owner)
+	result, err := db.DB.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// GetQueueJobByID 根据ID获取任务
+func GetQueueJobByID(id uint64) (*QueueJob, error) {
+	var job QueueJob
+	err := db.DB.Get(&job, "SELECT "+queueJobColumns+" FROM queue_jobs WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetQueueJobList 分页获取任务列表，筛选条件为空时不过滤
+func GetQueueJobList(queue, status, jobType string, page, pageSize int) ([]QueueJob, int64, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	if queue != "" {
+		where += " AND queue = ?"
+		args = append(args, queue)
+	}
+	if status != "" {
+		where += " AND status = ?"
+		args = append(args, status)
+	}
+	if jobType != "" {
+		where += " AND job_type = ?"
+		args = append(args, jobType)
+	}
+
+	var total int64
+	if err := db.DB.Get(&total, "SELECT COUNT(*) FROM queue_jobs"+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	jobs := []QueueJob{}
+	args = append(args, pageSize, (page-1)*pageSize)
+	err := db.DB.Select(&jobs, "SELECT "+queueJobColumns+" FROM queue_jobs"+where+" ORDER BY id DESC LIMIT ? OFFSET ?", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// RetryQueueJob 将死信任务重置为待执行（重新计算执行次数），返回是否成功
+func RetryQueueJob(id uint64) (bool, error) {
+	now := time.Now().Unix()
+	result, err := db.DB.Exec(`
+		UPDATE queue_jobs SET status = 'pending', attempts = 0, run_at = ?, finish_time = 0, update_time = ?
+		WHERE id = ? AND status = 'dead'`, now, now, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// GetQueueJobStats 按队列与状态统计任务数
+func GetQueueJobStats() ([]QueueJobStat, error) {
+	stats := []QueueJobStat{}
+	err := db.DB.Select(&stats, "SELECT queue, status, COUNT(*) AS count FROM queue_jobs GROUP BY queue, status ORDER BY queue, status")
+	return stats, err
+}
+
+// DeleteDoneQueueJobsBefore 删除指定时间之前已完成的任务
+func DeleteDoneQueueJobsBefore(before int64) (int64, error) {
+	result, err := db.DB.Exec("DELETE FROM queue_jobs WHERE status = 'done' AND finish_time < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fst/backend/internal/db"
+	"log"
+	"time"
+)
+
+// RealtimeMessage 实时推送消息，仅在 REALTIME_BROKER=db 时使用，供多个实例轮询扇出
+type RealtimeMessage struct {
+	ID         uint64 `db:"id" json:"id"`
+	Topic      string `db:"topic" json:"topic"`
+	Event      string `db:"event" json:"event"`
+	Data       string `db:"data" json:"data"`
+	CreateTime int64  `db:"create_time" json:"create_time"`
+}
+
+// InitRealtimeMessagesTable 初始化实时推送消息表
+func InitRealtimeMessagesTable() {
+	if db.CheckTableExists("realtime_messages") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS realtime_messages (
+		id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		topic       VARCHAR(100) NOT NULL DEFAULT '' COMMENT '主题',
+		event       VARCHAR(100) NOT NULL DEFAULT '' COMMENT '事件',
+		data        TEXT COMMENT '数据(JSON)',
+		create_time BIGINT       NOT NULL DEFAULT 0 COMMENT '创建时间',
+		INDEX idx_create_time (create_time)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='实时推送消息表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create realtime_messages table: %v", err)
+	} else {
+		log.Println("[Init] Created realtime_messages table")
+	}
+}
+
+// CreateRealtimeMessage 写入消息并回填 ID
+func CreateRealtimeMessage(msg *RealtimeMessage) error {
+	msg.CreateTime = time.Now().Unix()
+	result, err := db.DB.Exec("INSERT INTO realtime_messages (topic, event, data, create_time) VALUES (?, ?, ?, ?)",
+		msg.Topic, msg.Event, msg.Data, msg.CreateTime)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	msg.ID = uint64(id)
+	return nil
+}
+
+// GetRealtimeMessagesAfter 按 ID 升序获取大于 id 的消息
+func GetRealtimeMessagesAfter(id uint64, limit int) ([]RealtimeMessage, error) {
+	list := []RealtimeMessage{}
+	err := db.DB.Select(&list, "SELECT id, topic, event, COALESCE(data, '') AS data, create_time FROM realtime_messages WHERE id > ? ORDER BY id LIMIT ?", id, limit)
+	return list, err
+}
+
+// GetRealtimeMessageLastID 当前最大的消息 ID
+func GetRealtimeMessageLastID() (uint64, error) {
+	var id uint64
+	err := db.DB.Get(&id, "SELECT COALESCE(MAX(id), 0) FROM realtime_messages")
+	return id, err
+}
+
+// DeleteRealtimeMessagesBefore 删除指定时间之前的消息
+func DeleteRealtimeMessagesBefore(before int64) (int64, error) {
+	result, err := db.DB.Exec("DELETE FROM realtime_messages WHERE create_time < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
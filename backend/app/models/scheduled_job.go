@@ -0,0 +1,202 @@
+package models
+
+import (
+	"database/sql"
+	"fst/backend/internal/db"
+	"log"
+	"time"
+)
+
+// ScheduledJob 定时任务状态（集群共享）
+type ScheduledJob struct {
+	Name            string `db:"name" json:"name"`
+	Spec            string `db:"spec" json:"spec"`
+	Description     string `db:"description" json:"description"`
+	Paused          bool   `db:"paused" json:"paused"`
+	LeaseOwner      string `db:"lease_owner" json:"lease_owner"`             // 当前持有租约的实例
+	LeaseUntil      int64  `db:"lease_until" json:"lease_until"`             // 租约到期时间
+	LastScheduledAt int64  `db:"last_scheduled_at" json:"last_scheduled_at"` // 最近一次被执行的定时触发点
+	CreateTime      int64  `db:"create_time" json:"create_time"`
+	UpdateTime      int64  `db:"update_time" json:"update_time"`
+}
+
+// ScheduledJobRun 定时任务运行记录
+type ScheduledJobRun struct {
+	ID          uint64 `db:"id" json:"id"`
+	JobName     string `db:"job_name" json:"job_name"`
+	Instance    string `db:"instance" json:"instance"`
+	TriggerType string `db:"trigger_type" json:"trigger_type"` // schedule / manual
+	Status      string `db:"status" json:"status"`             // running / success / failed
+	ErrorMsg    string `db:"error_msg" json:"error_msg"`
+	StartedAt   int64  `db:"started_at" json:"started_at"`
+	FinishedAt  int64  `db:"finished_at" json:"finished_at"`
+	DurationMs  int64  `db:"duration_ms" json:"duration_ms"`
+}
+
+// InitScheduledJobsTable 初始化定时任务表与运行记录表
+func InitScheduledJobsTable() {
+	if !db.CheckTableExists("scheduled_jobs") {
+		schema := `CREATE TABLE IF NOT EXISTS scheduled_jobs (
+			name              VARCHAR(100)     NOT NULL PRIMARY KEY COMMENT '任务名称',
+			spec              VARCHAR(100)     NOT NULL DEFAULT '' COMMENT 'cron表达式',
+			description       VARCHAR(255)     NOT NULL DEFAULT '' COMMENT '描述',
+			paused            TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '是否暂停',
+			lease_owner       VARCHAR(100)     NOT NULL DEFAULT '' COMMENT '租约持有实例',
+			lease_until       BIGINT           NOT NULL DEFAULT 0 COMMENT '租约到期时间',
+			last_scheduled_at BIGINT           NOT NULL DEFAULT 0 COMMENT '最近执行的定时触发点',
+			create_time       BIGINT           NOT NULL DEFAULT 0 COMMENT '创建时间',
+			update_time       BIGINT           NOT NULL DEFAULT 0 COMMENT '更新时间'
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='定时任务表';`
+		if _, err := db.DB.Exec(schema); err != nil {
+			log.Printf("[Init] Failed to create scheduled_jobs table: %v", err)
+		} else {
+			log.Println("[Init] Created scheduled_jobs table")
+		}
+	}
+
+	if !db.CheckTableExists("scheduled_job_runs") {
+		schema := `CREATE TABLE IF NOT EXISTS scheduled_job_runs (
+			id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+			job_name     VARCHAR(100) NOT NULL DEFAULT '' COMMENT '任务名称',
+			instance     VARCHAR(100) NOT NULL DEFAULT '' COMMENT '执行实例',
+			trigger_type VARCHAR(20)  NOT NULL DEFAULT '' COMMENT '触发方式',
+			status       VARCHAR(20)  NOT NULL DEFAULT '' COMMENT '状态',
+			error_msg    TEXT COMMENT '错误信息',
+			started_at   BIGINT       NOT NULL DEFAULT 0 COMMENT '开始时间',
+			finished_at  BIGINT       NOT NULL DEFAULT 0 COMMENT '结束时间',
+			duration_ms  BIGINT       NOT NULL DEFAULT 0 COMMENT '耗时(ms)',
+			INDEX idx_job_started (job_name, started_at),
+			INDEX idx_started_at (started_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='定时任务运行记录表';`
+		if _, err := db.DB.Exec(schema); err != nil {
+			log.Printf("[Init] Failed to create scheduled_job_runs table: %v", err)
+		} else {
+			log.Println("[Init] Created scheduled_job_runs table")
+		}
+	}
+}
+
+// EnsureScheduledJob 登记任务，已存在时只更新表达式与描述，保留暂停状态
+func EnsureScheduledJob(name, spec, description string) error {
+	now := time.Now().Unix()
+	_, err := db.DB.Exec(`
+		INSERT INTO scheduled_jobs (name, spec, description, create_time, update_time)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE spec = VALUES(spec), description = VALUES(description), update_time = VALUES(update_time)`,
+		name, spec, description, now, now)
+	return err
+}
+
+// AcquireScheduledJobLease 通过条件更新抢占任务租约，返回是否成功
+// scheduledAt > 0 表示定时触发：要求任务未暂停且该触发点尚未被执行
+func AcquireScheduledJobLease(name, owner string, scheduledAt, leaseUntil int64) (bool, error) {
+	now := time.Now().Unix()
+	query := "UPDATE scheduled_jobs SET lease_owner = ?, lease_until = ?, update_time = ?"
+	args := []interface{}{owner, leaseUntil, now}
+	if scheduledAt > 0 {
+		query += ", last_scheduled_at = ?"
+		args = append(args, scheduledAt)
+	}
+	query += " WHERE name = ? AND (lease_owner = '' OR lease_until < ?)"
+	args = append(args, name, now)
+	if scheduledAt > 0 {
+		query += " AND paused = 0 AND last_scheduled_at < ?"
+		args = append(args, scheduledAt)
+	}
+
+	result, err := db.DB.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ReleaseScheduledJobLease 释放本实例持有的租约
+func ReleaseScheduledJobLease(name, owner string) error {
+	_, err := db.DB.Exec("UPDATE scheduled_jobs SET lease_owner = '', lease_until = 0, update_time = ? WHERE name = ? AND lease_owner = ?",
+		time.Now().Unix(), name, owner)
+	return err
+}
+
+// SetScheduledJobPaused 设置任务暂停状态
+func SetScheduledJobPaused(name string, paused bool) error {
+	_, err := db.DB.Exec("UPDATE scheduled_jobs SET paused = ?, update_time = ? WHERE name = ?", paused, time.Now().Unix(), name)
+	return err
+}
+
+// GetScheduledJobs 获取所有任务状态
+func GetScheduledJobs() ([]ScheduledJob, error) {
+	var jobs []ScheduledJob
+	err := db.DB.Select(&jobs, "SELECT name, spec, description, paused, lease_owner, lease_until, last_scheduled_at, create_time, update_time FROM scheduled_jobs ORDER BY name")
+	return jobs, err
+}
+
+// CreateScheduledJobRun 写入运行记录
+func CreateScheduledJobRun(run *ScheduledJobRun) error {
+	result, err := db.DB.Exec(`
+		INSERT INTO scheduled_job_runs (job_name, instance, trigger_type, status, error_msg, started_at, finished_at, duration_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.JobName, run.Instance, run.TriggerType, run.Status, run.ErrorMsg, run.StartedAt, run.FinishedAt, run.DurationMs)
+	if err != nil {
+		return err
+	}
+	id, _ := result.LastInsertId()
+	run.ID = uint64(id)
+	return nil
+}
+
+// FinishScheduledJobRun 更新运行结果
+func FinishScheduledJobRun(run *ScheduledJobRun) error {
+	_, err := db.DB.Exec("UPDATE scheduled_job_runs SET status = ?, error_msg = ?, finished_at = ?, duration_ms = ? WHERE id = ?",
+		run.Status, run.ErrorMsg, run.FinishedAt, run.DurationMs, run.ID)
+	return err
+}
+
+// GetScheduledJobRunList 分页获取运行记录，jobName 为空时返回全部
+func GetScheduledJobRunList(jobName string, page, pageSize int) ([]ScheduledJobRun, int64, error) {
+	where := ""
+	args := []interface{}{}
+	if jobName != "" {
+		where = " WHERE job_name = ?"
+		args = append(args, jobName)
+	}
+
+	var total int64
+	if err := db.DB.Get(&total, "SELECT COUNT(*) FROM scheduled_job_runs"+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	runs := []ScheduledJobRun{}
+	args = append(args, pageSize, (page-1)*pageSize)
+	err := db.DB.Select(&runs, "SELECT id, job_name, instance, trigger_type, status, COALESCE(error_msg, '') AS error_msg, started_at, finished_at, duration_ms FROM scheduled_job_runs"+where+" ORDER BY id DESC LIMIT ? OFFSET ?", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// GetLatestScheduledJobRun 获取任务最近一次运行记录
+func GetLatestScheduledJobRun(jobName string) (*ScheduledJobRun, error) {
+	var run ScheduledJobRun
+	err := db.DB.Get(&run, "SELECT id, job_name, instance, trigger_type, status, COALESCE(error_msg, '') AS error_msg, started_at, finished_at, duration_ms FROM scheduled_job_runs WHERE job_name = ? ORDER BY id DESC LIMIT 1", jobName)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// DeleteScheduledJobRunsBefore 删除指定时间之前的运行记录
+func DeleteScheduledJobRunsBefore(before int64) (int64, error) {
+	result, err := db.DB.Exec("DELETE FROM scheduled_job_runs WHERE started_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
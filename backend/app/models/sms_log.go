@@ -0,0 +1,146 @@
+package models
+
+import (
+	"fst/backend/internal/db"
+	"log"
+	"time"
+)
+
+// SMSLog 短信发送日志（不记录验证码内容）
+type SMSLog struct {
+	ID        uint64    `db:"id" json:"id"`
+	Phone     string    `db:"phone" json:"phone"`
+	Purpose   string    `db:"purpose" json:"purpose"`       // 用途：register、login、change_phone 等
+	Provider  string    `db:"provider" json:"provider"`     // 服务商：console、aliyun、tencent
+	Template  string    `db:"template" json:"template"`     // 模板 ID / 模板 Code
+	Status    uint8     `db:"status" json:"status"`         // 见 SMSLogFailed 等
+	RequestID string    `db:"request_id" json:"request_id"` // 服务商回执 ID（阿里云 BizId / 腾讯云 SerialNo）
+	ErrorMsg  string    `db:"error_msg" json:"error_msg"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// 短信日志状态
+const (
+	SMSLogFailed  = 0 // 发送失败
+	SMSLogSuccess = 1 // 发送成功
+)
+
+// InitSMSLogsTable 初始化短信日志表
+func InitSMSLogsTable() {
+	if db.CheckTableExists("sms_logs") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS sms_logs (
+		id         BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		phone      VARCHAR(32)  NOT NULL DEFAULT '' COMMENT '手机号',
+		purpose    VARCHAR(32)  NOT NULL DEFAULT '' COMMENT '用途',
+		provider   VARCHAR(32)  NOT NULL DEFAULT '' COMMENT '服务商',
+		template   VARCHAR(100) NOT NULL DEFAULT '' COMMENT '模板ID',
+		status     TINYINT      NOT NULL DEFAULT 0 COMMENT '状态: 0=失败, 1=成功',
+		request_id VARCHAR(100) NOT NULL DEFAULT '' COMMENT '服务商回执ID',
+		error_msg  VARCHAR(500) NOT NULL DEFAULT '' COMMENT '错误信息',
+		created_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '发送时间',
+		INDEX idx_phone (phone),
+		INDEX idx_created_at (created_at)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='短信发送日志表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create sms_logs table: %v", err)
+	} else {
+		log.Println("[Init] Created sms_logs table")
+	}
+}
+
+// CreateSMSLog 记录短信发送日志
+func CreateSMSLog(l *SMSLog) error {
+	if r := []rune(l.ErrorMsg); len(r) > 500 {
+		l.ErrorMsg = string(r[:500])
+	}
+	_, err := db.DB.Exec(`INSERT INTO sms_logs (phone, purpose, provider, template, status, request_id, error_msg) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		l.Phone, l.Purpose, l.Provider, l.Template, l.Status, l.RequestID, l.ErrorMsg)
+	return err
+}
+
+// SMSLogQuery 短信日志查询参数
+type SMSLogQuery struct {
+	Page      int    `form:"page" json:"page"`
+	PageSize  int    `form:"page_size" json:"page_size"`
+	Phone     string `form:"phone" json:"phone"`
+	Purpose   string `form:"purpose" json:"purpose"`
+	Provider  string `form:"provider" json:"provider"`
+	Status    int    `form:"status" json:"status"` // -1=全部, 0=失败, 1=成功
+	StartTime string `form:"start_time" json:"start_time"`
+	EndTime   string `form:"end_time" json:"end_time"`
+}
+
+// GetSMSLogList 分页查询短信日志
+func GetSMSLogList(q *SMSLogQuery) ([]SMSLog, int64, error) {
+	logs := []SMSLog{}
+	var total int64
+
+	where := "WHERE 1=1"
+	args := []interface{}{}
+
+	if q.Phone != "" {
+		where += " AND phone LIKE ?"
+		args = append(args, "%"+q.Phone+"%")
+	}
+	if q.Purpose != "" {
+		where += " AND purpose = ?"
+		args = append(args, q.Purpose)
+	}
+	if q.Provider != "" {
+		where += " AND provider = ?"
+		args = append(args, q.Provider)
+	}
+	if q.Status >= 0 {
+		where += " AND status = ?"
+		args = append(args, q.Status)
+	}
+	if q.StartTime != "" {
+		where += " AND created_at >= ?"
+		args = append(args, q.StartTime)
+	}
+	if q.EndTime != "" {
+		where += " AND created_at <= ?"
+		args = append(args, q.EndTime)
+	}
+
+	if err := db.DB.Get(&total, "SELECT COUNT(*) FROM sms_logs "+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = 20
+	}
+	offset := (q.Page - 1) * q.PageSize
+
+	args = append(args, q.PageSize, offset)
+	if err := db.DB.Select(&logs, "SELECT * FROM sms_logs "+where+" ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?", args...); err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// DeleteSMSLogsBefore 删除指定时间之前的短信日志
+func DeleteSMSLogsBefore(before string) (int64, error) {
+	result, err := db.DB.Exec("DELETE FROM sms_logs WHERE created_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetSMSLogStats 短信日志统计
+func GetSMSLogStats() (total int64, success int64, fail int64, err error) {
+	if err = db.DB.Get(&total, "SELECT COUNT(*) FROM sms_logs"); err != nil {
+		return
+	}
+	if err = db.DB.Get(&success, "SELECT COUNT(*) FROM sms_logs WHERE status = 1"); err != nil {
+		return
+	}
+	fail = total - success
+	return
+}
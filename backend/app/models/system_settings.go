@@ -3,10 +3,14 @@ package models
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"fst/backend/internal/db"
+	"fst/backend/internal/secrets"
 	"log"
 	"strings"
 	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // SystemSetting 系统配置项
@@ -103,6 +107,79 @@ func InitSystemSettingsTable() {
 
 	// 初始化默认配置
 	initDefaultSettings()
+	runSettingsMigrations()
+}
+
+// settingsMigrations 只执行一次的配置数据迁移，执行记录保存在 system_settings_migrations 表
+var settingsMigrations = []struct {
+	name string
+	run  func() error
+}{
+	{"clear_seeded_env_defaults", clearSeededEnvDefaults},
+	{"drop_mail_file_dir", dropMailFileDirSetting},
+}
+
+// runSettingsMigrations 执行尚未执行过的配置数据迁移
+// 先写入执行记录再执行，多实例同时启动时只有一个实例执行；执行失败时删除记录，下次启动重试
+func runSettingsMigrations() {
+	_, err := db.DB.Exec(`CREATE TABLE IF NOT EXISTS system_settings_migrations (
+		name VARCHAR(100) NOT NULL PRIMARY KEY COMMENT '迁移名称',
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '执行时间'
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='系统配置数据迁移记录';`)
+	if err != nil {
+		log.Printf("[Init] Failed to create system_settings_migrations table: %v", err)
+		return
+	}
+
+	for _, m := range settingsMigrations {
+		result, err := db.DB.Exec("INSERT IGNORE INTO system_settings_migrations (name) VALUES (?)", m.name)
+		if err != nil {
+			log.Printf("[Init] Failed to record settings migration %s: %v", m.name, err)
+			continue
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			continue
+		}
+		if err := m.run(); err != nil {
+			log.Printf("[Init] Settings migration %s failed: %v", m.name, err)
+			db.DB.Exec("DELETE FROM system_settings_migrations WHERE name = ?", m.name)
+			continue
+		}
+		log.Printf("[Init] Applied settings migration: %s", m.name)
+	}
+}
+
+// seededEnvDefaults 早期版本为有环境变量来源的配置写入的默认值
+// 数据库配置优先于环境变量，这些值会让环境变量失效；仍为该值的配置清空后回退到环境变量。
+// 只收录与配置默认值相同的项，未设置环境变量的部署清空后行为不变
+var seededEnvDefaults = map[string]string{
+	"rate_limit_auth_rate":  "5",
+	"rate_limit_auth_burst": "10",
+	"geetest_enabled":       "false",
+	"jwt_access_expire":     "7200",
+	"jwt_refresh_expire":    "604800",
+	"login_max_failure":     "5",
+	"login_lock_duration":   "10",
+	"email_verify_enabled":  "true",
+	"sms_verify_enabled":    "false",
+	"sms_provider":          "console",
+}
+
+// clearSeededEnvDefaults 清空仍为早期默认值的配置
+func clearSeededEnvDefaults() error {
+	for key, seeded := range seededEnvDefaults {
+		if _, err := db.DB.Exec("UPDATE system_settings SET setting_value = '' WHERE setting_key = ? AND setting_value = ?", key, seeded); err != nil {
+			return fmt.Errorf("clear %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// dropMailFileDirSetting 删除 mail_file_dir 配置项
+// 邮件文件目录只能通过环境变量 MAIL_FILE_DIR 配置，避免后台把邮件写入任意目录
+func dropMailFileDirSetting() error {
+	_, err := db.DB.Exec("DELETE FROM system_settings WHERE setting_key = 'mail_file_dir'")
+	return err
 }
 
 // 默认配置项定义
@@ -121,33 +198,49 @@ var defaultSettings = []SystemSetting{
 	{Key: "backend_api_url", Value: "", Type: "string", Category: "basic", Label: "后端API地址", Description: "后端API外网地址（如 http://api.example.com），结尾不要加 /", IsPublic: false, IsEditable: true, SortOrder: 10},
 
 	// ===== 安全设置 =====
-	{Key: "geetest_enabled", Value: "false", Type: "boolean", Category: "security", Label: "极验验证码", Description: "是否启用极验行为验证", IsPublic: true, IsEditable: true, SortOrder: 1},
+	{Key: "geetest_enabled", Value: "", Type: "boolean", Category: "security", Label: "极验验证码", Description: "是否启用极验行为验证，留空则沿用环境变量 GEETEST_ENABLED（默认关闭）", IsPublic: true, IsEditable: true, SortOrder: 1},
 	{Key: "geetest_captcha_id", Value: "", Type: "string", Category: "security", Label: "极验 Captcha ID", Description: "极验验证码 ID", IsPublic: true, IsEditable: true, SortOrder: 2},
 	{Key: "geetest_captcha_key", Value: "", Type: "string", Category: "security", Label: "极验 Captcha Key", Description: "极验验证码 Key", IsPublic: false, IsEditable: true, SortOrder: 3},
-	{Key: "jwt_access_expire", Value: "7200", Type: "number", Category: "security", Label: "Token有效期", Description: "Access Token 有效期（秒）", IsPublic: false, IsEditable: true, SortOrder: 4},
-	{Key: "jwt_refresh_expire", Value: "604800", Type: "number", Category: "security", Label: "Refresh Token有效期", Description: "Refresh Token 有效期（秒）", IsPublic: false, IsEditable: true, SortOrder: 5},
-	{Key: "login_max_failure", Value: "5", Type: "number", Category: "security", Label: "登录失败锁定次数", Description: "连续登录失败多少次后锁定账户", IsPublic: false, IsEditable: true, SortOrder: 6},
-	{Key: "login_lock_duration", Value: "10", Type: "number", Category: "security", Label: "账户锁定时长", Description: "账户锁定时长（分钟）", IsPublic: false, IsEditable: true, SortOrder: 7},
+	{Key: "jwt_access_expire", Value: "", Type: "number", Category: "security", Label: "Token有效期", Description: "Access Token 有效期（秒），留空则沿用环境变量 JWT_ACCESS_EXPIRE（默认 7200）", IsPublic: false, IsEditable: true, SortOrder: 4},
+	{Key: "jwt_refresh_expire", Value: "", Type: "number", Category: "security", Label: "Refresh Token有效期", Description: "Refresh Token 有效期（秒），留空则沿用环境变量 JWT_REFRESH_EXPIRE（默认 604800）", IsPublic: false, IsEditable: true, SortOrder: 5},
+	{Key: "login_max_failure", Value: "", Type: "number", Category: "security", Label: "登录失败锁定次数", Description: "连续登录失败多少次后锁定账户，留空则沿用环境变量 LOGIN_MAX_FAILURE_COUNT（默认 5）", IsPublic: false, IsEditable: true, SortOrder: 6},
+	{Key: "login_lock_duration", Value: "", Type: "number", Category: "security", Label: "账户锁定时长", Description: "账户锁定时长（分钟），留空则沿用环境变量 LOGIN_LOCK_DURATION_MINUTES（默认 10）", IsPublic: false, IsEditable: true, SortOrder: 7},
 	{Key: "operation_log_query_days", Value: "30", Type: "number", Category: "security", Label: "操作日志查询天数", Description: "操作日志默认查询范围（天）", IsPublic: false, IsEditable: true, SortOrder: 8},
 	{Key: "operation_log_max_count", Value: "20", Type: "number", Category: "security", Label: "操作日志最大数量", Description: "操作日志单页最大查询数量", IsPublic: false, IsEditable: true, SortOrder: 9},
+	{Key: "cors_origins", Value: "", Type: "string", Category: "security", Label: "跨域白名单", Description: "允许跨域访问的来源，多个用英文逗号分隔，留空则沿用环境变量 CORS_ORIGINS", IsPublic: false, IsEditable: true, SortOrder: 10},
+	{Key: "rate_limit_auth_rate", Value: "", Type: "number", Category: "security", Label: "认证接口限流速率", Description: "登录、注册等认证接口每个IP每秒允许的请求数，留空则沿用环境变量 RATE_LIMIT_AUTH_RATE（默认 5）", IsPublic: false, IsEditable: true, SortOrder: 11},
+	{Key: "rate_limit_auth_burst", Value: "", Type: "number", Category: "security", Label: "认证接口突发上限", Description: "登录、注册等认证接口每个IP允许的突发请求数，留空则沿用环境变量 RATE_LIMIT_AUTH_BURST（默认 10）", IsPublic: false, IsEditable: true, SortOrder: 12},
 
 	// ===== 邮件设置 =====
-	{Key: "email_verify_enabled", Value: "true", Type: "boolean", Category: "email", Label: "邮箱验证码", Description: "是否启用邮箱验证码功能（关闭后修改邮箱无需验证）", IsPublic: true, IsEditable: true, SortOrder: 0},
+	{Key: "email_verify_enabled", Value: "", Type: "boolean", Category: "email", Label: "邮箱验证码", Description: "是否启用邮箱验证码功能（关闭后修改邮箱无需验证），留空则沿用环境变量 EMAIL_VERIFY_ENABLED（默认开启）", IsPublic: true, IsEditable: true, SortOrder: 0},
 	{Key: "smtp_host", Value: "", Type: "string", Category: "email", Label: "SMTP服务器", Description: "SMTP邮件服务器地址", IsPublic: false, IsEditable: true, SortOrder: 1},
-	{Key: "smtp_port", Value: "587", Type: "number", Category: "email", Label: "SMTP端口", Description: "SMTP服务器端口", IsPublic: false, IsEditable: true, SortOrder: 2},
+	{Key: "smtp_port", Value: "", Type: "number", Category: "email", Label: "SMTP端口", Description: "SMTP服务器端口，留空则沿用环境变量 SMTP_PORT（默认 587）", IsPublic: false, IsEditable: true, SortOrder: 2},
 	{Key: "smtp_username", Value: "", Type: "string", Category: "email", Label: "发件人邮箱", Description: "SMTP登录用户名/邮箱", IsPublic: false, IsEditable: true, SortOrder: 3},
 	{Key: "smtp_password", Value: "", Type: "string", Category: "email", Label: "邮箱密码", Description: "SMTP登录密码或应用密钥", IsPublic: false, IsEditable: true, SortOrder: 4},
-	{Key: "smtp_ssl", Value: "true", Type: "boolean", Category: "email", Label: "SSL加密", Description: "是否启用SSL加密", IsPublic: false, IsEditable: true, SortOrder: 5},
-	{Key: "system_email_name", Value: "F.st", Type: "string", Category: "email", Label: "发件人名称", Description: "邮件中显示的发件人名称", IsPublic: false, IsEditable: true, SortOrder: 6},
+	{Key: "smtp_ssl", Value: "", Type: "boolean", Category: "email", Label: "SSL加密", Description: "是否启用SSL加密，留空则沿用环境变量 SMTP_SSL_TYPE", IsPublic: false, IsEditable: true, SortOrder: 5},
+	{Key: "system_email_name", Value: "", Type: "string", Category: "email", Label: "发件人名称", Description: "邮件中显示的发件人名称，留空则沿用环境变量 SYSTEM_EMAIL_NAME", IsPublic: false, IsEditable: true, SortOrder: 6},
+	{Key: "dkim_domain", Value: "", Type: "string", Category: "email", Label: "DKIM域名", Description: "DKIM 签名域名（d=），与选择器、私钥都配置后对外发邮件签名", IsPublic: false, IsEditable: true, SortOrder: 7},
+	{Key: "dkim_selector", Value: "", Type: "string", Category: "email", Label: "DKIM选择器", Description: "DKIM 选择器（s=），公钥发布在 <选择器>._domainkey.<域名> 的 TXT 记录中", IsPublic: false, IsEditable: true, SortOrder: 8},
+	{Key: "dkim_private_key", Value: "", Type: "string", Category: "email", Label: "DKIM私钥", Description: "PEM 格式的 RSA 或 Ed25519 私钥内容；私钥文件路径只能通过环境变量 DKIM_PRIVATE_KEY 配置", IsPublic: false, IsEditable: true, SortOrder: 9},
+	{Key: "mail_transport", Value: "", Type: "string", Category: "email", Label: "投递方式", Description: "邮件投递方式：smtp(SMTP 连接池)、http(HTTP 接口)；留空时使用环境变量 MAIL_TRANSPORT（默认 smtp），写入本地文件的 file 与 maildir 仅可通过环境变量在非生产环境启用", IsPublic: false, IsEditable: true, SortOrder: 10},
+	{Key: "smtp_pool_size", Value: "", Type: "number", Category: "email", Label: "SMTP连接数", Description: "SMTP 连接池最多同时保持的连接数，留空时默认 4", IsPublic: false, IsEditable: true, SortOrder: 11},
+	{Key: "mail_http_url", Value: "", Type: "string", Category: "email", Label: "HTTP投递地址", Description: "投递方式为 http 时，以 JSON POST 邮件的接口地址", IsPublic: false, IsEditable: true, SortOrder: 12},
+	{Key: "mail_http_token", Value: "", Type: "string", Category: "email", Label: "HTTP投递令牌", Description: "HTTP 投递接口的访问令牌，以 Authorization: Bearer 发送", IsPublic: false, IsEditable: true, SortOrder: 13},
+	{Key: "mail_webhook_token", Value: "", Type: "string", Category: "email", Label: "退信回调令牌", Description: "邮件服务商退信 / 投诉回调地址 /api/v1/public/email/webhook/<服务商>?token=<令牌> 中的令牌，为空时不接收回调", IsPublic: false, IsEditable: true, SortOrder: 15},
 
 	// ===== 短信设置 =====
-	{Key: "sms_verify_enabled", Value: "false", Type: "boolean", Category: "sms", Label: "短信验证码", Description: "是否启用短信验证码功能（关闭后修改手机号无需验证）", IsPublic: true, IsEditable: true, SortOrder: 0},
-	{Key: "sms_provider", Value: "console", Type: "string", Category: "sms", Label: "短信服务商", Description: "短信服务商标识：console(控制台日志)、aliyun(阿里云)、tencent(腾讯云)", IsPublic: false, IsEditable: true, SortOrder: 1},
+	{Key: "sms_verify_enabled", Value: "", Type: "boolean", Category: "sms", Label: "短信验证码", Description: "是否启用短信验证码功能：开启后可使用手机号注册、短信验证码登录与短信找回密码；关闭后修改手机号无需验证。留空则沿用环境变量 SMS_VERIFY_ENABLED（默认关闭）", IsPublic: true, IsEditable: true, SortOrder: 0},
+	{Key: "sms_provider", Value: "", Type: "string", Category: "sms", Label: "短信服务商", Description: "短信服务商标识：console(控制台日志)、aliyun(阿里云)、tencent(腾讯云)，留空则沿用环境变量 SMS_PROVIDER（默认 console）", IsPublic: false, IsEditable: true, SortOrder: 1},
 	{Key: "sms_access_key", Value: "", Type: "string", Category: "sms", Label: "AccessKey", Description: "短信服务商 AccessKey / API Key", IsPublic: false, IsEditable: true, SortOrder: 2},
 	{Key: "sms_secret_key", Value: "", Type: "string", Category: "sms", Label: "SecretKey", Description: "短信服务商 SecretKey / API Secret", IsPublic: false, IsEditable: true, SortOrder: 3},
 	{Key: "sms_sign_name", Value: "", Type: "string", Category: "sms", Label: "短信签名", Description: "短信签名（如：F.st）", IsPublic: false, IsEditable: true, SortOrder: 4},
-	{Key: "sms_template_code", Value: "", Type: "string", Category: "sms", Label: "验证码模板ID", Description: "短信验证码模板ID", IsPublic: false, IsEditable: true, SortOrder: 5},
+	{Key: "sms_template_code", Value: "", Type: "string", Category: "sms", Label: "验证码模板ID", Description: "默认的短信验证码模板ID，未在「按用途模板」中单独配置的用途使用该模板", IsPublic: false, IsEditable: true, SortOrder: 5},
 	{Key: "sms_region", Value: "", Type: "string", Category: "sms", Label: "服务区域", Description: "短信服务区域（部分服务商需要）", IsPublic: false, IsEditable: true, SortOrder: 6},
+	{Key: "sms_app_id", Value: "", Type: "string", Category: "sms", Label: "短信应用ID", Description: "腾讯云短信应用 SdkAppId（阿里云无需配置）", IsPublic: false, IsEditable: true, SortOrder: 7},
+	{Key: "sms_templates", Value: "", Type: "string", Category: "sms", Label: "按用途模板", Description: "按用途配置模板ID（JSON），用途：register(注册)、login(登录)、reset_password(找回密码)、change_phone(修改手机号)，如 {\"register\":\"SMS_1\",\"login\":\"SMS_2\"}；阿里云模板变量为 ${code}，腾讯云模板变量为 {1}=验证码、{2}=有效分钟数", IsPublic: false, IsEditable: true, SortOrder: 8},
+	{Key: "sms_phone_hourly_limit", Value: "", Type: "number", Category: "sms", Label: "单号每小时上限", Description: "同一手机号每小时最多发送的验证码短信条数，留空则沿用环境变量 SMS_PHONE_HOURLY_LIMIT（默认 5）", IsPublic: false, IsEditable: true, SortOrder: 9},
+	{Key: "sms_phone_daily_limit", Value: "", Type: "number", Category: "sms", Label: "单号每天上限", Description: "同一手机号 24 小时内最多发送的验证码短信条数，留空则沿用环境变量 SMS_PHONE_DAILY_LIMIT（默认 10）", IsPublic: false, IsEditable: true, SortOrder: 10},
+	{Key: "sms_ip_daily_limit", Value: "", Type: "number", Category: "sms", Label: "单IP每天上限", Description: "同一 IP 24 小时内最多请求发送的验证码短信条数，留空则沿用环境变量 SMS_IP_DAILY_LIMIT（默认 20）", IsPublic: false, IsEditable: true, SortOrder: 11},
 
 	// ===== 支付设置 =====
 	{Key: "payment_enabled", Value: "false", Type: "boolean", Category: "payment", Label: "支付功能", Description: "是否启用在线支付充值功能", IsPublic: true, IsEditable: true, SortOrder: 0},
@@ -190,6 +283,58 @@ func initDefaultSettings() {
 	}
 }
 
+// SecretSettingKeys 需要加密存储的敏感配置键
+var SecretSettingKeys = []string{
+	"geetest_captcha_key",
+	"smtp_password",
+	"dkim_private_key",
+	"mail_http_token",
+	"mail_webhook_token",
+	"sms_access_key",
+	"sms_secret_key",
+}
+
+// IsSecretSettingKey 判断配置键是否为加密存储的敏感配置
+func IsSecretSettingKey(key string) bool {
+	for _, k := range SecretSettingKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptSettingValue 敏感配置写入前加密，其他配置原样返回
+func encryptSettingValue(key, value string) (string, error) {
+	if !IsSecretSettingKey(key) {
+		return value, nil
+	}
+	enc, err := secrets.Encrypt(value)
+	if err != nil {
+		return "", fmt.Errorf("encrypt setting %s: %w", key, err)
+	}
+	return enc, nil
+}
+
+// decryptSettingValue 读取时解密敏感配置，解密失败时返回空值，绝不把密文当作配置值使用
+func decryptSettingValue(key, value string) string {
+	if !secrets.IsEncrypted(value) {
+		return value
+	}
+	plain, err := secrets.Decrypt(value)
+	if err != nil {
+		log.Printf("[Settings] Failed to decrypt setting %s: %v", key, err)
+		return ""
+	}
+	return plain
+}
+
+func decryptSettings(settings []SystemSetting) {
+	for i := range settings {
+		settings[i].Value = decryptSettingValue(settings[i].Key, settings[i].Value)
+	}
+}
+
 // GetSettingByKey 根据键名获取配置
 func GetSettingByKey(key string) (*SystemSetting, error) {
 	var setting SystemSetting
@@ -197,6 +342,7 @@ func GetSettingByKey(key string) (*SystemSetting, error) {
 	if err != nil {
 		return nil, err
 	}
+	setting.Value = decryptSettingValue(setting.Key, setting.Value)
 	return &setting, nil
 }
 
@@ -204,6 +350,7 @@ func GetSettingByKey(key string) (*SystemSetting, error) {
 func GetSettingsByCategory(category string) ([]SystemSetting, error) {
 	var settings []SystemSetting
 	err := db.DB.Select(&settings, "SELECT * FROM system_settings WHERE category = ? ORDER BY sort_order", category)
+	decryptSettings(settings)
 	return settings, err
 }
 
@@ -211,6 +358,7 @@ func GetSettingsByCategory(category string) ([]SystemSetting, error) {
 func GetAllSettings() ([]SystemSetting, error) {
 	var settings []SystemSetting
 	err := db.DB.Select(&settings, "SELECT * FROM system_settings ORDER BY category, sort_order")
+	decryptSettings(settings)
 	return settings, err
 }
 
@@ -218,31 +366,44 @@ func GetAllSettings() ([]SystemSetting, error) {
 func GetPublicSettings() ([]SystemSetting, error) {
 	var settings []SystemSetting
 	err := db.DB.Select(&settings, "SELECT * FROM system_settings WHERE is_public = 1 ORDER BY category, sort_order")
+	decryptSettings(settings)
 	return settings, err
 }
 
 // UpdateSetting 更新配置值
 func UpdateSetting(key string, value string) error {
-	_, err := db.DB.Exec("UPDATE system_settings SET setting_value = ?, updated_at = NOW() WHERE setting_key = ?", value, key)
+	value, err := encryptSettingValue(key, value)
+	if err != nil {
+		return err
+	}
+	_, err = db.DB.Exec("UPDATE system_settings SET setting_value = ?, updated_at = NOW() WHERE setting_key = ?", value, key)
 	return err
 }
 
 // UpdateSettingWithMeta 更新配置值和元数据
 func UpdateSettingWithMeta(setting *SystemSetting) error {
-	_, err := db.DB.Exec(`
+	value, err := encryptSettingValue(setting.Key, setting.Value)
+	if err != nil {
+		return err
+	}
+	_, err = db.DB.Exec(`
 		UPDATE system_settings 
 		SET setting_value = ?, setting_type = ?, category = ?, label = ?, description = ?, is_public = ?, is_editable = ?, sort_order = ?, updated_at = NOW()
 		WHERE setting_key = ?`,
-		setting.Value, setting.Type, setting.Category, setting.Label, setting.Description, setting.IsPublic, setting.IsEditable, setting.SortOrder, setting.Key)
+		value, setting.Type, setting.Category, setting.Label, setting.Description, setting.IsPublic, setting.IsEditable, setting.SortOrder, setting.Key)
 	return err
 }
 
 // CreateSetting 创建新配置
 func CreateSetting(setting *SystemSetting) error {
-	_, err := db.DB.Exec(`
+	value, err := encryptSettingValue(setting.Key, setting.Value)
+	if err != nil {
+		return err
+	}
+	_, err = db.DB.Exec(`
 		INSERT INTO system_settings (setting_key, setting_value, setting_type, category, label, description, is_public, is_editable, sort_order)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		setting.Key, setting.Value, setting.Type, setting.Category, setting.Label, setting.Description, setting.IsPublic, setting.IsEditable, setting.SortOrder)
+		setting.Key, value, setting.Type, setting.Category, setting.Label, setting.Description, setting.IsPublic, setting.IsEditable, setting.SortOrder)
 	return err
 }
 
@@ -261,7 +422,11 @@ func BatchUpdateSettings(settings map[string]string) error {
 	defer tx.Rollback()
 
 	for key, value := range settings {
-		_, err := tx.Exec("UPDATE system_settings SET setting_value = ?, updated_at = NOW() WHERE setting_key = ?", value, key)
+		value, err := encryptSettingValue(key, value)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE system_settings SET setting_value = ?, updated_at = NOW() WHERE setting_key = ?", value, key)
 		if err != nil {
 			return err
 		}
@@ -297,8 +462,80 @@ func GetSettingsMap(keys []string) (map[string]string, error) {
 		if err := rows.Scan(&key, &value); err != nil {
 			return nil, err
 		}
-		result[key] = value
+		result[key] = decryptSettingValue(key, value)
 	}
 
 	return result, nil
 }
+
+// FindPlaintextSecretSettings 返回仍以明文存储的敏感配置键
+func FindPlaintextSecretSettings() ([]string, error) {
+	raw, err := rawSecretSettings(db.DB)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for key, value := range raw {
+		if value != "" && !secrets.IsEncrypted(value) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// ReencryptSecretSettings 使用当前主密钥重新加密所有敏感配置（含明文），返回更新的行数
+func ReencryptSecretSettings() (int, error) {
+	tx, err := db.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	raw, err := rawSecretSettings(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for key, value := range raw {
+		if value == "" || secrets.IsCurrent(value) {
+			continue
+		}
+		plain, err := secrets.Decrypt(value)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt setting %s: %w", key, err)
+		}
+		enc, err := encryptSettingValue(key, plain)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("UPDATE system_settings SET setting_value = ?, updated_at = NOW() WHERE setting_key = ?", enc, key); err != nil {
+			return 0, err
+		}
+		updated++
+	}
+
+	return updated, tx.Commit()
+}
+
+func rawSecretSettings(q sqlx.Queryer) (map[string]string, error) {
+	query, args, err := sqlx.In("SELECT setting_key, setting_value FROM system_settings WHERE setting_key IN (?)", SecretSettingKeys)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, rows.Err()
+}
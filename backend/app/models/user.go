@@ -2,6 +2,7 @@ package models
 
 import (
 	"crypto/rand"
+	"database/sql"
 	"encoding/hex"
 	"fst/backend/internal/db"
 	"time"
@@ -40,6 +41,9 @@ type User struct {
 	UpdatedAtRaw *time.Time `db:"updated_at" json:"-"`
 	DeletedAtRaw *time.Time `db:"deleted_at" json:"-"`
 
+	// 邮箱唯一约束用的生成列（由数据库根据 email 计算，不在业务中使用，仅为避免扫描报错）
+	EmailUnique *string `db:"email_unique" json:"-"`
+
 	Apikey     *string `db:"apikey" json:"apikey"`
 	UpdateTime *int64  `db:"update_time" json:"update_time"`
 	CreateTime *int64  `db:"create_time" json:"create_time"`
@@ -130,6 +134,10 @@ func GetUserByUsername(username string) (*User, error) {
 
 // GetUserByEmail finds a user by email
 func GetUserByEmail(email string) (*User, error) {
+	// 手机号注册的用户邮箱为空，空值不参与查找
+	if email == "" {
+		return nil, sql.ErrNoRows
+	}
 	var user User
 	err := db.DB.Get(&user, "SELECT * FROM users WHERE email = ? AND delete_time IS NULL", email)
 	if err != nil {
@@ -140,6 +148,10 @@ func GetUserByEmail(email string) (*User, error) {
 
 // GetUserByMobile finds a user by mobile number
 func GetUserByMobile(mobile string) (*User, error) {
+	// 未绑定手机号的用户手机号为空，空值不参与查找
+	if mobile == "" {
+		return nil, sql.ErrNoRows
+	}
 	var user User
 	err := db.DB.Get(&user, "SELECT * FROM users WHERE mobile = ? AND delete_time IS NULL", mobile)
 	if err != nil {
@@ -150,6 +162,10 @@ func GetUserByMobile(mobile string) (*User, error) {
 
 // GetUserByUsernameOrEmail finds a user by username or email
 func GetUserByUsernameOrEmail(identifier string) (*User, error) {
+	// 手机号注册的用户邮箱为空，空值不参与查找
+	if identifier == "" {
+		return nil, sql.ErrNoRows
+	}
 	var user User
 	err := db.DB.Get(&user, "SELECT * FROM users WHERE (username = ? OR email = ?) AND delete_time IS NULL", identifier, identifier)
 	if err != nil {
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fst/backend/internal/db"
+	"log"
+	"time"
+)
+
+// UserLoginDevice 用户登录过的设备，用于识别新设备登录
+type UserLoginDevice struct {
+	ID           uint64 `db:"id" json:"id"`
+	UserID       uint64 `db:"user_id" json:"user_id"`
+	Device       string `db:"device" json:"device"` // 同 user_sessions.device
+	LastIP       string `db:"last_ip" json:"last_ip"`
+	FirstLoginAt int64  `db:"first_login_at" json:"first_login_at"`
+	LastLoginAt  int64  `db:"last_login_at" json:"last_login_at"`
+}
+
+// InitUserLoginDevicesTable 初始化用户登录设备表
+func InitUserLoginDevicesTable() {
+	if db.CheckTableExists("user_login_devices") {
+		return
+	}
+	schema := `CREATE TABLE IF NOT EXISTS user_login_devices (
+		id             BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		user_id        BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+		device         VARCHAR(100) NOT NULL DEFAULT '' COMMENT '设备信息',
+		last_ip        VARCHAR(45)  NOT NULL DEFAULT '' COMMENT '最近登录IP',
+		first_login_at BIGINT       NOT NULL DEFAULT 0 COMMENT '首次登录时间',
+		last_login_at  BIGINT       NOT NULL DEFAULT 0 COMMENT '最近登录时间',
+		UNIQUE KEY idx_user_device (user_id, device)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户登录设备表';`
+	if _, err := db.DB.Exec(schema); err != nil {
+		log.Printf("[Init] Failed to create user_login_devices table: %v", err)
+	} else {
+		log.Println("[Init] Created user_login_devices table")
+	}
+}
+
+// RecordUserLoginDevice 记录一次登录的设备；首次在该设备登录时 isNew 为 true，
+// known 为此前已记录的其他设备数（为 0 时即账号首次被记录，不视为新设备登录）
+func RecordUserLoginDevice(userID uint64, device, ip string) (isNew bool, known int64, err error) {
+	now := time.Now().Unix()
+	result, err := db.DB.Exec(`INSERT INTO user_login_devices (user_id, device, last_ip, first_login_at, last_login_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE last_ip = VALUES(last_ip), last_login_at = VALUES(last_login_at)`,
+		userID, device, ip, now, now)
+	if err != nil {
+		return false, 0, err
+	}
+	// ON DUPLICATE KEY UPDATE：插入返回 1，更新返回 2（或 0）
+	if rows, _ := result.RowsAffected(); rows != 1 {
+		return false, 0, nil
+	}
+	err = db.DB.Get(&known, "SELECT COUNT(*) FROM user_login_devices WHERE user_id = ? AND device != ?", userID, device)
+	return true, known, err
+}
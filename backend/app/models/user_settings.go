@@ -4,6 +4,8 @@ import (
 	"fst/backend/internal/db"
 	"log"
 	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // UserSettings 用户设置模型
@@ -77,3 +79,20 @@ func SaveUserSettings(settings *UserSettings) error {
 
 	return nil
 }
+
+// GetEmailNotifyDisabledEmails 返回给定地址中关闭了邮件通知的用户邮箱（小写）
+// 没有用户设置记录的用户视为开启（notify_email 默认为 1）
+func GetEmailNotifyDisabledEmails(emails []string) ([]string, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT LOWER(u.email) FROM users u
+		JOIN user_settings s ON s.user_id = u.id
+		WHERE s.notify_email = 0 AND u.delete_time IS NULL AND u.email IN (?)`, emails)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	err = db.DB.Select(&list, query, args...)
+	return list, err
+}
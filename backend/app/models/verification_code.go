@@ -12,17 +12,20 @@ func InitVerificationCodeTable() {
 		// 表不存在，创建新表
 		schema := `CREATE TABLE IF NOT EXISTS verification_codes (
 			id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
-			email VARCHAR(255) NOT NULL COMMENT '邮箱地址',
+			target VARCHAR(255) NOT NULL COMMENT '接收目标:邮箱或手机号',
+			channel VARCHAR(10) NOT NULL DEFAULT 'email' COMMENT '渠道:email=邮件,sms=短信',
 			code VARCHAR(10) NOT NULL COMMENT '验证码',
-			code_type VARCHAR(20) NOT NULL COMMENT '类型:register=注册,reset_password=重置密码',
+			code_type VARCHAR(20) NOT NULL COMMENT '类型:register=注册,login=登录,reset_password=重置密码,change_email/change_phone=换绑',
+			ip VARCHAR(50) NOT NULL DEFAULT '' COMMENT '请求IP',
+			attempts INT NOT NULL DEFAULT 0 COMMENT '校验失败次数',
 			expires_at TIMESTAMP NOT NULL COMMENT '过期时间',
 			is_used TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否已使用:0=未使用,1=已使用',
 			is_deleted TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否软删除:0=正常,1=已删除',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
-			INDEX idx_email_type (email, code_type),
-			INDEX idx_email_type_active_created (email, code_type, is_used, is_deleted, created_at),
-			INDEX idx_email_code_type_active (email, code, code_type, is_used, is_deleted),
+			INDEX idx_target_type_active_created (target, channel, code_type, is_used, is_deleted, created_at),
+			INDEX idx_channel_created (channel, created_at),
+			INDEX idx_ip_channel_created (ip, channel, created_at),
 			INDEX idx_expires_at (expires_at),
 			INDEX idx_is_deleted (is_deleted)
 		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;`
@@ -63,6 +66,9 @@ func repairVerificationCodeTable() {
 		"is_deleted": "ALTER TABLE verification_codes ADD COLUMN is_deleted TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否软删除:0=正常,1=已删除'",
 		"created_at": "ALTER TABLE verification_codes ADD COLUMN created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间'",
 		"updated_at": "ALTER TABLE verification_codes ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间'",
+		"channel":    "ALTER TABLE verification_codes ADD COLUMN channel VARCHAR(10) NOT NULL DEFAULT 'email' COMMENT '渠道:email=邮件,sms=短信' AFTER target",
+		"ip":         "ALTER TABLE verification_codes ADD COLUMN ip VARCHAR(50) NOT NULL DEFAULT '' COMMENT '请求IP' AFTER code_type",
+		"attempts":   "ALTER TABLE verification_codes ADD COLUMN attempts INT NOT NULL DEFAULT 0 COMMENT '校验失败次数' AFTER ip",
 	}
 
 	for col, alterSQL := range requiredColumns {
@@ -77,8 +83,9 @@ func repairVerificationCodeTable() {
 	}
 
 	indexRepairs := map[string]string{
-		"idx_email_type_active_created": "ALTER TABLE verification_codes ADD INDEX idx_email_type_active_created (email, code_type, is_used, is_deleted, created_at)",
-		"idx_email_code_type_active":    "ALTER TABLE verification_codes ADD INDEX idx_email_code_type_active (email, code, code_type, is_used, is_deleted)",
+		"idx_target_type_active_created": "ALTER TABLE verification_codes ADD INDEX idx_target_type_active_created (target, channel, code_type, is_used, is_deleted, created_at)",
+		"idx_channel_created":            "ALTER TABLE verification_codes ADD INDEX idx_channel_created (channel, created_at)",
+		"idx_ip_channel_created":         "ALTER TABLE verification_codes ADD INDEX idx_ip_channel_created (ip, channel, created_at)",
 	}
 
 	for indexName, alterSQL := range indexRepairs {
@@ -86,12 +93,21 @@ func repairVerificationCodeTable() {
 	}
 }
 
+// 验证码渠道
+const (
+	CodeChannelEmail = "email" // 邮件验证码，target 为邮箱
+	CodeChannelSMS   = "sms"   // 短信验证码，target 为手机号
+)
+
 // VerificationCode 验证码模型
 type VerificationCode struct {
 	ID        uint64    `db:"id" json:"id"`
-	Email     string    `db:"email" json:"email"`
+	Target    string    `db:"target" json:"target"`   // 邮箱或手机号
+	Channel   string    `db:"channel" json:"channel"` // 见 CodeChannelEmail 等
 	Code      string    `db:"code" json:"code"`
 	CodeType  string    `db:"code_type" json:"code_type"`
+	IP        string    `db:"ip" json:"ip"`
+	Attempts  int       `db:"attempts" json:"attempts"` // 校验失败次数
 	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
 	IsUsed    int       `db:"is_used" json:"is_used"`
 	IsDeleted int       `db:"is_deleted" json:"is_deleted"`
@@ -100,27 +116,27 @@ type VerificationCode struct {
 }
 
 // CreateVerificationCode 创建验证码记录
-func CreateVerificationCode(email, code, codeType string, expiresAt time.Time) error {
-	// 先将该邮箱该类型的旧验证码标记为软删除
+func CreateVerificationCode(channel, target, code, codeType, ip string, expiresAt time.Time) error {
+	// 先将该目标该类型的旧验证码标记为软删除
 	_, err := db.DB.Exec(
-		"UPDATE verification_codes SET is_deleted = 1 WHERE email = ? AND code_type = ? AND is_deleted = 0 AND is_used = 0",
-		email, codeType,
+		"UPDATE verification_codes SET is_deleted = 1 WHERE target = ? AND channel = ? AND code_type = ? AND is_deleted = 0 AND is_used = 0",
+		target, channel, codeType,
 	)
 	if err != nil {
 		return err
 	}
 
-	query := `INSERT INTO verification_codes (email, code, code_type, expires_at, is_used, is_deleted) 
-			  VALUES (?, ?, ?, ?, 0, 0)`
-	_, err = db.DB.Exec(query, email, code, codeType, expiresAt)
+	query := `INSERT INTO verification_codes (target, channel, code, code_type, ip, expires_at, is_used, is_deleted) 
+			  VALUES (?, ?, ?, ?, ?, ?, 0, 0)`
+	_, err = db.DB.Exec(query, target, channel, code, codeType, ip, expiresAt)
 	return err
 }
 
-func HasRecentVerificationCode(email, codeType string, since time.Time) (bool, error) {
+func HasRecentVerificationCode(channel, target, codeType string, since time.Time) (bool, error) {
 	var count int
 	err := db.DB.Get(&count,
-		"SELECT COUNT(*) FROM verification_codes WHERE email = ? AND code_type = ? AND is_used = 0 AND is_deleted = 0 AND created_at >= ?",
-		email, codeType, since,
+		"SELECT COUNT(*) FROM verification_codes WHERE target = ? AND channel = ? AND code_type = ? AND is_used = 0 AND is_deleted = 0 AND created_at >= ?",
+		target, channel, codeType, since,
 	)
 	if err != nil {
 		return false, err
@@ -128,13 +144,33 @@ func HasRecentVerificationCode(email, codeType string, since time.Time) (bool, e
 	return count > 0, nil
 }
 
+// CountVerificationCodesByTarget 统计某目标自 since 起的发送次数（含已使用、已作废的记录，用于发送配额）
+func CountVerificationCodesByTarget(channel, target string, since time.Time) (int, error) {
+	var count int
+	err := db.DB.Get(&count,
+		"SELECT COUNT(*) FROM verification_codes WHERE target = ? AND channel = ? AND created_at >= ?",
+		target, channel, since,
+	)
+	return count, err
+}
+
+// CountVerificationCodesByIP 统计某 IP 自 since 起的发送次数（用于发送配额）
+func CountVerificationCodesByIP(channel, ip string, since time.Time) (int, error) {
+	var count int
+	err := db.DB.Get(&count,
+		"SELECT COUNT(*) FROM verification_codes WHERE ip = ? AND channel = ? AND created_at >= ?",
+		ip, channel, since,
+	)
+	return count, err
+}
+
 // GetValidVerificationCode 获取有效的验证码（未使用、未过期、未软删除）
-func GetValidVerificationCode(email, codeType string) (*VerificationCode, error) {
+func GetValidVerificationCode(channel, target, codeType string) (*VerificationCode, error) {
 	var vc VerificationCode
 	query := `SELECT * FROM verification_codes 
-			  WHERE email = ? AND code_type = ? AND is_used = 0 AND is_deleted = 0 AND expires_at > NOW()
+			  WHERE target = ? AND channel = ? AND code_type = ? AND is_used = 0 AND is_deleted = 0 AND expires_at > NOW()
 			  ORDER BY created_at DESC LIMIT 1`
-	err := db.DB.Get(&vc, query, email, codeType)
+	err := db.DB.Get(&vc, query, target, channel, codeType)
 	if err != nil {
 		return nil, err
 	}
@@ -147,18 +183,18 @@ func MarkVerificationCodeAsUsed(id uint64) error {
 	return err
 }
 
-func ConsumeVerificationCode(email, code, codeType string) (bool, error) {
+func ConsumeVerificationCode(channel, target, code, codeType string) (bool, error) {
 	result, err := db.DB.Exec(
 		`UPDATE verification_codes
 		 SET is_used = 1
 		 WHERE id = (
 		 	SELECT id FROM (
 		 		SELECT id FROM verification_codes
-		 		WHERE email = ? AND code = ? AND code_type = ? AND is_used = 0 AND is_deleted = 0 AND expires_at > NOW()
+		 		WHERE target = ? AND channel = ? AND code = ? AND code_type = ? AND is_used = 0 AND is_deleted = 0 AND expires_at > NOW()
 		 		ORDER BY created_at DESC LIMIT 1
 		 	) AS latest
 		 ) AND is_used = 0`,
-		email, code, codeType,
+		target, channel, code, codeType,
 	)
 	if err != nil {
 		return false, err
@@ -170,16 +206,35 @@ func ConsumeVerificationCode(email, code, codeType string) (bool, error) {
 	return rows > 0, nil
 }
 
+// RecordVerificationCodeFailure 记录一次校验失败，失败次数达到 maxAttempts 的验证码立即作废
+func RecordVerificationCodeFailure(channel, target, codeType string, maxAttempts int) error {
+	_, err := db.DB.Exec(
+		`UPDATE verification_codes SET attempts = attempts + 1
+		 WHERE target = ? AND channel = ? AND code_type = ? AND is_used = 0 AND is_deleted = 0 AND expires_at > NOW()`,
+		target, channel, codeType,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = db.DB.Exec(
+		`UPDATE verification_codes SET is_deleted = 1
+		 WHERE target = ? AND channel = ? AND code_type = ? AND is_deleted = 0 AND attempts >= ?`,
+		target, channel, codeType, maxAttempts,
+	)
+	return err
+}
+
 // MarkVerificationCodeAsDeleted 软删除验证码
 func MarkVerificationCodeAsDeleted(id uint64) error {
 	_, err := db.DB.Exec("UPDATE verification_codes SET is_deleted = 1 WHERE id = ?", id)
 	return err
 }
 
-// DeleteVerificationCodesByEmail 彻底删除指定邮箱的所有验证码记录（用于注册/重置成功后清理）
-func DeleteVerificationCodesByEmail(email string, codeType string) error {
-	query := `DELETE FROM verification_codes WHERE email = ?`
-	args := []interface{}{email}
+// DiscardVerificationCodes 作废指定目标的验证码（用于注册/重置成功或发送失败后清理）
+// 只做软删除，记录保留到 CleanupOldVerificationCodes 清理，以便发送配额统计
+func DiscardVerificationCodes(channel, target, codeType string) error {
+	query := `UPDATE verification_codes SET is_deleted = 1 WHERE target = ? AND channel = ? AND is_deleted = 0`
+	args := []interface{}{target, channel}
 	if codeType != "" {
 		query += ` AND code_type = ?`
 		args = append(args, codeType)
@@ -203,12 +258,12 @@ func CleanupOldVerificationCodes() error {
 }
 
 // VerifyCode 验证验证码是否正确（改进版：直接匹配代码）
-func VerifyCode(email, code, codeType string) (bool, uint64, error) {
+func VerifyCode(channel, target, code, codeType string) (bool, uint64, error) {
 	var vc VerificationCode
 	query := `SELECT id, code, expires_at FROM verification_codes 
-			  WHERE email = ? AND code = ? AND code_type = ? AND is_used = 0 AND is_deleted = 0 
+			  WHERE target = ? AND channel = ? AND code = ? AND code_type = ? AND is_used = 0 AND is_deleted = 0 
 			  ORDER BY created_at DESC LIMIT 1`
-	err := db.DB.Get(&vc, query, email, code, codeType)
+	err := db.DB.Get(&vc, query, target, channel, code, codeType)
 	if err != nil {
 		return false, 0, err
 	}
@@ -0,0 +1,195 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 配置项类型
+const (
+	FieldString = "string"
+	FieldInt    = "int"
+	FieldFloat  = "float"
+	FieldBool   = "bool"
+)
+
+// SecretMaskedValue 敏感配置项在接口中的掩码，提交掩码表示保持原值
+const SecretMaskedValue = "********"
+
+// ConfigField 插件配置项声明
+type ConfigField struct {
+	Key         string      `json:"key"`
+	Type        string      `json:"type"` // string / int / float / bool
+	Default     interface{} `json:"default,omitempty"`
+	Required    bool        `json:"required"`
+	Secret      bool        `json:"secret"`            // 敏感项：加密存储，接口中掩码显示
+	Options     []string    `json:"options,omitempty"` // 可选值（仅 string）
+	Description string      `json:"description"`
+}
+
+// Configurable 声明配置结构的插件（可选接口）
+// 未实现的插件按旧方式接收 RegisterWithConfig 传入的配置
+type Configurable interface {
+	ConfigSchema() []ConfigField
+}
+
+// ConfigStore 插件配置持久化
+type ConfigStore interface {
+	// LoadConfig 读取插件已保存的配置，未保存时返回空 map
+	LoadConfig(name string) (map[string]interface{}, error)
+	// SaveConfig 保存插件配置
+	SaveConfig(name string, values map[string]interface{}) error
+}
+
+// ConfigError 配置校验错误
+type ConfigError struct {
+	Fields map[string]string // 配置项 -> 错误原因
+}
+
+func (e *ConfigError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+": "+e.Fields[k])
+	}
+	return "配置校验失败: " + strings.Join(parts, "; ")
+}
+
+// ValidateConfig 按声明校验配置：拒绝未声明的配置项，转换类型并填充默认值
+func ValidateConfig(schema []ConfigField, values map[string]interface{}) (PluginConfig, error) {
+	fields := make(map[string]string)
+	declared := make(map[string]bool, len(schema))
+	result := make(PluginConfig, len(schema))
+
+	for _, field := range schema {
+		declared[field.Key] = true
+
+		raw, ok := values[field.Key]
+		if !ok || raw == nil {
+			raw = field.Default
+		}
+		if raw == nil {
+			if field.Required {
+				fields[field.Key] = "不能为空"
+			}
+			continue
+		}
+
+		value, err := convertConfigValue(field.Type, raw)
+		if err != nil {
+			fields[field.Key] = err.Error()
+			continue
+		}
+		if s, ok := value.(string); ok {
+			if field.Required && s == "" {
+				fields[field.Key] = "不能为空"
+				continue
+			}
+			if len(field.Options) > 0 && s != "" && !containsString(field.Options, s) {
+				fields[field.Key] = "可选值: " + strings.Join(field.Options, ", ")
+				continue
+			}
+		}
+		result[field.Key] = value
+	}
+
+	for key := range values {
+		if !declared[key] {
+			fields[key] = "未声明的配置项"
+		}
+	}
+
+	if len(fields) > 0 {
+		return nil, &ConfigError{Fields: fields}
+	}
+	return result, nil
+}
+
+// convertConfigValue 将 JSON 解码后的值转换为声明的类型
+func convertConfigValue(typ string, raw interface{}) (interface{}, error) {
+	switch typ {
+	case FieldString:
+		if s, ok := raw.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("应为字符串")
+	case FieldBool:
+		switch v := raw.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("应为布尔值")
+	case FieldInt:
+		switch v := raw.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int(v), nil
+			}
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				return int(i), nil
+			}
+		case string:
+			if i, err := strconv.Atoi(v); err == nil {
+				return i, nil
+			}
+		}
+		return nil, fmt.Errorf("应为整数")
+	case FieldFloat:
+		switch v := raw.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return f, nil
+			}
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("应为数字")
+	}
+	return nil, fmt.Errorf("未知的配置类型 %s", typ)
+}
+
+// MaskConfig 返回掩码敏感项后的配置副本
+func MaskConfig(schema []ConfigField, values PluginConfig) PluginConfig {
+	masked := make(PluginConfig, len(values))
+	for k, v := range values {
+		masked[k] = v
+	}
+	for _, field := range schema {
+		if s, ok := masked[field.Key].(string); ok && field.Secret && s != "" {
+			masked[field.Key] = SecretMaskedValue
+		}
+	}
+	return masked
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
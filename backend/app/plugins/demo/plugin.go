@@ -1,10 +1,19 @@
 package demo
 
 import (
+	"context"
+	"errors"
 	"fst/backend/app/plugins"
+	"fst/backend/internal/events"
+	"fst/backend/internal/openapi"
+	"fst/backend/internal/scheduler"
 	"fst/backend/pkg/pluginregistry"
 	"fst/backend/utils"
 	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,7 +27,11 @@ func init() {
 // 展示如何实现完整的插件接口
 type DemoPlugin struct {
 	plugins.BasePlugin
+	mu     sync.RWMutex
 	config map[string]interface{}
+	subs   []*events.Subscription
+
+	heartbeats atomic.Int64 // 定时任务执行次数
 }
 
 // NewPlugin 创建示例插件实例
@@ -38,15 +51,36 @@ func NewPlugin() plugins.Plugin {
 	return p
 }
 
-// Configure 接收配置
+// ConfigSchema 声明配置项，可在管理端 /api/v1/admin/plugins/demo-plugin/config 修改
+func (p *DemoPlugin) ConfigSchema() []plugins.ConfigField {
+	return []plugins.ConfigField{
+		{Key: "greeting", Type: plugins.FieldString, Default: "Hello from Demo Plugin!", Description: "Hello 接口返回的问候语"},
+		{Key: "echo_enabled", Type: plugins.FieldBool, Default: true, Description: "是否开放 Echo 接口"},
+		{Key: "max_echo_keys", Type: plugins.FieldInt, Default: 20, Description: "Echo 接口允许的最大字段数"},
+		{Key: "api_token", Type: plugins.FieldString, Secret: true, Description: "示例敏感配置（加密存储，不会回显）"},
+		{Key: "blocked_email_domain", Type: plugins.FieldString, Description: "拒绝该邮箱域名注册（演示 before 钩子，留空不限制）"},
+	}
+}
+
+// Configure 接收配置，配置在管理端修改后会再次调用
 func (p *DemoPlugin) Configure(config map[string]interface{}) error {
-	if config != nil {
-		p.config = config
-		log.Printf("[DemoPlugin] 配置已加载: %v", config)
+	if config == nil {
+		return nil
 	}
+	p.mu.Lock()
+	p.config = config
+	p.mu.Unlock()
+	log.Printf("[DemoPlugin] 配置已加载: %v", plugins.MaskConfig(p.ConfigSchema(), config))
 	return nil
 }
 
+// getConfig 读取当前配置项
+func (p *DemoPlugin) getConfig(key string) interface{} {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config[key]
+}
+
 // Migrate 数据库迁移
 func (p *DemoPlugin) Migrate() error {
 	// 示例：创建插件所需的数据表
@@ -55,24 +89,92 @@ func (p *DemoPlugin) Migrate() error {
 	return nil
 }
 
-// Init 初始化插件
+// Init 初始化插件，订阅核心事件
 func (p *DemoPlugin) Init() error {
+	p.subs = append(p.subs,
+		events.UserBeforeRegister.Subscribe(p.onBeforeRegister),
+		events.UserRegistered.SubscribeAsync(func(ctx context.Context, e events.UserRegisteredEvent) error {
+			log.Printf("[DemoPlugin] 新用户注册: id=%d username=%s", e.UserID, e.Username)
+			return nil
+		}),
+	)
 	log.Println("[DemoPlugin] 初始化完成")
 	return nil
 }
 
-// RegisterRoutes 注册路由
-func (p *DemoPlugin) RegisterRoutes(router *gin.RouterGroup) {
-	// 示例路由
-	router.GET("/demo/hello", p.helloHandler)
-	router.GET("/demo/info", p.infoHandler)
-	router.POST("/demo/echo", p.echoHandler)
+// onBeforeRegister 注册前钩子：返回错误即拒绝注册
+func (p *DemoPlugin) onBeforeRegister(ctx context.Context, req events.UserRegistration) error {
+	domain, _ := p.getConfig("blocked_email_domain").(string)
+	if domain != "" && strings.HasSuffix(strings.ToLower(req.Email), "@"+strings.ToLower(domain)) {
+		return events.Reject("该邮箱域名不允许注册")
+	}
+	return nil
+}
+
+// RegisterGroupRoutes 按权限分组注册路由，路由位于 /api/v1/plugins/demo-plugin/ 下
+func (p *DemoPlugin) RegisterGroupRoutes(groups plugins.RouteGroups) {
+	// 公开接口
+	groups.Public.GET("/hello", p.helloHandler)
+	groups.Describe(groups.Public, "GET", "/hello", openapi.Op("Demo插件Hello", "Plugin-Demo").Notes("示例插件的Hello接口").
+		Returns(nil))
+	groups.Public.GET("/info", p.infoHandler)
+	groups.Describe(groups.Public, "GET", "/info", openapi.Op("Demo插件信息", "Plugin-Demo").Notes("获取Demo插件的详细信息").
+		Returns(nil))
+
+	// 登录用户接口
+	groups.User.POST("/echo", p.echoHandler)
+	groups.Describe(groups.User, "POST", "/echo", openapi.Op("Demo插件Echo", "Plugin-Demo").Notes("回显请求数据（需要登录）").Auth().
+		Body(map[string]interface{}{}, "请求数据").Returns(nil))
 
 	log.Println("[DemoPlugin] 路由注册完成")
 }
 
+// HealthCheck 健康检查，汇总到 /api/v1/admin/plugins/health
+func (p *DemoPlugin) HealthCheck(ctx context.Context) error {
+	// 示例：实际插件可在此检查数据库连接、第三方接口等
+	if p.getConfig("greeting") == nil {
+		return errors.New("配置未加载")
+	}
+	return nil
+}
+
+// ScheduledTasks 定时任务，注册后在 /api/v1/admin/jobs 中显示为 plugin.demo-plugin.heartbeat
+func (p *DemoPlugin) ScheduledTasks() []scheduler.Job {
+	return []scheduler.Job{
+		{
+			Name:        "heartbeat",
+			Description: "示例插件心跳",
+			Spec:        "*/30 * * * *",
+			Timeout:     time.Minute,
+			Run: func(ctx context.Context) error {
+				log.Printf("[DemoPlugin] 心跳 #%d", p.heartbeats.Add(1))
+				return nil
+			},
+		},
+	}
+}
+
+// AdminMenu 管理端菜单，通过 /api/v1/admin/plugins/menus 下发给前端
+func (p *DemoPlugin) AdminMenu() []plugins.MenuItem {
+	return []plugins.MenuItem{
+		{Name: "overview", Title: "示例插件", Icon: "icon-park-outline:plug", Path: "/plugins/demo-plugin", Order: 900, Permission: "view"},
+	}
+}
+
+// AdminPermissions 插件声明的权限
+func (p *DemoPlugin) AdminPermissions() []plugins.Permission {
+	return []plugins.Permission{
+		{Key: "view", Title: "查看示例插件"},
+	}
+}
+
 // Shutdown 关闭插件
 func (p *DemoPlugin) Shutdown() error {
+	// 取消订阅，避免禁用或重新初始化后重复处理事件
+	for _, sub := range p.subs {
+		sub.Unsubscribe()
+	}
+	p.subs = nil
 	log.Println("[DemoPlugin] 已关闭")
 	return nil
 }
@@ -82,28 +184,14 @@ func (p *DemoPlugin) Shutdown() error {
 // ========================================
 
 // helloHandler 示例Hello接口
-// @Summary Demo插件Hello
-// @Description 示例插件的Hello接口
-// @Tags Plugin-Demo
-// @Accept json
-// @Produce json
-// @Success 200 {object} utils.Response
-// @Router /api/v1/demo/hello [get]
 func (p *DemoPlugin) helloHandler(c *gin.Context) {
 	utils.Success(c, gin.H{
-		"message": "Hello from Demo Plugin!",
+		"message": p.getConfig("greeting"),
 		"version": p.Version(),
 	})
 }
 
 // infoHandler 获取插件信息
-// @Summary Demo插件信息
-// @Description 获取Demo插件的详细信息
-// @Tags Plugin-Demo
-// @Accept json
-// @Produce json
-// @Success 200 {object} utils.Response
-// @Router /api/v1/demo/info [get]
 func (p *DemoPlugin) infoHandler(c *gin.Context) {
 	utils.Success(c, gin.H{
 		"name":         p.Name(),
@@ -115,23 +203,27 @@ func (p *DemoPlugin) infoHandler(c *gin.Context) {
 }
 
 // echoHandler Echo接口
-// @Summary Demo插件Echo
-// @Description 回显请求数据
-// @Tags Plugin-Demo
-// @Accept json
-// @Produce json
-// @Param body body map[string]interface{} true "请求数据"
-// @Success 200 {object} utils.Response
-// @Router /api/v1/demo/echo [post]
 func (p *DemoPlugin) echoHandler(c *gin.Context) {
+	if enabled, _ := p.getConfig("echo_enabled").(bool); !enabled {
+		utils.Fail(c, 403, "Echo 接口已关闭")
+		return
+	}
+
 	var body map[string]interface{}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		utils.Fail(c, 400, "请求体格式错误")
 		return
 	}
+	if max, ok := p.getConfig("max_echo_keys").(int); ok && len(body) > max {
+		utils.Fail(c, 400, "字段数量超出限制")
+		return
+	}
 
+	p.mu.RLock()
+	config := plugins.MaskConfig(p.ConfigSchema(), p.config)
+	p.mu.RUnlock()
 	utils.Success(c, gin.H{
 		"echo":   body,
-		"config": p.config,
+		"config": config,
 	})
 }
@@ -0,0 +1,208 @@
+package plugins
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dependency 插件依赖声明
+//
+// 格式为 "<插件名>[<约束>[,<约束>...]]"，例如：
+//
+//	payment-ext            任意版本
+//	payment-ext>=1.2       不低于 1.2.0
+//	payment-ext>=1.2,<2    1.2.0 及以上、2.0.0 以下
+//	payment-ext^1.4        兼容 1.4.0（>=1.4.0 <2.0.0；0.x 时次版本号不变）
+//	payment-ext~1.4.2      >=1.4.2 <1.5.0
+//
+// 支持的比较符：= == != > >= < <= ^ ~
+type Dependency struct {
+	Name        string
+	Constraints []VersionConstraint
+}
+
+// VersionConstraint 单个版本约束
+type VersionConstraint struct {
+	Op      string
+	Version string
+}
+
+// 比较符按长度优先匹配
+var constraintOps = []string{">=", "<=", "==", "!=", ">", "<", "=", "^", "~"}
+
+// ParseDependency 解析依赖声明
+func ParseDependency(s string) (Dependency, error) {
+	s = strings.TrimSpace(s)
+	idx := strings.IndexAny(s, "<>=!^~")
+	if idx < 0 {
+		if s == "" {
+			return Dependency{}, fmt.Errorf("依赖名称为空")
+		}
+		return Dependency{Name: s}, nil
+	}
+
+	dep := Dependency{Name: strings.TrimSpace(s[:idx])}
+	if dep.Name == "" {
+		return Dependency{}, fmt.Errorf("依赖 %q 缺少插件名称", s)
+	}
+	for _, part := range strings.Split(s[idx:], ",") {
+		c, err := parseConstraint(strings.TrimSpace(part))
+		if err != nil {
+			return Dependency{}, fmt.Errorf("依赖 %q: %v", s, err)
+		}
+		dep.Constraints = append(dep.Constraints, c)
+	}
+	return dep, nil
+}
+
+func parseConstraint(s string) (VersionConstraint, error) {
+	for _, op := range constraintOps {
+		if rest, ok := strings.CutPrefix(s, op); ok {
+			version := strings.TrimSpace(rest)
+			if _, err := parseVersion(version); err != nil {
+				return VersionConstraint{}, err
+			}
+			return VersionConstraint{Op: op, Version: version}, nil
+		}
+	}
+	return VersionConstraint{}, fmt.Errorf("无法识别的版本约束 %q", s)
+}
+
+// Satisfied 版本是否满足全部约束
+func (d Dependency) Satisfied(version string) bool {
+	for _, c := range d.Constraints {
+		if !c.match(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// String 还原为依赖声明格式
+func (d Dependency) String() string {
+	parts := make([]string, 0, len(d.Constraints))
+	for _, c := range d.Constraints {
+		parts = append(parts, c.Op+c.Version)
+	}
+	return d.Name + strings.Join(parts, ",")
+}
+
+func (c VersionConstraint) match(version string) bool {
+	v, err := parseVersion(version)
+	if err != nil {
+		return false
+	}
+	want, _ := parseVersion(c.Version)
+	cmp := v.compare(want)
+
+	switch c.Op {
+	case "=", "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "^":
+		upper := semver{major: want.major + 1}
+		if want.major == 0 {
+			upper = semver{minor: want.minor + 1}
+		}
+		return cmp >= 0 && v.compare(upper) < 0
+	case "~":
+		return cmp >= 0 && v.compare(semver{major: want.major, minor: want.minor + 1}) < 0
+	}
+	return false
+}
+
+// CompareVersions 比较两个语义化版本，a<b 返回 -1，相等返回 0，a>b 返回 1
+// 无法解析的版本视为 0.0.0
+func CompareVersions(a, b string) int {
+	va, _ := parseVersion(a)
+	vb, _ := parseVersion(b)
+	return va.compare(vb)
+}
+
+// semver 语义化版本（缺省的次版本号、修订号为 0，忽略 +build 元数据）
+type semver struct {
+	major, minor, patch int
+	pre                 string
+}
+
+func parseVersion(s string) (semver, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, _ := strings.Cut(s, "-")
+
+	parts := strings.Split(core, ".")
+	if core == "" || len(parts) > 3 {
+		return semver{}, fmt.Errorf("版本号 %q 格式错误", s)
+	}
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semver{}, fmt.Errorf("版本号 %q 格式错误", s)
+		}
+		nums[i] = n
+	}
+	return semver{major: nums[0], minor: nums[1], patch: nums[2], pre: pre}, nil
+}
+
+func (v semver) compare(o semver) int {
+	for _, d := range []int{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	// 预发布版本低于正式版本
+	switch {
+	case v.pre == o.pre:
+		return 0
+	case v.pre == "":
+		return 1
+	case o.pre == "":
+		return -1
+	}
+	return comparePrerelease(v.pre, o.pre)
+}
+
+// comparePrerelease 按点分段比较，数字段按数值比较
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
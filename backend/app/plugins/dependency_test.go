@@ -0,0 +1,47 @@
+package plugins
+
+import "testing"
+
+func TestDependencyConstraints(t *testing.T) {
+	cases := []struct {
+		dep     string
+		version string
+		want    bool
+	}{
+		{"pay", "0.1.0", true},
+		{"pay>=1.2", "1.2.0", true},
+		{"pay>=1.2", "1.10.0", true},
+		{"pay>=1.2", "1.1.9", false},
+		{"pay>=1.2,<2", "2.0.0", false},
+		{"pay>=1.2,<2", "1.9.3", true},
+		{"pay^1.4", "1.9.0", true},
+		{"pay^1.4", "2.0.0", false},
+		{"pay^0.3.1", "0.3.9", true},
+		{"pay^0.3.1", "0.4.0", false},
+		{"pay~1.4.2", "1.4.9", true},
+		{"pay~1.4.2", "1.5.0", false},
+		{"pay=1.0", "v1.0.0", true},
+		{"pay!=1.0", "1.0.0", false},
+		{"pay>=1.0.0", "1.0.0-beta.2", false},
+		{"pay>1.0.0-beta.2", "1.0.0-beta.10", true},
+		{"pay>=1.2", "dev", false},
+	}
+	for _, c := range cases {
+		dep, err := ParseDependency(c.dep)
+		if err != nil {
+			t.Fatalf("%s: %v", c.dep, err)
+		}
+		if dep.Name != "pay" {
+			t.Fatalf("%s: name = %q", c.dep, dep.Name)
+		}
+		if got := dep.Satisfied(c.version); got != c.want {
+			t.Errorf("%s satisfied by %s = %v, want %v", c.dep, c.version, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"", ">=1.0", "pay>=x.y", "pay>>1"} {
+		if _, err := ParseDependency(bad); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"fst/backend/internal/scheduler"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 健康状态
+const (
+	HealthUp       = "up"
+	HealthDown     = "down"
+	HealthOK       = "ok"       // 汇总：全部正常
+	HealthDegraded = "degraded" // 汇总：存在异常插件
+)
+
+// healthCheckTimeout 单个插件健康检查超时
+const healthCheckTimeout = 5 * time.Second
+
+// HealthChecker 提供健康检查的插件（可选接口）
+type HealthChecker interface {
+	// HealthCheck 返回 nil 表示正常，ctx 带超时
+	HealthCheck(ctx context.Context) error
+}
+
+// ScheduledTasks 提供定时任务的插件（可选接口）
+// 任务名会加上 plugin.<插件名>. 前缀，插件未启用时触发会跳过执行
+type ScheduledTasks interface {
+	ScheduledTasks() []scheduler.Job
+}
+
+// MenuItem 管理端菜单项
+type MenuItem struct {
+	Name       string `json:"name"`                 // 唯一标识（插件内），返回时加 <插件名>. 前缀
+	Title      string `json:"title"`                // 菜单标题
+	Icon       string `json:"icon,omitempty"`       // 图标，如 icon-park-outline:plug
+	Path       string `json:"path,omitempty"`       // 前端路由路径
+	Href       string `json:"href,omitempty"`       // 外链（与 Path 二选一）
+	Order      int    `json:"order"`                // 排序，越小越靠前
+	Permission string `json:"permission,omitempty"` // 访问所需权限，需在 AdminPermissions 中声明
+}
+
+// Permission 插件声明的权限
+type Permission struct {
+	Key   string `json:"key"` // 插件内唯一，返回时加 <插件名>. 前缀
+	Title string `json:"title"`
+}
+
+// AdminMenu 提供管理端菜单与权限的插件（可选接口）
+type AdminMenu interface {
+	AdminMenu() []MenuItem
+	AdminPermissions() []Permission
+}
+
+// JobRegistrar 定时任务注册（*scheduler.Scheduler 已实现）
+type JobRegistrar interface {
+	Register(job scheduler.Job) error
+}
+
+// PluginHealth 单个插件的健康状态
+type PluginHealth struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"` // up / down
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// HealthReport 插件健康汇总
+type HealthReport struct {
+	Status  string         `json:"status"` // ok / degraded
+	Plugins []PluginHealth `json:"plugins"`
+}
+
+// AdminMenus 管理端菜单与权限汇总
+type AdminMenus struct {
+	Menus       []MenuItem   `json:"menus"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// pluginMenu 插件加载时收集的菜单与权限（已加前缀）
+type pluginMenu struct {
+	menus       []MenuItem
+	permissions []Permission
+}
+
+// SetJobRegistrar 设置定时任务注册器，需在 LoadAll 之前调用
+func (m *Manager) SetJobRegistrar(jobs JobRegistrar) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs = jobs
+}
+
+// wireExtensions 插件加载成功后接入可选接口，调用方需持有写锁
+func (m *Manager) wireExtensions(name string, p Plugin) {
+	if tasks, ok := p.(ScheduledTasks); ok {
+		m.registerTasks(name, tasks)
+	}
+	if menu, ok := p.(AdminMenu); ok {
+		m.menus[name] = collectMenu(name, menu)
+	}
+}
+
+// registerTasks 注册插件的定时任务
+// 调度器不支持注销任务，每个插件只注册一次，禁用期间由包装函数跳过执行
+func (m *Manager) registerTasks(name string, tasks ScheduledTasks) {
+	if m.jobs == nil || m.tasksRegistered[name] {
+		return
+	}
+	m.tasksRegistered[name] = true
+
+	for _, job := range tasks.ScheduledTasks() {
+		job.Name = "plugin." + name + "." + job.Name
+		run := job.Run
+		job.Run = func(ctx context.Context) error {
+			if !m.IsActive(name) {
+				log.Printf("[Plugin] %s 未启用，跳过定时任务", name)
+				return nil
+			}
+			return run(ctx)
+		}
+		if err := m.jobs.Register(job); err != nil {
+			log.Printf("[Plugin] %s 注册定时任务失败: %v", name, err)
+		}
+	}
+}
+
+// collectMenu 收集插件菜单，给名称与权限加上插件名前缀，未声明的权限记录日志并忽略
+func collectMenu(name string, menu AdminMenu) pluginMenu {
+	var result pluginMenu
+	declared := make(map[string]bool)
+	for _, perm := range menu.AdminPermissions() {
+		declared[perm.Key] = true
+		perm.Key = name + "." + perm.Key
+		result.permissions = append(result.permissions, perm)
+	}
+	for _, item := range menu.AdminMenu() {
+		if item.Permission != "" {
+			if !declared[item.Permission] {
+				log.Printf("[Plugin] %s 菜单 %s 使用了未声明的权限 %s，已忽略", name, item.Name, item.Permission)
+				continue
+			}
+			item.Permission = name + "." + item.Permission
+		}
+		item.Name = name + "." + item.Name
+		result.menus = append(result.menus, item)
+	}
+	return result
+}
+
+// GetAdminMenus 返回已启用插件的管理端菜单与权限，菜单按 Order 排序
+func (m *Manager) GetAdminMenus() AdminMenus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := AdminMenus{Menus: []MenuItem{}, Permissions: []Permission{}}
+	for _, name := range m.sortByPriority() {
+		menu, ok := m.menus[name]
+		if !ok || !m.active(name) {
+			continue
+		}
+		result.Menus = append(result.Menus, menu.menus...)
+		result.Permissions = append(result.Permissions, menu.permissions...)
+	}
+	sort.SliceStable(result.Menus, func(i, j int) bool { return result.Menus[i].Order < result.Menus[j].Order })
+	return result
+}
+
+// CheckHealth 并发检查已启用插件的健康状态
+// 加载失败或因依赖跳过的插件记为 down，禁用的插件不参与检查
+func (m *Manager) CheckHealth(ctx context.Context) HealthReport {
+	type check struct {
+		name    string
+		checker HealthChecker
+	}
+
+	m.mu.RLock()
+	var checks []check
+	var results []PluginHealth
+	for _, name := range m.sortByPriority() {
+		if m.disabled[name] {
+			continue
+		}
+		if err, ok := m.errors[name]; ok {
+			results = append(results, PluginHealth{Name: name, Status: HealthDown, Error: err.Error()})
+			continue
+		}
+		if err, ok := m.skipped[name]; ok {
+			results = append(results, PluginHealth{Name: name, Status: HealthDown, Error: err.Error()})
+			continue
+		}
+		if checker, ok := m.pm.plugins[name].(HealthChecker); ok && m.loaded[name] {
+			checks = append(checks, check{name, checker})
+		}
+	}
+	m.mu.RUnlock()
+
+	checked := make([]PluginHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			checked[i] = runHealthCheck(ctx, c.name, c.checker)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := HealthReport{Status: HealthOK, Plugins: append(results, checked...)}
+	sort.Slice(report.Plugins, func(i, j int) bool { return report.Plugins[i].Name < report.Plugins[j].Name })
+	for _, h := range report.Plugins {
+		if h.Status != HealthUp {
+			report.Status = HealthDegraded
+		}
+	}
+	return report
+}
+
+func runHealthCheck(ctx context.Context, name string, checker HealthChecker) (result PluginHealth) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	result = PluginHealth{Name: name, Status: HealthUp}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Status, result.Error = HealthDown, fmt.Sprintf("panic: %v", r)
+		}
+		result.LatencyMs = time.Since(start).Milliseconds()
+	}()
+
+	if err := checker.HealthCheck(ctx); err != nil {
+		result.Status, result.Error = HealthDown, strings.TrimSpace(err.Error())
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package external
+
+import (
+	"fst/backend/app/plugins"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Discover 扫描目录下的进程外插件
+// 目录结构：<dir>/<插件名>/<插件名>（Windows 下为 <插件名>.exe），目录名即插件名。
+// 读取清单失败的插件记录日志后跳过，不影响其他插件。
+func Discover(dir string) []plugins.Plugin {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[Plugin] 读取进程外插件目录失败: %v", err)
+		}
+		return nil
+	}
+
+	var result []plugins.Plugin
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		exe := filepath.Join(dir, name, name)
+		if runtime.GOOS == "windows" {
+			exe += ".exe"
+		}
+		if info, err := os.Stat(exe); err != nil || info.IsDir() {
+			continue
+		}
+
+		p, err := New(name, exe)
+		if err != nil {
+			log.Printf("[Plugin] 进程外插件 %s 无法加载: %v", name, err)
+			continue
+		}
+		log.Printf("[Plugin] 发现进程外插件 %s v%s", name, p.Version())
+		result = append(result, p.Adapt())
+	}
+	return result
+}
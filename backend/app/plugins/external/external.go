@@ -0,0 +1,506 @@
+// Package external 进程外插件
+//
+// 插件是独立的可执行文件，按 pkg/pluginsdk 定义的协议与主程序通信。
+// Plugin 将其适配为 plugins.Plugin：生命周期调用转为 RPC，路由请求鉴权后反向代理到插件进程，
+// 清单中订阅的核心事件以 JSON 推送；插件进程异常退出时按退避间隔自动重启并恢复配置与初始化。
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fst/backend/app/plugins"
+	"fst/backend/internal/events"
+	"fst/backend/internal/openapi"
+	"fst/backend/pkg/pluginsdk"
+	"fst/backend/utils"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rpcTimeout 生命周期与事件调用的超时
+const rpcTimeout = 10 * time.Second
+
+// ErrNotRunning 插件进程未运行（正在重启或已关闭）
+var ErrNotRunning = errors.New("插件进程未运行")
+
+// remoteError 插件返回的业务错误（code 非 200）
+type remoteError struct {
+	Code    int
+	Message string
+}
+
+func (e *remoteError) Error() string {
+	return e.Message
+}
+
+// Plugin 进程外插件适配器
+type Plugin struct {
+	name     string
+	path     string
+	args     []string
+	manifest pluginsdk.Manifest
+	client   *http.Client
+
+	mu       sync.Mutex
+	proc     *process
+	config   map[string]interface{}
+	running  bool // 已初始化且未关闭，进程退出时需要重启
+	restarts int  // 连续重启次数
+	subs     []*events.Subscription
+}
+
+// New 启动一次插件进程读取清单后关闭，清单中的名称必须与 name 一致
+func New(name, path string, args ...string) (*Plugin, error) {
+	p := &Plugin{
+		name:   name,
+		path:   path,
+		args:   args,
+		client: &http.Client{Timeout: rpcTimeout},
+	}
+
+	proc, err := startProcess(name, path, args)
+	if err != nil {
+		return nil, err
+	}
+	err = p.rpc(context.Background(), proc, http.MethodGet, pluginsdk.PathManifest, nil, &p.manifest)
+	p.stopProcess(proc)
+	if err != nil {
+		return nil, fmt.Errorf("读取插件清单失败: %v", err)
+	}
+	if p.manifest.Name != name {
+		return nil, fmt.Errorf("插件清单名称 %q 与目录名 %q 不一致", p.manifest.Name, name)
+	}
+	if p.manifest.Protocol != pluginsdk.ProtocolVersion {
+		return nil, fmt.Errorf("协议版本不兼容: 插件 %d，主程序 %d", p.manifest.Protocol, pluginsdk.ProtocolVersion)
+	}
+	for _, sub := range p.manifest.Events {
+		if !events.Known(sub.Name) {
+			return nil, fmt.Errorf("订阅了未定义的事件 %s", sub.Name)
+		}
+	}
+	return p, nil
+}
+
+// Adapt 返回注册到管理器的插件：声明了配置项时同时实现 plugins.Configurable
+func (p *Plugin) Adapt() plugins.Plugin {
+	if len(p.manifest.ConfigSchema) > 0 {
+		return &configurablePlugin{p}
+	}
+	return p
+}
+
+// ========================================
+// plugins.Plugin
+// ========================================
+
+func (p *Plugin) Name() string        { return p.name }
+func (p *Plugin) Version() string     { return p.manifest.Version }
+func (p *Plugin) Description() string { return p.manifest.Description }
+
+// Priority 清单未设置时默认 100，与 BasePlugin 一致
+func (p *Plugin) Priority() int {
+	if p.manifest.Priority == 0 {
+		return 100
+	}
+	return p.manifest.Priority
+}
+
+func (p *Plugin) Dependencies() []string { return p.manifest.Dependencies }
+
+// Configure 启动插件进程（未运行时）并下发配置
+// 启动进程与 RPC 期间不持有锁，转发中的请求不受影响
+func (p *Plugin) Configure(config map[string]interface{}) error {
+	proc, err := p.ensureProcess()
+	if err != nil {
+		return err
+	}
+	if err := p.rpc(context.Background(), proc, http.MethodPost, pluginsdk.PathConfigure, config, nil); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config = config
+	return nil
+}
+
+// Migrate 数据库迁移由插件在 init 中自行完成
+func (p *Plugin) Migrate() error {
+	return nil
+}
+
+// Init 初始化插件并订阅清单中的事件
+func (p *Plugin) Init() error {
+	proc, err := p.ensureProcess()
+	if err != nil {
+		return err
+	}
+	if err := p.rpc(context.Background(), proc, http.MethodPost, pluginsdk.PathInit, nil, nil); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proc != proc {
+		// 初始化期间进程已退出或被关闭
+		return ErrNotRunning
+	}
+	p.running = true
+	p.restarts = 0
+	p.subscribe()
+	return nil
+}
+
+// RegisterRoutes 进程外插件通过 RegisterGroupRoutes 注册路由
+func (p *Plugin) RegisterRoutes(router *gin.RouterGroup) {}
+
+// RegisterGroupRoutes 按清单注册路由，请求鉴权后转发到插件进程，并登记到接口文档
+func (p *Plugin) RegisterGroupRoutes(groups plugins.RouteGroups) {
+	for _, route := range p.manifest.Routes {
+		group, name := groups.Public, pluginsdk.GroupPublic
+		switch route.Group {
+		case pluginsdk.GroupUser:
+			group, name = groups.User, pluginsdk.GroupUser
+		case pluginsdk.GroupAdmin:
+			group, name = groups.Admin, pluginsdk.GroupAdmin
+		}
+		method := strings.ToUpper(route.Method)
+		group.Handle(method, route.Path, p.proxy(name, group.BasePath()))
+
+		op := openapi.Op(route.Summary, "Plugin-"+p.name).Notes(route.Description)
+		if name != pluginsdk.GroupPublic {
+			op = op.Auth()
+		}
+		groups.Describe(group, method, route.Path, op)
+	}
+}
+
+// Shutdown 取消事件订阅、通知插件关闭并结束进程
+func (p *Plugin) Shutdown() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running = false
+	for _, sub := range p.subs {
+		sub.Unsubscribe()
+	}
+	p.subs = nil
+
+	if p.proc == nil {
+		return nil
+	}
+	proc := p.proc
+	p.proc = nil
+	p.stopProcess(proc)
+	return nil
+}
+
+// HealthCheck 调用插件健康检查，进程未运行视为异常
+func (p *Plugin) HealthCheck(ctx context.Context) error {
+	proc := p.current()
+	if proc == nil {
+		return ErrNotRunning
+	}
+	return p.rpc(ctx, proc, http.MethodGet, pluginsdk.PathHealth, nil, nil)
+}
+
+// configurablePlugin 声明了配置项的进程外插件
+type configurablePlugin struct {
+	*Plugin
+}
+
+// ConfigSchema 返回清单中声明的配置项
+func (p *configurablePlugin) ConfigSchema() []plugins.ConfigField {
+	schema := make([]plugins.ConfigField, 0, len(p.manifest.ConfigSchema))
+	for _, f := range p.manifest.ConfigSchema {
+		schema = append(schema, plugins.ConfigField{
+			Key:         f.Key,
+			Type:        f.Type,
+			Default:     f.Default,
+			Required:    f.Required,
+			Secret:      f.Secret,
+			Options:     f.Options,
+			Description: f.Description,
+		})
+	}
+	return schema
+}
+
+// ========================================
+// 进程监管
+// ========================================
+
+// ensureProcess 返回运行中的进程，未运行时启动新进程并开始监管
+// 启动期间不持有锁；其间已有其他调用登记了进程时，结束新进程并返回已登记的进程
+func (p *Plugin) ensureProcess() (*process, error) {
+	if proc := p.current(); proc != nil {
+		return proc, nil
+	}
+	proc, err := startProcess(p.name, p.path, p.args)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	if existing := p.proc; existing != nil {
+		p.mu.Unlock()
+		proc.kill()
+		return existing, nil
+	}
+	p.proc = proc
+	p.mu.Unlock()
+
+	p.supervise(proc)
+	return proc, nil
+}
+
+// supervise 开始监管已登记的进程
+func (p *Plugin) supervise(proc *process) {
+	go p.watch(proc)
+	log.Printf("[Plugin] %s 进程已启动: pid=%d addr=%s", p.name, proc.cmd.Process.Pid, proc.addr)
+}
+
+// watch 进程退出后，若插件仍处于启用状态则按退避间隔重启
+func (p *Plugin) watch(proc *process) {
+	<-proc.done
+
+	p.mu.Lock()
+	if p.proc != proc {
+		p.mu.Unlock()
+		return
+	}
+	p.proc = nil
+	if !p.running {
+		p.mu.Unlock()
+		return
+	}
+	log.Printf("[Plugin] %s 进程异常退出: %v", p.name, proc.err)
+	if time.Since(proc.started) > stableRuntime {
+		p.restarts = 0
+	}
+	p.mu.Unlock()
+
+	for {
+		p.mu.Lock()
+		p.restarts++
+		delay := restartDelay(p.restarts)
+		p.mu.Unlock()
+		time.Sleep(delay)
+
+		p.mu.Lock()
+		if !p.running || p.proc != nil {
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+		err := p.restart()
+		if err == nil {
+			log.Printf("[Plugin] %s 已重启", p.name)
+			return
+		}
+		log.Printf("[Plugin] %s 重启失败（%v 后重试）: %v", p.name, restartDelay(p.restarts+1), err)
+	}
+}
+
+// restart 启动新进程并恢复配置与初始化，成功后才登记为当前进程
+// 启动与 RPC 期间不持有锁；其间插件被关闭或已有其他调用登记了进程时，关闭新进程
+func (p *Plugin) restart() error {
+	proc, err := startProcess(p.name, p.path, p.args)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	config := p.config
+	p.mu.Unlock()
+
+	ctx := context.Background()
+	err = p.rpc(ctx, proc, http.MethodPost, pluginsdk.PathConfigure, config, nil)
+	if err == nil {
+		err = p.rpc(ctx, proc, http.MethodPost, pluginsdk.PathInit, nil, nil)
+	}
+	if err != nil {
+		proc.kill()
+		return err
+	}
+
+	p.mu.Lock()
+	if !p.running || p.proc != nil {
+		p.mu.Unlock()
+		p.stopProcess(proc)
+		return nil
+	}
+	p.proc = proc
+	p.mu.Unlock()
+
+	p.supervise(proc)
+	return nil
+}
+
+// stopProcess 通知插件关闭并等待退出，超时强制结束
+func (p *Plugin) stopProcess(proc *process) {
+	if !proc.exited() {
+		if err := p.rpc(context.Background(), proc, http.MethodPost, pluginsdk.PathShutdown, nil, nil); err != nil {
+			log.Printf("[Plugin] %s 关闭失败: %v", p.name, err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	proc.wait(ctx)
+}
+
+// current 返回运行中的进程，重启期间为 nil
+func (p *Plugin) current() *process {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.proc
+}
+
+// ========================================
+// 事件与路由转发
+// ========================================
+
+// subscribe 订阅清单中声明的事件，调用方需持有锁
+func (p *Plugin) subscribe() {
+	for _, sub := range p.subs {
+		sub.Unsubscribe()
+	}
+	p.subs = nil
+
+	for _, decl := range p.manifest.Events {
+		name := decl.Name
+		forward := func(ctx context.Context, payload any) error {
+			return p.forwardEvent(ctx, name, payload)
+		}
+		var sub *events.Subscription
+		var err error
+		if decl.Sync {
+			sub, err = events.SubscribeName(name, forward)
+		} else {
+			sub, err = events.SubscribeNameAsync(name, forward)
+		}
+		if err != nil {
+			log.Printf("[Plugin] %s 订阅事件失败: %v", p.name, err)
+			continue
+		}
+		p.subs = append(p.subs, sub)
+	}
+}
+
+// forwardEvent 推送事件；插件返回失败即否决，插件不可用时只记录日志，不阻断核心业务
+func (p *Plugin) forwardEvent(ctx context.Context, name string, payload any) error {
+	proc := p.current()
+	if proc == nil {
+		log.Printf("[Plugin] %s 未运行，跳过事件 %s", p.name, name)
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	err = p.rpc(ctx, proc, http.MethodPost, pluginsdk.PathEvents, pluginsdk.Event{Name: name, Payload: data}, nil)
+	var remote *remoteError
+	if errors.As(err, &remote) {
+		return events.Reject(remote.Message)
+	}
+	if err != nil {
+		log.Printf("[Plugin] %s 推送事件 %s 失败: %v", p.name, name, err)
+	}
+	return nil
+}
+
+// proxy 将路由请求转发到插件进程的 /fst/v1/http/<group><path>，附带登录用户信息
+func (p *Plugin) proxy(group, basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		proc := p.current()
+		if proc == nil {
+			utils.Fail(c, 503, "插件服务不可用")
+			return
+		}
+
+		target := &url.URL{Scheme: "http", Host: proc.addr}
+		rel := strings.TrimPrefix(c.Request.URL.Path, basePath)
+		rp := &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				r.SetURL(target)
+				r.Out.URL.Path = pluginsdk.PathHTTP + "/" + group + rel
+				r.Out.URL.RawPath = ""
+				r.Out.Host = proc.addr
+
+				for _, h := range []string{pluginsdk.HeaderUserID, pluginsdk.HeaderUsername, pluginsdk.HeaderRole, pluginsdk.HeaderAuthGuard} {
+					r.Out.Header.Del(h)
+				}
+				if userID, ok := c.Get("userID"); ok {
+					r.Out.Header.Set(pluginsdk.HeaderUserID, fmt.Sprint(userID))
+					r.Out.Header.Set(pluginsdk.HeaderUsername, c.GetString("username"))
+					r.Out.Header.Set(pluginsdk.HeaderRole, c.GetString("role"))
+					r.Out.Header.Set(pluginsdk.HeaderAuthGuard, c.GetString("authGuard"))
+				}
+				r.Out.Header.Set("Authorization", "Bearer "+proc.token)
+			},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				log.Printf("[Plugin] %s 转发请求失败: %v", p.name, err)
+				utils.Fail(c, 502, "插件服务不可用")
+			},
+		}
+		rp.ServeHTTP(responseWriter{c.Writer}, c.Request)
+	}
+}
+
+// responseWriter 隐藏 gin 的 CloseNotify（底层不支持时会 panic），
+// 通过 Unwrap 保留 ResponseController 的 Flush 等能力
+type responseWriter struct {
+	http.ResponseWriter
+}
+
+func (w responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// rpc 调用插件协议接口，code 非 200 时返回 *remoteError
+func (p *Plugin) rpc(ctx context.Context, proc *process, method, path string, body, out interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, "http://"+proc.addr+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+proc.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result pluginsdk.Response
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("插件响应格式错误: %v", err)
+	}
+	if result.Code != 200 {
+		return &remoteError{Code: result.Code, Message: result.Message}
+	}
+	if out != nil && len(result.Data) > 0 {
+		return json.Unmarshal(result.Data, out)
+	}
+	return nil
+}
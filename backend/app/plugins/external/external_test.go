@@ -0,0 +1,168 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fst/backend/app/plugins"
+	"fst/backend/internal/events"
+	"fst/backend/pkg/pluginsdk"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestHelperPlugin 以测试二进制自身作为进程外插件运行
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("FST_TEST_PLUGIN") != "1" {
+		return
+	}
+
+	var mu sync.Mutex
+	greeting := ""
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /public/hello", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 200,
+			"data": map[string]string{"greeting": greeting, "user": r.Header.Get(pluginsdk.HeaderUserID)},
+		})
+	})
+	mux.HandleFunc("GET /public/crash", func(w http.ResponseWriter, r *http.Request) {
+		os.Exit(3)
+	})
+
+	err := pluginsdk.Serve(&pluginsdk.Plugin{
+		Manifest: pluginsdk.Manifest{
+			Name:         "fake",
+			Version:      "0.1.0",
+			ConfigSchema: []pluginsdk.ConfigField{{Key: "greeting", Type: pluginsdk.FieldString, Default: "hi"}},
+			Routes: []pluginsdk.Route{
+				{Method: "GET", Path: "/hello"},
+				{Method: "GET", Path: "/crash"},
+			},
+			Events: []pluginsdk.EventSubscription{{Name: "user.before_register", Sync: true}},
+		},
+		Configure: func(config map[string]interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			greeting, _ = config["greeting"].(string)
+			return nil
+		},
+		OnEvent: func(ctx context.Context, event pluginsdk.Event) error {
+			var req events.UserRegistration
+			json.Unmarshal(event.Payload, &req)
+			if strings.HasSuffix(req.Email, "@blocked.test") {
+				return errors.New("blocked")
+			}
+			return nil
+		},
+		Handler: mux,
+	})
+	if err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func get(r *gin.Engine, path string, header http.Header) (int, map[string]string) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	r.ServeHTTP(w, req)
+	var body struct {
+		Code int               `json:"code"`
+		Data map[string]string `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	return body.Code, body.Data
+}
+
+func TestExternalPluginLifecycle(t *testing.T) {
+	t.Setenv("FST_TEST_PLUGIN", "1")
+	p, err := New("fake", os.Args[0], "-test.run=^TestHelperPlugin$")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := plugins.NewManager()
+	m.Register(p.Adapt())
+	if err := m.LoadAll(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.ShutdownAll()
+	if !m.IsActive("fake") {
+		t.Fatalf("plugin not active: %v", m.GetErrors())
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	m.RegisterAllRoutes(r.Group("/api"), r.Routes())
+
+	// 路由转发，客户端伪造的用户头被清除
+	code, data := get(r, "/api/plugins/fake/hello", http.Header{pluginsdk.HeaderUserID: {"1"}})
+	if code != 200 || data["greeting"] != "hi" || data["user"] != "" {
+		t.Fatalf("hello code = %d, data = %v", code, data)
+	}
+
+	// 同步事件：插件返回错误即否决
+	if err := events.UserBeforeRegister.Publish(context.Background(), events.UserRegistration{Email: "a@blocked.test"}); events.Message(err) != "blocked" {
+		t.Fatalf("veto err = %v", err)
+	}
+	if err := events.UserBeforeRegister.Publish(context.Background(), events.UserRegistration{Email: "a@ok.test"}); err != nil {
+		t.Fatalf("publish err = %v", err)
+	}
+
+	// 配置修改后立即下发
+	if _, err := m.UpdateConfig("fake", map[string]interface{}{"greeting": "hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 进程崩溃后自动重启并恢复配置
+	get(r, "/api/plugins/fake/crash", nil)
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		code, data = get(r, "/api/plugins/fake/hello", nil)
+		if code == 200 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("plugin not restarted, last code = %d", code)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if data["greeting"] != "hello" {
+		t.Fatalf("config not restored after restart: %v", data)
+	}
+
+	// 禁用后取消事件订阅、结束进程
+	if err := m.Disable("fake"); err != nil {
+		t.Fatal(err)
+	}
+	if err := events.UserBeforeRegister.Publish(context.Background(), events.UserRegistration{Email: "a@blocked.test"}); err != nil {
+		t.Fatalf("disabled plugin still receives events: %v", err)
+	}
+	if p.current() != nil {
+		t.Fatal("process still running after disable")
+	}
+}
+
+func TestParseHandshake(t *testing.T) {
+	if addr, err := parseHandshake("FST_PLUGIN|1|127.0.0.1:4000\n"); err != nil || addr != "127.0.0.1:4000" {
+		t.Fatalf("addr = %q, err = %v", addr, err)
+	}
+	for _, line := range []string{"FST_PLUGIN|2|127.0.0.1:4000", "FST_PLUGIN|1|", "hello"} {
+		if _, err := parseHandshake(line); err == nil {
+			t.Fatalf("%q should be rejected", line)
+		}
+	}
+}
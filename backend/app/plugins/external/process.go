@@ -0,0 +1,173 @@
+package external
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"fst/backend/pkg/pluginsdk"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	handshakeTimeout = 10 * time.Second // 等待握手行的最长时间
+	stopTimeout      = 5 * time.Second  // shutdown 后等待进程退出的时间，超时强制结束
+	restartBaseDelay = time.Second      // 首次重启间隔，之后逐次翻倍
+	restartMaxDelay  = time.Minute      // 重启间隔上限
+	stableRuntime    = time.Minute      // 运行超过该时长后重启间隔归零
+)
+
+// process 一次启动的插件进程
+type process struct {
+	cmd     *exec.Cmd
+	addr    string // 插件监听地址 host:port
+	token   string // 本次启动的调用令牌
+	started time.Time
+	done    chan struct{} // 进程退出后关闭
+	err     error         // 进程退出原因
+}
+
+// startProcess 启动插件进程并等待握手
+func startProcess(name, path string, args []string) (*process, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(),
+		pluginsdk.EnvProtocol+"="+strconv.Itoa(pluginsdk.ProtocolVersion),
+		pluginsdk.EnvToken+"="+token,
+	)
+	// 使用 io.Pipe 而非 StdoutPipe：Wait 会等输出读取完毕，不会截断日志
+	stdout, stdoutW := io.Pipe()
+	stderr, stderrW := io.Pipe()
+	cmd.Stdout, cmd.Stderr = stdoutW, stderrW
+	cmd.WaitDelay = stopTimeout // 插件的子进程占用输出时不无限等待
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("启动插件进程失败: %v", err)
+	}
+
+	p := &process{cmd: cmd, token: token, started: time.Now(), done: make(chan struct{})}
+	go forwardOutput(name, stderr)
+
+	// 读取握手行，其余输出转发到日志
+	handshake := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		sent := false
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !sent && strings.HasPrefix(line, pluginsdk.HandshakePrefix+"|") {
+				handshake <- line
+				sent = true
+				continue
+			}
+			log.Printf("[Plugin:%s] %s", name, line)
+		}
+		if !sent {
+			close(handshake)
+		}
+	}()
+	go func() {
+		p.err = cmd.Wait()
+		stdoutW.Close()
+		stderrW.Close()
+		close(p.done)
+	}()
+
+	select {
+	case line, ok := <-handshake:
+		if !ok {
+			p.kill()
+			return nil, errors.New("插件进程未完成握手即退出")
+		}
+		addr, err := parseHandshake(line)
+		if err != nil {
+			p.kill()
+			return nil, err
+		}
+		p.addr = addr
+		return p, nil
+	case <-time.After(handshakeTimeout):
+		p.kill()
+		return nil, errors.New("等待插件握手超时")
+	}
+}
+
+// parseHandshake 解析握手行 FST_PLUGIN|<协议版本>|<地址>
+func parseHandshake(line string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) != 3 || parts[0] != pluginsdk.HandshakePrefix {
+		return "", fmt.Errorf("握手格式错误: %q", line)
+	}
+	version, err := strconv.Atoi(parts[1])
+	if err != nil || version != pluginsdk.ProtocolVersion {
+		return "", fmt.Errorf("协议版本不兼容: 插件 %s，主程序 %d", parts[1], pluginsdk.ProtocolVersion)
+	}
+	if parts[2] == "" {
+		return "", errors.New("握手缺少监听地址")
+	}
+	return parts[2], nil
+}
+
+// exited 进程是否已退出
+func (p *process) exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait 等待进程退出，超时后强制结束
+func (p *process) wait(ctx context.Context) {
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		p.kill()
+	}
+}
+
+func (p *process) kill() {
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+	<-p.done
+}
+
+// restartDelay 第 n 次连续重启前的等待时间
+func restartDelay(n int) time.Duration {
+	d := restartBaseDelay
+	for i := 1; i < n && d < restartMaxDelay; i++ {
+		d *= 2
+	}
+	if d > restartMaxDelay {
+		d = restartMaxDelay
+	}
+	return d
+}
+
+func forwardOutput(name string, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		log.Printf("[Plugin:%s] %s", name, scanner.Text())
+	}
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
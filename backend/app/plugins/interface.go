@@ -1,9 +1,22 @@
 package plugins
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	// ErrPluginNotFound 插件不存在
+	ErrPluginNotFound = errors.New("插件不存在")
+	// ErrPluginDisabled 插件已禁用
+	ErrPluginDisabled = errors.New("插件已禁用")
+	// ErrPluginBusy 插件正在启用、禁用或重新初始化
+	ErrPluginBusy = errors.New("插件正在处理中，请稍后重试")
+	// ErrNotConfigurable 插件未声明配置结构
+	ErrNotConfigurable = errors.New("插件未声明配置项")
+)
+
 // Plugin is the interface that all plugins must implement
 type Plugin interface {
 	// ========================================
@@ -43,6 +56,8 @@ type Plugin interface {
 	Init() error
 
 	// RegisterRoutes allows the plugin to register its own routes
+	// 启动时会被调用两次（先在临时路由表中试注册检测冲突，再正式注册），
+	// 实现中只能注册路由，不要创建资源、订阅事件或修改插件状态
 	RegisterRoutes(router *gin.RouterGroup)
 
 	// Shutdown performs cleanup when the system shuts down
@@ -124,7 +139,10 @@ type PluginInfo struct {
 	Description  string   `json:"description"`
 	Priority     int      `json:"priority"`
 	Dependencies []string `json:"dependencies"`
-	Status       string   `json:"status"` // "active", "inactive", "error"
+	Enabled      bool     `json:"enabled"`
+	Configurable bool     `json:"configurable"`    // 是否声明了配置结构
+	Status       string   `json:"status"`          // "active", "inactive", "disabled", "error"
+	Error        string   `json:"error,omitempty"` // 加载错误
 }
 
 // PluginConfig 插件配置
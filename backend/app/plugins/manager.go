@@ -2,6 +2,8 @@ package plugins
 
 import (
 	"fmt"
+	"fst/backend/internal/secrets"
+	"fst/backend/utils"
 	"log"
 	"sort"
 	"sync"
@@ -9,6 +11,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// StateStore 插件启用状态持久化
+type StateStore interface {
+	// LoadDisabled 返回被禁用的插件名称集合
+	LoadDisabled() (map[string]bool, error)
+	// SetEnabled 保存插件启用状态
+	SetEnabled(name string, enabled bool) error
+}
+
 // Manager 插件管理器（增强版）
 type Manager struct {
 	pm          *PluginManager
@@ -16,16 +26,44 @@ type Manager struct {
 	initialized bool
 	shutdown    bool
 	errors      map[string]error // 插件初始化错误记录
+	loaded      map[string]bool  // 已完成 Configure/Init/Migrate 的插件
+	disabled    map[string]bool  // 被管理员禁用的插件
+	store       StateStore
+	configStore ConfigStore
+	conflicts   map[string]error // 路由冲突（插件路由未注册）
+	unresolved  map[string]error // 依赖不满足（不存在、版本不符、循环依赖）
+	skipped     map[string]error // 因依赖未加载而跳过的原因
+	busy        map[string]bool  // 正在启用、禁用或重新初始化的插件
+	report      []LoadResult     // 最近一次 LoadAll 的加载报告
+
+	jobs            JobRegistrar
+	tasksRegistered map[string]bool       // 已注册定时任务的插件
+	menus           map[string]pluginMenu // 插件声明的管理端菜单
 }
 
 // NewManager 创建插件管理器
 func NewManager() *Manager {
 	return &Manager{
-		pm:     NewPluginManager(),
-		errors: make(map[string]error),
+		pm:        NewPluginManager(),
+		errors:    make(map[string]error),
+		loaded:    make(map[string]bool),
+		disabled:  make(map[string]bool),
+		conflicts: make(map[string]error),
+		skipped:   make(map[string]error),
+		busy:      make(map[string]bool),
+
+		tasksRegistered: make(map[string]bool),
+		menus:           make(map[string]pluginMenu),
 	}
 }
 
+// SetStateStore 设置启用状态存储，需在 LoadAll 之前调用
+func (m *Manager) SetStateStore(store StateStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.store = store
+}
+
 // Register 注册插件
 func (m *Manager) Register(p Plugin) {
 	m.mu.Lock()
@@ -40,8 +78,16 @@ func (m *Manager) RegisterWithConfig(p Plugin, config PluginConfig) {
 	m.pm.RegisterWithConfig(p, config)
 }
 
+// SetConfigStore 设置插件配置存储，需在 LoadAll 之前调用
+func (m *Manager) SetConfigStore(store ConfigStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.configStore = store
+}
+
 // LoadAll 加载所有插件
-// 按优先级和依赖关系排序后依次初始化
+// 按优先级和依赖关系排序后依次初始化；依赖不满足或依赖加载失败的插件被跳过，
+// 不影响其他插件，原因记录在加载报告中
 func (m *Manager) LoadAll() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -50,143 +96,629 @@ func (m *Manager) LoadAll() error {
 		return fmt.Errorf("插件已经初始化")
 	}
 
-	// 解析依赖
-	if err := m.resolveDependencies(); err != nil {
-		return err
+	// 解析依赖（不存在、版本不符、循环依赖）
+	m.unresolved = m.resolveDependencies()
+
+	// 读取持久化的禁用状态
+	if m.store != nil {
+		disabled, err := m.store.LoadDisabled()
+		if err != nil {
+			log.Printf("[Plugin] 读取插件启用状态失败: %v", err)
+		} else {
+			m.disabled = disabled
+		}
 	}
 
 	// 按优先级排序
 	sorted_plugins := m.sortByPriority()
 
-	// 依次初始化
+	// 依次初始化（被禁用的插件跳过，启用时再初始化）
+	m.report = m.report[:0]
 	for _, name := range sorted_plugins {
+		result := LoadResult{Name: name, Version: m.pm.plugins[name].Version()}
+		switch {
+		case m.disabled[name]:
+			result.Status, result.Reason = LoadStatusDisabled, "管理员已禁用"
+		default:
+			if err := m.checkDependencies(name); err != nil {
+				m.skipped[name] = err
+				result.Status, result.Reason = LoadStatusSkipped, err.Error()
+				log.Printf("[Plugin] %s 已跳过: %v", name, err)
+			} else if err := m.loadPlugin(name); err != nil {
+				result.Status, result.Reason = LoadStatusFailed, err.Error()
+			} else {
+				result.Status = LoadStatusLoaded
+			}
+		}
+		m.report = append(m.report, result)
+	}
+	logLoadReport(m.report)
+
+	m.initialized = true
+	return nil
+}
+
+// 加载结果
+const (
+	LoadStatusLoaded   = "loaded"   // 加载成功
+	LoadStatusFailed   = "failed"   // 配置、初始化或迁移失败
+	LoadStatusSkipped  = "skipped"  // 依赖不满足或依赖未加载
+	LoadStatusDisabled = "disabled" // 管理员已禁用
+)
+
+// LoadResult 单个插件的加载结果
+type LoadResult struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// LoadReport 返回最近一次 LoadAll 的加载报告（按加载顺序）
+func (m *Manager) LoadReport() []LoadResult {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]LoadResult{}, m.report...)
+}
+
+// logLoadReport 输出加载汇总及未加载插件的原因
+func logLoadReport(report []LoadResult) {
+	counts := make(map[string]int)
+	for _, r := range report {
+		counts[r.Status]++
+	}
+	log.Printf("[Plugin] 加载报告: 成功 %d，失败 %d，跳过 %d，禁用 %d",
+		counts[LoadStatusLoaded], counts[LoadStatusFailed], counts[LoadStatusSkipped], counts[LoadStatusDisabled])
+	for _, r := range report {
+		if r.Status == LoadStatusFailed || r.Status == LoadStatusSkipped {
+			log.Printf("[Plugin]   %s v%s %s: %s", r.Name, r.Version, r.Status, r.Reason)
+		}
+	}
+}
+
+// loadPlugin 依次执行配置、初始化与数据库迁移，调用方需持有写锁
+func (m *Manager) loadPlugin(name string) error {
+	config, err := m.prepareLoad(name)
+	if err == nil {
+		err = runLoadHooks(m.pm.plugins[name], config)
+	}
+	return m.finishLoad(name, err)
+}
+
+// loadPluginUnlocked 与 loadPlugin 相同，但执行插件钩子期间释放写锁，避免阻塞插件路由的状态检查
+// 调用方需持有写锁并已通过 acquire 占用该插件
+func (m *Manager) loadPluginUnlocked(name string) error {
+	config, err := m.prepareLoad(name)
+	if err == nil {
 		p := m.pm.plugins[name]
+		m.mu.Unlock()
+		err = runLoadHooks(p, config)
+		m.mu.Lock()
+	}
+	return m.finishLoad(name, err)
+}
 
-		// 1. 配置
-		config := m.pm.GetConfig(name)
-		if err := p.Configure(config); err != nil {
-			m.errors[name] = fmt.Errorf("配置失败: %v", err)
-			log.Printf("[Plugin] %s 配置失败: %v", name, err)
+// prepareLoad 清除上次的加载结果并计算生效配置，调用方需持有写锁
+func (m *Manager) prepareLoad(name string) (PluginConfig, error) {
+	delete(m.errors, name)
+	delete(m.skipped, name)
+	config, err := m.resolveConfig(name)
+	if err != nil {
+		return nil, fmt.Errorf("配置失败: %v", err)
+	}
+	return config, nil
+}
+
+// runLoadHooks 依次执行插件的 Configure、Init 与 Migrate，不访问管理器状态
+func runLoadHooks(p Plugin, config PluginConfig) error {
+	if err := p.Configure(config); err != nil {
+		return fmt.Errorf("配置失败: %v", err)
+	}
+	if err := p.Init(); err != nil {
+		return fmt.Errorf("初始化失败: %v", err)
+	}
+	if err := p.Migrate(); err != nil {
+		return fmt.Errorf("迁移失败: %v", err)
+	}
+	return nil
+}
+
+// finishLoad 记录加载结果，成功时接入可选接口，调用方需持有写锁
+func (m *Manager) finishLoad(name string, err error) error {
+	p := m.pm.plugins[name]
+	if err != nil {
+		m.errors[name] = err
+		log.Printf("[Plugin] %s %v", name, err)
+		return err
+	}
+	m.loaded[name] = true
+	m.wireExtensions(name, p)
+	log.Printf("[Plugin] %s v%s 加载成功", p.Name(), p.Version())
+	return nil
+}
+
+// resolveConfig 计算插件的生效配置，调用方需持有写锁
+// 声明了配置结构的插件：代码传入的配置 < 数据库保存的配置，按声明校验并填充默认值
+// 数据库中已不再声明的配置项（如插件升级后移除）记录警告后忽略，不影响插件加载
+func (m *Manager) resolveConfig(name string) (PluginConfig, error) {
+	configurable, ok := m.pm.plugins[name].(Configurable)
+	if !ok {
+		return m.pm.GetConfig(name), nil
+	}
+	schema := configurable.ConfigSchema()
+
+	values := make(map[string]interface{})
+	for k, v := range m.pm.GetConfig(name) {
+		values[k] = v
+	}
+	if m.configStore != nil {
+		stored, err := m.configStore.LoadConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range decryptSecretFields(schema, dropUndeclaredFields(name, schema, stored)) {
+			values[k] = v
+		}
+	}
+
+	config, err := ValidateConfig(schema, values)
+	if err != nil {
+		return nil, err
+	}
+	m.pm.SetConfig(name, config)
+	return config, nil
+}
+
+// ConfigView 插件配置（敏感项已掩码）
+type ConfigView struct {
+	Schema []ConfigField `json:"schema"`
+	Values PluginConfig  `json:"values"`
+}
+
+// GetConfigView 获取插件配置结构与当前值
+func (m *Manager) GetConfigView(name string) (*ConfigView, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.pm.plugins[name]
+	if !ok {
+		return nil, ErrPluginNotFound
+	}
+	configurable, ok := p.(Configurable)
+	if !ok {
+		return nil, ErrNotConfigurable
+	}
+	schema := configurable.ConfigSchema()
+	config, err := m.resolveConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigView{Schema: schema, Values: MaskConfig(schema, config)}, nil
+}
+
+// UpdateConfig 校验并保存插件配置，插件已启用时立即通过 Configure 生效
+// values 为部分更新：未提交的项保持原值，值为 null 恢复默认值，敏感项提交掩码表示不修改
+// 执行 Configure 期间释放写锁并占用该插件，避免阻塞插件路由的状态检查
+func (m *Manager) UpdateConfig(name string, values map[string]interface{}) (*ConfigView, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.acquire(name); err != nil {
+		return nil, err
+	}
+	defer delete(m.busy, name)
+	p := m.pm.plugins[name]
+	configurable, ok := p.(Configurable)
+	if !ok {
+		return nil, ErrNotConfigurable
+	}
+	schema := configurable.ConfigSchema()
+
+	current, err := m.resolveConfig(name)
+	if err != nil {
+		// 已保存的配置不再合法（如插件升级后声明变化），以默认值为基础修正，保留已保存的敏感项
+		current, err = m.storedSecretFields(name, schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	merged := make(map[string]interface{}, len(current)+len(values))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range values {
+		if v == SecretMaskedValue && isSecretField(schema, k) {
 			continue
 		}
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = v
+	}
+
+	config, err := ValidateConfig(schema, merged)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.active(name) {
+		m.mu.Unlock()
+		err := p.Configure(config)
+		if err != nil {
+			// 回滚到原配置，保证插件状态与保存的配置一致
+			p.Configure(current)
+		}
+		m.mu.Lock()
+		if err != nil {
+			return nil, fmt.Errorf("插件拒绝了新配置: %w", err)
+		}
+	}
+
+	if m.configStore != nil {
+		stored, err := encryptSecretFields(schema, config)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.configStore.SaveConfig(name, stored); err != nil {
+			return nil, err
+		}
+	}
+	m.pm.SetConfig(name, config)
+	log.Printf("[Plugin] %s 配置已更新", name)
+	return &ConfigView{Schema: schema, Values: MaskConfig(schema, config)}, nil
+}
+
+// storedSecretFields 读取数据库中保存的敏感项（已解密），调用方需持有写锁
+func (m *Manager) storedSecretFields(name string, schema []ConfigField) (PluginConfig, error) {
+	result := PluginConfig{}
+	if m.configStore == nil {
+		return result, nil
+	}
+	stored, err := m.configStore.LoadConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range decryptSecretFields(schema, stored) {
+		if isSecretField(schema, k) {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+// dropUndeclaredFields 移除已保存但未在配置结构中声明的配置项
+func dropUndeclaredFields(name string, schema []ConfigField, stored map[string]interface{}) map[string]interface{} {
+	declared := make(map[string]bool, len(schema))
+	for _, field := range schema {
+		declared[field.Key] = true
+	}
+	for k := range stored {
+		if !declared[k] {
+			log.Printf("[Plugin] %s 已保存的配置项 %s 未声明，已忽略", name, k)
+			delete(stored, k)
+		}
+	}
+	return stored
+}
+
+func isSecretField(schema []ConfigField, key string) bool {
+	for _, field := range schema {
+		if field.Key == key {
+			return field.Secret
+		}
+	}
+	return false
+}
 
-		// 2. 初始化
-		if err := p.Init(); err != nil {
-			m.errors[name] = fmt.Errorf("初始化失败: %v", err)
-			log.Printf("[Plugin] %s 初始化失败: %v", name, err)
+// encryptSecretFields 加密敏感项后用于持久化
+func encryptSecretFields(schema []ConfigField, config PluginConfig) (map[string]interface{}, error) {
+	stored := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		stored[k] = v
+	}
+	for _, field := range schema {
+		s, ok := stored[field.Key].(string)
+		if !field.Secret || !ok || s == "" {
 			continue
 		}
+		encrypted, err := secrets.Encrypt(s)
+		if err != nil {
+			return nil, err
+		}
+		stored[field.Key] = encrypted
+	}
+	return stored, nil
+}
 
-		// 3. 数据库迁移
-		if err := p.Migrate(); err != nil {
-			m.errors[name] = fmt.Errorf("迁移失败: %v", err)
-			log.Printf("[Plugin] %s 迁移失败: %v", name, err)
+// decryptSecretFields 解密持久化的敏感项，解密失败时保留原值
+func decryptSecretFields(schema []ConfigField, stored map[string]interface{}) map[string]interface{} {
+	for _, field := range schema {
+		s, ok := stored[field.Key].(string)
+		if !field.Secret || !ok {
+			continue
+		}
+		plaintext, err := secrets.Decrypt(s)
+		if err != nil {
+			log.Printf("[Plugin] 解密配置项 %s 失败: %v", field.Key, err)
 			continue
 		}
+		stored[field.Key] = plaintext
+	}
+	return stored
+}
 
-		log.Printf("[Plugin] %s v%s 加载成功", p.Name(), p.Version())
+// unloadPlugin 下线并关闭已加载的插件，执行 Shutdown 期间释放写锁
+// 调用方需持有写锁并已通过 acquire 占用该插件
+func (m *Manager) unloadPlugin(name string) {
+	if !m.loaded[name] {
+		return
+	}
+	// 先标记为未加载，插件路由立即返回 404
+	m.loaded[name] = false
+	p := m.pm.plugins[name]
+	m.mu.Unlock()
+	err := p.Shutdown()
+	m.mu.Lock()
+	if err != nil {
+		log.Printf("[Plugin] %s 关闭失败: %v", name, err)
 	}
+}
 
-	m.initialized = true
+// acquire 占用插件以执行启用、禁用、重新初始化或更新配置，同一插件的这些操作不能并发，调用方需持有写锁
+// 操作结束后需在持有写锁时 delete(m.busy, name) 释放
+func (m *Manager) acquire(name string) error {
+	if _, ok := m.pm.plugins[name]; !ok {
+		return ErrPluginNotFound
+	}
+	if m.busy[name] {
+		return ErrPluginBusy
+	}
+	m.busy[name] = true
 	return nil
 }
 
-// RegisterAllRoutes 注册所有插件的路由
-func (m *Manager) RegisterAllRoutes(router *gin.RouterGroup) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+// Enable 启用插件并持久化状态，未加载的插件会立即初始化
+func (m *Manager) Enable(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	sorted_plugins := m.sortByPriority()
+	if err := m.acquire(name); err != nil {
+		return err
+	}
+	defer delete(m.busy, name)
+	if err := m.checkDependencies(name); err != nil {
+		return err
+	}
 
-	for _, name := range sorted_plugins {
-		p := m.pm.plugins[name]
+	if m.store != nil {
+		if err := m.store.SetEnabled(name, true); err != nil {
+			return err
+		}
+	}
+	delete(m.disabled, name)
+	if m.loaded[name] {
+		return nil
+	}
+	return m.loadPluginUnlocked(name)
+}
+
+// Disable 禁用插件并持久化状态，插件路由立即返回 404，插件随之关闭
+func (m *Manager) Disable(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-		// 跳过初始化失败的插件
-		if _, has_error := m.errors[name]; has_error {
+	if err := m.acquire(name); err != nil {
+		return err
+	}
+	defer delete(m.busy, name)
+	for other := range m.pm.plugins {
+		if other == name || m.disabled[other] {
 			continue
 		}
+		for _, dep := range m.dependencyNames(other) {
+			if dep == name {
+				return fmt.Errorf("插件 %s 依赖该插件，请先禁用", other)
+			}
+		}
+	}
 
-		p.RegisterRoutes(router)
-		log.Printf("[Plugin] %s 路由注册完成", name)
+	if m.store != nil {
+		if err := m.store.SetEnabled(name, false); err != nil {
+			return err
+		}
 	}
+	m.disabled[name] = true
+	m.unloadPlugin(name)
+	log.Printf("[Plugin] %s 已禁用", name)
+	return nil
 }
 
-// ShutdownAll 关闭所有插件
-func (m *Manager) ShutdownAll() error {
+// Reinit 重新初始化插件：先关闭再依次执行配置、初始化与迁移
+func (m *Manager) Reinit(name string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.acquire(name); err != nil {
+		return err
+	}
+	defer delete(m.busy, name)
+	if m.disabled[name] {
+		return ErrPluginDisabled
+	}
+	m.unloadPlugin(name)
+	if err := m.checkDependencies(name); err != nil {
+		delete(m.errors, name)
+		m.skipped[name] = err
+		return err
+	}
+	return m.loadPluginUnlocked(name)
+}
+
+// IsActive 插件是否已启用且加载成功
+func (m *Manager) IsActive(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.active(name)
+}
+
+func (m *Manager) active(name string) bool {
+	return !m.disabled[name] && m.loaded[name]
+}
+
+// gate 在请求时检查插件状态，禁用或加载失败的插件返回 404
+func (m *Manager) gate(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.IsActive(name) {
+			utils.Fail(c, 404, "插件未启用")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ShutdownAll 关闭所有插件，执行 Shutdown 期间释放写锁
+// 正在启用、禁用、重新初始化或更新配置的插件由进行中的操作负责，此处跳过
+func (m *Manager) ShutdownAll() error {
+	m.mu.Lock()
 	if m.shutdown {
+		m.mu.Unlock()
 		return nil
 	}
+	m.shutdown = true
 
-	// 按优先级逆序关闭
+	// 按优先级逆序关闭，先全部下线并占用，再在锁外依次关闭
 	sorted_plugins := m.sortByPriority()
+	var closing []string
+	var closing_plugins []Plugin
 	for i := len(sorted_plugins) - 1; i >= 0; i-- {
 		name := sorted_plugins[i]
-		p := m.pm.plugins[name]
+		if !m.loaded[name] {
+			continue
+		}
+		if err := m.acquire(name); err != nil {
+			log.Printf("[Plugin] %s 正在执行其他操作，跳过关闭", name)
+			continue
+		}
+		m.loaded[name] = false
+		closing = append(closing, name)
+		closing_plugins = append(closing_plugins, m.pm.plugins[name])
+	}
+	m.mu.Unlock()
 
-		if err := p.Shutdown(); err != nil {
+	for i, name := range closing {
+		if err := closing_plugins[i].Shutdown(); err != nil {
 			log.Printf("[Plugin] %s 关闭失败: %v", name, err)
 		} else {
 			log.Printf("[Plugin] %s 已关闭", name)
 		}
 	}
 
-	m.shutdown = true
+	m.mu.Lock()
+	for _, name := range closing {
+		delete(m.busy, name)
+	}
+	m.mu.Unlock()
 	return nil
 }
 
-// resolveDependencies 解析依赖关系
-func (m *Manager) resolveDependencies() error {
-	// 检查依赖是否存在
+// resolveDependencies 检查每个插件的依赖声明，返回不满足的原因
+// 依赖不存在、版本不符、声明格式错误或处于循环依赖中的插件不会加载，其他插件不受影响
+func (m *Manager) resolveDependencies() map[string]error {
+	unresolved := make(map[string]error)
 	for name, p := range m.pm.plugins {
-		for _, dep := range p.Dependencies() {
-			if !m.pm.HasPlugin(dep) {
-				return fmt.Errorf("插件 %s 依赖的 %s 不存在", name, dep)
+		for _, raw := range p.Dependencies() {
+			dep, err := ParseDependency(raw)
+			if err != nil {
+				unresolved[name] = err
+				break
+			}
+			target, ok := m.pm.plugins[dep.Name]
+			if !ok {
+				unresolved[name] = fmt.Errorf("依赖的插件 %s 不存在", dep.Name)
+				break
+			}
+			if !dep.Satisfied(target.Version()) {
+				unresolved[name] = fmt.Errorf("依赖 %s，当前版本 %s 不满足", dep, target.Version())
+				break
 			}
 		}
 	}
 
-	// 检查循环依赖
-	visited := make(map[string]bool)
-	visiting := make(map[string]bool)
-
-	for name := range m.pm.plugins {
-		if err := m.checkCycle(name, visited, visiting); err != nil {
-			return err
+	for _, name := range m.cyclicPlugins() {
+		if _, ok := unresolved[name]; !ok {
+			unresolved[name] = fmt.Errorf("检测到循环依赖")
 		}
 	}
-
-	return nil
+	return unresolved
 }
 
-// checkCycle 检查循环依赖（DFS）
-func (m *Manager) checkCycle(name string, visited, visiting map[string]bool) error {
-	if visited[name] {
-		return nil
+// checkDependencies 检查依赖声明已满足且依赖的插件均已加载，调用方需持有锁
+func (m *Manager) checkDependencies(name string) error {
+	if err := m.unresolved[name]; err != nil {
+		return err
 	}
-
-	if visiting[name] {
-		return fmt.Errorf("检测到循环依赖: %s", name)
+	for _, dep := range m.dependencyNames(name) {
+		if m.active(dep) {
+			continue
+		}
+		switch {
+		case m.disabled[dep]:
+			return fmt.Errorf("依赖的插件 %s 已禁用", dep)
+		case m.skipped[dep] != nil:
+			return fmt.Errorf("依赖的插件 %s 未加载（%v）", dep, m.skipped[dep])
+		case m.errors[dep] != nil:
+			return fmt.Errorf("依赖的插件 %s 加载失败（%v）", dep, m.errors[dep])
+		default:
+			return fmt.Errorf("依赖的插件 %s 未启用", dep)
+		}
 	}
+	return nil
+}
 
-	visiting[name] = true
-
+// dependencyNames 返回插件依赖的插件名称（忽略版本约束与无法解析的声明）
+func (m *Manager) dependencyNames(name string) []string {
 	p, ok := m.pm.plugins[name]
 	if !ok {
 		return nil
 	}
+	names := make([]string, 0, len(p.Dependencies()))
+	for _, raw := range p.Dependencies() {
+		if dep, err := ParseDependency(raw); err == nil {
+			names = append(names, dep.Name)
+		}
+	}
+	return names
+}
 
-	for _, dep := range p.Dependencies() {
-		if err := m.checkCycle(dep, visited, visiting); err != nil {
-			return err
+// cyclicPlugins 返回处于循环依赖环上的插件
+func (m *Manager) cyclicPlugins() []string {
+	var cyclic []string
+	for name := range m.pm.plugins {
+		if m.reaches(name, name, make(map[string]bool)) {
+			cyclic = append(cyclic, name)
 		}
 	}
+	sort.Strings(cyclic)
+	return cyclic
+}
 
-	visiting[name] = false
-	visited[name] = true
-	return nil
+// reaches 从 from 的依赖出发能否到达 target（DFS）
+func (m *Manager) reaches(from, target string, visited map[string]bool) bool {
+	for _, dep := range m.dependencyNames(from) {
+		if dep == target {
+			return true
+		}
+		if visited[dep] {
+			continue
+		}
+		visited[dep] = true
+		if m.reaches(dep, target, visited) {
+			return true
+		}
+	}
+	return false
 }
 
 // sortByPriority 按优先级和依赖关系排序
@@ -214,9 +746,12 @@ func (m *Manager) topologicalSort(plugins []Plugin) []string {
 		adj[name] = []string{}
 	}
 
-	// 构建邻接表
+	// 构建邻接表（只统计已注册的依赖）
 	for _, p := range plugins {
-		for _, dep := range p.Dependencies() {
+		for _, dep := range m.dependencyNames(p.Name()) {
+			if _, ok := in_degree[dep]; !ok {
+				continue
+			}
 			adj[dep] = append(adj[dep], p.Name())
 			in_degree[p.Name()]++
 		}
@@ -259,6 +794,22 @@ func (m *Manager) topologicalSort(plugins []Plugin) []string {
 		queue = append(queue, next_zero...)
 	}
 
+	// 循环依赖中的插件入度无法归零，按名称追加到末尾（加载时会被跳过）
+	if len(result) < len(plugins) {
+		emitted := make(map[string]bool, len(result))
+		for _, name := range result {
+			emitted[name] = true
+		}
+		var rest []string
+		for name := range in_degree {
+			if !emitted[name] {
+				rest = append(rest, name)
+			}
+		}
+		sort.Strings(rest)
+		result = append(result, rest...)
+	}
+
 	return result
 }
 
@@ -272,19 +823,44 @@ func (m *Manager) GetPlugins() map[string]Plugin {
 	return m.pm.GetPlugins()
 }
 
-// GetPluginInfos 获取插件信息列表
+// GetPluginInfos 获取插件信息列表（按加载顺序）
 func (m *Manager) GetPluginInfos() []PluginInfo {
-	infos := m.pm.GetPluginInfos()
-
-	// 更新状态
-	for i := range infos {
-		if err, ok := m.errors[infos[i].Name]; ok {
-			infos[i].Status = "error: " + err.Error()
-		} else if m.initialized {
-			infos[i].Status = "active"
-		} else {
-			infos[i].Status = "inactive"
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	infos := make([]PluginInfo, 0, len(m.pm.plugins))
+	for _, name := range m.sortByPriority() {
+		p := m.pm.plugins[name]
+		info := PluginInfo{
+			Name:         p.Name(),
+			Version:      p.Version(),
+			Description:  p.Description(),
+			Priority:     p.Priority(),
+			Dependencies: p.Dependencies(),
+			Enabled:      !m.disabled[name],
+		}
+		_, info.Configurable = p.(Configurable)
+
+		// 更新状态
+		err, has_error := m.errors[name]
+		if conflict, ok := m.conflicts[name]; ok {
+			err, has_error = conflict, true
+		}
+		switch {
+		case m.disabled[name]:
+			info.Status = "disabled"
+		case has_error:
+			info.Status = "error"
+			info.Error = err.Error()
+		case m.skipped[name] != nil:
+			info.Status = "skipped"
+			info.Error = m.skipped[name].Error()
+		case m.loaded[name]:
+			info.Status = "active"
+		default:
+			info.Status = "inactive"
 		}
+		infos = append(infos, info)
 	}
 
 	return infos
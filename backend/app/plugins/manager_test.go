@@ -0,0 +1,612 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fst/backend/internal/openapi"
+	"fst/backend/internal/scheduler"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testPlugin struct {
+	BasePlugin
+	inits     int
+	shutdowns int
+}
+
+func newTestPlugin(name string, deps ...string) *testPlugin {
+	p := &testPlugin{BasePlugin: NewBasePlugin(name, "1.0.0", "")}
+	p.SetDependencies(deps)
+	return p
+}
+
+func (p *testPlugin) Init() error {
+	p.inits++
+	return nil
+}
+
+func (p *testPlugin) Shutdown() error {
+	p.shutdowns++
+	return nil
+}
+
+func (p *testPlugin) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/ping", func(c *gin.Context) { c.JSON(200, gin.H{"code": 200}) })
+}
+
+type memStateStore map[string]bool
+
+func (s memStateStore) LoadDisabled() (map[string]bool, error) {
+	disabled := make(map[string]bool)
+	for name, enabled := range s {
+		if !enabled {
+			disabled[name] = true
+		}
+	}
+	return disabled, nil
+}
+
+func (s memStateStore) SetEnabled(name string, enabled bool) error {
+	s[name] = enabled
+	return nil
+}
+
+func newTestRouter(m *Manager) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	m.RegisterAllRoutes(r.Group("/api"), r.Routes())
+	return r
+}
+
+type groupPlugin struct {
+	testPlugin
+}
+
+func (p *groupPlugin) RegisterGroupRoutes(groups RouteGroups) {
+	ok := func(c *gin.Context) { c.JSON(200, gin.H{"code": 200}) }
+	groups.Public.GET("/info", ok)
+	groups.Describe(groups.Public, "GET", "/info", openapi.Operation{Summary: p.Name() + " info"})
+	groups.User.GET("/items", ok)
+	groups.Admin.GET("/stats", ok)
+}
+
+func responseCode(t *testing.T, r *gin.Engine, path string) int {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	var body struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return body.Code
+}
+
+func TestDisableGatesRoutesAndPersists(t *testing.T) {
+	store := memStateStore{}
+	m := NewManager()
+	m.SetStateStore(store)
+	p := newTestPlugin("alpha")
+	m.Register(p)
+	if err := m.LoadAll(); err != nil {
+		t.Fatal(err)
+	}
+	r := newTestRouter(m)
+
+	if code := responseCode(t, r, "/api/plugins/alpha/ping"); code != 200 {
+		t.Fatalf("enabled plugin code = %d, want 200", code)
+	}
+	if err := m.Disable("alpha"); err != nil {
+		t.Fatal(err)
+	}
+	if code := responseCode(t, r, "/api/plugins/alpha/ping"); code != 404 {
+		t.Fatalf("disabled plugin code = %d, want 404", code)
+	}
+	if p.shutdowns != 1 || store["alpha"] {
+		t.Fatalf("shutdowns = %d, stored enabled = %v", p.shutdowns, store["alpha"])
+	}
+
+	if err := m.Enable("alpha"); err != nil {
+		t.Fatal(err)
+	}
+	if code := responseCode(t, r, "/api/plugins/alpha/ping"); code != 200 || p.inits != 2 {
+		t.Fatalf("re-enabled code = %d, inits = %d", code, p.inits)
+	}
+}
+
+func TestDisabledStateSurvivesRestart(t *testing.T) {
+	store := memStateStore{"alpha": false}
+	m := NewManager()
+	m.SetStateStore(store)
+	p := newTestPlugin("alpha")
+	m.Register(p)
+	m.LoadAll()
+
+	if p.inits != 0 {
+		t.Fatalf("disabled plugin initialized %d times", p.inits)
+	}
+	infos := m.GetPluginInfos()
+	if len(infos) != 1 || infos[0].Status != "disabled" || infos[0].Enabled {
+		t.Fatalf("infos = %+v", infos)
+	}
+	if err := m.Reinit("alpha"); err != ErrPluginDisabled {
+		t.Fatalf("reinit disabled plugin err = %v", err)
+	}
+}
+
+func TestDependencyChecks(t *testing.T) {
+	m := NewManager()
+	m.Register(newTestPlugin("base"))
+	m.Register(newTestPlugin("child", "base"))
+	m.LoadAll()
+
+	if err := m.Disable("base"); err == nil {
+		t.Fatal("disabling a plugin with enabled dependents should fail")
+	}
+	if err := m.Disable("child"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Disable("base"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Enable("child"); err == nil {
+		t.Fatal("enabling a plugin whose dependency is disabled should fail")
+	}
+	if err := m.Enable("missing"); err != ErrPluginNotFound {
+		t.Fatalf("enable missing err = %v", err)
+	}
+}
+
+type configPlugin struct {
+	testPlugin
+	applied PluginConfig
+}
+
+func (p *configPlugin) ConfigSchema() []ConfigField {
+	return []ConfigField{
+		{Key: "title", Type: FieldString, Default: "hi"},
+		{Key: "limit", Type: FieldInt, Default: 10},
+		{Key: "token", Type: FieldString, Secret: true},
+	}
+}
+
+func (p *configPlugin) Configure(config map[string]interface{}) error {
+	p.applied = config
+	return nil
+}
+
+type memConfigStore map[string]map[string]interface{}
+
+func (s memConfigStore) LoadConfig(name string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for k, v := range s[name] {
+		values[k] = v
+	}
+	return values, nil
+}
+
+func (s memConfigStore) SaveConfig(name string, values map[string]interface{}) error {
+	s[name] = values
+	return nil
+}
+
+func TestValidateConfig(t *testing.T) {
+	schema := (&configPlugin{}).ConfigSchema()
+	config, err := ValidateConfig(schema, map[string]interface{}{"limit": float64(5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config["limit"] != 5 || config["title"] != "hi" {
+		t.Fatalf("config = %v", config)
+	}
+	if _, ok := config["token"]; ok {
+		t.Fatal("field without value or default should be omitted")
+	}
+
+	_, err = ValidateConfig(schema, map[string]interface{}{"limit": "abc", "unknown": 1})
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Fields) != 2 {
+		t.Fatalf("err = %v, want errors for limit and unknown", err)
+	}
+}
+
+func TestUpdateConfigAppliesWithoutRestart(t *testing.T) {
+	store := memConfigStore{"cfg": {"limit": float64(3)}}
+	m := NewManager()
+	m.SetConfigStore(store)
+	p := &configPlugin{testPlugin: *newTestPlugin("cfg")}
+	m.Register(p)
+	m.LoadAll()
+
+	if p.applied["limit"] != 3 {
+		t.Fatalf("stored config not applied at load: %v", p.applied)
+	}
+
+	view, err := m.UpdateConfig("cfg", map[string]interface{}{"title": "hello", "token": "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.applied["title"] != "hello" || p.applied["token"] != "s3cret" || p.applied["limit"] != 3 {
+		t.Fatalf("applied = %v", p.applied)
+	}
+	if view.Values["token"] != SecretMaskedValue {
+		t.Fatalf("secret not masked: %v", view.Values["token"])
+	}
+
+	// 提交掩码保持原值，null 恢复默认值
+	if _, err := m.UpdateConfig("cfg", map[string]interface{}{"token": SecretMaskedValue, "limit": nil}); err != nil {
+		t.Fatal(err)
+	}
+	if p.applied["token"] != "s3cret" || p.applied["limit"] != 10 {
+		t.Fatalf("applied = %v", p.applied)
+	}
+
+	if _, err := m.UpdateConfig("cfg", map[string]interface{}{"limit": "many"}); err == nil {
+		t.Fatal("invalid value should be rejected")
+	}
+	if store["cfg"]["limit"] != 10 {
+		t.Fatalf("invalid update persisted: %v", store["cfg"])
+	}
+}
+
+func TestGroupRoutesAreNamespacedAndAuthenticated(t *testing.T) {
+	m := NewManager()
+	m.Register(&groupPlugin{testPlugin: *newTestPlugin("shop")})
+	m.LoadAll()
+	r := newTestRouter(m)
+
+	if code := responseCode(t, r, "/api/plugins/shop/info"); code != 200 {
+		t.Fatalf("public route code = %d, want 200", code)
+	}
+	for _, path := range []string{"/api/plugins/shop/user/items", "/api/plugins/shop/admin/stats"} {
+		if code := responseCode(t, r, path); code != 401 {
+			t.Fatalf("%s without token code = %d, want 401", path, code)
+		}
+	}
+}
+
+func TestRouteConflictWithCoreIsReported(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := NewManager()
+	m.Register(newTestPlugin("alpha"))
+	m.Register(newTestPlugin("beta"))
+	m.LoadAll()
+
+	r := gin.New()
+	r.GET("/api/plugins/alpha/ping", func(c *gin.Context) { c.JSON(200, gin.H{"code": 201}) })
+	m.RegisterAllRoutes(r.Group("/api"), r.Routes())
+
+	if code := responseCode(t, r, "/api/plugins/alpha/ping"); code != 201 {
+		t.Fatalf("core route code = %d, want 201", code)
+	}
+	if code := responseCode(t, r, "/api/plugins/beta/ping"); code != 200 {
+		t.Fatalf("non-conflicting plugin code = %d, want 200", code)
+	}
+	for _, info := range m.GetPluginInfos() {
+		if info.Name == "alpha" && (info.Status != "error" || info.Error == "") {
+			t.Fatalf("alpha info = %+v, want route conflict error", info)
+		}
+		if info.Name == "beta" && info.Status != "active" {
+			t.Fatalf("beta info = %+v", info)
+		}
+	}
+}
+
+func TestSkippedPluginLeavesNoRouteDocs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := NewManager()
+	m.Register(&groupPlugin{testPlugin: *newTestPlugin("docs-skipped")})
+	m.Register(&groupPlugin{testPlugin: *newTestPlugin("docs-kept")})
+	m.LoadAll()
+
+	// 冲突发生在 /info 登记说明之后，试注册中登记的说明不能保留
+	r := gin.New()
+	r.GET("/api/plugins/docs-skipped/admin/stats", func(c *gin.Context) {})
+	m.RegisterAllRoutes(r.Group("/api"), r.Routes())
+	r.GET("/api/plugins/docs-skipped/info", func(c *gin.Context) {})
+
+	doc := openapi.BuildEngine(r)
+	if op := doc.Paths["/api/plugins/docs-skipped/info"].Get; op.Summary != "" {
+		t.Fatalf("skipped plugin summary = %q, want none", op.Summary)
+	}
+	if op := doc.Paths["/api/plugins/docs-kept/info"].Get; op.Summary != "docs-kept info" {
+		t.Fatalf("plugin route summary = %q", op.Summary)
+	}
+}
+
+type extPlugin struct {
+	testPlugin
+	healthErr error
+	runs      int
+}
+
+func (p *extPlugin) HealthCheck(ctx context.Context) error { return p.healthErr }
+
+func (p *extPlugin) ScheduledTasks() []scheduler.Job {
+	return []scheduler.Job{{Name: "sync", Spec: "@hourly", Run: func(context.Context) error {
+		p.runs++
+		return nil
+	}}}
+}
+
+func (p *extPlugin) AdminMenu() []MenuItem {
+	return []MenuItem{
+		{Name: "home", Title: "Home", Path: "/plugins/ext", Order: 2, Permission: "view"},
+		{Name: "secret", Title: "Secret", Permission: "undeclared"},
+	}
+}
+
+func (p *extPlugin) AdminPermissions() []Permission {
+	return []Permission{{Key: "view", Title: "View"}}
+}
+
+type memJobRegistrar map[string]scheduler.Job
+
+func (r memJobRegistrar) Register(job scheduler.Job) error {
+	r[job.Name] = job
+	return nil
+}
+
+func TestOptionalInterfacesAreWiredAtLoad(t *testing.T) {
+	jobs := memJobRegistrar{}
+	m := NewManager()
+	m.SetJobRegistrar(jobs)
+	ext := &extPlugin{testPlugin: *newTestPlugin("ext")}
+	m.Register(ext)
+	m.Register(newTestPlugin("plain"))
+	m.LoadAll()
+
+	job, ok := jobs["plugin.ext.sync"]
+	if !ok || len(jobs) != 1 {
+		t.Fatalf("jobs = %v, want plugin.ext.sync", jobs)
+	}
+	job.Run(context.Background())
+	m.Disable("ext")
+	job.Run(context.Background())
+	if ext.runs != 1 {
+		t.Fatalf("runs = %d, task should be skipped while plugin is disabled", ext.runs)
+	}
+	if menus := m.GetAdminMenus(); len(menus.Menus) != 0 {
+		t.Fatalf("disabled plugin menus = %+v", menus)
+	}
+
+	// 重新启用不重复注册定时任务
+	m.Enable("ext")
+	menus := m.GetAdminMenus()
+	if len(menus.Menus) != 1 || menus.Menus[0].Name != "ext.home" || menus.Menus[0].Permission != "ext.view" {
+		t.Fatalf("menus = %+v", menus.Menus)
+	}
+	if len(menus.Permissions) != 1 || menus.Permissions[0].Key != "ext.view" {
+		t.Fatalf("permissions = %+v", menus.Permissions)
+	}
+
+	if report := m.CheckHealth(context.Background()); report.Status != HealthOK || len(report.Plugins) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	ext.healthErr = errors.New("db unreachable")
+	report := m.CheckHealth(context.Background())
+	if report.Status != HealthDegraded || report.Plugins[0].Status != HealthDown || report.Plugins[0].Error != "db unreachable" {
+		t.Fatalf("report = %+v", report)
+	}
+}
+
+type failingPlugin struct {
+	testPlugin
+}
+
+func (p *failingPlugin) Init() error { return errors.New("boom") }
+
+func TestDependencyFailuresCascadeOnlyToDependents(t *testing.T) {
+	m := NewManager()
+	base := newTestPlugin("base")
+	base.BasePlugin = NewBasePlugin("base", "1.1.0", "")
+	m.Register(base)
+	m.Register(newTestPlugin("needs-new-base", "base>=1.2"))
+	m.Register(newTestPlugin("needs-base", "base^1.0"))
+	m.Register(&failingPlugin{testPlugin: *newTestPlugin("broken")})
+	m.Register(newTestPlugin("uses-broken", "broken"))
+	m.Register(newTestPlugin("chained", "uses-broken"))
+	m.Register(newTestPlugin("orphan", "missing"))
+	m.Register(newTestPlugin("loop-a", "loop-b"))
+	m.Register(newTestPlugin("loop-b", "loop-a"))
+	if err := m.LoadAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"base":           LoadStatusLoaded,
+		"needs-base":     LoadStatusLoaded,
+		"needs-new-base": LoadStatusSkipped,
+		"broken":         LoadStatusFailed,
+		"uses-broken":    LoadStatusSkipped,
+		"chained":        LoadStatusSkipped,
+		"orphan":         LoadStatusSkipped,
+		"loop-a":         LoadStatusSkipped,
+		"loop-b":         LoadStatusSkipped,
+	}
+	report := m.LoadReport()
+	if len(report) != len(want) {
+		t.Fatalf("report has %d entries, want %d: %+v", len(report), len(want), report)
+	}
+	for _, r := range report {
+		if r.Status != want[r.Name] {
+			t.Errorf("%s status = %s (%s), want %s", r.Name, r.Status, r.Reason, want[r.Name])
+		}
+		if r.Status != LoadStatusLoaded && r.Reason == "" {
+			t.Errorf("%s has no reason", r.Name)
+		}
+	}
+
+	for _, info := range m.GetPluginInfos() {
+		if info.Name == "chained" && (info.Status != "skipped" || info.Error == "") {
+			t.Fatalf("chained info = %+v", info)
+		}
+	}
+	if err := m.Enable("needs-new-base"); err == nil {
+		t.Fatal("enabling a plugin with an unsatisfied version constraint should fail")
+	}
+}
+
+type blockingPlugin struct {
+	testPlugin
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingPlugin) Init() error {
+	if p.entered != nil {
+		p.entered <- struct{}{}
+		<-p.release
+	}
+	return p.testPlugin.Init()
+}
+
+func TestEnableDoesNotHoldLockDuringHooks(t *testing.T) {
+	m := NewManager()
+	m.SetStateStore(memStateStore{"alpha": false})
+	p := &blockingPlugin{testPlugin: *newTestPlugin("alpha")}
+	m.Register(p)
+	m.LoadAll()
+
+	p.entered, p.release = make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- m.Enable("alpha") }()
+	<-p.entered
+
+	// Init 执行期间状态检查不应被阻塞，插件尚未上线
+	if m.IsActive("alpha") {
+		t.Fatal("plugin active before Init returned")
+	}
+	if err := m.Reinit("alpha"); err != ErrPluginBusy {
+		t.Fatalf("concurrent reinit err = %v, want ErrPluginBusy", err)
+	}
+
+	close(p.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !m.IsActive("alpha") || p.inits != 1 {
+		t.Fatalf("active = %v, inits = %d", m.IsActive("alpha"), p.inits)
+	}
+	p.entered = nil
+	if err := m.Reinit("alpha"); err != nil {
+		t.Fatal(err)
+	}
+	if p.shutdowns != 1 || p.inits != 2 {
+		t.Fatalf("shutdowns = %d, inits = %d", p.shutdowns, p.inits)
+	}
+}
+
+type blockingConfigPlugin struct {
+	configPlugin
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingConfigPlugin) Configure(config map[string]interface{}) error {
+	if p.entered != nil {
+		p.entered <- struct{}{}
+		<-p.release
+	}
+	return p.configPlugin.Configure(config)
+}
+
+func TestUpdateConfigDoesNotHoldLockDuringConfigure(t *testing.T) {
+	m := NewManager()
+	m.SetConfigStore(memConfigStore{})
+	p := &blockingConfigPlugin{configPlugin: configPlugin{testPlugin: *newTestPlugin("cfg")}}
+	m.Register(p)
+	m.LoadAll()
+
+	p.entered, p.release = make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.UpdateConfig("cfg", map[string]interface{}{"title": "hello"})
+		done <- err
+	}()
+	<-p.entered
+
+	// Configure 执行期间状态检查不应被阻塞，同一插件的其他操作返回忙碌
+	if !m.IsActive("cfg") {
+		t.Fatal("plugin should stay active while reconfiguring")
+	}
+	if err := m.Reinit("cfg"); err != ErrPluginBusy {
+		t.Fatalf("concurrent reinit err = %v, want ErrPluginBusy", err)
+	}
+
+	close(p.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if p.applied["title"] != "hello" {
+		t.Fatalf("applied = %v", p.applied)
+	}
+}
+
+type blockingShutdownPlugin struct {
+	testPlugin
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingShutdownPlugin) Shutdown() error {
+	if p.entered != nil {
+		p.entered <- struct{}{}
+		<-p.release
+	}
+	return p.testPlugin.Shutdown()
+}
+
+func TestShutdownAllDoesNotHoldLockDuringShutdown(t *testing.T) {
+	m := NewManager()
+	p := &blockingShutdownPlugin{testPlugin: *newTestPlugin("alpha")}
+	m.Register(p)
+	m.LoadAll()
+
+	p.entered, p.release = make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- m.ShutdownAll() }()
+	<-p.entered
+
+	if m.IsActive("alpha") {
+		t.Fatal("plugin should be offline while shutting down")
+	}
+	close(p.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if p.shutdowns != 1 {
+		t.Fatalf("shutdowns = %d", p.shutdowns)
+	}
+	if err := m.ShutdownAll(); err != nil || p.shutdowns != 1 {
+		t.Fatalf("second ShutdownAll err = %v, shutdowns = %d", err, p.shutdowns)
+	}
+}
+
+func TestStoredConfigSurvivesSchemaChanges(t *testing.T) {
+	// 已移除的配置项不影响加载
+	store := memConfigStore{"cfg": {"limit": float64(3), "legacy": "x"}}
+	m := NewManager()
+	m.SetConfigStore(store)
+	p := &configPlugin{testPlugin: *newTestPlugin("cfg")}
+	m.Register(p)
+	m.LoadAll()
+	if !m.IsActive("cfg") || p.applied["limit"] != 3 {
+		t.Fatalf("active = %v, applied = %v", m.IsActive("cfg"), p.applied)
+	}
+
+	// 已保存的值不再合法时，修正配置不应丢失已保存的敏感项
+	store["cfg"] = map[string]interface{}{"limit": "many", "token": "s3cret"}
+	if _, err := m.UpdateConfig("cfg", map[string]interface{}{"limit": 5}); err != nil {
+		t.Fatal(err)
+	}
+	if store["cfg"]["limit"] != 5 || store["cfg"]["token"] != "s3cret" {
+		t.Fatalf("stored = %v", store["cfg"])
+	}
+}
@@ -0,0 +1,118 @@
+package plugins
+
+import (
+	"fmt"
+	"fst/backend/internal/middleware"
+	"fst/backend/internal/openapi"
+	"fst/backend/utils"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutePrefix 插件路由的命名空间前缀，插件路由位于 <api>/plugins/<插件名>/
+const RoutePrefix = "/plugins"
+
+// RouteGroups 插件可用的路由组，均位于插件命名空间下
+type RouteGroups struct {
+	Public *gin.RouterGroup // /plugins/<name>        无需登录
+	User   *gin.RouterGroup // /plugins/<name>/user   用户或管理员登录（AuthMiddlewareForGuard）
+	Admin  *gin.RouterGroup // /plugins/<name>/admin  仅管理员（AuthMiddlewareForGuard + AdminOnly）
+
+	dryRun bool // 试注册，不登记接口文档
+}
+
+// Describe 登记插件接口的文档说明，group 与 relativePath 同注册路由时一致
+// 与注册路由写在一起即可：试注册时不登记，冲突被跳过的插件也不会留下说明
+func (g RouteGroups) Describe(group *gin.RouterGroup, method, relativePath string, op openapi.Operation) {
+	if g.dryRun {
+		return
+	}
+	openapi.Describe(method, path.Join(group.BasePath(), relativePath), op)
+}
+
+// GroupRouter 按权限分组注册路由的插件（可选接口）
+// 未实现的插件通过 RegisterRoutes 接收 Public 组
+// 与 RegisterRoutes 一样会被调用两次，实现中只能注册路由，不能有其他副作用；
+// 接口文档通过 groups.Describe 登记
+type GroupRouter interface {
+	RegisterGroupRoutes(groups RouteGroups)
+}
+
+// RegisterAllRoutes 在插件命名空间下注册所有插件的路由
+// core 为已注册的核心路由：先在临时路由表中试注册检测冲突，冲突的插件不注册路由并记录错误
+func (m *Manager) RegisterAllRoutes(router *gin.RouterGroup, core gin.RoutesInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	accepted := append(gin.RoutesInfo{}, core...)
+	for _, name := range m.sortByPriority() {
+		routes, err := m.dryRunRoutes(name, router.BasePath(), accepted)
+		if err != nil {
+			m.conflicts[name] = fmt.Errorf("路由冲突: %v", err)
+			log.Printf("[Plugin] %s 路由冲突，已跳过: %v", name, err)
+			continue
+		}
+		accepted = append(accepted, routes...)
+
+		// 所有插件都注册路由，由 gate 在请求时按启用状态放行，
+		// 这样运行时启用插件无需重启
+		m.registerPluginRoutes(name, router, false)
+		log.Printf("[Plugin] %s 路由注册完成: %s", name, joinPath(router.BasePath(), RoutePrefix, name))
+	}
+}
+
+// dryRunRoutes 在包含已接受路由的临时路由表中注册插件路由，返回插件新增的路由
+// gin 的路由注册后无法撤销，冲突时又会在注册到一半时 panic，所以只能先试注册再正式注册，
+// 插件的注册方法因此会被调用两次，必须没有副作用
+func (m *Manager) dryRunRoutes(name, basePath string, accepted gin.RoutesInfo) (routes gin.RoutesInfo, err error) {
+	// 临时路由表不输出调试日志
+	printRoute := gin.DebugPrintRouteFunc
+	gin.DebugPrintRouteFunc = func(string, string, string, int) {}
+	defer func() { gin.DebugPrintRouteFunc = printRoute }()
+
+	scratch := gin.New()
+	noop := func(*gin.Context) {}
+	existing := make(map[string]bool, len(accepted))
+	for _, r := range accepted {
+		scratch.Handle(r.Method, r.Path, noop)
+		existing[r.Method+" "+r.Path] = true
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	m.registerPluginRoutes(name, scratch.Group(basePath), true)
+	for _, r := range scratch.Routes() {
+		if !existing[r.Method+" "+r.Path] {
+			routes = append(routes, r)
+		}
+	}
+	return routes, nil
+}
+
+// registerPluginRoutes 构建插件命名空间路由组并调用插件注册，dryRun 为试注册
+func (m *Manager) registerPluginRoutes(name string, router *gin.RouterGroup, dryRun bool) {
+	root := router.Group(RoutePrefix+"/"+name, m.gate(name))
+	groups := RouteGroups{
+		Public: root,
+		User:   root.Group("/user", middleware.AuthMiddlewareForGuard(utils.UserAuthGuard, utils.AdminAuthGuard)),
+		Admin:  root.Group("/admin", middleware.AuthMiddlewareForGuard(utils.AdminAuthGuard), middleware.AdminOnly()),
+		dryRun: dryRun,
+	}
+
+	p := m.pm.plugins[name]
+	if gr, ok := p.(GroupRouter); ok {
+		gr.RegisterGroupRoutes(groups)
+		return
+	}
+	p.RegisterRoutes(groups.Public)
+}
+
+func joinPath(parts ...string) string {
+	return strings.ReplaceAll(strings.Join(parts, "/"), "//", "/")
+}
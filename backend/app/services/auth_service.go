@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"fst/backend/app/models"
 	"fst/backend/internal/config"
+	"fst/backend/internal/events"
 	"fst/backend/utils"
 	"time"
 )
@@ -90,10 +92,82 @@ func (s *AuthService) Login(username, password, authGuard, clientIP string) (*Lo
 	// 验证密码
 	if !utils.CheckPasswordHash(password, user.Password) {
 		// 增加失败次数（带锁定）
-		s.userService.IncrementLoginFailureWithLock(user.ID, config.GlobalConfig.LoginMaxFailureCount, config.GlobalConfig.LoginLockDurationMinutes)
+		s.userService.IncrementLoginFailureWithLock(user.ID, config.Get().LoginMaxFailureCount, config.Get().LoginLockDurationMinutes)
 		return nil, NewServiceError(401, "Invalid account or password")
 	}
 
+	return s.completeLogin(user, authGuard, clientIP)
+}
+
+// LoginBySMS 手机号 + 短信验证码登录
+// 验证码错误与密码错误一样计入登录失败次数，达到上限后锁定账户
+func (s *AuthService) LoginBySMS(phone, code, authGuard, clientIP string) (*LoginResult, *ServiceError) {
+	var ok bool
+	authGuard, ok = normalizeAuthGuard(authGuard)
+	if !ok {
+		return nil, NewServiceError(400, "Invalid auth guard")
+	}
+	user, err := models.GetUserByMobile(phone)
+	if err != nil {
+		return nil, NewServiceError(401, "Invalid phone number or verification code")
+	}
+
+	if svc_err := s.checkAccountLock(user); svc_err != nil {
+		return nil, svc_err
+	}
+
+	if user.Status == 0 {
+		return nil, NewServiceError(403, "Account is inactive")
+	}
+
+	if !ConsumeSMSCode(phone, code, SMSPurposeLogin) {
+		s.userService.IncrementLoginFailureWithLock(user.ID, config.Get().LoginMaxFailureCount, config.Get().LoginLockDurationMinutes)
+		return nil, NewServiceError(401, "Invalid phone number or verification code")
+	}
+
+	return s.completeLogin(user, authGuard, clientIP)
+}
+
+// ResetPasswordBySMS 使用短信验证码重置密码
+// 与验证码登录共用账户锁定：验证码错误计入登录失败次数，锁定期间不能重置
+func (s *AuthService) ResetPasswordBySMS(phone, code, newPassword string) *ServiceError {
+	user, err := models.GetUserByMobile(phone)
+	if err != nil {
+		// 与验证码错误返回相同信息，避免探测手机号是否注册
+		return NewServiceError(400, "Invalid or expired verification code")
+	}
+
+	if svc_err := s.checkAccountLock(user); svc_err != nil {
+		return svc_err
+	}
+
+	if !ConsumeSMSCode(phone, code, SMSPurposeResetPassword) {
+		s.userService.IncrementLoginFailureWithLock(user.ID, config.Get().LoginMaxFailureCount, config.Get().LoginLockDurationMinutes)
+		return NewServiceError(400, "Invalid or expired verification code")
+	}
+
+	if err := s.UpdatePassword(user.ID, newPassword); err != nil {
+		return NewServiceError(500, "Failed to update password")
+	}
+	return nil
+}
+
+// checkAccountLock 账户处于锁定期时返回错误，锁定已过期时清除锁定
+func (s *AuthService) checkAccountLock(user *models.User) *ServiceError {
+	now := time.Now().Unix()
+	if user.LockUntil == nil {
+		return nil
+	}
+	if *user.LockUntil > now {
+		remaining := (*user.LockUntil - now) / 60
+		return NewServiceError(403, fmt.Sprintf("Account is locked. Please try again in %d minutes", remaining))
+	}
+	s.userService.ClearLockUntil(user.ID)
+	return nil
+}
+
+// completeLogin 凭据校验通过后：更新登录信息、校验入口权限、触发登录事件并签发 Token
+func (s *AuthService) completeLogin(user *models.User, authGuard, clientIP string) (*LoginResult, *ServiceError) {
 	// 更新登录信息
 	s.userService.UpdateLoginInfo(user.ID, clientIP)
 
@@ -101,9 +175,16 @@ func (s *AuthService) Login(username, password, authGuard, clientIP string) (*Lo
 		return nil, NewServiceError(403, "Admin access only")
 	}
 
+	events.UserLogin.Emit(context.Background(), events.UserLoginEvent{
+		UserID:    user.ID,
+		Username:  user.Username,
+		AuthGuard: authGuard,
+		IP:        clientIP,
+	})
+
 	// 生成 Token
-	accessTTL := time.Duration(config.GlobalConfig.JWTAccessExpire) * time.Second
-	refreshTTL := time.Duration(config.GlobalConfig.JWTRefreshExpire) * time.Second
+	accessTTL := time.Duration(config.Get().JWTAccessExpire) * time.Second
+	refreshTTL := time.Duration(config.Get().JWTRefreshExpire) * time.Second
 
 	accessToken, err := utils.GenerateTokenForGuardWithTTL(user.ID, user.Role, authGuard, accessTTL)
 	if err != nil {
@@ -145,7 +226,17 @@ func (s *AuthService) Register(user *models.User) error {
 	}
 
 	// 创建用户
-	return models.CreateUser(user)
+	if err := models.CreateUser(user); err != nil {
+		return err
+	}
+
+	events.UserRegistered.Emit(context.Background(), events.UserRegisteredEvent{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Mobile:   user.Mobile,
+	})
+	return nil
 }
 
 // RefreshToken 刷新Token
@@ -174,8 +265,8 @@ func (s *AuthService) RefreshToken(refreshToken, authGuard, clientIP, userAgent,
 	}
 
 	// 生成新Token
-	accessTTL := time.Duration(config.GlobalConfig.JWTAccessExpire) * time.Second
-	refreshTTL := time.Duration(config.GlobalConfig.JWTRefreshExpire) * time.Second
+	accessTTL := time.Duration(config.Get().JWTAccessExpire) * time.Second
+	refreshTTL := time.Duration(config.Get().JWTRefreshExpire) * time.Second
 
 	accessToken, err := utils.GenerateTokenForGuardWithTTL(user.ID, user.Role, authGuard, accessTTL)
 	if err != nil {
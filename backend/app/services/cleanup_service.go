@@ -1,80 +1,78 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"fst/backend/app/models"
 	"fst/backend/internal/config"
+	"fst/backend/internal/scheduler"
 	"log"
-	"sync"
 	"time"
 )
 
-// CleanupStatus 存储验证码清理任务的运行状态（仅内存）
-type CleanupStatus struct {
-	mu              sync.RWMutex
-	lastCleanupTime time.Time
-	intervalMinutes int
-	running         bool
-}
-
-var cleanupStatus = &CleanupStatus{}
+// CleanupJobName 验证码清理定时任务名称
+const CleanupJobName = "verification_cleanup"
 
-// StartCleanupTask 启动验证码定时清理后台任务
-// 不输出周期性日志，仅在出错时打印，清理时间记录在内存中
-func StartCleanupTask() {
-	interval := config.GlobalConfig.CleanupIntervalMinutes
+// cleanupInterval 返回清理间隔（分钟），可通过 CLEANUP_INTERVAL_MINUTES 配置，默认10分钟
+func cleanupInterval() int {
+	interval := config.Get().CleanupIntervalMinutes
 	if interval <= 0 {
 		interval = 10
 	}
+	return interval
+}
 
-	cleanupStatus.mu.Lock()
-	cleanupStatus.intervalMinutes = interval
-	cleanupStatus.running = true
-	cleanupStatus.mu.Unlock()
-
-	go func() {
-		ticker := time.NewTicker(time.Duration(interval) * time.Minute)
-		defer ticker.Stop()
-
-		// 立即执行一次清理
-		runCleanup()
-
-		for range ticker.C {
-			runCleanup()
-		}
-	}()
+// cleanupJob 验证码与会话清理任务，由调度器保证集群内只执行一次
+func cleanupJob() scheduler.Job {
+	return scheduler.Job{
+		Name:        CleanupJobName,
+		Description: "清理过期验证码与用户会话",
+		Spec:        fmt.Sprintf("@every %dm", cleanupInterval()),
+		Timeout:     5 * time.Minute,
+		Run:         runCleanup,
+	}
 }
 
-// runCleanup 执行一次清理，只在出错时输出日志
-func runCleanup() {
+// runCleanup 执行一次清理，错误汇总后写入运行记录
+func runCleanup(ctx context.Context) error {
+	var errs []error
 	if err := models.SoftDeleteExpiredCodes(); err != nil {
-		log.Printf("[Cleanup] Failed to soft delete expired codes: %v", err)
+		errs = append(errs, fmt.Errorf("soft delete expired codes: %w", err))
 	}
 	if err := models.CleanupOldVerificationCodes(); err != nil {
-		log.Printf("[Cleanup] Failed to cleanup old codes: %v", err)
+		errs = append(errs, fmt.Errorf("cleanup old codes: %w", err))
 	}
 	if err := models.CleanupExpiredSessions(); err != nil {
-		log.Printf("[Cleanup] Failed to cleanup user sessions: %v", err)
+		errs = append(errs, fmt.Errorf("cleanup user sessions: %w", err))
 	}
-
-	cleanupStatus.mu.Lock()
-	cleanupStatus.lastCleanupTime = time.Now()
-	cleanupStatus.mu.Unlock()
+	return errors.Join(errs...)
 }
 
-// GetCleanupStatus 返回清理任务的当前状态
+// GetCleanupStatus 返回清理任务的当前状态（取自集群共享的运行记录）
 func GetCleanupStatus() map[string]interface{} {
-	cleanupStatus.mu.RLock()
-	defer cleanupStatus.mu.RUnlock()
-
+	interval := cleanupInterval()
 	result := map[string]interface{}{
-		"running":          cleanupStatus.running,
-		"interval_minutes": cleanupStatus.intervalMinutes,
+		"running":          false,
+		"interval_minutes": interval,
 	}
 
-	if !cleanupStatus.lastCleanupTime.IsZero() {
-		result["last_cleanup_time"] = cleanupStatus.lastCleanupTime.Format("2006-01-02 15:04:05")
-		next := cleanupStatus.lastCleanupTime.Add(time.Duration(cleanupStatus.intervalMinutes) * time.Minute)
-		result["next_cleanup_time"] = next.Format("2006-01-02 15:04:05")
+	// 暂停状态与最近运行记录保存在数据库中，需经 ListJobs 合并后才是准确的
+	jobs, err := ListJobs()
+	if err != nil {
+		log.Printf("[Cleanup] Failed to load job status: %v", err)
+	}
+	for _, job := range jobs {
+		if job.Name != CleanupJobName {
+			continue
+		}
+		result["running"] = !job.Paused
+		if !job.NextRun.IsZero() {
+			result["next_cleanup_time"] = job.NextRun.Format("2006-01-02 15:04:05")
+		}
+		if job.LastRun != nil {
+			result["last_cleanup_time"] = time.Unix(job.LastRun.StartedAt, 0).Format("2006-01-02 15:04:05")
+		}
 	}
 
 	return result
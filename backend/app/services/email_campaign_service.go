@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fst/backend/app/models"
+	"fst/backend/internal/queue"
+	"log"
+	"strings"
+	"time"
+)
+
+// ========================================
+// 邮件群发：按筛选条件分批取用户，每批一个队列任务，按限速延迟投递下一批
+// ========================================
+
+// EmailCampaignJob 群发任务的队列任务类型
+const EmailCampaignJob = "email.campaign"
+
+// campaignBatchMax 每个队列任务最多处理的收件人数
+const campaignBatchMax = 100
+
+// EmailCampaignPayload 群发队列任务参数
+type EmailCampaignPayload struct {
+	CampaignID uint64 `json:"campaign_id"`
+	RunID      int    `json:"run_id"`
+}
+
+var (
+	// ErrCampaignInvalid 群发参数无效（模板不存在、无法渲染等）
+	ErrCampaignInvalid = errors.New("群发任务无效")
+	// ErrCampaignState 任务当前状态不允许该操作
+	ErrCampaignState = errors.New("群发任务当前状态不能执行该操作")
+)
+
+// EmailCampaignRequest 创建群发任务参数
+type EmailCampaignRequest struct {
+	Name          string                     `json:"name" binding:"required,max=100"`
+	TemplateName  string                     `json:"template_name" binding:"required"`
+	Lang          string                     `json:"lang"` // 用户语言没有对应模板时使用，默认 zh-CN
+	Vars          map[string]any             `json:"vars"` // 所有收件人共用的模板变量
+	Filter        models.EmailCampaignFilter `json:"filter"`
+	ScheduledAt   int64                      `json:"scheduled_at"`                               // 计划发送时间，0 或已过去表示立即发送
+	RatePerMinute int                        `json:"rate_per_minute" binding:"min=0,max=100000"` // 每分钟最多发送数，0=不限
+}
+
+// PreviewCampaignRecipients 统计筛选条件当前匹配的收件人数
+func (s *EmailService) PreviewCampaignRecipients(filter *models.EmailCampaignFilter) (int64, error) {
+	return models.CountCampaignRecipients(filter, 0)
+}
+
+// CreateCampaign 校验模板并创建群发任务，到计划时间后开始发送
+func (s *EmailService) CreateCampaign(req *EmailCampaignRequest, author TemplateAuthor) (*models.EmailCampaign, error) {
+	if req.Lang == "" {
+		req.Lang = "zh-CN"
+	}
+	tpl, err := models.GetEmailTemplate(req.TemplateName, req.Lang)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 模板不存在或未启用: %s (%s)", ErrCampaignInvalid, req.TemplateName, req.Lang)
+	}
+	if _, err := s.RenderTemplate(tpl, campaignVars(req.Vars, &models.CampaignRecipient{})); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCampaignInvalid, err)
+	}
+
+	vars, err := json.Marshal(req.Vars)
+	if err != nil || req.Vars == nil {
+		vars = []byte("{}")
+	}
+	filter, _ := json.Marshal(req.Filter)
+	total, err := models.CountCampaignRecipients(&req.Filter, 0)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	if req.ScheduledAt < now {
+		req.ScheduledAt = now
+	}
+
+	c := &models.EmailCampaign{
+		Name:          req.Name,
+		TemplateName:  req.TemplateName,
+		Lang:          req.Lang,
+		Vars:          vars,
+		Filter:        filter,
+		ScheduledAt:   req.ScheduledAt,
+		RatePerMinute: req.RatePerMinute,
+		Total:         total,
+		CreatedBy:     author.ID,
+		CreatedByName: author.Name,
+	}
+	if err := models.CreateEmailCampaign(c); err != nil {
+		return nil, err
+	}
+	if err := enqueueCampaign(c); err != nil {
+		// 入队失败时暂停，管理员可稍后恢复
+		models.PauseEmailCampaign(c.ID)
+		return nil, err
+	}
+	log.Printf("[Email] 群发任务 #%d 已创建: %s，预计 %d 人，计划时间 %s", c.ID, c.Name, total,
+		time.Unix(c.ScheduledAt, 0).Format("2006-01-02 15:04:05"))
+	return c, nil
+}
+
+// PauseCampaign 暂停群发，正在处理的一批在当前收件人发送完后停止
+func (s *EmailService) PauseCampaign(id uint64) error {
+	return changeCampaign(id, models.PauseEmailCampaign)
+}
+
+// CancelCampaign 取消群发，已发送的邮件不受影响
+func (s *EmailService) CancelCampaign(id uint64) error {
+	return changeCampaign(id, models.CancelEmailCampaign)
+}
+
+// ResumeCampaign 恢复已暂停的群发，从上次处理到的用户继续
+func (s *EmailService) ResumeCampaign(id uint64) error {
+	if err := changeCampaign(id, models.ResumeEmailCampaign); err != nil {
+		return err
+	}
+	c, err := models.GetEmailCampaignByID(id)
+	if err != nil {
+		return err
+	}
+	return enqueueCampaign(c)
+}
+
+// changeCampaign 执行状态变更，任务不存在时返回 sql.ErrNoRows，状态不符时返回 ErrCampaignState
+func changeCampaign(id uint64, change func(uint64) (bool, error)) error {
+	if _, err := models.GetEmailCampaignByID(id); err != nil {
+		return err
+	}
+	ok, err := change(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCampaignState
+	}
+	return nil
+}
+
+// enqueueCampaign 投递下一批的队列任务，未到计划时间时延迟到计划时间
+func enqueueCampaign(c *models.EmailCampaign) error {
+	return enqueueCampaignAfter(c, time.Until(time.Unix(c.ScheduledAt, 0)))
+}
+
+func enqueueCampaignAfter(c *models.EmailCampaign, delay time.Duration) error {
+	var opts []queue.EnqueueOption
+	if delay > 0 {
+		opts = append(opts, queue.Delay(delay))
+	}
+	_, err := queue.Enqueue(EmailCampaignJob, EmailCampaignPayload{CampaignID: c.ID, RunID: c.RunID}, opts...)
+	return err
+}
+
+// runCampaign 处理群发的一批收件人；任务被暂停、取消或恢复为新的运行序号后，旧任务直接结束
+func (s *EmailService) runCampaign(ctx context.Context, p EmailCampaignPayload) error {
+	c, err := models.GetEmailCampaignByID(p.CampaignID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if c.RunID != p.RunID {
+		return nil
+	}
+
+	var filter models.EmailCampaignFilter
+	if err := json.Unmarshal(c.Filter, &filter); err != nil {
+		return fmt.Errorf("群发任务 #%d 筛选条件无效: %w", c.ID, err)
+	}
+
+	switch c.Status {
+	case models.EmailCampaignScheduled:
+		if wait := time.Until(time.Unix(c.ScheduledAt, 0)); wait > 0 {
+			return enqueueCampaignAfter(c, wait)
+		}
+		// 开始时确定收件人范围，之后注册的用户不会收到
+		max_user_id, err := models.GetMaxUserID()
+		if err != nil {
+			return err
+		}
+		total, err := models.CountCampaignRecipients(&filter, max_user_id)
+		if err != nil {
+			return err
+		}
+		ok, err := models.StartEmailCampaign(c.ID, c.RunID, max_user_id, total)
+		if err != nil || !ok {
+			return err
+		}
+		c.MaxUserID, c.Status = max_user_id, models.EmailCampaignRunning
+		log.Printf("[Email] 群发任务 #%d 开始发送，共 %d 人", c.ID, total)
+	case models.EmailCampaignRunning:
+	default:
+		return nil
+	}
+
+	batch := campaignBatchSize(c.RatePerMinute)
+	recipients, err := models.GetCampaignRecipients(&filter, c.LastUserID, c.MaxUserID, batch)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		if ok, err := models.FinishEmailCampaign(c.ID, c.RunID); err != nil || !ok {
+			return err
+		}
+		log.Printf("[Email] 群发任务 #%d 已完成", c.ID)
+		return nil
+	}
+
+	var vars map[string]any
+	if len(c.Vars) > 0 {
+		if err := json.Unmarshal(c.Vars, &vars); err != nil {
+			return fmt.Errorf("群发任务 #%d 模板变量无效: %w", c.ID, err)
+		}
+	}
+
+	started := time.Now()
+	langs := map[string]string{}
+	processed := 0
+	for i := range recipients {
+		// 队列停止时交给下一个任务继续
+		if ctx.Err() != nil {
+			break
+		}
+		active, err := models.IsEmailCampaignActive(c.ID, c.RunID)
+		if err != nil {
+			return err
+		}
+		if !active {
+			return nil
+		}
+		r := &recipients[i]
+		result := s.sendCampaignEmail(c, r, s.campaignLang(c, r.Language, langs), vars)
+		if err := models.AdvanceEmailCampaign(c.ID, r.ID, result); err != nil {
+			return err
+		}
+		processed++
+	}
+
+	return enqueueCampaignAfter(c, campaignDelay(processed, c.RatePerMinute, time.Since(started)))
+}
+
+// sendCampaignEmail 向一个收件人发送群发邮件（非事务邮件），返回 email_logs 状态
+func (s *EmailService) sendCampaignEmail(c *models.EmailCampaign, r *models.CampaignRecipient, lang string, vars map[string]any) int {
+	msg, body, err := s.buildTemplateMessage(r.Email, c.TemplateName, lang, campaignVars(vars, r))
+	if err != nil {
+		models.CreateCampaignEmailLog(c.ID, r.Email, c.Name, "", c.TemplateName, models.EmailLogFailed, err.Error())
+		return models.EmailLogFailed
+	}
+	err = s.deliver(msg, c.TemplateName, body, true, c.ID)
+	switch {
+	case err == nil:
+		return models.EmailLogSuccess
+	case IsEmailSkipped(err):
+		return models.EmailLogSkipped
+	default:
+		return models.EmailLogFailed
+	}
+}
+
+// campaignLang 收件人语言有对应模板时使用该语言，否则使用任务的默认语言；langs 缓存本批的查询结果
+func (s *EmailService) campaignLang(c *models.EmailCampaign, lang string, langs map[string]string) string {
+	lang = strings.TrimSpace(lang)
+	if lang == "" || lang == c.Lang {
+		return c.Lang
+	}
+	if resolved, ok := langs[lang]; ok {
+		return resolved
+	}
+	resolved := c.Lang
+	if models.CheckTemplateExists(c.TemplateName, lang) {
+		resolved = lang
+	}
+	langs[lang] = resolved
+	return resolved
+}
+
+// campaignVars 合并任务变量与收件人变量（username、nickname、email），收件人变量优先
+func campaignVars(vars map[string]any, r *models.CampaignRecipient) map[string]any {
+	result := make(map[string]any, len(vars)+3)
+	for k, v := range vars {
+		result[k] = v
+	}
+	nickname := r.Nickname
+	if nickname == "" {
+		nickname = r.Username
+	}
+	result["username"] = r.Username
+	result["nickname"] = nickname
+	result["email"] = r.Email
+	return result
+}
+
+// campaignBatchSize 每批处理的收件人数：限速时不超过每分钟发送数
+func campaignBatchSize(rate int) int {
+	if rate > 0 && rate < campaignBatchMax {
+		return rate
+	}
+	return campaignBatchMax
+}
+
+// campaignDelay 按限速计算下一批的延迟：sent 封邮件应占用的时间减去实际耗时
+func campaignDelay(sent, rate int, elapsed time.Duration) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	delay := time.Duration(sent)*time.Minute/time.Duration(rate) - elapsed
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
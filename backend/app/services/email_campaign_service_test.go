@@ -0,0 +1,52 @@
+package services
+
+import (
+	"fst/backend/app/models"
+	"testing"
+	"time"
+)
+
+// TestCampaignThrottle 测试群发的分批大小与限速延迟
+func TestCampaignThrottle(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      int
+		sent      int
+		elapsed   time.Duration
+		wantBatch int
+		wantDelay time.Duration
+	}{
+		{"不限速", 0, 100, time.Second, campaignBatchMax, 0},
+		{"每分钟 10 封", 10, 10, 5 * time.Second, 10, 55 * time.Second},
+		{"每分钟 600 封", 600, 100, 2 * time.Second, campaignBatchMax, 8 * time.Second},
+		{"发送耗时超过限速", 60, 60, 2 * time.Minute, 60, 0},
+		{"中途停止只按已发送数计算", 30, 3, 0, 30, 6 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := campaignBatchSize(tt.rate); got != tt.wantBatch {
+				t.Errorf("campaignBatchSize(%d) = %d, want %d", tt.rate, got, tt.wantBatch)
+			}
+			if got := campaignDelay(tt.sent, tt.rate, tt.elapsed); got != tt.wantDelay {
+				t.Errorf("campaignDelay(%d, %d, %v) = %v, want %v", tt.sent, tt.rate, tt.elapsed, got, tt.wantDelay)
+			}
+		})
+	}
+}
+
+// TestCampaignVars 测试收件人变量覆盖任务变量，昵称为空时使用用户名
+func TestCampaignVars(t *testing.T) {
+	vars := map[string]any{"title": "活动", "username": "旧值"}
+	got := campaignVars(vars, &models.CampaignRecipient{Username: "alice", Email: "alice@example.com"})
+
+	want := map[string]any{"title": "活动", "username": "alice", "nickname": "alice", "email": "alice@example.com"}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("vars[%q] = %v, want %v", k, got[k], v)
+		}
+	}
+	if vars["username"] != "旧值" {
+		t.Error("campaignVars 不应修改任务变量")
+	}
+}
@@ -1,10 +1,19 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"fst/backend/app/models"
 	"fst/backend/internal/config"
+	"fst/backend/internal/events"
+	"fst/backend/internal/lifecycle"
+	"fst/backend/internal/mailer"
+	"fst/backend/internal/mailtpl"
+	"fst/backend/internal/queue"
 	"fst/backend/utils"
+	"log"
+	"net/mail"
 	"strings"
 	"time"
 )
@@ -17,75 +26,203 @@ func NewEmailService() *EmailService {
 	return &EmailService{}
 }
 
-// SendResult 发送结果
-type SendResult struct {
-	Success bool
-	Error   error
+// 邮件队列任务类型
+const (
+	EmailSendJob         = "email.send"
+	EmailSendTemplateJob = "email.send_template"
+)
+
+// EmailSendPayload 普通邮件任务参数
+type EmailSendPayload struct {
+	To           string `json:"to"`
+	Subject      string `json:"subject"`
+	Body         string `json:"body"`
+	Notification bool   `json:"notification,omitempty"` // 非事务邮件，收件人关闭邮件通知时不发送
+}
+
+// EmailTemplatePayload 模板邮件任务参数
+type EmailTemplatePayload struct {
+	To           string         `json:"to"`
+	Template     string         `json:"template"`
+	Lang         string         `json:"lang"`
+	Vars         map[string]any `json:"vars"`
+	Notification bool           `json:"notification,omitempty"`
+}
+
+var (
+	// ErrEmailSuppressed 收件人都在抑制列表中（退信或投诉），邮件未发送
+	ErrEmailSuppressed = errors.New("收件人已被抑制")
+	// ErrEmailNotifyDisabled 收件人都关闭了邮件通知，非事务邮件未发送
+	ErrEmailNotifyDisabled = errors.New("收件人已关闭邮件通知")
+)
+
+// IsEmailSkipped 判断错误是否为收件人被抑制或关闭通知而未发送（无需重试）
+func IsEmailSkipped(err error) bool {
+	return errors.Is(err, ErrEmailSuppressed) || errors.Is(err, ErrEmailNotifyDisabled)
 }
 
-// SendEmail 发送简单邮件
+// SendEmail 发送简单邮件（事务邮件）
 func (s *EmailService) SendEmail(to, subject, body string) error {
-	msg := utils.EmailMessage{
-		To:      to,
+	return s.sendEmail(to, subject, body, false)
+}
+
+// SendNotificationEmail 发送非事务邮件（通知、公告等），收件人关闭邮件通知时返回 ErrEmailNotifyDisabled
+func (s *EmailService) SendNotificationEmail(to, subject, body string) error {
+	return s.sendEmail(to, subject, body, true)
+}
+
+func (s *EmailService) sendEmail(to, subject, body string, notification bool) error {
+	msg := &mailer.Message{
+		To:      []string{to},
 		Subject: subject,
-		Body:    body,
+		HTML:    body,
 	}
+	return s.deliver(msg, "", body, notification, 0)
+}
 
-	err := utils.SendEmail(msg)
+// NewMessage 创建邮件，发件人为系统默认发件人；可继续设置抄送、密送、Reply-To、附件、内嵌图片等
+func (s *EmailService) NewMessage(to ...string) *mailer.Message {
+	return &mailer.Message{From: utils.DefaultFrom(), To: to}
+}
 
-	// 记录日志
-	status := 1
-	error_msg := ""
-	if err != nil {
-		status = 0
-		error_msg = err.Error()
+// SendMessage 发送 MIME 邮件并记录日志
+func (s *EmailService) SendMessage(msg *mailer.Message) error {
+	content := msg.HTML
+	if content == "" {
+		content = msg.Text
 	}
+	return s.deliver(msg, "", content, false, 0)
+}
 
-	go models.CreateEmailLog(to, subject, body, "", status, error_msg)
+// SendTemplateEmail 发送模板邮件（事务邮件）
+func (s *EmailService) SendTemplateEmail(to, template_name, lang string, vars map[string]any) error {
+	return s.sendTemplateEmail(to, template_name, lang, vars, false)
+}
 
-	return err
+// SendNotificationTemplateEmail 发送非事务模板邮件，收件人关闭邮件通知时返回 ErrEmailNotifyDisabled
+func (s *EmailService) SendNotificationTemplateEmail(to, template_name, lang string, vars map[string]any) error {
+	return s.sendTemplateEmail(to, template_name, lang, vars, true)
+}
+
+func (s *EmailService) sendTemplateEmail(to, template_name, lang string, vars map[string]any, notification bool) error {
+	msg, body, err := s.buildTemplateMessage(to, template_name, lang, vars)
+	if err != nil {
+		return err
+	}
+	return s.deliver(msg, template_name, body, notification, 0)
 }
 
-// SendTemplateEmail 发送模板邮件
-func (s *EmailService) SendTemplateEmail(to, template_name, lang string, vars map[string]string) error {
+// buildTemplateMessage 渲染模板邮件，返回邮件与用于日志的正文
+func (s *EmailService) buildTemplateMessage(to, template_name, lang string, vars map[string]any) (*mailer.Message, string, error) {
 	// 获取模板
 	tpl, err := models.GetEmailTemplate(template_name, lang)
 	if err != nil {
-		return fmt.Errorf("模板不存在: %s (%s)", template_name, lang)
+		return nil, "", fmt.Errorf("模板不存在: %s (%s)", template_name, lang)
 	}
 
-	// 合并默认变量
-	all_vars := s.buildDefaultVars(vars)
-
-	// 渲染主题
-	subject := s.renderTemplate(tpl.Subject, all_vars)
-
-	// 渲染内容
-	content := s.renderTemplate(tpl.Content, all_vars)
-
-	// 包装 HTML 布局
-	htmlBody := s.WrapHTMLLayout(subject, content)
+	// 渲染主题与内容并套用布局
+	rendered, err := s.RenderTemplate(tpl, vars)
+	if err != nil {
+		return nil, "", fmt.Errorf("模板渲染失败: %s (%s): %w", template_name, lang, err)
+	}
 
-	// 发送邮件
-	msg := utils.EmailMessage{
-		To:      to,
-		Subject: subject,
-		Body:    htmlBody,
+	// HTML 与纯文本两个版本
+	msg := &mailer.Message{
+		To:      []string{to},
+		Subject: rendered.Subject,
+		HTML:    rendered.HTML,
+		Text:    rendered.Text,
 	}
+	return msg, rendered.Body, nil
+}
 
-	send_err := utils.SendEmail(msg)
+// deliver 过滤被抑制的收件人后发送邮件，异步记录日志并发布 EmailSent 事件
+// notification 为 true 时还会过滤关闭了邮件通知的用户；campaign_id 非 0 时日志关联到群发任务
+func (s *EmailService) deliver(msg *mailer.Message, template_name, log_content string, notification bool, campaign_id uint64) error {
+	// 过滤前的收件人，全部被跳过时日志仍能看到原收件人
+	to := strings.Join(msg.To, ", ")
+	err := s.screenRecipients(msg, notification)
+	if err == nil {
+		err = utils.SendMessage(msg)
+	}
 
 	// 记录日志
-	status := 1
+	status := models.EmailLogSuccess
 	error_msg := ""
-	if send_err != nil {
-		status = 0
-		error_msg = send_err.Error()
+	if err != nil {
+		status = models.EmailLogFailed
+		if IsEmailSkipped(err) {
+			status = models.EmailLogSkipped
+		}
+		error_msg = err.Error()
 	}
 
-	go models.CreateEmailLog(to, subject, content, template_name, status, error_msg)
+	subject := msg.Subject
+	lifecycle.Go("email-log", func(context.Context) {
+		models.CreateCampaignEmailLog(campaign_id, to, subject, log_content, template_name, status, error_msg)
+	})
+	events.EmailSent.Emit(context.Background(), events.EmailSentEvent{
+		To: to, Subject: subject, Template: template_name, Success: err == nil, Error: error_msg,
+	})
 
-	return send_err
+	return err
+}
+
+// screenRecipients 从收件人、抄送、密送中移除被抑制（及关闭通知）的地址，全部被移除时返回对应错误
+// 查询失败时不拦截，照常发送
+func (s *EmailService) screenRecipients(msg *mailer.Message, notification bool) error {
+	rcpts, err := msg.Recipients()
+	if err != nil {
+		return err
+	}
+
+	blocked := map[string]error{}
+	suppressed, err := models.GetSuppressedEmails(rcpts)
+	if err != nil {
+		log.Printf("[Email] 查询抑制列表失败: %v", err)
+	}
+	for _, email := range suppressed {
+		blocked[email] = ErrEmailSuppressed
+	}
+	if notification {
+		disabled, err := models.GetEmailNotifyDisabledEmails(rcpts)
+		if err != nil {
+			log.Printf("[Email] 查询邮件通知设置失败: %v", err)
+		}
+		for _, email := range disabled {
+			if blocked[email] == nil {
+				blocked[email] = ErrEmailNotifyDisabled
+			}
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+
+	var reason error
+	var skipped []string
+	filter := func(list []string) []string {
+		var kept []string
+		for _, item := range list {
+			addr, err := mail.ParseAddress(item)
+			if err == nil {
+				if e := blocked[models.NormalizeSuppressionEmail(addr.Address)]; e != nil {
+					reason = e
+					skipped = append(skipped, addr.Address)
+					continue
+				}
+			}
+			kept = append(kept, item)
+		}
+		return kept
+	}
+	msg.To, msg.Cc, msg.Bcc = filter(msg.To), filter(msg.Cc), filter(msg.Bcc)
+
+	if _, err := msg.Recipients(); errors.Is(err, mailer.ErrNoRecipients) {
+		return fmt.Errorf("%w: %s", reason, strings.Join(skipped, ", "))
+	}
+	log.Printf("[Email] 已跳过收件人: %s", strings.Join(skipped, ", "))
+	return nil
 }
 
 // SendVerificationCode 发送验证码邮件
@@ -95,9 +232,9 @@ func (s *EmailService) SendVerificationCode(to, code, lang string, expire_minute
 		lang = "zh-CN"
 	}
 
-	vars := map[string]string{
-		"code":            code,
-		"expire_minutes":  fmt.Sprintf("%d", expire_minutes),
+	vars := map[string]any{
+		"code":           code,
+		"expire_minutes": expire_minutes,
 	}
 
 	return s.SendTemplateEmail(to, "register_code", lang, vars)
@@ -110,7 +247,7 @@ func (s *EmailService) SendPasswordReset(to, link, code, lang string) error {
 		lang = "zh-CN"
 	}
 
-	vars := map[string]string{
+	vars := map[string]any{
 		"link": link,
 		"code": code,
 	}
@@ -118,39 +255,41 @@ func (s *EmailService) SendPasswordReset(to, link, code, lang string) error {
 	return s.SendTemplateEmail(to, "reset_password", lang, vars)
 }
 
-// SendEmailAsync 异步发送邮件
-func (s *EmailService) SendEmailAsync(to, subject, body string, callback func(SendResult)) {
-	go func() {
-		err := s.SendEmail(to, subject, body)
-		if callback != nil {
-			callback(SendResult{
-				Success: err == nil,
-				Error:   err,
-			})
-		}
-	}()
+// SendEmailAsync 将邮件写入发送队列，失败时由队列按退避策略重试
+func (s *EmailService) SendEmailAsync(to, subject, body string) error {
+	_, err := queue.Enqueue(EmailSendJob, EmailSendPayload{To: to, Subject: subject, Body: body})
+	return err
 }
 
-// SendTemplateEmailAsync 异步发送模板邮件
-func (s *EmailService) SendTemplateEmailAsync(to, template_name, lang string, vars map[string]string, callback func(SendResult)) {
-	go func() {
-		err := s.SendTemplateEmail(to, template_name, lang, vars)
-		if callback != nil {
-			callback(SendResult{
-				Success: err == nil,
-				Error:   err,
-			})
-		}
-	}()
+// SendTemplateEmailAsync 将模板邮件写入发送队列
+func (s *EmailService) SendTemplateEmailAsync(to, template_name, lang string, vars map[string]any) error {
+	_, err := queue.Enqueue(EmailSendTemplateJob, EmailTemplatePayload{To: to, Template: template_name, Lang: lang, Vars: vars})
+	return err
+}
+
+// SendNotificationEmailAsync 将非事务邮件写入发送队列
+func (s *EmailService) SendNotificationEmailAsync(to, subject, body string) error {
+	_, err := queue.Enqueue(EmailSendJob, EmailSendPayload{To: to, Subject: subject, Body: body, Notification: true})
+	return err
 }
 
+// SendNotificationTemplateEmailAsync 将非事务模板邮件写入发送队列
+func (s *EmailService) SendNotificationTemplateEmailAsync(to, template_name, lang string, vars map[string]any) error {
+	_, err := queue.Enqueue(EmailSendTemplateJob, EmailTemplatePayload{To: to, Template: template_name, Lang: lang, Vars: vars, Notification: true})
+	return err
+}
+
+// defaultVarNames 所有模板都可使用的默认变量，无需在 variables 中声明
+var defaultVarNames = []string{"app_name", "app_url", "year"}
+
 // buildDefaultVars 构建默认变量
-func (s *EmailService) buildDefaultVars(vars map[string]string) map[string]string {
-	cfg := config.GlobalConfig
+func (s *EmailService) buildDefaultVars(vars map[string]any) map[string]any {
+	cfg := config.Get()
 
-	result := map[string]string{
+	result := map[string]any{
 		"app_name": cfg.AppName,
 		"app_url":  "", // 可扩展
+		"year":     time.Now().Year(),
 	}
 
 	// 合并传入的变量
@@ -161,98 +300,38 @@ func (s *EmailService) buildDefaultVars(vars map[string]string) map[string]strin
 	return result
 }
 
-// renderTemplate 渲染模板
-func (s *EmailService) renderTemplate(template string, vars map[string]string) string {
-	result := template
-	for k, v := range vars {
-		placeholder := fmt.Sprintf("{%s}", k)
-		result = strings.ReplaceAll(result, placeholder, v)
-	}
-	return result
+// RenderTemplate 渲染模板（自动转义变量并套用默认布局），vars 会与默认变量合并
+func (s *EmailService) RenderTemplate(tpl *models.EmailTemplate, vars map[string]any) (*mailtpl.Message, error) {
+	return mailtpl.Default.Render(mailtpl.Source{Subject: tpl.Subject, Content: tpl.Content}, s.buildDefaultVars(vars))
 }
 
-// WrapHTMLLayout 将邮件内容包装在精美的 HTML 布局中
-func (s *EmailService) WrapHTMLLayout(subject, content string) string {
-	cfg := config.GlobalConfig
-	appName := cfg.AppName
-	if appName == "" {
-		appName = "System"
-	}
-
-	year := fmt.Sprintf("%d", time.Now().Year())
-
-	return `<!DOCTYPE html>
-<html lang="zh">
-<head>
-<meta charset="UTF-8">
-<meta name="viewport" content="width=device-width, initial-scale=1.0">
-<title>` + subject + `</title>
-</head>
-<body style="margin:0;padding:0;background-color:#f0f2f5;font-family:'Segoe UI','PingFang SC','Microsoft YaHei',sans-serif;">
-<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="background-color:#f0f2f5;padding:40px 0;">
-  <tr>
-    <td align="center">
-      <!-- Main Card -->
-      <table role="presentation" width="600" cellpadding="0" cellspacing="0" style="max-width:600px;width:100%;background-color:#ffffff;border-radius:16px;overflow:hidden;box-shadow:0 4px 24px rgba(0,0,0,0.08);">
-        <!-- Header -->
-        <tr>
-          <td style="background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);padding:36px 40px;text-align:center;">
-            <h1 style="margin:0;font-size:26px;font-weight:700;color:#ffffff;letter-spacing:1px;">` + appName + `</h1>
-          </td>
-        </tr>
-        <!-- Subject -->
-        <tr>
-          <td style="padding:32px 40px 0 40px;">
-            <h2 style="margin:0 0 8px 0;font-size:20px;font-weight:600;color:#1a1a2e;">` + subject + `</h2>
-            <div style="width:48px;height:3px;background:linear-gradient(90deg,#667eea,#764ba2);border-radius:2px;"></div>
-          </td>
-        </tr>
-        <!-- Content -->
-        <tr>
-          <td style="padding:24px 40px 36px 40px;">
-            <div style="font-size:15px;line-height:1.8;color:#4a4a68;">` + content + `</div>
-          </td>
-        </tr>
-        <!-- Divider -->
-        <tr>
-          <td style="padding:0 40px;">
-            <div style="border-top:1px solid #e8e8f0;"></div>
-          </td>
-        </tr>
-        <!-- Footer -->
-        <tr>
-          <td style="padding:24px 40px 32px 40px;text-align:center;">
-            <p style="margin:0 0 4px 0;font-size:12px;color:#a0a0b8;">此邮件由系统自动发送，请勿直接回复</p>
-            <p style="margin:0;font-size:12px;color:#a0a0b8;">&copy; ` + year + ` ` + appName + ` · All rights reserved</p>
-          </td>
-        </tr>
-      </table>
-    </td>
-  </tr>
-</table>
-</body>
-</html>`
+// ValidateTemplate 校验模板能否渲染，且只引用了 variables 中声明的变量与默认变量
+func (s *EmailService) ValidateTemplate(tpl *models.EmailTemplate) error {
+	declared := append(mailtpl.ParseVariables(tpl.Variables), defaultVarNames...)
+	return mailtpl.Default.Validate(mailtpl.Source{Subject: tpl.Subject, Content: tpl.Content}, declared)
 }
 
-// BatchSendEmail 批量发送邮件
+// BatchSendEmail 批量发送邮件（非事务邮件），每个收件人一个队列任务，返回入队失败的收件人
 func (s *EmailService) BatchSendEmail(recipients []string, subject, body string) map[string]error {
 	results := make(map[string]error)
 
 	for _, to := range recipients {
-		err := s.SendEmail(to, subject, body)
-		results[to] = err
+		if err := s.SendNotificationEmailAsync(to, subject, body); err != nil {
+			results[to] = err
+		}
 	}
 
 	return results
 }
 
-// BatchSendTemplateEmail 批量发送模板邮件
-func (s *EmailService) BatchSendTemplateEmail(recipients []string, template_name, lang string, vars map[string]string) map[string]error {
+// BatchSendTemplateEmail 批量发送模板邮件（非事务邮件），每个收件人一个队列任务，返回入队失败的收件人
+func (s *EmailService) BatchSendTemplateEmail(recipients []string, template_name, lang string, vars map[string]any) map[string]error {
 	results := make(map[string]error)
 
 	for _, to := range recipients {
-		err := s.SendTemplateEmail(to, template_name, lang, vars)
-		results[to] = err
+		if err := s.SendNotificationTemplateEmailAsync(to, template_name, lang, vars); err != nil {
+			results[to] = err
+		}
 	}
 
 	return results
@@ -273,14 +352,9 @@ func (s *EmailService) CreateTemplate(tpl *models.EmailTemplate) error {
 	return models.CreateEmailTemplate(tpl)
 }
 
-// UpdateTemplateContent 更新模板内容
-func (s *EmailService) UpdateTemplateContent(name, lang, content string) error {
-	return models.UpdateEmailTemplateContent(name, lang, content)
-}
-
 // ValidateEmailConfig 验证邮件配置
 func (s *EmailService) ValidateEmailConfig() error {
-	cfg := config.GlobalConfig
+	cfg := config.Get()
 
 	if cfg.SMTPHost == "" {
 		return fmt.Errorf("SMTP主机未配置")
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fst/backend/app/models"
+	"fst/backend/internal/events"
+	"fst/backend/internal/mailer"
+	"log"
+)
+
+// ErrEmailFeedbackInvalid 回调内容无法解析
+var ErrEmailFeedbackInvalid = errors.New("退信回调内容无效")
+
+// HandleEmailFeedback 解析服务商的退信 / 投诉回调并更新抑制列表，返回处理的事件数
+func HandleEmailFeedback(provider string, body []byte) (int, error) {
+	list, err := mailer.ParseFeedback(provider, body)
+	if errors.Is(err, mailer.ErrUnknownFeedbackProvider) {
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrEmailFeedbackInvalid, err)
+	}
+	for _, f := range list {
+		s, err := models.RecordEmailFeedback(f.Email, f.Type, provider, f.Reason)
+		if err != nil {
+			return 0, err
+		}
+		log.Printf("[Email] 收到 %s 回调: %s %s，抑制=%v", provider, f.Type, f.Email, s.Suppressed)
+		events.EmailFeedback.Emit(context.Background(), events.EmailFeedbackEvent{
+			Email: s.Email, Type: f.Type, Provider: provider, Reason: f.Reason, Suppressed: s.Suppressed,
+		})
+	}
+	return len(list), nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"fst/backend/app/models"
+	"strings"
+)
+
+// ========================================
+// 邮件模板版本：每次修改（编辑、重置、回滚）都会新增一个版本并记录修改人
+// ========================================
+
+// ErrTemplateUnchanged 提交的内容与当前模板一致
+var ErrTemplateUnchanged = errors.New("模板内容没有变化")
+
+// ErrTemplateInvalid 模板无法解析或引用了未声明的变量
+var ErrTemplateInvalid = errors.New("模板无效")
+
+// TemplateAuthor 模板修改人
+type TemplateAuthor struct {
+	ID   uint64
+	Name string
+}
+
+// TemplateUpdate 模板可编辑字段
+type TemplateUpdate struct {
+	Subject     string
+	Content     string
+	Description string
+	Status      uint8
+}
+
+// UpdateTemplate 校验并修改模板，新增版本
+func (s *EmailService) UpdateTemplate(id uint64, update TemplateUpdate, author TemplateAuthor) (*models.EmailTemplateRevision, error) {
+	return s.saveTemplate(id, models.EmailRevisionEdit, 0, author, func(tpl *models.EmailTemplate) (TemplateUpdate, error) {
+		candidate := *tpl
+		candidate.Subject, candidate.Content = update.Subject, update.Content
+		if err := s.ValidateTemplate(&candidate); err != nil {
+			return TemplateUpdate{}, fmt.Errorf("%w: %v", ErrTemplateInvalid, err)
+		}
+		return update, nil
+	})
+}
+
+// ResetTemplate 将模板恢复为内置默认内容并新增版本
+func (s *EmailService) ResetTemplate(id uint64, author TemplateAuthor) (*models.EmailTemplateRevision, error) {
+	return s.saveTemplate(id, models.EmailRevisionReset, 0, author, func(tpl *models.EmailTemplate) (TemplateUpdate, error) {
+		builtin := models.GetBuiltinEmailTemplate(tpl.Name, tpl.Lang)
+		if builtin == nil {
+			return TemplateUpdate{}, fmt.Errorf("模板 %s (%s) 没有内置默认内容", tpl.Name, tpl.Lang)
+		}
+		return TemplateUpdate{Subject: builtin.Subject, Content: builtin.Content, Description: tpl.Description, Status: tpl.Status}, nil
+	})
+}
+
+// RollbackTemplate 将模板恢复为指定版本的内容，作为新版本保存（历史版本不会被删除）
+func (s *EmailService) RollbackTemplate(id uint64, version int, author TemplateAuthor) (*models.EmailTemplateRevision, error) {
+	target, err := models.GetEmailTemplateRevision(id, version)
+	if err != nil {
+		return nil, fmt.Errorf("版本 %d 不存在", version)
+	}
+	return s.saveTemplate(id, models.EmailRevisionRollback, version, author, func(*models.EmailTemplate) (TemplateUpdate, error) {
+		return TemplateUpdate{Subject: target.Subject, Content: target.Content, Description: target.Description, Status: target.Status}, nil
+	})
+}
+
+// saveTemplate 按 build 生成的新内容更新模板；模板没有版本时先记录修改前的内容
+func (s *EmailService) saveTemplate(id uint64, source string, sourceRef int, author TemplateAuthor,
+	build func(tpl *models.EmailTemplate) (TemplateUpdate, error)) (*models.EmailTemplateRevision, error) {
+	tpl, err := models.GetEmailTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+	update, err := build(tpl)
+	if err != nil {
+		return nil, err
+	}
+	if tpl.Subject == update.Subject && tpl.Content == update.Content &&
+		tpl.Description == update.Description && tpl.Status == update.Status {
+		return nil, ErrTemplateUnchanged
+	}
+
+	if err := models.EnsureEmailTemplateBaseline(tpl); err != nil {
+		return nil, err
+	}
+	if err := models.UpdateEmailTemplate(id, update.Subject, update.Content, update.Description, update.Status); err != nil {
+		return nil, err
+	}
+	tpl.Subject, tpl.Content, tpl.Description, tpl.Status = update.Subject, update.Content, update.Description, update.Status
+
+	rev := models.NewEmailTemplateRevision(tpl, source, author.ID, author.Name)
+	rev.SourceRef = sourceRef
+	return models.CreateEmailTemplateRevision(rev)
+}
+
+// TemplateRevisions 模板的版本列表，新版本在前
+func (s *EmailService) TemplateRevisions(id uint64) ([]models.EmailTemplateRevision, error) {
+	return models.GetEmailTemplateRevisions(id)
+}
+
+// TemplateRevision 模板的指定版本
+func (s *EmailService) TemplateRevision(id uint64, version int) (*models.EmailTemplateRevision, error) {
+	return models.GetEmailTemplateRevision(id, version)
+}
+
+// TemplateDiff 两个版本之间的差异
+type TemplateDiff struct {
+	From    int        `json:"from"`
+	To      int        `json:"to"` // 0 表示模板当前内容
+	Subject []DiffLine `json:"subject"`
+	Content []DiffLine `json:"content"`
+}
+
+// DiffLine 差异行
+type DiffLine struct {
+	Op   string `json:"op"` // equal / add / remove
+	Text string `json:"text"`
+}
+
+// DiffTemplate 对比模板的两个版本，to 为 0 时与模板当前内容对比
+func (s *EmailService) DiffTemplate(id uint64, from, to int) (*TemplateDiff, error) {
+	old, err := models.GetEmailTemplateRevision(id, from)
+	if err != nil {
+		return nil, fmt.Errorf("版本 %d 不存在", from)
+	}
+
+	var subject, content string
+	if to == 0 {
+		tpl, err := models.GetEmailTemplateByID(id)
+		if err != nil {
+			return nil, err
+		}
+		subject, content = tpl.Subject, tpl.Content
+	} else {
+		rev, err := models.GetEmailTemplateRevision(id, to)
+		if err != nil {
+			return nil, fmt.Errorf("版本 %d 不存在", to)
+		}
+		subject, content = rev.Subject, rev.Content
+	}
+
+	return &TemplateDiff{
+		From:    from,
+		To:      to,
+		Subject: diffLines(splitTemplateLines(old.Subject), splitTemplateLines(subject)),
+		Content: diffLines(splitTemplateLines(old.Content), splitTemplateLines(content)),
+	}, nil
+}
+
+// splitTemplateLines 按行切分模板；内置模板的 HTML 写在一行中，在相邻标签之间断开，便于逐段对比
+func splitTemplateLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "><", ">\n<")
+	return strings.Split(text, "\n")
+}
+
+// diffLines 基于最长公共子序列的逐行对比
+func diffLines(a, b []string) []DiffLine {
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]DiffLine, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: "equal", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: "remove", Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: "add", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: "remove", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: "add", Text: b[j]})
+	}
+	return lines
+}
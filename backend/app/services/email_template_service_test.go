@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fst/backend/app/models"
+	"fst/backend/internal/config"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDiffLines 测试模板逐行对比
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []DiffLine
+	}{
+		{"相同", "<p>a</p>", "<p>a</p>", []DiffLine{{"equal", "<p>a</p>"}}},
+		{"新增", "", "<p>a</p>", []DiffLine{{"add", "<p>a</p>"}}},
+		{"删除", "<p>a</p>", "", []DiffLine{{"remove", "<p>a</p>"}}},
+		{
+			"相邻标签断行后对比",
+			`<p>hello</p><div>{code}</div><p>bye</p>`,
+			`<p>hello</p><div>{{.code}}</div><p>bye</p>`,
+			[]DiffLine{
+				{"equal", "<p>hello</p>"},
+				{"remove", "<div>{code}</div>"},
+				{"add", "<div>{{.code}}</div>"},
+				{"equal", "<p>bye</p>"},
+			},
+		},
+		{
+			"多行文本",
+			"a\nb\nc\nd",
+			"a\nc\nd\ne",
+			[]DiffLine{{"equal", "a"}, {"remove", "b"}, {"equal", "c"}, {"equal", "d"}, {"add", "e"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffLines(splitTemplateLines(tt.a), splitTemplateLines(tt.b))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBuiltinTemplatesRender 测试内置模板通过保存校验，且变量被转义
+func TestBuiltinTemplatesRender(t *testing.T) {
+	old := config.Get()
+	config.Set(&config.Config{AppName: "Demo"})
+	defer config.Set(old)
+
+	svc := NewEmailService()
+	for _, key := range [][2]string{{"register_code", "zh-CN"}, {"register_code", "en-US"}, {"reset_password", "zh-CN"}, {"reset_password", "en-US"}} {
+		tpl := models.GetBuiltinEmailTemplate(key[0], key[1])
+		if tpl == nil {
+			t.Fatalf("missing builtin %v", key)
+		}
+		if err := svc.ValidateTemplate(tpl); err != nil {
+			t.Errorf("%v: %v", key, err)
+		}
+		msg, err := svc.RenderTemplate(tpl, map[string]any{"code": "<b>1</b>", "link": "https://example.com/r?a=1&b=2", "expire_minutes": 5})
+		if err != nil {
+			t.Fatalf("%v: %v", key, err)
+		}
+		if strings.Contains(msg.HTML, "<b>1</b>") || !strings.Contains(msg.HTML, "&lt;b&gt;1&lt;/b&gt;") {
+			t.Errorf("%v: code not escaped", key)
+		}
+		if !strings.Contains(msg.Text, "<b>1</b>") {
+			t.Errorf("%v: text = %s", key, msg.Text)
+		}
+	}
+
+	tpl := &models.EmailTemplate{Subject: "hi", Content: "{{.nickname}}", Variables: "code"}
+	if err := svc.ValidateTemplate(tpl); err == nil || !strings.Contains(err.Error(), "nickname") {
+		t.Errorf("undeclared variable accepted: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fst/backend/app/models"
+	"fst/backend/utils"
+	"log"
+	"strings"
+	"time"
+)
+
+// NotificationRetentionDays 已读通知保留天数
+const NotificationRetentionDays = 90
+
+// ErrNotificationEmpty 广播的标题或内容为空
+var ErrNotificationEmpty = errors.New("通知标题和内容不能为空")
+
+// Notify 给用户发送一条站内通知；title 与 content 为多语言文本，如 {"zhCN": "...", "enUS": "..."}，
+// 与余额日志备注一样通过 utils.BuildMemo 存储
+func Notify(user_id uint64, typ string, title, content map[string]string, link string) error {
+	n := &models.Notification{
+		UserID:  user_id,
+		Type:    typ,
+		Title:   utils.BuildMemo("", title),
+		Content: utils.BuildMemo("", content),
+		Link:    link,
+	}
+	if err := models.CreateNotification(n); err != nil {
+		log.Printf("[Notification] 创建通知失败: user_id=%d, type=%s, err=%v", user_id, typ, err)
+		return err
+	}
+	PushToUser(user_id, RealtimeNotification, n)
+	return nil
+}
+
+// NotificationBroadcast 管理员广播参数，单语言与多语言二选一（多语言优先）
+type NotificationBroadcast struct {
+	Title       string            `json:"title"`
+	TitleI18n   map[string]string `json:"title_i18n"` // 如 {"zhCN": "系统维护", "enUS": "Maintenance"}
+	Content     string            `json:"content"`
+	ContentI18n map[string]string `json:"content_i18n"`
+	Link        string            `json:"link" binding:"max=255"`
+	UserIDs     []uint64          `json:"user_ids"` // 为空时发送给全部用户
+}
+
+// BroadcastNotification 向指定用户或全部用户发送系统通知，返回发送人数
+func BroadcastNotification(req *NotificationBroadcast) (int64, error) {
+	title := utils.BuildMemo(strings.TrimSpace(utils.Clean_XSS(req.Title)), cleanI18n(req.TitleI18n))
+	content := utils.BuildMemo(strings.TrimSpace(utils.Clean_XSS(req.Content)), cleanI18n(req.ContentI18n))
+	if title == "" || content == "" {
+		return 0, ErrNotificationEmpty
+	}
+	link := strings.TrimSpace(utils.Clean_XSS(req.Link))
+	count, err := models.BroadcastNotification(req.UserIDs, models.NotificationSystem, title, content, link)
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("[Notification] 已广播系统通知，共 %d 人", count)
+
+	// 广播通知不逐条推送内容，客户端收到后刷新列表与未读数
+	data := map[string]string{"type": models.NotificationSystem}
+	if len(req.UserIDs) == 0 {
+		PushBroadcast(RealtimeNotification, data)
+	}
+	for _, user_id := range req.UserIDs {
+		PushToUser(user_id, RealtimeNotification, data)
+	}
+	return count, nil
+}
+
+// cleanI18n 过滤多语言文本中的 XSS 与空值
+func cleanI18n(texts map[string]string) map[string]string {
+	result := make(map[string]string, len(texts))
+	for lang, text := range texts {
+		if text = strings.TrimSpace(utils.Clean_XSS(text)); text != "" {
+			result[lang] = text
+		}
+	}
+	return result
+}
+
+// ========================================
+// 系统事件通知
+// ========================================
+
+// notifyRechargeCredited 充值到账
+func notifyRechargeCredited(order *models.PaymentOrder, after_money float64) {
+	Notify(order.UserID, models.NotificationRecharge, map[string]string{
+		"zhCN": "充值到账",
+		"enUS": "Recharge credited",
+	}, map[string]string{
+		"zhCN": fmt.Sprintf("订单 %s 充值的 %.2f 已到账，当前余额 %.2f。", order.OrderNo, order.Amount, after_money),
+		"enUS": fmt.Sprintf("Your recharge of %.2f (order %s) has been credited. Current balance: %.2f.", order.Amount, order.OrderNo, after_money),
+	}, "")
+}
+
+// NotifyAdminBalanceChange 管理员调整了用户余额
+func NotifyAdminBalanceChange(user_id uint64, amount, after_money float64, memo string) {
+	zh := fmt.Sprintf("管理员调整了您的余额：%+.2f，当前余额 %.2f。", amount, after_money)
+	en := fmt.Sprintf("An administrator adjusted your balance by %+.2f. Current balance: %.2f.", amount, after_money)
+	if memo != "" {
+		zh += "备注：" + memo
+		en += " Note: " + memo
+	}
+	Notify(user_id, models.NotificationBalance, map[string]string{
+		"zhCN": "余额变动",
+		"enUS": "Balance adjusted",
+	}, map[string]string{"zhCN": zh, "enUS": en}, "")
+}
+
+// RecordLoginDevice 记录登录设备，在此前已登录过其他设备的账号首次使用该设备时发送新设备登录通知
+func RecordLoginDevice(user_id uint64, device, ip string) {
+	is_new, known, err := models.RecordUserLoginDevice(user_id, device, ip)
+	if err != nil {
+		log.Printf("[Notification] 记录登录设备失败: user_id=%d, err=%v", user_id, err)
+		return
+	}
+	if !is_new || known == 0 {
+		return
+	}
+	at := time.Now().Format("2006-01-02 15:04:05")
+	Notify(user_id, models.NotificationLogin, map[string]string{
+		"zhCN": "新设备登录",
+		"enUS": "New device sign-in",
+	}, map[string]string{
+		"zhCN": fmt.Sprintf("您的账号于 %s 在新设备（%s，IP %s）上登录。如非本人操作，请立即修改密码。", at, device, ip),
+		"enUS": fmt.Sprintf("Your account signed in on a new device (%s, IP %s) at %s. If this wasn't you, change your password immediately.", device, ip, at),
+	}, "")
+}
+
+// notifyPasswordChanged 密码已修改（本人修改、找回密码或管理员重置）
+func notifyPasswordChanged(user_id uint64) {
+	at := time.Now().Format("2006-01-02 15:04:05")
+	Notify(user_id, models.NotificationPassword, map[string]string{
+		"zhCN": "密码已修改",
+		"enUS": "Password changed",
+	}, map[string]string{
+		"zhCN": fmt.Sprintf("您的登录密码已于 %s 修改。如非本人操作，请立即重置密码并联系管理员。", at),
+		"enUS": fmt.Sprintf("Your password was changed at %s. If this wasn't you, reset your password and contact support immediately.", at),
+	}, "")
+}
+
+// pruneNotifications 清理过期的已读通知
+func pruneNotifications(ctx context.Context) error {
+	before := time.Now().AddDate(0, 0, -NotificationRetentionDays).Unix()
+	_, err := models.DeleteReadNotificationsBefore(before)
+	return err
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"fst/backend/utils"
+	"testing"
+)
+
+// TestCleanI18n 测试多语言文本去除空白与空值
+func TestCleanI18n(t *testing.T) {
+	got := cleanI18n(map[string]string{"zhCN": "  系统维护  ", "enUS": "   "})
+	if len(got) != 1 || got["zhCN"] != "系统维护" {
+		t.Errorf("cleanI18n = %v, want map[zhCN:系统维护]", got)
+	}
+	if utils.BuildMemo("", cleanI18n(nil)) != "" {
+		t.Error("空多语言文本应生成空字符串")
+	}
+}
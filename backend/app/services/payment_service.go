@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"fst/backend/app/models"
 	"fst/backend/internal/db"
+	"fst/backend/internal/events"
 	"fst/backend/utils"
 	"log"
 	"strconv"
@@ -83,6 +85,16 @@ func CreatePaymentOrder(userID uint64, req *CreatePaymentOrderRequest, notifyURL
 		return nil, errors.New("您有过多未支付订单，请先支付或等待过期后重试")
 	}
 
+	// 5.1 下单前钩子：插件可拒绝下单
+	if err := events.PaymentBeforeCreate.Publish(context.Background(), events.PaymentOrderRequest{
+		UserID:    userID,
+		GatewayID: gateway.ID,
+		PayType:   gateway.PayType,
+		Amount:    req.Amount,
+	}); err != nil {
+		return nil, errors.New(events.Message(err))
+	}
+
 	// 6. 计算手续费
 	fee, payAmount, creditAmount := CalculateFee(req.Amount, gateway.FeeRate, gateway.FeeMode)
 
@@ -285,6 +297,15 @@ func HandlePaymentNotify(params map[string]string) (bool, error) {
 	log.Printf("[Payment] 充值到账成功: order_no=%s, user_id=%d, amount=%.2f, fee=%.2f, pay_amount=%.2f, before=%.2f, after=%.2f",
 		outTradeNo, order.UserID, order.Amount, order.Fee, order.PayAmount, balanceResult.BeforeMoney, balanceResult.AfterMoney)
 
+	emitMoneyChanged(order.UserID, order.Amount, balanceResult, balanceResult.MoneyLog.Memo)
+	notifyRechargeCredited(order, balanceResult.AfterMoney)
+	events.PaymentPaid.Emit(context.Background(), events.PaymentPaidEvent{
+		OrderNo: outTradeNo,
+		UserID:  order.UserID,
+		Amount:  order.Amount,
+		TradeNo: tradeNo,
+	})
+
 	return true, nil
 }
 
@@ -350,7 +371,7 @@ func AdminCompleteOrder(orderID uint64, memo string) error {
 		memoZh += " (" + memo + ")"
 		memoEn += " (" + memo + ")"
 	}
-	_, err = utils.ExecuteBalanceOpTx(tx, &utils.BalanceReq{
+	balanceResult, err := utils.ExecuteBalanceOpTx(tx, &utils.BalanceReq{
 		UserID: order.UserID,
 		Amount: order.Amount,
 		MemoI18n: map[string]string{
@@ -371,6 +392,16 @@ func AdminCompleteOrder(orderID uint64, memo string) error {
 
 	log.Printf("[Payment] 管理员手动补单成功: order_no=%s, user_id=%d, amount=%.2f",
 		order.OrderNo, order.UserID, order.Amount)
+
+	emitMoneyChanged(order.UserID, order.Amount, balanceResult, balanceResult.MoneyLog.Memo)
+	notifyRechargeCredited(order, balanceResult.AfterMoney)
+	events.PaymentPaid.Emit(context.Background(), events.PaymentPaidEvent{
+		OrderNo: order.OrderNo,
+		UserID:  order.UserID,
+		Amount:  order.Amount,
+		TradeNo: "MANUAL",
+		Manual:  true,
+	})
 	return nil
 }
 
@@ -403,15 +434,15 @@ func AdminCancelOrder(orderID uint64) error {
 }
 
 // CancelExpiredOrders 取消过期未支付订单（定时任务调用）
-func CancelExpiredOrders() {
+func CancelExpiredOrders(ctx context.Context) error {
 	affected, err := models.CancelExpiredOrders()
 	if err != nil {
-		log.Printf("[Payment] 取消过期订单失败: %v", err)
-		return
+		return fmt.Errorf("取消过期订单失败: %w", err)
 	}
 	if affected > 0 {
 		log.Printf("[Payment] 已取消 %d 个过期订单", affected)
 	}
+	return nil
 }
 
 func AdminDeleteOrder(orderID uint64) error {
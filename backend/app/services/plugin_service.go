@@ -0,0 +1,91 @@
+package services
+
+import (
+	"encoding/json"
+	"fst/backend/app/models"
+	"fst/backend/app/plugins"
+	"fst/backend/app/plugins/external"
+	"fst/backend/internal/config"
+	"log"
+)
+
+// GlobalPluginManager 全局插件管理器
+var GlobalPluginManager *plugins.Manager
+
+// InitPlugins 创建插件管理器，注册通过 init() 导入的插件并按持久化的启用状态与配置加载
+// 插件的定时任务（ScheduledTasks）注册到 GlobalScheduler
+func InitPlugins() *plugins.Manager {
+	mgr := plugins.NewManager()
+	mgr.SetStateStore(pluginStateStore{})
+	mgr.SetConfigStore(pluginConfigStore{})
+	if GlobalScheduler != nil {
+		// 插件定时任务注册到全局调度器，需先调用 InitScheduler
+		mgr.SetJobRegistrar(GlobalScheduler)
+	}
+
+	// 插件通过 init() 函数自动注册到全局注册表
+	plugins.AutoRegisterAll(mgr)
+
+	// 进程外插件：PLUGIN_EXTERNAL_DIR 下的独立可执行文件，与内置插件同名时跳过
+	if dir := config.PluginExternalDir.Get(); dir != "" {
+		for _, p := range external.Discover(dir) {
+			if _, exists := mgr.GetPlugin(p.Name()); exists {
+				log.Printf("[Plugin] 进程外插件 %s 与内置插件同名，已跳过", p.Name())
+				continue
+			}
+			mgr.Register(p)
+		}
+	}
+
+	if err := mgr.LoadAll(); err != nil {
+		log.Printf("[Plugin] 插件加载失败: %v", err)
+	}
+
+	GlobalPluginManager = mgr
+	return mgr
+}
+
+// pluginStateStore 基于数据库的插件启用状态存储
+type pluginStateStore struct{}
+
+func (pluginStateStore) LoadDisabled() (map[string]bool, error) {
+	names, err := models.GetDisabledPluginNames()
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		disabled[name] = true
+	}
+	return disabled, nil
+}
+
+func (pluginStateStore) SetEnabled(name string, enabled bool) error {
+	return models.SetPluginEnabled(name, enabled)
+}
+
+// pluginConfigStore 基于数据库的插件配置存储
+type pluginConfigStore struct{}
+
+func (pluginConfigStore) LoadConfig(name string) (map[string]interface{}, error) {
+	raw, err := models.GetPluginConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	if raw == "" {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (pluginConfigStore) SaveConfig(name string, values map[string]interface{}) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return models.SavePluginConfig(name, string(data))
+}
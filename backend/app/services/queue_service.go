@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fst/backend/app/models"
+	"fst/backend/internal/lifecycle"
+	"fst/backend/internal/middleware"
+	"fst/backend/internal/queue"
+	"fst/backend/internal/scheduler"
+	"fst/backend/utils"
+	"log"
+	"time"
+)
+
+// QueueJobRetentionDays 已完成任务保留天数
+const QueueJobRetentionDays = 7
+
+// ErrQueueJobNotDead 仅死信任务可以手动重试
+var ErrQueueJobNotDead = errors.New("only dead jobs can be retried")
+
+// GlobalQueue 全局后台任务队列
+var GlobalQueue *queue.Manager
+
+// InitQueue 初始化任务队列并注册内置任务类型，需在 InitScheduler 之后调用
+func InitQueue() {
+	GlobalQueue = queue.New(queueStore{}, scheduler.DefaultInstanceID())
+	GlobalQueue.DefineQueue("email", queue.QueueConfig{Concurrency: 4})
+	GlobalQueue.DefineQueue("campaign", queue.QueueConfig{Concurrency: 1})
+	GlobalQueue.DefineQueue("oplog", queue.QueueConfig{Concurrency: 2})
+	GlobalQueue.DefineQueue("default", queue.QueueConfig{Concurrency: 2})
+
+	emailService := NewEmailService()
+	handlers := []error{
+		queue.Register(GlobalQueue, EmailSendJob, func(ctx context.Context, p EmailSendPayload) error {
+			return skipUnsendable(emailService.sendEmail(p.To, p.Subject, p.Body, p.Notification))
+		}, queue.HandlerOptions{Queue: "email"}),
+		queue.Register(GlobalQueue, EmailSendTemplateJob, func(ctx context.Context, p EmailTemplatePayload) error {
+			return skipUnsendable(emailService.sendTemplateEmail(p.To, p.Template, p.Lang, p.Vars, p.Notification))
+		}, queue.HandlerOptions{Queue: "email"}),
+		// 每批最多 100 封，串行发送，放宽单次超时
+		queue.Register(GlobalQueue, EmailCampaignJob, emailService.runCampaign,
+			queue.HandlerOptions{Queue: "campaign", Timeout: 10 * time.Minute}),
+		middleware.RegisterOperationLogJob(GlobalQueue),
+	}
+	for _, err := range handlers {
+		if err != nil {
+			log.Printf("[Queue] %v", err)
+		}
+	}
+	queue.SetDefault(GlobalQueue)
+
+	if err := RegisterJob(scheduler.Job{
+		Name:        "prune_queue_jobs",
+		Description: "清理已完成的队列任务",
+		Spec:        "30 4 * * *",
+		Run:         pruneQueueJobs,
+	}); err != nil {
+		log.Printf("[Queue] %v", err)
+	}
+
+	// 先于队列注册，关闭时在队列停止、邮件任务结束后再释放 SMTP 连接
+	lifecycle.Append(lifecycle.Hook{Name: "mail-transport", Stop: utils.CloseMailTransport})
+	lifecycle.Append(lifecycle.Hook{
+		Name:  "queue",
+		Start: GlobalQueue.Start,
+		Stop:  GlobalQueue.Stop,
+	})
+	log.Println("[Queue] Initialized")
+}
+
+// skipUnsendable 收件人被抑制或关闭通知的邮件任务视为完成，不再重试
+func skipUnsendable(err error) error {
+	if IsEmailSkipped(err) {
+		return nil
+	}
+	return err
+}
+
+// RetryQueueJob 重试死信任务
+func RetryQueueJob(id uint64) error {
+	job, err := models.GetQueueJobByID(id)
+	if err != nil {
+		return err
+	}
+	ok, err := models.RetryQueueJob(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrQueueJobNotDead
+	}
+	if GlobalQueue != nil {
+		GlobalQueue.Wake(job.Queue)
+	}
+	return nil
+}
+
+func pruneQueueJobs(ctx context.Context) error {
+	before := time.Now().AddDate(0, 0, -QueueJobRetentionDays).Unix()
+	_, err := models.DeleteDoneQueueJobsBefore(before)
+	return err
+}
+
+// queueStore 基于数据库的队列存储
+type queueStore struct{}
+
+func (queueStore) Enqueue(job *queue.Job) error {
+	row := &models.QueueJob{
+		Queue:       job.Queue,
+		JobType:     job.Type,
+		Payload:     string(job.Payload),
+		Status:      job.Status,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt.Unix(),
+	}
+	if err := models.CreateQueueJob(row); err != nil {
+		return err
+	}
+	job.ID = row.ID
+	return nil
+}
+
+func (queueStore) Claim(name, owner string, now, lockUntil time.Time) (*queue.Job, error) {
+	row, err := models.ClaimQueueJob(name, owner, now.Unix(), lockUntil.Unix())
+	if err != nil || row == nil {
+		return nil, err
+	}
+	return &queue.Job{
+		ID:          row.ID,
+		Queue:       row.Queue,
+		Type:        row.JobType,
+		Payload:     []byte(row.Payload),
+		Status:      row.Status,
+		Attempts:    row.Attempts,
+		MaxAttempts: row.MaxAttempts,
+		LastError:   row.LastError,
+		RunAt:       time.Unix(row.RunAt, 0),
+		LockedBy:    row.LockedBy,
+	}, nil
+}
+
+func (queueStore) Complete(job *queue.Job) error {
+	return finishQueueJob(job, queue.StatusDone, 0, "")
+}
+
+func (queueStore) Retry(job *queue.Job, runAt time.Time, errMsg string) error {
+	return finishQueueJob(job, queue.StatusPending, runAt.Unix(), errMsg)
+}
+
+func (queueStore) Bury(job *queue.Job, errMsg string) error {
+	return finishQueueJob(job, queue.StatusDead, 0, errMsg)
+}
+
+// finishQueueJob 写回执行结果，任务已被其他实例重新领取时返回 queue.ErrLockLost
+func finishQueueJob(job *queue.Job, status string, runAt int64, errMsg string) error {
+	ok, err := models.FinishQueueJob(job.ID, job.LockedBy, status, runAt, errMsg)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return queue.ErrLockLost
+	}
+	return nil
+}
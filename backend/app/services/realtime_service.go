@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fst/backend/app/models"
+	"fst/backend/internal/config"
+	"fst/backend/internal/events"
+	"fst/backend/internal/lifecycle"
+	"fst/backend/internal/realtime"
+	"fst/backend/internal/scheduler"
+	"log"
+	"strings"
+	"time"
+)
+
+// 推送给客户端的事件
+const (
+	RealtimeReady           = "ready"            // 连接建立
+	RealtimePaymentPaid     = "payment.paid"     // 订单已支付
+	RealtimeSessionRevoked  = "session.revoked"  // 连接所用的会话已失效（被踢出、其他设备登录或令牌已刷新）
+	RealtimeNotification    = "notification.new" // 新的站内通知
+	RealtimeSettingsUpdated = "settings.updated" // 系统配置已更新
+)
+
+// RealtimeMessageRetention db 中转的消息保留时长
+const RealtimeMessageRetention = time.Hour
+
+// GlobalRealtime 全局实时推送中心
+var GlobalRealtime *realtime.Hub
+
+// InitRealtime 初始化实时推送并订阅核心事件，需在 InitScheduler 之后调用
+func InitRealtime() {
+	var broker realtime.Broker
+	switch kind := strings.ToLower(strings.TrimSpace(config.RealtimeBroker.Get())); kind {
+	case "db":
+		broker = realtime.NewPollingBroker(realtimeStore{}, time.Second)
+		if err := RegisterJob(scheduler.Job{
+			Name:        "prune_realtime_messages",
+			Description: "清理过期的实时推送消息",
+			Spec:        "*/10 * * * *",
+			Run:         pruneRealtimeMessages,
+		}); err != nil {
+			log.Printf("[Realtime] %v", err)
+		}
+	case "", "local":
+		broker = realtime.NewLocalBroker(0)
+	default:
+		log.Printf("[Realtime] 未知的 REALTIME_BROKER=%s，使用 local", kind)
+		broker = realtime.NewLocalBroker(0)
+	}
+	GlobalRealtime = realtime.NewHub(broker)
+
+	events.PaymentPaid.SubscribeAsync(func(ctx context.Context, e events.PaymentPaidEvent) error {
+		PushToUser(e.UserID, RealtimePaymentPaid, map[string]any{
+			"order_no": e.OrderNo,
+			"amount":   e.Amount,
+			"manual":   e.Manual,
+		})
+		return nil
+	})
+	events.SettingsUpdated.SubscribeAsync(func(ctx context.Context, e events.SettingsUpdatedEvent) error {
+		PushBroadcast(RealtimeSettingsUpdated, map[string]any{"keys": e.Keys})
+		return nil
+	})
+
+	lifecycle.Append(lifecycle.Hook{
+		Name:  "realtime",
+		Start: GlobalRealtime.Start,
+		Stop:  GlobalRealtime.Stop,
+	})
+	log.Println("[Realtime] Initialized")
+}
+
+// SubscribeRealtime 订阅用户私有主题与全体广播
+func SubscribeRealtime(user_id uint64) (*realtime.Subscriber, error) {
+	if GlobalRealtime == nil {
+		return nil, realtime.ErrHubClosed
+	}
+	return GlobalRealtime.Subscribe(realtime.UserTopic(user_id), realtime.TopicBroadcast)
+}
+
+// CloseRealtime 断开所有实时推送连接，在 HTTP 服务关闭时调用，避免长连接阻塞关闭
+func CloseRealtime() {
+	if GlobalRealtime != nil {
+		GlobalRealtime.Close()
+	}
+}
+
+// PushToUser 向用户的所有在线连接推送事件
+func PushToUser(user_id uint64, event string, data any) {
+	publishRealtime(realtime.UserTopic(user_id), event, data)
+}
+
+// PushBroadcast 向全部在线连接推送事件
+func PushBroadcast(event string, data any) {
+	publishRealtime(realtime.TopicBroadcast, event, data)
+}
+
+// PushSessionRevoked 通知用户在该 guard 下的连接检查会话；会话已失效的连接收到 session.revoked 后断开
+func PushSessionRevoked(user_id uint64, auth_guard string) {
+	PushToUser(user_id, RealtimeSessionRevoked, map[string]string{"auth_guard": auth_guard})
+}
+
+func publishRealtime(topic, event string, data any) {
+	if GlobalRealtime == nil {
+		return
+	}
+	if err := GlobalRealtime.Publish(context.Background(), topic, event, data); err != nil {
+		log.Printf("[Realtime] 推送失败: topic=%s, event=%s, err=%v", topic, event, err)
+	}
+}
+
+func pruneRealtimeMessages(ctx context.Context) error {
+	before := time.Now().Add(-RealtimeMessageRetention).Unix()
+	_, err := models.DeleteRealtimeMessagesBefore(before)
+	return err
+}
+
+// realtimeStore 基于数据库的实时消息存储（REALTIME_BROKER=db）
+type realtimeStore struct{}
+
+func (realtimeStore) Append(msg *realtime.Message) error {
+	row := &models.RealtimeMessage{Topic: msg.Topic, Event: msg.Event, Data: string(msg.Data)}
+	if err := models.CreateRealtimeMessage(row); err != nil {
+		return err
+	}
+	msg.ID = row.ID
+	return nil
+}
+
+func (realtimeStore) After(id uint64, limit int) ([]*realtime.Message, error) {
+	rows, err := models.GetRealtimeMessagesAfter(id, limit)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]*realtime.Message, 0, len(rows))
+	for _, row := range rows {
+		msg := &realtime.Message{ID: row.ID, Topic: row.Topic, Event: row.Event}
+		if row.Data != "" {
+			msg.Data = json.RawMessage(row.Data)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (realtimeStore) LastID() (uint64, error) {
+	return models.GetRealtimeMessageLastID()
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fst/backend/app/models"
+	"fst/backend/internal/lifecycle"
+	"fst/backend/internal/scheduler"
+	"log"
+	"time"
+)
+
+// JobRunRetentionDays 运行记录保留天数
+const JobRunRetentionDays = 30
+
+// GlobalScheduler 全局定时任务调度器
+var GlobalScheduler *scheduler.Scheduler
+
+// JobStatus 定时任务状态（合并本实例调度信息与集群共享状态）
+type JobStatus struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Spec        string                  `json:"spec"`
+	Paused      bool                    `json:"paused"`
+	NextRun     time.Time               `json:"next_run"`
+	Running     bool                    `json:"running"`     // 是否有实例正在执行
+	LeaseOwner  string                  `json:"lease_owner"` // 正在执行的实例
+	LastRun     *models.ScheduledJobRun `json:"last_run"`    // 最近一次运行记录
+}
+
+// InitScheduler 初始化调度器并注册内置任务，随生命周期启动与停止
+func InitScheduler() {
+	GlobalScheduler = scheduler.New(jobStore{}, "")
+
+	for _, job := range []scheduler.Job{
+		cleanupJob(),
+		{
+			Name:        "cancel_expired_orders",
+			Description: "取消过期未支付订单",
+			Spec:        "* * * * *",
+			Timeout:     time.Minute,
+			Run:         CancelExpiredOrders,
+		},
+		{
+			Name:        "prune_job_runs",
+			Description: "清理过期的定时任务运行记录",
+			Spec:        "0 4 * * *",
+			Run:         pruneJobRuns,
+		},
+		{
+			Name:        "prune_notifications",
+			Description: "清理过期的已读站内通知",
+			Spec:        "15 4 * * *",
+			Run:         pruneNotifications,
+		},
+	} {
+		if err := RegisterJob(job); err != nil {
+			log.Printf("[Scheduler] %v", err)
+		}
+	}
+
+	lifecycle.Append(lifecycle.Hook{
+		Name:  "scheduler",
+		Start: GlobalScheduler.Start,
+		Stop:  GlobalScheduler.Stop,
+	})
+	log.Printf("[Scheduler] Initialized, instance: %s", GlobalScheduler.Instance())
+}
+
+// RegisterJob 注册定时任务（插件等外部模块也可调用）
+func RegisterJob(job scheduler.Job) error {
+	return GlobalScheduler.Register(job)
+}
+
+// ListJobs 返回所有定时任务的状态
+func ListJobs() ([]JobStatus, error) {
+	rows, err := models.GetScheduledJobs()
+	if err != nil {
+		return nil, err
+	}
+	shared := make(map[string]models.ScheduledJob, len(rows))
+	for _, row := range rows {
+		shared[row.Name] = row
+	}
+
+	jobs := globalJobs()
+	now := time.Now().Unix()
+	for i := range jobs {
+		row, ok := shared[jobs[i].Name]
+		if !ok {
+			continue
+		}
+		jobs[i].Paused = row.Paused
+		if row.LeaseOwner != "" && row.LeaseUntil >= now {
+			jobs[i].Running = true
+			jobs[i].LeaseOwner = row.LeaseOwner
+		}
+		if jobs[i].LastRun, err = models.GetLatestScheduledJobRun(jobs[i].Name); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
+
+// globalJobs 返回本实例注册的任务，调度器未初始化时为空
+func globalJobs() []JobStatus {
+	if GlobalScheduler == nil {
+		return nil
+	}
+	infos := GlobalScheduler.Jobs()
+	jobs := make([]JobStatus, 0, len(infos))
+	for _, info := range infos {
+		jobs = append(jobs, JobStatus{
+			Name:        info.Name,
+			Description: info.Description,
+			Spec:        info.Spec,
+			NextRun:     info.NextRun,
+			Running:     info.Running,
+		})
+	}
+	return jobs
+}
+
+func pruneJobRuns(ctx context.Context) error {
+	before := time.Now().AddDate(0, 0, -JobRunRetentionDays).Unix()
+	_, err := models.DeleteScheduledJobRunsBefore(before)
+	return err
+}
+
+// jobStore 基于数据库的调度状态存储
+type jobStore struct{}
+
+func (jobStore) EnsureJob(job scheduler.Job) error {
+	return models.EnsureScheduledJob(job.Name, job.Spec, job.Description)
+}
+
+func (jobStore) AcquireLease(name, owner string, scheduledAt, until time.Time) (bool, error) {
+	var scheduled int64
+	if !scheduledAt.IsZero() {
+		scheduled = scheduledAt.Unix()
+	}
+	return models.AcquireScheduledJobLease(name, owner, scheduled, until.Unix())
+}
+
+func (jobStore) ReleaseLease(name, owner string) error {
+	return models.ReleaseScheduledJobLease(name, owner)
+}
+
+func (jobStore) SetPaused(name string, paused bool) error {
+	return models.SetScheduledJobPaused(name, paused)
+}
+
+func (jobStore) StartRun(rec *scheduler.RunRecord) error {
+	run := &models.ScheduledJobRun{
+		JobName:     rec.Job,
+		Instance:    rec.Instance,
+		TriggerType: rec.Trigger,
+		Status:      rec.Status,
+		StartedAt:   rec.StartedAt.Unix(),
+	}
+	if err := models.CreateScheduledJobRun(run); err != nil {
+		return err
+	}
+	rec.ID = run.ID
+	return nil
+}
+
+func (jobStore) FinishRun(rec *scheduler.RunRecord) error {
+	if rec.ID == 0 {
+		return nil
+	}
+	return models.FinishScheduledJobRun(&models.ScheduledJobRun{
+		ID:         rec.ID,
+		Status:     rec.Status,
+		ErrorMsg:   rec.Error,
+		FinishedAt: rec.FinishedAt.Unix(),
+		DurationMs: rec.Duration.Milliseconds(),
+	})
+}
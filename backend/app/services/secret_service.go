@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"fst/backend/app/models"
+	"fst/backend/internal/config"
+	"fst/backend/internal/secrets"
+	"log"
+)
+
+// SecretRotationResult 密钥轮换结果
+type SecretRotationResult struct {
+	Settings    int // 重新加密的 system_settings 行数
+	PayGateways int // 重新加密的 pay_gateways 行数
+}
+
+// RotateSecrets 使用当前主密钥重新加密所有敏感数据
+// 旧密文需要能被 SECRET_PREVIOUS_KEYS 中的密钥解密，明文会被直接加密
+func RotateSecrets() (*SecretRotationResult, error) {
+	if !secrets.Enabled() {
+		return nil, secrets.ErrNoMasterKey
+	}
+
+	result := &SecretRotationResult{}
+	var err error
+	if result.Settings, err = models.ReencryptSecretSettings(); err != nil {
+		return nil, fmt.Errorf("rotate system_settings: %w", err)
+	}
+	if result.PayGateways, err = models.ReencryptPayGatewayKeys(); err != nil {
+		return nil, fmt.Errorf("rotate pay_gateways: %w", err)
+	}
+
+	if GlobalSettingsService != nil {
+		GlobalSettingsService.InvalidateCache()
+	}
+	return result, nil
+}
+
+// CheckSecretsAtRest 启动时检查敏感数据是否已加密
+// 生产模式下存在明文敏感数据时返回错误，开发模式下仅输出警告
+func CheckSecretsAtRest() error {
+	settingKeys, err := models.FindPlaintextSecretSettings()
+	if err != nil {
+		return fmt.Errorf("check system_settings secrets: %w", err)
+	}
+	gatewayIDs, err := models.FindPlaintextPayGatewayKeys()
+	if err != nil {
+		return fmt.Errorf("check pay_gateways secrets: %w", err)
+	}
+	if len(settingKeys) == 0 && len(gatewayIDs) == 0 {
+		return nil
+	}
+
+	detail := fmt.Sprintf("plaintext secrets found: settings=%v pay_gateways=%v", settingKeys, gatewayIDs)
+	if config.IsProductionMode() {
+		if !secrets.Enabled() {
+			return errors.New(detail + "; configure SECRET_MASTER_KEY and run `go run backend/cmd/rotate_secrets.go`")
+		}
+		return errors.New(detail + "; run `go run backend/cmd/rotate_secrets.go` to encrypt them")
+	}
+
+	if secrets.Enabled() {
+		log.Printf("[Secrets] %s; run `go run backend/cmd/rotate_secrets.go` to encrypt them", detail)
+	}
+	return nil
+}
@@ -27,7 +27,6 @@ func InitSettingsService() {
 	if err := GlobalSettingsService.RefreshCache(); err != nil {
 		log.Printf("[SettingsService] Refresh cache failed: %v", err)
 	}
-	ApplyGlobalRuntimeConfig()
 	log.Println("[SettingsService] Initialized with cache TTL: 5m")
 }
 
@@ -39,25 +38,41 @@ func NewSettingsService(ttl time.Duration) *SettingsService {
 	}
 }
 
-// RefreshCache refreshes all settings from DB.
+// RefreshCache refreshes all settings from DB and pushes them into the
+// system_settings layer of the runtime config.
 func (s *SettingsService) RefreshCache() error {
 	settings, err := models.GetAllSettings()
 	if err != nil {
 		return err
 	}
 
-	s.cacheMu.Lock()
-	defer s.cacheMu.Unlock()
-
-	s.cache = make(map[string]*models.SystemSetting)
+	values := make(map[string]string, len(settings))
+	cache := make(map[string]*models.SystemSetting, len(settings))
 	for i := range settings {
-		s.cache[settings[i].Key] = &settings[i]
+		cache[settings[i].Key] = &settings[i]
+		values[settings[i].Key] = settings[i].Value
 	}
+
+	s.cacheMu.Lock()
+	s.cache = cache
 	s.cacheTime = time.Now()
+	s.cacheMu.Unlock()
+
+	// 在释放缓存锁之后再通知订阅者，避免订阅者回调读取配置时死锁
+	config.ApplySettings(values)
 
 	return nil
 }
 
+// ReloadRuntimeConfig reloads settings from DB and applies them to the runtime
+// config immediately. Subscribers registered through config.Subscribe are notified.
+func ReloadRuntimeConfig() error {
+	if GlobalSettingsService == nil {
+		return nil
+	}
+	return GlobalSettingsService.RefreshCache()
+}
+
 // Get returns setting value by key.
 func (s *SettingsService) Get(key string) (string, bool) {
 	s.cacheMu.RLock()
@@ -197,6 +212,8 @@ type SMSRuntimeConfig struct {
 	SecretKey    string
 	SignName     string
 	TemplateCode string
+	Templates    string // 按用途配置的模板(JSON)
+	AppID        string
 	Region       string
 }
 
@@ -214,12 +231,12 @@ func parseBoolSetting(val string) bool {
 // GetGeetestRuntimeConfig returns effective geetest config.
 // Priority: database values -> environment fallback.
 func (s *SettingsService) GetGeetestRuntimeConfig() GeetestRuntimeConfig {
-	enabled := config.GlobalConfig.GeetestEnabled
-	if val, ok := s.Get("geetest_enabled"); ok {
+	enabled := config.Get().GeetestEnabled
+	if val, ok := s.Get("geetest_enabled"); ok && strings.TrimSpace(val) != "" {
 		enabled = parseBoolSetting(strings.TrimSpace(val))
 	}
 
-	captchaID := strings.TrimSpace(config.GlobalConfig.GeetestID)
+	captchaID := strings.TrimSpace(config.Get().GeetestID)
 	if val, ok := s.Get("geetest_captcha_id"); ok {
 		val = strings.TrimSpace(val)
 		if val != "" {
@@ -227,7 +244,7 @@ func (s *SettingsService) GetGeetestRuntimeConfig() GeetestRuntimeConfig {
 		}
 	}
 
-	captchaKey := strings.TrimSpace(config.GlobalConfig.GeetestKey)
+	captchaKey := strings.TrimSpace(config.Get().GeetestKey)
 	if val, ok := s.Get("geetest_captcha_key"); ok {
 		val = strings.TrimSpace(val)
 		if val != "" {
@@ -250,11 +267,11 @@ func GetGlobalGeetestRuntimeConfig() GeetestRuntimeConfig {
 		return GlobalSettingsService.GetGeetestRuntimeConfig()
 	}
 
-	captchaID := strings.TrimSpace(config.GlobalConfig.GeetestID)
-	captchaKey := strings.TrimSpace(config.GlobalConfig.GeetestKey)
+	captchaID := strings.TrimSpace(config.Get().GeetestID)
+	captchaKey := strings.TrimSpace(config.Get().GeetestKey)
 
 	return GeetestRuntimeConfig{
-		Enabled:    config.GlobalConfig.GeetestEnabled && captchaID != "" && captchaKey != "",
+		Enabled:    config.Get().GeetestEnabled && captchaID != "" && captchaKey != "",
 		CaptchaID:  captchaID,
 		CaptchaKey: captchaKey,
 	}
@@ -263,13 +280,13 @@ func GetGlobalGeetestRuntimeConfig() GeetestRuntimeConfig {
 // GetVerifyConfig returns effective verify enable/disable config.
 // Priority: database values -> environment fallback.
 func (s *SettingsService) GetVerifyConfig() VerifyConfig {
-	emailEnabled := config.GlobalConfig.EmailVerifyEnabled
-	if val, ok := s.Get("email_verify_enabled"); ok {
+	emailEnabled := config.Get().EmailVerifyEnabled
+	if val, ok := s.Get("email_verify_enabled"); ok && strings.TrimSpace(val) != "" {
 		emailEnabled = parseBoolSetting(strings.TrimSpace(val))
 	}
 
-	smsEnabled := config.GlobalConfig.SMSVerifyEnabled
-	if val, ok := s.Get("sms_verify_enabled"); ok {
+	smsEnabled := config.Get().SMSVerifyEnabled
+	if val, ok := s.Get("sms_verify_enabled"); ok && strings.TrimSpace(val) != "" {
 		smsEnabled = parseBoolSetting(strings.TrimSpace(val))
 	}
 
@@ -289,12 +306,14 @@ func (s *SettingsService) GetSMSRuntimeConfig() SMSRuntimeConfig {
 	}
 
 	return SMSRuntimeConfig{
-		Provider:     get("sms_provider", config.GlobalConfig.SMSProvider),
-		AccessKey:    get("sms_access_key", config.GlobalConfig.SMSAccessKey),
-		SecretKey:    get("sms_secret_key", config.GlobalConfig.SMSSecretKey),
-		SignName:     get("sms_sign_name", config.GlobalConfig.SMSSignName),
-		TemplateCode: get("sms_template_code", config.GlobalConfig.SMSTemplateCode),
-		Region:       get("sms_region", config.GlobalConfig.SMSRegion),
+		Provider:     get("sms_provider", config.Get().SMSProvider),
+		AccessKey:    get("sms_access_key", config.Get().SMSAccessKey),
+		SecretKey:    get("sms_secret_key", config.Get().SMSSecretKey),
+		SignName:     get("sms_sign_name", config.Get().SMSSignName),
+		TemplateCode: get("sms_template_code", config.Get().SMSTemplateCode),
+		Templates:    get("sms_templates", config.Get().SMSTemplates),
+		AppID:        get("sms_app_id", config.Get().SMSAppID),
+		Region:       get("sms_region", config.Get().SMSRegion),
 	}
 }
 
@@ -304,8 +323,8 @@ func GetGlobalVerifyConfig() VerifyConfig {
 		return GlobalSettingsService.GetVerifyConfig()
 	}
 	return VerifyConfig{
-		EmailEnabled: config.GlobalConfig.EmailVerifyEnabled,
-		SMSEnabled:   config.GlobalConfig.SMSVerifyEnabled,
+		EmailEnabled: config.Get().EmailVerifyEnabled,
+		SMSEnabled:   config.Get().SMSVerifyEnabled,
 	}
 }
 
@@ -315,34 +334,17 @@ func GetGlobalSMSRuntimeConfig() SMSRuntimeConfig {
 		return GlobalSettingsService.GetSMSRuntimeConfig()
 	}
 	return SMSRuntimeConfig{
-		Provider:     config.GlobalConfig.SMSProvider,
-		AccessKey:    config.GlobalConfig.SMSAccessKey,
-		SecretKey:    config.GlobalConfig.SMSSecretKey,
-		SignName:     config.GlobalConfig.SMSSignName,
-		TemplateCode: config.GlobalConfig.SMSTemplateCode,
-		Region:       config.GlobalConfig.SMSRegion,
+		Provider:     config.Get().SMSProvider,
+		AccessKey:    config.Get().SMSAccessKey,
+		SecretKey:    config.Get().SMSSecretKey,
+		SignName:     config.Get().SMSSignName,
+		TemplateCode: config.Get().SMSTemplateCode,
+		Templates:    config.Get().SMSTemplates,
+		AppID:        config.Get().SMSAppID,
+		Region:       config.Get().SMSRegion,
 	}
 }
 
-func ApplyGlobalRuntimeConfig() {
-	geetestConfig := GetGlobalGeetestRuntimeConfig()
-	config.GlobalConfig.GeetestEnabled = geetestConfig.Enabled
-	config.GlobalConfig.GeetestID = geetestConfig.CaptchaID
-	config.GlobalConfig.GeetestKey = geetestConfig.CaptchaKey
-
-	verifyConfig := GetGlobalVerifyConfig()
-	config.GlobalConfig.EmailVerifyEnabled = verifyConfig.EmailEnabled
-	config.GlobalConfig.SMSVerifyEnabled = verifyConfig.SMSEnabled
-
-	smsConfig := GetGlobalSMSRuntimeConfig()
-	config.GlobalConfig.SMSProvider = smsConfig.Provider
-	config.GlobalConfig.SMSAccessKey = smsConfig.AccessKey
-	config.GlobalConfig.SMSSecretKey = smsConfig.SecretKey
-	config.GlobalConfig.SMSSignName = smsConfig.SignName
-	config.GlobalConfig.SMSTemplateCode = smsConfig.TemplateCode
-	config.GlobalConfig.SMSRegion = smsConfig.Region
-}
-
 // GetPublicAppConfig returns public app config consumed by frontend bootstrap.
 func (s *SettingsService) GetPublicAppConfig() *PublicAppConfig {
 	geetestConfig := s.GetGeetestRuntimeConfig()
@@ -365,22 +367,20 @@ func (s *SettingsService) GetPublicAppConfig() *PublicAppConfig {
 	}
 }
 
-// UpdateSettingsWithCache updates settings in DB and invalidates cache.
+// UpdateSettingsWithCache updates settings in DB and reloads the cache.
 func (s *SettingsService) UpdateSettingsWithCache(settings map[string]string) error {
 	err := models.BatchUpdateSettings(settings)
 	if err != nil {
 		return err
 	}
-	s.InvalidateCache()
-	return nil
+	return s.RefreshCache()
 }
 
-// UpdateSingleSettingWithCache updates one setting in DB and invalidates cache.
+// UpdateSingleSettingWithCache updates one setting in DB and reloads the cache.
 func (s *SettingsService) UpdateSingleSettingWithCache(key, value string) error {
 	err := models.UpdateSetting(key, value)
 	if err != nil {
 		return err
 	}
-	s.InvalidateCache()
-	return nil
+	return s.RefreshCache()
 }
@@ -0,0 +1,157 @@
+package services
+
+import (
+	crypto_rand "crypto/rand"
+	"fmt"
+	"fst/backend/app/models"
+	"fst/backend/internal/config"
+	"log"
+	"math/big"
+	"time"
+)
+
+// SMSCodeExpireMinutes 短信验证码有效期（分钟）
+const SMSCodeExpireMinutes = 10
+
+// smsCodeCooldown 同一手机号同一用途两次发送的最小间隔
+const smsCodeCooldown = time.Minute
+
+// SMSCodeMaxAttempts 验证码允许输错的次数，达到后作废，需重新获取
+const SMSCodeMaxAttempts = 5
+
+// smsCodeStore 短信验证码的校验状态存储
+type smsCodeStore interface {
+	// Consume 校验并消费验证码
+	Consume(phone, code, purpose string) (bool, error)
+	// RecordFailure 记录一次校验失败，达到 maxAttempts 时作废验证码
+	RecordFailure(phone, purpose string, maxAttempts int) error
+	// Discard 作废该手机号同用途的验证码
+	Discard(phone, purpose string) error
+}
+
+// smsCodes 默认使用 verification_codes 表，测试中替换为内存实现
+var smsCodes smsCodeStore = dbSMSCodeStore{}
+
+// dbSMSCodeStore 基于数据库的验证码存储
+type dbSMSCodeStore struct{}
+
+func (dbSMSCodeStore) Consume(phone, code, purpose string) (bool, error) {
+	return models.ConsumeVerificationCode(models.CodeChannelSMS, phone, code, purpose)
+}
+
+func (dbSMSCodeStore) RecordFailure(phone, purpose string, maxAttempts int) error {
+	return models.RecordVerificationCodeFailure(models.CodeChannelSMS, phone, purpose, maxAttempts)
+}
+
+func (dbSMSCodeStore) Discard(phone, purpose string) error {
+	return models.DiscardVerificationCodes(models.CodeChannelSMS, phone, purpose)
+}
+
+// Available 短信服务是否可以发送验证码
+// 生产环境不允许使用控制台占位 Provider，避免验证码只打印在日志里
+func (s *SMSService) Available() bool {
+	if s == nil {
+		return false
+	}
+	switch name := s.GetProviderName(); name {
+	case "none":
+		return false
+	case "console":
+		return !config.IsProductionMode()
+	default:
+		return s.IsConfigured()
+	}
+}
+
+// SendVerifyCode 生成、保存并发送短信验证码
+// 依次检查发送间隔、手机号每小时/每天配额和 IP 每天配额；发送失败时作废刚生成的验证码
+func (s *SMSService) SendVerifyCode(phone, purpose, ip string) *ServiceError {
+	if !s.Available() {
+		return NewServiceError(500, "SMS service not configured")
+	}
+
+	now := time.Now()
+	has_recent, err := models.HasRecentVerificationCode(models.CodeChannelSMS, phone, purpose, now.Add(-smsCodeCooldown))
+	if err != nil {
+		return NewServiceError(500, "Failed to check verification cooldown")
+	}
+	if has_recent {
+		return NewServiceError(429, "Please wait before requesting another verification code")
+	}
+	if svc_err := checkSMSQuota(phone, ip, now); svc_err != nil {
+		return svc_err
+	}
+
+	code, err := generateSMSCode()
+	if err != nil {
+		return NewServiceError(500, "Failed to generate verification code")
+	}
+	expires_at := now.Add(SMSCodeExpireMinutes * time.Minute)
+	if err := models.CreateVerificationCode(models.CodeChannelSMS, phone, code, purpose, ip, expires_at); err != nil {
+		return NewServiceError(500, "Failed to generate verification code")
+	}
+	if err := s.SendCode(phone, purpose, code, SMSCodeExpireMinutes); err != nil {
+		log.Printf("[SMSService] Failed to send %s code to %s via %s: %v\n", purpose, phone, s.GetProviderName(), err)
+		_ = models.DiscardVerificationCodes(models.CodeChannelSMS, phone, purpose)
+		return NewServiceError(500, "Failed to send verification code")
+	}
+	return nil
+}
+
+// ConsumeSMSCode 校验并消费短信验证码，成功后作废该手机号同用途的其余验证码
+// 输错累计 SMSCodeMaxAttempts 次后验证码作废，防止暴力尝试 6 位验证码
+func ConsumeSMSCode(phone, code, purpose string) bool {
+	consumed, err := smsCodes.Consume(phone, code, purpose)
+	if err != nil {
+		return false
+	}
+	if !consumed {
+		if err := smsCodes.RecordFailure(phone, purpose, SMSCodeMaxAttempts); err != nil {
+			log.Printf("[SMSService] Failed to record %s code failure for %s: %v\n", purpose, phone, err)
+		}
+		return false
+	}
+	_ = smsCodes.Discard(phone, purpose)
+	return true
+}
+
+// checkSMSQuota 检查短信发送配额，按 verification_codes 中的发送记录统计（不区分用途）
+func checkSMSQuota(phone, ip string, now time.Time) *ServiceError {
+	phone_limits := []struct {
+		limit  int
+		window time.Duration
+	}{
+		{config.SMSPhoneHourlyLimit.Get(), time.Hour},
+		{config.SMSPhoneDailyLimit.Get(), 24 * time.Hour},
+	}
+	for _, l := range phone_limits {
+		count, err := models.CountVerificationCodesByTarget(models.CodeChannelSMS, phone, now.Add(-l.window))
+		if err != nil {
+			return NewServiceError(500, "Failed to check SMS quota")
+		}
+		if count >= l.limit {
+			return NewServiceError(429, "Too many verification codes sent to this phone number, please try again later")
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+	count, err := models.CountVerificationCodesByIP(models.CodeChannelSMS, ip, now.Add(-24*time.Hour))
+	if err != nil {
+		return NewServiceError(500, "Failed to check SMS quota")
+	}
+	if count >= config.SMSIPDailyLimit.Get() {
+		return NewServiceError(429, "Too many verification codes requested, please try again later")
+	}
+	return nil
+}
+
+// generateSMSCode 生成 6 位数字验证码
+func generateSMSCode() (string, error) {
+	n, err := crypto_rand.Int(crypto_rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
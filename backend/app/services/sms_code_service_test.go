@@ -0,0 +1,95 @@
+package services
+
+import (
+	"fst/backend/app/models"
+	"testing"
+	"time"
+)
+
+// memSMSCodeStore 内存验证码存储，每个手机号同用途只保留一条有效验证码
+type memSMSCodeStore struct {
+	codes    map[string]string
+	attempts map[string]int
+}
+
+func newMemSMSCodeStore() *memSMSCodeStore {
+	return &memSMSCodeStore{codes: make(map[string]string), attempts: make(map[string]int)}
+}
+
+func (s *memSMSCodeStore) Consume(phone, code, purpose string) (bool, error) {
+	key := phone + "/" + purpose
+	if stored, ok := s.codes[key]; ok && stored == code {
+		delete(s.codes, key)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *memSMSCodeStore) RecordFailure(phone, purpose string, maxAttempts int) error {
+	key := phone + "/" + purpose
+	if _, ok := s.codes[key]; !ok {
+		return nil
+	}
+	s.attempts[key]++
+	if s.attempts[key] >= maxAttempts {
+		delete(s.codes, key)
+	}
+	return nil
+}
+
+func (s *memSMSCodeStore) Discard(phone, purpose string) error {
+	delete(s.codes, phone+"/"+purpose)
+	return nil
+}
+
+func useMemSMSCodeStore(t *testing.T) *memSMSCodeStore {
+	t.Helper()
+	store := newMemSMSCodeStore()
+	old := smsCodes
+	smsCodes = store
+	t.Cleanup(func() { smsCodes = old })
+	return store
+}
+
+func TestConsumeSMSCodeLocksOutAfterMaxAttempts(t *testing.T) {
+	store := useMemSMSCodeStore(t)
+	const phone = "13800138000"
+	store.codes[phone+"/"+SMSPurposeResetPassword] = "123456"
+
+	for i := 0; i < SMSCodeMaxAttempts; i++ {
+		if ConsumeSMSCode(phone, "000000", SMSPurposeResetPassword) {
+			t.Fatalf("attempt %d: wrong code accepted", i+1)
+		}
+	}
+	if ConsumeSMSCode(phone, "123456", SMSPurposeResetPassword) {
+		t.Fatal("correct code accepted after too many failed attempts")
+	}
+}
+
+func TestConsumeSMSCodeAcceptsCodeBeforeLimit(t *testing.T) {
+	store := useMemSMSCodeStore(t)
+	const phone = "13800138000"
+	store.codes[phone+"/"+SMSPurposeLogin] = "123456"
+
+	for i := 0; i < SMSCodeMaxAttempts-1; i++ {
+		ConsumeSMSCode(phone, "000000", SMSPurposeLogin)
+	}
+	if !ConsumeSMSCode(phone, "123456", SMSPurposeLogin) {
+		t.Fatal("correct code rejected before reaching the attempt limit")
+	}
+	if ConsumeSMSCode(phone, "123456", SMSPurposeLogin) {
+		t.Fatal("code accepted twice")
+	}
+}
+
+func TestCheckAccountLockRejectsLockedAccount(t *testing.T) {
+	lock_until := time.Now().Add(10 * time.Minute).Unix()
+	svc := &AuthService{}
+	svc_err := svc.checkAccountLock(&models.User{ID: 1, LockUntil: &lock_until})
+	if svc_err == nil || svc_err.Code != 403 {
+		t.Fatalf("checkAccountLock = %v, want 403 for locked account", svc_err)
+	}
+	if svc_err := svc.checkAccountLock(&models.User{ID: 1}); svc_err != nil {
+		t.Fatalf("checkAccountLock = %v, want nil for unlocked account", svc_err)
+	}
+}
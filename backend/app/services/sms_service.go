@@ -1,11 +1,26 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"fst/backend/app/models"
+	"fst/backend/internal/config"
+	"fst/backend/internal/lifecycle"
+	"fst/backend/internal/sms"
 	"log"
+	"strings"
 	"sync"
 )
 
+// 短信用途，对应按用途配置的模板
+const (
+	SMSPurposeRegister      = "register"       // 注册
+	SMSPurposeLogin         = "login"          // 验证码登录
+	SMSPurposeResetPassword = "reset_password" // 找回密码
+	SMSPurposeChangePhone   = "change_phone"   // 修改手机号
+)
+
 // ========================================
 // SMS Provider 接口定义
 // ========================================
@@ -15,23 +30,58 @@ import (
 type SMSProvider interface {
 	// Name 返回服务商名称
 	Name() string
-	// Send 发送短信
-	Send(phone, content string) error
-	// SendCode 发送验证码短信
-	SendCode(phone, code string, expireMinutes int) error
+	// SendCode 使用指定模板发送验证码短信，成功时返回服务商回执 ID
+	SendCode(phone, template, code string, expireMinutes int) (string, error)
 	// IsConfigured 检查是否已正确配置
 	IsConfigured() bool
 }
 
 // SMSConfig 短信服务配置
 type SMSConfig struct {
-	Provider  string // 服务商标识: aliyun, tencent, custom
-	AccessKey string // AccessKey / API Key
-	SecretKey string // SecretKey / API Secret
-	SignName  string // 短信签名
-	TemplateCode string // 验证码模板ID
-	Region   string // 区域（部分服务商需要）
-	Endpoint string // 自定义端点（自定义服务商）
+	Provider     string            // 服务商标识: aliyun, tencent, console
+	AccessKey    string            // AccessKey / SecretId
+	SecretKey    string            // SecretKey / API Secret
+	SignName     string            // 短信签名
+	TemplateCode string            // 默认验证码模板ID
+	Templates    map[string]string // 按用途配置的模板ID，未配置的用途使用 TemplateCode
+	AppID        string            // 短信应用ID（腾讯云 SdkAppId）
+	Region       string            // 区域（部分服务商需要）
+	Endpoint     string            // 自定义接口地址（测试或代理）
+}
+
+// Template 返回用途对应的模板ID
+func (c SMSConfig) Template(purpose string) string {
+	if tpl := strings.TrimSpace(c.Templates[purpose]); tpl != "" {
+		return tpl
+	}
+	return c.TemplateCode
+}
+
+// hasTemplate 是否配置了至少一个模板
+func (c SMSConfig) hasTemplate() bool {
+	if c.TemplateCode != "" {
+		return true
+	}
+	for _, tpl := range c.Templates {
+		if strings.TrimSpace(tpl) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSMSTemplates 解析按用途配置的模板(JSON)，格式错误时忽略并记录日志
+func parseSMSTemplates(raw string) map[string]string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	templates := map[string]string{}
+	if err := json.Unmarshal([]byte(raw), &templates); err != nil {
+		log.Printf("[SMSService] Invalid sms_templates, ignored: %v\n", err)
+		return nil
+	}
+	return templates
 }
 
 // ========================================
@@ -49,8 +99,18 @@ type SMSService struct {
 var GlobalSMSService *SMSService
 
 // InitSMSService 初始化全局短信服务
+// 短信相关配置变更后会自动重新选择 Provider，无需重启
 func InitSMSService() {
 	GlobalSMSService = &SMSService{}
+	applySMSRuntimeConfig()
+	config.Subscribe(func(config.Change) {
+		applySMSRuntimeConfig()
+	}, config.SMSProvider, config.SMSAccessKey, config.SMSSecretKey, config.SMSSignName, config.SMSTemplateCode, config.SMSTemplates, config.SMSAppID, config.SMSRegion)
+	log.Println("[SMSService] Initialized")
+}
+
+// applySMSRuntimeConfig 将当前生效的短信配置应用到全局短信服务
+func applySMSRuntimeConfig() {
 	smsConfig := GetGlobalSMSRuntimeConfig()
 	GlobalSMSService.SetConfig(SMSConfig{
 		Provider:     smsConfig.Provider,
@@ -58,9 +118,10 @@ func InitSMSService() {
 		SecretKey:    smsConfig.SecretKey,
 		SignName:     smsConfig.SignName,
 		TemplateCode: smsConfig.TemplateCode,
+		Templates:    parseSMSTemplates(smsConfig.Templates),
+		AppID:        smsConfig.AppID,
 		Region:       smsConfig.Region,
 	})
-	log.Println("[SMSService] Initialized (no provider configured)")
 }
 
 // SetProvider 设置当前使用的短信服务商
@@ -82,9 +143,9 @@ func (s *SMSService) SetConfig(cfg SMSConfig) {
 	// 根据 provider 标识自动选择实现
 	switch cfg.Provider {
 	case "aliyun":
-		s.provider = &AliyunSMSProvider{config: cfg}
+		s.provider = NewAliyunSMSProvider(cfg)
 	case "tencent":
-		s.provider = &TencentSMSProvider{config: cfg}
+		s.provider = NewTencentSMSProvider(cfg)
 	default:
 		// 未配置或未知的 provider，使用日志占位
 		s.provider = &ConsoleSMSProvider{}
@@ -100,24 +161,11 @@ func (s *SMSService) GetConfig() SMSConfig {
 	return s.config
 }
 
-// Send 发送短信
-func (s *SMSService) Send(phone, content string) error {
-	s.mu.RLock()
-	provider := s.provider
-	s.mu.RUnlock()
-
-	if provider == nil {
-		log.Printf("[SMSService] No provider configured, skipping SMS to %s\n", phone)
-		return nil
-	}
-
-	return provider.Send(phone, content)
-}
-
-// SendCode 发送验证码
-func (s *SMSService) SendCode(phone, code string, expireMinutes int) error {
+// SendCode 按用途选择模板发送验证码，并异步记录短信日志
+func (s *SMSService) SendCode(phone, purpose, code string, expireMinutes int) error {
 	s.mu.RLock()
 	provider := s.provider
+	template := s.config.Template(purpose)
 	s.mu.RUnlock()
 
 	if provider == nil {
@@ -125,7 +173,25 @@ func (s *SMSService) SendCode(phone, code string, expireMinutes int) error {
 		return nil
 	}
 
-	return provider.SendCode(phone, code, expireMinutes)
+	request_id, err := provider.SendCode(phone, template, code, expireMinutes)
+	sms_log := &models.SMSLog{
+		Phone:     phone,
+		Purpose:   purpose,
+		Provider:  provider.Name(),
+		Template:  template,
+		Status:    models.SMSLogSuccess,
+		RequestID: request_id,
+	}
+	if err != nil {
+		sms_log.Status = models.SMSLogFailed
+		sms_log.ErrorMsg = err.Error()
+	}
+	lifecycle.Go("sms-log", func(context.Context) {
+		if log_err := models.CreateSMSLog(sms_log); log_err != nil {
+			log.Printf("[SMSService] Failed to write sms log: %v\n", log_err)
+		}
+	})
+	return err
 }
 
 // IsConfigured 检查短信服务是否已配置
@@ -161,80 +227,76 @@ type ConsoleSMSProvider struct{}
 
 func (p *ConsoleSMSProvider) Name() string { return "console" }
 
-func (p *ConsoleSMSProvider) Send(phone, content string) error {
-	fmt.Printf("[SMS-Console] To: %s, Content: %s\n", phone, content)
-	return nil
-}
-
-func (p *ConsoleSMSProvider) SendCode(phone, code string, expireMinutes int) error {
-	fmt.Printf("[SMS-Console] To: %s, Code: %s, Expires: %d min\n", phone, code, expireMinutes)
-	return nil
+func (p *ConsoleSMSProvider) SendCode(phone, template, code string, expireMinutes int) (string, error) {
+	fmt.Printf("[SMS-Console] To: %s, Template: %s, Code: %s, Expires: %d min\n", phone, template, code, expireMinutes)
+	return "", nil
 }
 
 func (p *ConsoleSMSProvider) IsConfigured() bool { return true }
 
 // ========================================
-// 阿里云短信 Provider（预留）
+// 阿里云短信 Provider
 // ========================================
 
-// AliyunSMSProvider 阿里云短信服务
+// AliyunSMSProvider 阿里云短信服务，模板变量为 ${code}
 type AliyunSMSProvider struct {
 	config SMSConfig
+	client *sms.AliyunClient
 }
 
-func (p *AliyunSMSProvider) Name() string { return "aliyun" }
-
-func (p *AliyunSMSProvider) Send(phone, content string) error {
-	// TODO: 对接阿里云短信 SDK
-	// sdk: github.com/alibabacloud-go/dysmsapi-20170525/v3/client
-	// 1. 创建 client: dysmsapi.NewClient(...)
-	// 2. 构建请求: dysmsapi.SendSmsRequest{PhoneNumbers: phone, SignName: p.config.SignName, ...}
-	// 3. 发送: client.SendSms(request)
-	fmt.Printf("[SMS-Aliyun] TODO: Send to %s, content: %s\n", phone, content)
-	return fmt.Errorf("aliyun SMS provider not implemented yet")
+// NewAliyunSMSProvider 创建阿里云短信 Provider
+func NewAliyunSMSProvider(cfg SMSConfig) *AliyunSMSProvider {
+	return &AliyunSMSProvider{
+		config: cfg,
+		client: &sms.AliyunClient{
+			AccessKeyID:     cfg.AccessKey,
+			AccessKeySecret: cfg.SecretKey,
+			Region:          cfg.Region,
+			Endpoint:        cfg.Endpoint,
+		},
+	}
 }
 
-func (p *AliyunSMSProvider) SendCode(phone, code string, expireMinutes int) error {
-	// TODO: 对接阿里云验证码模板
-	// templateParam := fmt.Sprintf(`{"code":"%s"}`, code)
-	// 使用 p.config.TemplateCode 作为模板ID
-	fmt.Printf("[SMS-Aliyun] TODO: SendCode to %s, code: %s, template: %s\n", phone, code, p.config.TemplateCode)
-	return fmt.Errorf("aliyun SMS provider not implemented yet")
+func (p *AliyunSMSProvider) Name() string { return "aliyun" }
+
+func (p *AliyunSMSProvider) SendCode(phone, template, code string, expireMinutes int) (string, error) {
+	return p.client.Send(context.Background(), sms.CodeMessage(phone, p.config.SignName, template, code, expireMinutes))
 }
 
 func (p *AliyunSMSProvider) IsConfigured() bool {
-	return p.config.AccessKey != "" && p.config.SecretKey != "" && p.config.SignName != "" && p.config.TemplateCode != ""
+	return p.config.AccessKey != "" && p.config.SecretKey != "" && p.config.SignName != "" && p.config.hasTemplate()
 }
 
 // ========================================
-// 腾讯云短信 Provider（预留）
+// 腾讯云短信 Provider
 // ========================================
 
-// TencentSMSProvider 腾讯云短信服务
+// TencentSMSProvider 腾讯云短信服务，模板变量为 {1}=验证码、{2}=有效分钟数
 type TencentSMSProvider struct {
 	config SMSConfig
+	client *sms.TencentClient
 }
 
-func (p *TencentSMSProvider) Name() string { return "tencent" }
-
-func (p *TencentSMSProvider) Send(phone, content string) error {
-	// TODO: 对接腾讯云短信 SDK
-	// sdk: github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/sms/v20210111
-	// 1. 创建 credential: common.NewCredential(p.config.AccessKey, p.config.SecretKey)
-	// 2. 创建 client: sms.NewClient(credential, p.config.Region, ...)
-	// 3. 构建请求: sms.SendSmsRequest{...}
-	// 4. 发送: client.SendSms(request)
-	fmt.Printf("[SMS-Tencent] TODO: Send to %s, content: %s\n", phone, content)
-	return fmt.Errorf("tencent SMS provider not implemented yet")
+// NewTencentSMSProvider 创建腾讯云短信 Provider
+func NewTencentSMSProvider(cfg SMSConfig) *TencentSMSProvider {
+	return &TencentSMSProvider{
+		config: cfg,
+		client: &sms.TencentClient{
+			SecretID:  cfg.AccessKey,
+			SecretKey: cfg.SecretKey,
+			AppID:     cfg.AppID,
+			Region:    cfg.Region,
+			Endpoint:  cfg.Endpoint,
+		},
+	}
 }
 
-func (p *TencentSMSProvider) SendCode(phone, code string, expireMinutes int) error {
-	// TODO: 对接腾讯云验证码模板
-	// templateParamSet := []*string{&code, fmt.Sprintf("%d", expireMinutes)}
-	fmt.Printf("[SMS-Tencent] TODO: SendCode to %s, code: %s, template: %s\n", phone, code, p.config.TemplateCode)
-	return fmt.Errorf("tencent SMS provider not implemented yet")
+func (p *TencentSMSProvider) Name() string { return "tencent" }
+
+func (p *TencentSMSProvider) SendCode(phone, template, code string, expireMinutes int) (string, error) {
+	return p.client.Send(context.Background(), sms.CodeMessage(phone, p.config.SignName, template, code, expireMinutes))
 }
 
 func (p *TencentSMSProvider) IsConfigured() bool {
-	return p.config.AccessKey != "" && p.config.SecretKey != "" && p.config.SignName != "" && p.config.TemplateCode != ""
+	return p.config.AccessKey != "" && p.config.SecretKey != "" && p.config.SignName != "" && p.config.AppID != "" && p.config.hasTemplate()
 }
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSMSConfigTemplate(t *testing.T) {
+	cfg := SMSConfig{
+		TemplateCode: "SMS_DEFAULT",
+		Templates:    parseSMSTemplates(`{"register":"SMS_REG","login":" "}`),
+	}
+	cases := map[string]string{
+		SMSPurposeRegister:    "SMS_REG",
+		SMSPurposeLogin:       "SMS_DEFAULT",
+		SMSPurposeChangePhone: "SMS_DEFAULT",
+	}
+	for purpose, want := range cases {
+		if got := cfg.Template(purpose); got != want {
+			t.Errorf("Template(%q) = %q, want %q", purpose, got, want)
+		}
+	}
+	if parseSMSTemplates(`{"register":`) != nil {
+		t.Error("invalid JSON should be ignored")
+	}
+}
+
+func TestSMSProviderConfigured(t *testing.T) {
+	base := SMSConfig{AccessKey: "id", SecretKey: "secret", SignName: "F.st"}
+	if NewAliyunSMSProvider(base).IsConfigured() {
+		t.Error("aliyun without any template should not be configured")
+	}
+	with_template := base
+	with_template.Templates = map[string]string{SMSPurposeLogin: "SMS_1"}
+	if !NewAliyunSMSProvider(with_template).IsConfigured() {
+		t.Error("aliyun with a purpose template should be configured")
+	}
+	if NewTencentSMSProvider(with_template).IsConfigured() {
+		t.Error("tencent without AppID should not be configured")
+	}
+	with_template.AppID = "1400000000"
+	if !NewTencentSMSProvider(with_template).IsConfigured() {
+		t.Error("tencent with AppID should be configured")
+	}
+}
+
+func TestAliyunSMSProviderSendCode(t *testing.T) {
+	var template_code string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		template_code = r.PostForm.Get("TemplateCode")
+		io.WriteString(w, `{"Code":"OK","BizId":"biz-1","RequestId":"r-1"}`)
+	}))
+	defer srv.Close()
+
+	cfg := SMSConfig{AccessKey: "id", SecretKey: "secret", SignName: "F.st", TemplateCode: "SMS_DEFAULT", Endpoint: srv.URL}
+	request_id, err := NewAliyunSMSProvider(cfg).SendCode("13800000000", cfg.Template(SMSPurposeChangePhone), "123456", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request_id != "biz-1" || template_code != "SMS_DEFAULT" {
+		t.Errorf("request_id = %q, template = %q", request_id, template_code)
+	}
+}
@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fst/backend/app/models"
 	"fst/backend/internal/db"
+	"fst/backend/internal/events"
 	"fst/backend/utils"
 	"strings"
 	"time"
@@ -36,6 +38,7 @@ func ChangeUserMoney(userID uint64, amount float64, memo string) (*models.UserMo
 	if err != nil {
 		return nil, err
 	}
+	emitMoneyChanged(userID, amount, result, memo)
 	return result.MoneyLog, nil
 }
 
@@ -49,6 +52,7 @@ func ChangeUserMoneyI18n(userID uint64, amount float64, memoI18n map[string]stri
 	if err != nil {
 		return nil, err
 	}
+	emitMoneyChanged(userID, amount, result, utils.BuildMemo("", memoI18n))
 	return result.MoneyLog, nil
 }
 
@@ -87,6 +91,7 @@ func SetUserMoney(userID uint64, newMoney float64, memo string) (*models.UserMon
 		return nil, errors.New("提交事务失败: " + err.Error())
 	}
 
+	emitMoneyChanged(userID, amount, result, memo)
 	return result.MoneyLog, nil
 }
 
@@ -161,9 +166,23 @@ func OperateUserMoney(userID uint64, req MoneyOperationRequest) (*utils.BalanceR
 		return nil, err
 	}
 
+	if opType == utils.OpChangeOnly || opType == utils.OpChangeAndLog || opType == utils.OpChangeAndOrder || opType == utils.OpFull {
+		emitMoneyChanged(userID, req.Amount, result, req.Memo)
+	}
 	return result, nil
 }
 
+// emitMoneyChanged 余额实际变动（事务提交）后发布 money.changed 事件
+func emitMoneyChanged(userID uint64, amount float64, result *utils.BalanceResult, memo string) {
+	events.MoneyChanged.Emit(context.Background(), events.MoneyChangedEvent{
+		UserID:      userID,
+		Amount:      amount,
+		BeforeMoney: result.BeforeMoney,
+		AfterMoney:  result.AfterMoney,
+		Memo:        memo,
+	})
+}
+
 // createOrderForMoneyOperation 在管理员执行余额+订单操作时，自动补建缺失订单。
 func createOrderForMoneyOperation(userID uint64, req MoneyOperationRequest) error {
 	amount := req.Amount
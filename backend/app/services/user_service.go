@@ -247,9 +247,13 @@ func (s *UserService) UpdateStatus(user_id uint64, status uint8) error {
 	return err
 }
 
-// UpdatePassword 更新用户密码
+// UpdatePassword 更新用户密码，成功后发送密码已修改通知
 func (s *UserService) UpdatePassword(user_id uint64, hashed_password string) error {
-	return models.UpdatePassword(user_id, hashed_password)
+	if err := models.UpdatePassword(user_id, hashed_password); err != nil {
+		return err
+	}
+	notifyPasswordChanged(user_id)
+	return nil
 }
 
 // Delete 软删除用户（同时禁用账号状态）
@@ -0,0 +1,144 @@
+//go:build ignore
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"fst/backend/app/plugins"
+	"fst/backend/internal/config"
+	"fst/backend/internal/openapi"
+	"fst/backend/internal/openapi/clientgen"
+	"fst/backend/routes"
+
+	"github.com/gin-gonic/gin"
+
+	// 与 main.go 的插件导入区域保持一致，插件接口同样生成到客户端中
+	_ "fst/backend/app/plugins/demo"
+)
+
+// 客户端生成脚本：根据 OpenAPI 文档生成类型化的 Go 与 TypeScript 客户端
+//
+//	go run backend/cmd/gen_client.go
+//	go run backend/cmd/gen_client.go -spec http://localhost:8080/openapi.json
+//
+// 不指定 -spec 时在进程内注册核心路由与内置插件路由后生成文档，不需要数据库；
+// 进程外插件的接口只能通过 -spec 读取运行中服务的文档获得。
+// 接口变更后重新执行即可保持调用方与服务端一致，-go-out / -ts-out 置空则不生成对应语言。
+
+func main() {
+	spec := flag.String("spec", "", "OpenAPI 文档的文件路径或 URL，默认从当前代码生成")
+	goOut := flag.String("go-out", "backend/pkg/apiclient/client.go", "Go 客户端输出文件")
+	goPkg := flag.String("go-package", "apiclient", "Go 客户端包名")
+	tsOut := flag.String("ts-out", "frontend/src/service/generated/api.ts", "TypeScript 客户端输出文件")
+	flag.Parse()
+
+	doc, err := loadSpec(*spec)
+	if err != nil {
+		log.Fatalf("读取接口文档失败: %v", err)
+	}
+
+	var skipped []string
+	if *goOut != "" {
+		src, s, err := clientgen.Go(doc, *goPkg)
+		if err != nil {
+			log.Fatalf("生成 Go 客户端失败: %v", err)
+		}
+		write(*goOut, src)
+		skipped = appendUnique(skipped, s...)
+	}
+	if *tsOut != "" {
+		src, s := clientgen.TypeScript(doc)
+		write(*tsOut, src)
+		skipped = appendUnique(skipped, s...)
+	}
+
+	count := 0
+	for _, item := range doc.Paths {
+		count += len(item.Operations())
+	}
+	fmt.Println("========================================")
+	fmt.Printf("接口: %d，数据结构: %d\n", count-len(skipped), len(doc.Components.Schemas))
+	for _, op := range skipped {
+		fmt.Printf("跳过（非 JSON 请求体）: %s\n", op)
+	}
+	fmt.Println("========================================")
+}
+
+// loadSpec 读取文件或 URL 中的文档；未指定时从当前代码的路由生成
+func loadSpec(spec string) (*openapi.Document, error) {
+	if spec == "" {
+		return buildSpec(), nil
+	}
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		resp, err := http.Get(spec)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s 返回 %s", spec, resp.Status)
+		}
+		data, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+	} else if data, err = os.ReadFile(spec); err != nil {
+		return nil, err
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func buildSpec() *openapi.Document {
+	config.InitConfig()
+	cfg := *config.Get()
+	cfg.EnableSwagger = true
+	config.Set(&cfg)
+	gin.SetMode(gin.ReleaseMode)
+
+	router := gin.New()
+	routes.SetupRoutes(router)
+
+	mgr := plugins.NewManager()
+	plugins.AutoRegisterAll(mgr)
+	mgr.RegisterAllRoutes(router.Group("/api/v1"), router.Routes())
+
+	return openapi.BuildEngine(router)
+}
+
+// appendUnique 合并两种语言跳过的接口，同一接口只列出一次
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func write(path string, content []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		log.Fatalf("写入 %s 失败: %v", path, err)
+	}
+	fmt.Printf("已生成 %s\n", path)
+}
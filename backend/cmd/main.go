@@ -3,49 +3,54 @@
 package main
 
 import (
+	"context"
 	"fst/backend/app/models"
-	"fst/backend/app/plugins"
 	"fst/backend/app/services"
 	"fst/backend/internal/config"
 	"fst/backend/internal/db"
+	"fst/backend/internal/lifecycle"
 	"fst/backend/internal/middleware"
+	"fst/backend/internal/secrets"
 	"fst/backend/routes"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	// ========================================
 	// 插件自动导入区域
-	// 运行 gen-swagger.bat 会自动扫描并更新此区域
+	// 运行 go run backend/app/plugins/gen_plugins.go 会自动扫描并更新此区域
 	// ========================================
 	// @plugins-start
 	_ "fst/backend/app/plugins/demo"
 	// @plugins-end
 )
 
-// @title FST Platform API
-// @version 1.0
-// @description FST Platform 后端 API 接口文档
-// @host localhost:8080
-// @BasePath /api
-// @securityDefinitions.apikey BearerAuth
-// @in header
-// @name Authorization
-// @description JWT认证令牌，格式: Bearer {token}
-
 func main() {
-	// 1. 初始化配置
+	// 1. 初始化配置，并监听 .env 文件变化自动热更新
 	config.InitConfig()
+	stopWatch := config.WatchFile(5 * time.Second)
+	lifecycle.Append(lifecycle.Hook{Name: "config-watcher", Stop: func(context.Context) error {
+		stopWatch()
+		return nil
+	}})
+
+	// 1.1 加载敏感数据加密主密钥
+	if err := secrets.Init(); err != nil {
+		log.Fatalf("[Secrets] %v", err)
+	}
 
 	// 2. 初始化数据库
 	db.InitDB()
 
 	// 3. 初始化邮件模板
 	models.InitEmailTemplates()
+	models.InitEmailSuppressionsTable()
+	models.InitEmailCampaignsTable()
+	models.InitSMSLogsTable()
+	models.InitNotificationsTable()
+	models.InitUserLoginDevicesTable()
+	models.InitRealtimeMessagesTable()
 
 	// 4. 初始化验证码表
 	models.InitVerificationCodeTable()
@@ -70,23 +75,31 @@ func main() {
 	// 5.5 初始化支付通道表
 	models.InitPayGatewaysTable()
 
+	// 5.6 初始化定时任务表
+	models.InitScheduledJobsTable()
+	models.InitQueueJobsTable()
+	models.InitPluginStatesTable()
+	models.InitPluginConfigsTable()
+
+	// 5.7 检查敏感数据是否已加密（生产模式拒绝明文）
+	if err := services.CheckSecretsAtRest(); err != nil {
+		log.Fatalf("[Secrets] %v", err)
+	}
+
 	// 6. 初始化配置服务（缓存）
 	services.InitSettingsService()
 
 	// 6.1 初始化短信服务
 	services.InitSMSService()
 
-	// 7. 启动定时清理任务
-	services.StartCleanupTask()
+	// 7. 初始化定时任务调度（验证码清理、过期订单取消等，集群内只执行一次）
+	services.InitScheduler()
+
+	// 7.1 初始化后台任务队列（邮件发送、操作日志写入，失败自动重试）
+	services.InitQueue()
 
-	// 7.1 启动过期订单自动取消任务（每分钟检查一次）
-	go func() {
-		ticker := time.NewTicker(1 * time.Minute)
-		defer ticker.Stop()
-		for range ticker.C {
-			services.CancelExpiredOrders()
-		}
-	}()
+	// 7.2 初始化实时推送（SSE，支付结果、站内通知、会话撤销等）
+	services.InitRealtime()
 
 	// 8. 创建路由
 	router := gin.New()
@@ -94,51 +107,29 @@ func main() {
 	router.SetTrustedProxies(nil)
 	router.Use(middleware.CorsMiddleware())
 
+	// 请求 ID 与 API 版本（X-API-Version: 2 时错误响应使用真实的 HTTP 状态码）
+	router.Use(middleware.RequestID(), middleware.APIVersion())
+
 	// 9. 添加请求日志中间件
 	router.Use(middleware.LoggerMiddleware())
 
 	// 10. 注册路由
 	routes.SetupRoutes(router)
 
-	// 11. 初始化插件系统（使用新的管理器）
-	pluginMgr := plugins.NewManager()
+	// 11. 初始化插件系统：注册通过 init() 导入的插件，并按 plugin_states 表中的启用状态加载
+	pluginMgr := services.InitPlugins()
 
-	// 自动注册所有导入的插件
-	// 注意：插件通过 init() 函数自动注册到全局注册表
-	plugins.AutoRegisterAll(pluginMgr)
-
-	// 加载所有插件
-	if err := pluginMgr.LoadAll(); err != nil {
-		log.Printf("[Plugin] 插件加载失败: %v", err)
-	}
-
-	// 注册插件路由
+	// 注册插件路由：位于 /api/v1/plugins/<插件名>/ 下，与核心路由冲突的插件不注册（禁用的插件在请求时返回 404）
 	apiGroup := router.Group("/api/v1")
-	pluginMgr.RegisterAllRoutes(apiGroup)
-
-	// 12. 优雅关闭处理
-	go func() {
-		quit := make(chan os.Signal, 1)
-		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-		<-quit
-		log.Println("[Server] 正在关闭...")
-
-		// 关闭插件
-		if err := pluginMgr.ShutdownAll(); err != nil {
-			log.Printf("[Plugin] 关闭失败: %v", err)
-		}
-
-		log.Println("[Server] 已关闭")
-		os.Exit(0)
-	}()
-
-	// 13. 启动服务
-	port := config.GlobalConfig.Port
+	pluginMgr.RegisterAllRoutes(apiGroup, router.Routes())
+
+	// 12. 启动服务，收到退出信号后按生命周期优雅关闭
+	port := config.Get().Port
 	log.Printf("[Server] 服务启动，端口: %s", port)
-	log.Printf("[Server] Swagger 文档: http://localhost:%s/swagger/index.html", port)
+	if config.Get().EnableSwagger {
+		log.Printf("[Server] 接口文档: http://localhost:%s/openapi.json（Swagger UI: /swagger/index.html）", port)
+	}
 	log.Printf("[Server] 已加载插件数量: %d", pluginMgr.Count())
 
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("[Server] 启动失败: %v", err)
-	}
+	serve(router, pluginMgr, port)
 }
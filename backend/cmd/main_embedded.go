@@ -3,20 +3,23 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"fst/backend/app/models"
-	"fst/backend/app/plugins"
-	"fst/backend/app/plugins/demo"
+	_ "fst/backend/app/plugins/demo"
 	"fst/backend/app/services"
 	"fst/backend/internal/config"
 	"fst/backend/internal/db"
+	"fst/backend/internal/lifecycle"
 	"fst/backend/internal/middleware"
+	"fst/backend/internal/secrets"
 	"fst/backend/routes"
 	"fst/backend/utils"
 	"io/fs"
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -36,10 +39,24 @@ var frontendFS embed.FS
 
 func main() {
 	config.InitConfig()
+	stopWatch := config.WatchFile(5 * time.Second)
+	lifecycle.Append(lifecycle.Hook{Name: "config-watcher", Stop: func(context.Context) error {
+		stopWatch()
+		return nil
+	}})
+	if err := secrets.Init(); err != nil {
+		log.Fatalf("[Secrets] %v", err)
+	}
 	db.InitDB()
 
 	// 初始化邮件模板
 	models.InitEmailTemplates()
+	models.InitEmailSuppressionsTable()
+	models.InitEmailCampaignsTable()
+	models.InitSMSLogsTable()
+	models.InitNotificationsTable()
+	models.InitUserLoginDevicesTable()
+	models.InitRealtimeMessagesTable()
 
 	// 初始化验证码表（如果不存在）
 	models.InitVerificationCodeTable()
@@ -64,12 +81,28 @@ func main() {
 	// 初始化支付通道表
 	models.InitPayGatewaysTable()
 
+	// 初始化定时任务表
+	models.InitScheduledJobsTable()
+	models.InitQueueJobsTable()
+	models.InitPluginStatesTable()
+	models.InitPluginConfigsTable()
+
+	// 检查敏感数据是否已加密（生产模式拒绝明文）
+	if err := services.CheckSecretsAtRest(); err != nil {
+		log.Fatalf("[Secrets] %v", err)
+	}
+
 	// 初始化配置服务（缓存）
 	services.InitSettingsService()
 
-	// 启动定时清理任务：间隔可通过 CLEANUP_INTERVAL_MINUTES 配置，默认10分钟
-	// 清理状态仅在内存中记录，不输出周期性日志，可通过接口查询
-	services.StartCleanupTask()
+	// 初始化定时任务调度：验证码清理间隔可通过 CLEANUP_INTERVAL_MINUTES 配置，默认10分钟
+	services.InitScheduler()
+
+	// 初始化后台任务队列（邮件发送、操作日志写入，失败自动重试）
+	services.InitQueue()
+
+	// 初始化实时推送（SSE，支付结果、站内通知、会话撤销等）
+	services.InitRealtime()
 
 	// 初始化短信服务
 	services.InitSMSService()
@@ -78,21 +111,19 @@ func main() {
 	router.Use(gin.Logger(), gin.Recovery())
 	router.SetTrustedProxies(nil) // 修复 "trusted all proxies" 警告
 	router.Use(middleware.CorsMiddleware())
+
+	// 请求 ID 与 API 版本（X-API-Version: 2 时错误响应使用真实的 HTTP 状态码）
+	router.Use(middleware.RequestID(), middleware.APIVersion())
 	routes.SetupRoutes(router)
 
-	// 插件初始化
-	pluginMgr := plugins.NewManager()
-	plugins.AutoRegisterAll(pluginMgr)
-	pluginMgr.Register(demo.NewPlugin())
-	if err := pluginMgr.LoadAll(); err != nil {
-		log.Printf("[Plugin] 插件加载失败: %v", err)
-	}
+	// 插件初始化（按 plugin_states 表中的启用状态加载）
+	pluginMgr := services.InitPlugins()
 	apiGroup := router.Group("/api/v1")
-	pluginMgr.RegisterAllRoutes(apiGroup)
+	pluginMgr.RegisterAllRoutes(apiGroup, router.Routes()) // 插件路由位于 /api/v1/plugins/<插件名>/
 
 	// 前端资源处理
 	// 仅当 AppMode == "integrated" 且 BuildMode != "none" 时，才提供前端托管能力
-	if config.GlobalConfig.AppMode == "integrated" && BuildMode != "none" {
+	if config.Get().AppMode == "integrated" && BuildMode != "none" {
 		var publicFS http.FileSystem
 
 		if BuildMode == "external" {
@@ -128,12 +159,10 @@ func main() {
 			})
 		}
 	} else {
-		log.Printf("[Mode] Backend only: AppMode=%s, BuildMode=%s, frontend not served", config.GlobalConfig.AppMode, BuildMode)
+		log.Printf("[Mode] Backend only: AppMode=%s, BuildMode=%s, frontend not served", config.Get().AppMode, BuildMode)
 	}
 
-	port := config.GlobalConfig.Port
+	port := config.Get().Port
 	log.Printf("Server starting on port %s [%s Mode]...", port, BuildMode)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Server error: %v", err)
-	}
+	serve(router, pluginMgr, port)
 }
@@ -0,0 +1,43 @@
+//go:build ignore
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"fst/backend/app/services"
+	"fst/backend/internal/config"
+	"fst/backend/internal/db"
+	"fst/backend/internal/secrets"
+)
+
+// 敏感数据密钥轮换脚本：使用当前主密钥重新加密 system_settings 与 pay_gateways 中的所有敏感数据
+//
+// 轮换步骤：
+//  1. 将新密钥写入 SECRET_MASTER_KEY，旧密钥移入 SECRET_PREVIOUS_KEYS（逗号分隔）
+//  2. 执行 go run backend/cmd/rotate_secrets.go
+//  3. 确认无误后从 SECRET_PREVIOUS_KEYS 中移除旧密钥
+//
+// 首次启用加密时直接执行即可，明文数据会被加密。
+
+func main() {
+	config.InitConfig()
+	if err := secrets.Init(); err != nil {
+		log.Fatalf("加载主密钥失败: %v", err)
+	}
+	if !secrets.Enabled() {
+		log.Fatal("未配置 SECRET_MASTER_KEY 或 SECRET_MASTER_KEY_FILE")
+	}
+	db.InitDB()
+
+	result, err := services.RotateSecrets()
+	if err != nil {
+		log.Fatalf("密钥轮换失败: %v", err)
+	}
+
+	fmt.Println("========================================")
+	fmt.Printf("system_settings 重新加密: %d 行\n", result.Settings)
+	fmt.Printf("pay_gateways    重新加密: %d 行\n", result.PayGateways)
+	fmt.Println("========================================")
+}
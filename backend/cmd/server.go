@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fst/backend/app/plugins"
+	"fst/backend/app/services"
+	"fst/backend/internal/config"
+	"fst/backend/internal/lifecycle"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serve 启动 HTTP 服务并阻塞，直到收到退出信号或关闭请求后按生命周期优雅关闭
+// 关闭顺序：HTTP 服务停止接收并排空请求 → 插件关闭 → 后台任务退出并等待异步任务完成
+func serve(router *gin.Engine, pluginMgr *plugins.Manager, port string) {
+	lifecycle.Append(lifecycle.Hook{
+		Name: "plugins",
+		Stop: func(context.Context) error { return pluginMgr.ShutdownAll() },
+	})
+
+	srv := &http.Server{Addr: ":" + port, Handler: router}
+	// 关闭时先断开实时推送长连接，否则 Shutdown 会一直等待到超时
+	srv.RegisterOnShutdown(services.CloseRealtime)
+	serverErr := make(chan error, 1)
+	lifecycle.Append(lifecycle.Hook{
+		Name: "http",
+		Start: func(context.Context) error {
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					serverErr <- err
+				}
+			}()
+			return nil
+		},
+		Stop: srv.Shutdown,
+	})
+
+	if err := lifecycle.Start(context.Background()); err != nil {
+		log.Fatalf("[Server] 启动失败: %v", err)
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case sig := <-quit:
+		log.Printf("[Server] 收到信号 %v，正在关闭...", sig)
+	case <-lifecycle.ShutdownRequested():
+		log.Println("[Server] 收到关闭请求，正在关闭...")
+	case err := <-serverErr:
+		log.Printf("[Server] 服务异常退出: %v，正在关闭...", err)
+	}
+
+	timeout := time.Duration(config.ShutdownTimeoutSeconds.Get()) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := lifecycle.Stop(ctx); err != nil {
+		log.Printf("[Server] 关闭未完全完成: %v", err)
+		return
+	}
+	log.Println("[Server] 已关闭")
+}
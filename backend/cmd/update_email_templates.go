@@ -13,6 +13,8 @@ import (
 )
 
 // 临时脚本：强制更新所有邮件模板为最新的 HTML 格式
+// 注意：直接覆盖且不记录版本；启动时 models.InitEmailTemplates 已会自动升级未修改过的模板，
+// 需要放弃管理员修改时请使用后台的重置接口
 
 func main() {
 	// 加载 .env 配置
@@ -1,14 +1,14 @@
 package config
 
 import (
-	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
-
-	"github.com/joho/godotenv"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Config struct {
@@ -34,6 +34,15 @@ type Config struct {
 	SMTPSSL                   bool
 	SystemEmail               string
 	SystemEmailName           string
+	DKIMDomain                string // DKIM 签名域名
+	DKIMSelector              string // DKIM 选择器
+	DKIMPrivateKey            string // DKIM 私钥（PEM 内容或文件路径）
+	MailTransport             string // 邮件投递方式: smtp, http, file, maildir
+	MailHTTPURL               string // HTTP 投递接口地址
+	MailHTTPToken             string // HTTP 投递接口令牌（Bearer）
+	MailFileDir               string // file / maildir 投递目录
+	SMTPPoolSize              int    // SMTP 连接池最大连接数
+	MailWebhookToken          string // 退信 / 投诉回调令牌
 	RegisterCodeExpireMinutes int
 	LoginMaxFailureCount      int    // 登录最大失败次数，超过此次数将锁定账户
 	LoginLockDurationMinutes  int    // 账户锁定持续时间（分钟）
@@ -46,11 +55,29 @@ type Config struct {
 	SMSAccessKey              string // 短信服务 AccessKey
 	SMSSecretKey              string // 短信服务 SecretKey
 	SMSSignName               string // 短信签名
-	SMSTemplateCode           string // 短信验证码模板ID
+	SMSTemplateCode           string // 短信验证码模板ID（未单独配置模板的用途使用）
+	SMSTemplates              string // 按用途配置的短信模板(JSON)，如 {"register":"SMS_1","login":"SMS_2"}
+	SMSAppID                  string // 短信应用ID（腾讯云 SdkAppId）
 	SMSRegion                 string // 短信服务区域
+	RateLimitRate             int    // 通用限流：每秒请求数
+	RateLimitBurst            int    // 通用限流：突发上限
+	AuthRateLimitRate         int    // 登录/注册等敏感接口限流：每秒请求数
+	AuthRateLimitBurst        int    // 登录/注册等敏感接口限流：突发上限
+}
+
+// current 当前生效的配置快照
+// 热更新时整体替换为新的快照，请求中的读取不会看到写了一半的配置
+var current atomic.Pointer[Config]
+
+// Get 返回当前生效的配置快照，快照只读，不要修改其字段
+func Get() *Config {
+	return current.Load()
 }
 
-var GlobalConfig *Config
+// Set 替换当前配置快照，供测试和命令行工具使用；正常运行时由 InitConfig / 热更新维护
+func Set(cfg *Config) {
+	current.Store(cfg)
+}
 
 const defaultJWTSecret = "secret"
 
@@ -59,29 +86,39 @@ func isProductionEnvMode(mode string) bool {
 	return mode == "prod" || mode == "production"
 }
 
+// checkCriticalSecurityConfig 返回生产环境下不允许的安全配置错误
+func checkCriticalSecurityConfig(cfg *Config) error {
+	if cfg == nil || !isProductionEnvMode(cfg.AppMode) {
+		return nil
+	}
+	secret := strings.TrimSpace(cfg.JWTSecret)
+	if secret == "" || secret == defaultJWTSecret {
+		return fmt.Errorf("empty or default JWT_SECRET in production mode")
+	}
+	return nil
+}
+
 func validateCriticalSecurityConfig(cfg *Config) {
 	if cfg == nil {
 		return
 	}
 
-	secret := strings.TrimSpace(cfg.JWTSecret)
-	if isProductionEnvMode(cfg.AppMode) {
-		if secret == "" || secret == defaultJWTSecret {
-			log.Fatal("[Security] Refusing to start with an empty or default JWT_SECRET in production mode")
-		}
-		return
+	if err := checkCriticalSecurityConfig(cfg); err != nil {
+		log.Fatalf("[Security] Refusing to start: %v", err)
 	}
 
+	secret := strings.TrimSpace(cfg.JWTSecret)
 	if secret == "" || secret == defaultJWTSecret {
 		log.Println("[Security Warning] JWT_SECRET is using the default development value")
 	}
 }
 
 func IsProductionMode() bool {
-	if GlobalConfig == nil {
+	cfg := Get()
+	if cfg == nil {
 		return false
 	}
-	return isProductionEnvMode(GlobalConfig.AppMode)
+	return isProductionEnvMode(cfg.AppMode)
 }
 
 func findDotEnvPath() (string, bool) {
@@ -107,340 +144,190 @@ func findDotEnvPath() (string, bool) {
 	return "", false
 }
 
+// applyMu 串行化各层级的更新，保证通知顺序与配置快照一致
+var applyMu sync.Mutex
+
+// InitConfig 加载默认值、.env 文件与环境变量，生成配置快照
+// system_settings 层由 SettingsService 在数据库就绪后通过 ApplySettings 注入
 func InitConfig() {
-	dotEnvPath, hasDotEnv := findDotEnvPath()
-	if hasDotEnv {
-		if cfg, ok := loadJSONDotEnv(dotEnvPath); ok {
-			GlobalConfig = cfg
-			log.Printf("[Config] Loaded JSON .env from %s", dotEnvPath)
-			validateCriticalSecurityConfig(GlobalConfig)
-			return
-		}
+	applyMu.Lock()
+	defer applyMu.Unlock()
 
-		if err := godotenv.Load(dotEnvPath); err != nil {
-			log.Printf("Error loading .env file %s, using default environment variables", dotEnvPath)
+	if dotEnvPath, ok := findDotEnvPath(); ok {
+		defaultStore.filePath = dotEnvPath
+		if values, err := readFileLayer(dotEnvPath); err != nil {
+			log.Printf("Error loading .env file %s: %v, using default environment variables", dotEnvPath, err)
 		} else {
+			defaultStore.setLayer(LayerFile, values)
 			log.Printf("[Config] Loaded .env from %s", dotEnvPath)
 		}
+		stamp, _ := statFile(dotEnvPath)
+		defaultStore.setFileStamp(stamp)
 	} else {
 		log.Println("Error loading .env file, using default environment variables")
 	}
+	defaultStore.setLayer(LayerEnv, readEnvLayer())
 
-	geetestEnabled, _ := strconv.ParseBool(strings.TrimSpace(getEnv("GEETEST_ENABLED", "false")))
-	if !geetestEnabled {
-		geetestEnabled, _ = strconv.ParseBool(strings.TrimSpace(getEnv("GEETEST_ENABLE", "false")))
-	}
+	cfg := buildConfig(defaultStore.snapshot())
+	validateCriticalSecurityConfig(cfg)
+	Set(cfg)
+}
 
-	enableSwagger, _ := strconv.ParseBool(strings.TrimSpace(getEnv("ENABLE_SWAGGER", "false")))
+// Subscribe 注册配置变更回调，keys 为空时任意键变化都会触发
+// 回调在配置更新后同步执行，返回值用于取消订阅
+func Subscribe(fn func(Change), keys ...Key) func() {
+	return defaultStore.subscribe(fn, keys)
+}
 
-	geetestID := getEnv("GEETEST_ID", "")
-	if geetestID == "" {
-		geetestID = getEnv("GEETEST_CAPTCHA_ID", "")
-	}
-	geetestKey := getEnv("GEETEST_KEY", "")
-	if geetestKey == "" {
-		geetestKey = getEnv("GEETEST_CAPTCHA_KEY", "")
-	}
+// ApplySettings 使用 system_settings 的最新键值替换数据库配置层
+func ApplySettings(settings map[string]string) {
+	applyLayer(LayerSettings, settingsLayerFromMap(settings))
+}
 
-	GlobalConfig = &Config{
-		AppName:         getEnv("APP_NAME", "F.st"),
-		AppTitle:        getEnv("APP_TITLE", "F.st - Think Fast,Run F.st"),
-		AppMode:         getEnv("APP_MODE", "separate"),
-		Port:            getEnv("PORT", "8080"),
-		DBDriver:        getEnv("DB_DRIVER", "mysql"),
-		DBDSN:           buildDSN(),
-		GeetestEnabled:  geetestEnabled && geetestID != "" && geetestKey != "",
-		GeetestID:       geetestID,
-		GeetestKey:      geetestKey,
-		JWTSecret:       getEnv("JWT_SECRET", "secret"),
-		AdminJWTSecret:  getEnv("JWT_ADMIN_SECRET", getEnv("JWT_SECRET", "secret")),
-		AdminPath:       "/admin",
-		CorsOrigins:     getEnv("CORS_ORIGINS", ""),
-		EnableSwagger:   enableSwagger,
-		FrontendURL:     getEnv("FRONTEND_URL", ""),
-		SMTPHost:        getEnv("SMTP_HOST", ""),
-		SMTPPort:        getEnv("SMTP_PORT", ""),
-		SMTPUser:        getEnv("SMTP_USERNAME", ""),
-		SMTPPass:        getEnv("SMTP_PASSWORD", ""),
-		SMTPSSL:         getEnv("SMTP_SSL_TYPE", "") == "ssl",
-		SystemEmail:     getEnv("SYSTEM_EMAIL_ADDRESS", ""),
-		SystemEmailName: getEnv("SYSTEM_EMAIL_NAME", ""),
-		RegisterCodeExpireMinutes: func() int {
-			v, err := strconv.Atoi(strings.TrimSpace(getEnv("REGISTER_CODE_EXPIRE_MINUTES", "60")))
-			if err != nil || v <= 0 {
-				return 60
-			}
-			return v
-		}(),
-		LoginMaxFailureCount: func() int {
-			v, err := strconv.Atoi(strings.TrimSpace(getEnv("LOGIN_MAX_FAILURE_COUNT", "5")))
-			if err != nil || v <= 0 {
-				return 5
-			}
-			return v
-		}(),
-		LoginLockDurationMinutes: func() int {
-			v, err := strconv.Atoi(strings.TrimSpace(getEnv("LOGIN_LOCK_DURATION_MINUTES", "10")))
-			if err != nil || v <= 0 {
-				return 10
-			}
-			return v
-		}(),
-		JWTAccessExpire: func() int {
-			v, err := strconv.Atoi(strings.TrimSpace(getEnv("JWT_ACCESS_EXPIRE", "7200")))
-			if err != nil || v <= 0 {
-				return 7200
-			}
-			return v
-		}(),
-		JWTRefreshExpire: func() int {
-			v, err := strconv.Atoi(strings.TrimSpace(getEnv("JWT_REFRESH_EXPIRE", "604800")))
-			if err != nil || v <= 0 {
-				return 604800
-			}
-			return v
-		}(),
-		CleanupIntervalMinutes: func() int {
-			v, err := strconv.Atoi(strings.TrimSpace(getEnv("CLEANUP_INTERVAL_MINUTES", "10")))
-			if err != nil || v <= 0 {
-				return 10
-			}
-			return v
-		}(),
-		EmailVerifyEnabled: func() bool {
-			v, _ := strconv.ParseBool(strings.TrimSpace(getEnv("EMAIL_VERIFY_ENABLED", "true")))
-			return v
-		}(),
-		SMSVerifyEnabled: func() bool {
-			v, _ := strconv.ParseBool(strings.TrimSpace(getEnv("SMS_VERIFY_ENABLED", "false")))
-			return v
-		}(),
-		SMSProvider:     getEnv("SMS_PROVIDER", "console"),
-		SMSAccessKey:    getEnv("SMS_ACCESS_KEY", ""),
-		SMSSecretKey:    getEnv("SMS_SECRET_KEY", ""),
-		SMSSignName:     getEnv("SMS_SIGN_NAME", ""),
-		SMSTemplateCode: getEnv("SMS_TEMPLATE_CODE", ""),
-		SMSRegion:       getEnv("SMS_REGION", ""),
+// Reload 重新读取 .env 文件与环境变量
+func Reload() error {
+	if path := defaultStore.filePath; path != "" {
+		values, err := readFileLayer(path)
+		if err != nil {
+			return err
+		}
+		stamp, _ := statFile(path)
+		defaultStore.setFileStamp(stamp)
+		applyLayer(LayerFile, values)
 	}
-
-	validateCriticalSecurityConfig(GlobalConfig)
+	applyLayer(LayerEnv, readEnvLayer())
+	return nil
 }
 
-func getEnv(key, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
+// WatchFile 定期检查 .env 文件的修改时间，变化后自动重新加载
+// 返回的函数用于停止监听
+func WatchFile(interval time.Duration) func() {
+	stop := make(chan struct{})
+	path := defaultStore.filePath
+	if path == "" {
+		return func() {}
 	}
-	return fallback
-}
 
-func buildDSN() string {
-	user := getEnv("DB_USER", "root")
-	pass := getEnv("DB_PASSWORD", "")
-	host := getEnv("DB_HOST", "127.0.0.1")
-	port := getEnv("DB_PORT", "3306")
-	name := getEnv("DB_NAME", "fst_platform")
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				stamp, ok := statFile(path)
+				if !ok || stamp == defaultStore.fileStamp() {
+					continue
+				}
+				log.Printf("[Config] Detected change in %s, reloading", path)
+				if err := Reload(); err != nil {
+					log.Printf("[Config] Reload failed: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
 
-	return user + ":" + pass + "@tcp(" + host + ":" + port + ")/" + name + "?charset=utf8mb4&parseTime=True&loc=Local"
+	var once sync.Once
+	return func() { once.Do(func() { close(stop) }) }
 }
 
-type jsonDotEnv struct {
-	DBHost                    string `json:"db_host"`
-	DBPort                    string `json:"db_port"`
-	DBUser                    string `json:"db_user"`
-	DBPassword                string `json:"db_password"`
-	DBName                    string `json:"db_name"`
-	Port                      string `json:"port"`
-	CorsOrigins               string `json:"cors_origins"`
-	JWTSecret                 string `json:"jwt_secret"`
-	JWTAdminSecret            string `json:"jwt_admin_secret"`
-	JWTExpireHours            string `json:"jwt_expire_hours"`
-	Debug                     string `json:"debug"`
-	GeetestEnabled            string `json:"geetest_enabled"`
-	GeetestCaptchaID          string `json:"geetest_captcha_id"`
-	GeetestCaptchaKey         string `json:"geetest_captcha_key"`
-	EnableSwagger             string `json:"enable_swagger"`
-	SMTPHost                  string `json:"smtp_host"`
-	SMTPPort                  string `json:"smtp_port"`
-	SMTPUser                  string `json:"smtp_username"`
-	SMTPPass                  string `json:"smtp_password"`
-	SMTPSSL                   string `json:"smtp_ssl_type"`
-	SystemEmail               string `json:"system_email_address"`
-	SystemEmailName           string `json:"system_email_name"`
-	RegisterCodeExpireMinutes string `json:"register_code_expire_minutes"`
-	FrontendURL               string `json:"frontend_url"`
-	LoginMaxFailureCount      string `json:"login_max_failure_count"`
-	LoginLockDurationMinutes  string `json:"login_lock_duration_minutes"`
-	JWTAccessExpire           string `json:"jwt_access_expire"`
-	JWTRefreshExpire          string `json:"jwt_refresh_expire"`
-	CleanupIntervalMinutes    string `json:"cleanup_interval_minutes"`
-	EmailVerifyEnabled        string `json:"email_verify_enabled"`
-	SMSVerifyEnabled          string `json:"sms_verify_enabled"`
-	SMSProvider               string `json:"sms_provider"`
-	SMSAccessKey              string `json:"sms_access_key"`
-	SMSSecretKey              string `json:"sms_secret_key"`
-	SMSSignName               string `json:"sms_sign_name"`
-	SMSTemplateCode           string `json:"sms_template_code"`
-	SMSRegion                 string `json:"sms_region"`
-}
+// applyLayer 更新某一层级，发布新的配置快照并通知订阅者
+func applyLayer(layer Layer, values map[string]string) {
+	applyMu.Lock()
 
-func loadJSONDotEnv(path string) (*Config, bool) {
-	b, err := os.ReadFile(path)
-	if err != nil {
-		return nil, false
-	}
-	s := strings.TrimSpace(string(b))
-	if !strings.HasPrefix(s, "{") {
-		return nil, false
+	previous := defaultStore.layers[layer]
+	changed := defaultStore.setLayer(layer, values)
+	if len(changed) == 0 {
+		applyMu.Unlock()
+		return
 	}
 
-	var raw jsonDotEnv
-	if err := json.Unmarshal([]byte(s), &raw); err != nil {
-		return nil, false
+	next := buildConfig(defaultStore.snapshot())
+	if err := checkCriticalSecurityConfig(next); err != nil {
+		defaultStore.setLayer(layer, previous)
+		applyMu.Unlock()
+		log.Printf("[Config] Rejected config change: %v", err)
+		return
 	}
 
-	port := raw.Port
-	if port == "" {
-		port = "8080"
+	var old Config
+	if prev := current.Swap(next); prev != nil {
+		old = *prev
 	}
+	applyMu.Unlock()
 
-	jwtSecret := raw.JWTSecret
-	if jwtSecret == "" {
-		jwtSecret = "secret"
-	}
+	log.Printf("[Config] Updated keys: %s", strings.Join(changed, ", "))
+	defaultStore.notify(Change{Keys: changed, Old: old, New: *next})
+}
 
-	jwtAdminSecret := raw.JWTAdminSecret
-	if jwtAdminSecret == "" {
-		jwtAdminSecret = jwtSecret
-	}
+// buildConfig 由合并后的键值生成 Config 结构
+func buildConfig(v map[string]string) *Config {
+	str := func(k StringKey) string { return v[k.Name()] }
+	num := func(k IntKey) int { return parsePositiveInt(v[k.Name()], specDefaultInt(k.Name())) }
+	flag := func(k BoolKey) bool { return parseBool(v[k.Name()]) }
 
-	geetestEnabled, _ := strconv.ParseBool(strings.TrimSpace(raw.GeetestEnabled))
-	enableSwagger, _ := strconv.ParseBool(strings.TrimSpace(raw.EnableSwagger))
+	geetestID := strings.TrimSpace(str(GeetestID))
+	geetestKey := strings.TrimSpace(str(GeetestKey))
 
-	user := raw.DBUser
-	if user == "" {
-		user = "root"
-	}
-	pass := raw.DBPassword
-	host := raw.DBHost
-	if host == "" {
-		host = "127.0.0.1"
-	}
-	dbPort := raw.DBPort
-	if dbPort == "" {
-		dbPort = "3306"
-	}
-	name := raw.DBName
-	if name == "" {
-		name = "fst_platform"
+	adminSecret := str(JWTAdminSecret)
+	if adminSecret == "" {
+		adminSecret = str(JWTSecret)
 	}
-	dsn := user + ":" + pass + "@tcp(" + host + ":" + dbPort + ")/" + name + "?charset=utf8mb4&parseTime=True&loc=Local"
-
-	cfg := &Config{
-		AppName:         "F.st",
-		AppTitle:        "F.st - Think Fast,Run F.st",
-		AppMode:         "separate",
-		Port:            port,
-		DBDriver:        "mysql",
-		DBDSN:           dsn,
-		GeetestEnabled:  geetestEnabled && raw.GeetestCaptchaID != "" && raw.GeetestCaptchaKey != "",
-		GeetestID:       raw.GeetestCaptchaID,
-		GeetestKey:      raw.GeetestCaptchaKey,
-		JWTSecret:       jwtSecret,
-		AdminJWTSecret:  jwtAdminSecret,
-		AdminPath:       "/admin",
-		CorsOrigins:     raw.CorsOrigins,
-		EnableSwagger:   enableSwagger,
-		FrontendURL:     raw.FrontendURL,
-		SMTPHost:        raw.SMTPHost,
-		SMTPPort:        raw.SMTPPort,
-		SMTPUser:        raw.SMTPUser,
-		SMTPPass:        raw.SMTPPass,
-		SMTPSSL:         raw.SMTPSSL == "ssl",
-		SystemEmail:     raw.SystemEmail,
-		SystemEmailName: raw.SystemEmailName,
-		RegisterCodeExpireMinutes: func() int {
-			if raw.RegisterCodeExpireMinutes == "" {
-				return 60
-			}
-			v, err := strconv.Atoi(strings.TrimSpace(raw.RegisterCodeExpireMinutes))
-			if err != nil || v <= 0 {
-				return 60
-			}
-			return v
-		}(),
-		LoginMaxFailureCount: func() int {
-			if raw.LoginMaxFailureCount == "" {
-				return 5
-			}
-			v, err := strconv.Atoi(strings.TrimSpace(raw.LoginMaxFailureCount))
-			if err != nil || v <= 0 {
-				return 5
-			}
-			return v
-		}(),
-		LoginLockDurationMinutes: func() int {
-			if raw.LoginLockDurationMinutes == "" {
-				return 10
-			}
-			v, err := strconv.Atoi(strings.TrimSpace(raw.LoginLockDurationMinutes))
-			if err != nil || v <= 0 {
-				return 10
-			}
-			return v
-		}(),
-		JWTAccessExpire: func() int {
-			if raw.JWTAccessExpire == "" {
-				return 7200
-			}
-			v, err := strconv.Atoi(strings.TrimSpace(raw.JWTAccessExpire))
-			if err != nil || v <= 0 {
-				return 7200
-			}
-			return v
-		}(),
-		JWTRefreshExpire: func() int {
-			if raw.JWTRefreshExpire == "" {
-				return 604800
-			}
-			v, err := strconv.Atoi(strings.TrimSpace(raw.JWTRefreshExpire))
-			if err != nil || v <= 0 {
-				return 604800
-			}
-			return v
-		}(),
-		CleanupIntervalMinutes: func() int {
-			if raw.CleanupIntervalMinutes == "" {
-				return 10
-			}
-			v, err := strconv.Atoi(strings.TrimSpace(raw.CleanupIntervalMinutes))
-			if err != nil || v <= 0 {
-				return 10
-			}
-			return v
-		}(),
-		EmailVerifyEnabled: func() bool {
-			if raw.EmailVerifyEnabled == "" {
-				return true
-			}
-			v, _ := strconv.ParseBool(strings.TrimSpace(raw.EmailVerifyEnabled))
-			return v
-		}(),
-		SMSVerifyEnabled: func() bool {
-			if raw.SMSVerifyEnabled == "" {
-				return false
-			}
-			v, _ := strconv.ParseBool(strings.TrimSpace(raw.SMSVerifyEnabled))
-			return v
-		}(),
-		SMSProvider:     raw.SMSProvider,
-		SMSAccessKey:    raw.SMSAccessKey,
-		SMSSecretKey:    raw.SMSSecretKey,
-		SMSSignName:     raw.SMSSignName,
-		SMSTemplateCode: raw.SMSTemplateCode,
-		SMSRegion:       raw.SMSRegion,
+
+	dsn := str(DBUser) + ":" + str(DBPassword) + "@tcp(" + str(DBHost) + ":" + str(DBPort) + ")/" + str(DBName) + "?charset=utf8mb4&parseTime=True&loc=Local"
+
+	return &Config{
+		AppName:                   str(AppName),
+		AppTitle:                  str(AppTitle),
+		AppMode:                   str(AppMode),
+		Port:                      str(Port),
+		DBDriver:                  str(DBDriver),
+		DBDSN:                     dsn,
+		GeetestEnabled:            flag(GeetestEnabled) && geetestID != "" && geetestKey != "",
+		GeetestID:                 geetestID,
+		GeetestKey:                geetestKey,
+		JWTSecret:                 str(JWTSecret),
+		AdminJWTSecret:            adminSecret,
+		AdminPath:                 "/admin",
+		CorsOrigins:               str(CorsOrigins),
+		EnableSwagger:             flag(EnableSwagger),
+		FrontendURL:               str(FrontendURL),
+		SMTPHost:                  strings.TrimSpace(str(SMTPHost)),
+		SMTPPort:                  strings.TrimSpace(str(SMTPPort)),
+		SMTPUser:                  strings.TrimSpace(str(SMTPUser)),
+		SMTPPass:                  str(SMTPPass),
+		SMTPSSL:                   str(SMTPSSLType) == "ssl",
+		SystemEmail:               str(SystemEmail),
+		SystemEmailName:           strings.TrimSpace(str(SystemEmailName)),
+		DKIMDomain:                strings.TrimSpace(str(DKIMDomain)),
+		DKIMSelector:              strings.TrimSpace(str(DKIMSelector)),
+		DKIMPrivateKey:            strings.TrimSpace(str(DKIMPrivateKey)),
+		MailTransport:             strings.ToLower(strings.TrimSpace(str(MailTransport))),
+		MailHTTPURL:               strings.TrimSpace(str(MailHTTPURL)),
+		MailHTTPToken:             strings.TrimSpace(str(MailHTTPToken)),
+		MailFileDir:               strings.TrimSpace(str(MailFileDir)),
+		SMTPPoolSize:              num(SMTPPoolSize),
+		MailWebhookToken:          strings.TrimSpace(str(MailWebhookToken)),
+		RegisterCodeExpireMinutes: num(RegisterCodeExpireMinutes),
+		LoginMaxFailureCount:      num(LoginMaxFailureCount),
+		LoginLockDurationMinutes:  num(LoginLockDurationMinutes),
+		JWTAccessExpire:           num(JWTAccessExpire),
+		JWTRefreshExpire:          num(JWTRefreshExpire),
+		CleanupIntervalMinutes:    num(CleanupIntervalMinutes),
+		EmailVerifyEnabled:        flag(EmailVerifyEnabled),
+		SMSVerifyEnabled:          flag(SMSVerifyEnabled),
+		SMSProvider:               strings.TrimSpace(str(SMSProvider)),
+		SMSAccessKey:              strings.TrimSpace(str(SMSAccessKey)),
+		SMSSecretKey:              strings.TrimSpace(str(SMSSecretKey)),
+		SMSSignName:               strings.TrimSpace(str(SMSSignName)),
+		SMSTemplateCode:           strings.TrimSpace(str(SMSTemplateCode)),
+		SMSTemplates:              strings.TrimSpace(str(SMSTemplates)),
+		SMSAppID:                  strings.TrimSpace(str(SMSAppID)),
+		SMSRegion:                 strings.TrimSpace(str(SMSRegion)),
+		RateLimitRate:             num(RateLimitRate),
+		RateLimitBurst:            num(RateLimitBurst),
+		AuthRateLimitRate:         num(AuthRateLimitRate),
+		AuthRateLimitBurst:        num(AuthRateLimitBurst),
 	}
-	log.Printf("[Config] RegisterCodeExpireMinutes: %d\n", cfg.RegisterCodeExpireMinutes)
-	log.Printf("[Config] LoginMaxFailureCount: %d\n", cfg.LoginMaxFailureCount)
-	log.Printf("[Config] LoginLockDurationMinutes: %d\n", cfg.LoginLockDurationMinutes)
-	return cfg, true
 }
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Key 配置键标识
+// 通过 StringKey / IntKey / BoolKey 获得带类型的读取方法
+type Key interface {
+	Name() string
+}
+
+// StringKey 字符串类型配置键
+type StringKey string
+
+// Name 返回配置键名
+func (k StringKey) Name() string { return string(k) }
+
+// Get 读取当前生效的字符串值
+func (k StringKey) Get() string { return defaultStore.value(string(k)) }
+
+// IntKey 整数类型配置键（非法值或非正数回退到默认值）
+type IntKey string
+
+// Name 返回配置键名
+func (k IntKey) Name() string { return string(k) }
+
+// Get 读取当前生效的整数值
+func (k IntKey) Get() int {
+	return parsePositiveInt(defaultStore.value(string(k)), specDefaultInt(string(k)))
+}
+
+// BoolKey 布尔类型配置键
+type BoolKey string
+
+// Name 返回配置键名
+func (k BoolKey) Name() string { return string(k) }
+
+// Get 读取当前生效的布尔值
+func (k BoolKey) Get() bool { return parseBool(defaultStore.value(string(k))) }
+
+// ========================================
+// 配置键定义
+// ========================================
+
+const (
+	AppName  StringKey = "app_name"
+	AppTitle StringKey = "app_title"
+	AppMode  StringKey = "app_mode"
+	Port     StringKey = "port"
+
+	DBDriver   StringKey = "db_driver"
+	DBHost     StringKey = "db_host"
+	DBPort     StringKey = "db_port"
+	DBUser     StringKey = "db_user"
+	DBPassword StringKey = "db_password"
+	DBName     StringKey = "db_name"
+
+	GeetestEnabled BoolKey   = "geetest_enabled"
+	GeetestID      StringKey = "geetest_captcha_id"
+	GeetestKey     StringKey = "geetest_captcha_key"
+
+	JWTSecret        StringKey = "jwt_secret"
+	JWTAdminSecret   StringKey = "jwt_admin_secret"
+	JWTAccessExpire  IntKey    = "jwt_access_expire"
+	JWTRefreshExpire IntKey    = "jwt_refresh_expire"
+
+	CorsOrigins   StringKey = "cors_origins"
+	EnableSwagger BoolKey   = "enable_swagger"
+	FrontendURL   StringKey = "frontend_url"
+
+	SMTPHost         StringKey = "smtp_host"
+	SMTPPort         StringKey = "smtp_port"
+	SMTPUser         StringKey = "smtp_username"
+	SMTPPass         StringKey = "smtp_password"
+	SMTPSSLType      StringKey = "smtp_ssl_type"
+	SystemEmail      StringKey = "system_email_address"
+	SystemEmailName  StringKey = "system_email_name"
+	DKIMDomain       StringKey = "dkim_domain"
+	DKIMSelector     StringKey = "dkim_selector"
+	DKIMPrivateKey   StringKey = "dkim_private_key"
+	MailTransport    StringKey = "mail_transport"
+	MailHTTPURL      StringKey = "mail_http_url"
+	MailHTTPToken    StringKey = "mail_http_token"
+	MailFileDir      StringKey = "mail_file_dir"
+	SMTPPoolSize     IntKey    = "smtp_pool_size"
+	MailWebhookToken StringKey = "mail_webhook_token"
+
+	RegisterCodeExpireMinutes IntKey = "register_code_expire_minutes"
+	LoginMaxFailureCount      IntKey = "login_max_failure_count"
+	LoginLockDurationMinutes  IntKey = "login_lock_duration_minutes"
+	CleanupIntervalMinutes    IntKey = "cleanup_interval_minutes"
+
+	EmailVerifyEnabled BoolKey   = "email_verify_enabled"
+	SMSVerifyEnabled   BoolKey   = "sms_verify_enabled"
+	SMSProvider        StringKey = "sms_provider"
+	SMSAccessKey       StringKey = "sms_access_key"
+	SMSSecretKey       StringKey = "sms_secret_key"
+	SMSSignName        StringKey = "sms_sign_name"
+	SMSTemplateCode    StringKey = "sms_template_code"
+	SMSTemplates       StringKey = "sms_templates"
+	SMSAppID           StringKey = "sms_app_id"
+	SMSRegion          StringKey = "sms_region"
+
+	SMSPhoneHourlyLimit IntKey = "sms_phone_hourly_limit"
+	SMSPhoneDailyLimit  IntKey = "sms_phone_daily_limit"
+	SMSIPDailyLimit     IntKey = "sms_ip_daily_limit"
+
+	RateLimitRate      IntKey = "rate_limit_rate"
+	RateLimitBurst     IntKey = "rate_limit_burst"
+	AuthRateLimitRate  IntKey = "rate_limit_auth_rate"
+	AuthRateLimitBurst IntKey = "rate_limit_auth_burst"
+
+	ShutdownTimeoutSeconds IntKey = "shutdown_timeout_seconds"
+
+	PluginExternalDir StringKey = "plugin_external_dir"
+
+	RealtimeBroker StringKey = "realtime_broker"
+
+	SecretMasterKey     StringKey = "secret_master_key"
+	SecretMasterKeyFile StringKey = "secret_master_key_file"
+	SecretPreviousKeys  StringKey = "secret_previous_keys"
+)
+
+// keySpec 描述配置键在各层级中的来源
+type keySpec struct {
+	def     string   // 默认值
+	env     []string // 环境变量名（也是 .env 文件中的键名），按顺序取第一个非空值
+	setting string   // system_settings 中的键名，为空表示不可由数据库覆盖
+	// fromSetting 将 system_settings 中的值转换为本键的取值格式，为空则原样使用
+	fromSetting func(string) string
+	// checkSetting 校验 system_settings 中的值，不通过时忽略该值（沿用环境变量或默认值）
+	checkSetting func(string) error
+}
+
+var keySpecs = map[string]keySpec{
+	AppName.Name():  {def: "F.st", env: []string{"APP_NAME"}},
+	AppTitle.Name(): {def: "F.st - Think Fast,Run F.st", env: []string{"APP_TITLE"}},
+	AppMode.Name():  {def: "separate", env: []string{"APP_MODE"}},
+	Port.Name():     {def: "8080", env: []string{"PORT"}},
+
+	DBDriver.Name():   {def: "mysql", env: []string{"DB_DRIVER"}},
+	DBHost.Name():     {def: "127.0.0.1", env: []string{"DB_HOST"}},
+	DBPort.Name():     {def: "3306", env: []string{"DB_PORT"}},
+	DBUser.Name():     {def: "root", env: []string{"DB_USER"}},
+	DBPassword.Name(): {env: []string{"DB_PASSWORD"}},
+	DBName.Name():     {def: "fst_platform", env: []string{"DB_NAME"}},
+
+	GeetestEnabled.Name(): {def: "false", env: []string{"GEETEST_ENABLED", "GEETEST_ENABLE"}, setting: "geetest_enabled"},
+	GeetestID.Name():      {env: []string{"GEETEST_ID", "GEETEST_CAPTCHA_ID"}, setting: "geetest_captcha_id"},
+	GeetestKey.Name():     {env: []string{"GEETEST_KEY", "GEETEST_CAPTCHA_KEY"}, setting: "geetest_captcha_key"},
+
+	JWTSecret.Name():        {def: defaultJWTSecret, env: []string{"JWT_SECRET"}},
+	JWTAdminSecret.Name():   {env: []string{"JWT_ADMIN_SECRET"}},
+	JWTAccessExpire.Name():  {def: "7200", env: []string{"JWT_ACCESS_EXPIRE"}, setting: "jwt_access_expire"},
+	JWTRefreshExpire.Name(): {def: "604800", env: []string{"JWT_REFRESH_EXPIRE"}, setting: "jwt_refresh_expire"},
+
+	CorsOrigins.Name():   {env: []string{"CORS_ORIGINS"}, setting: "cors_origins"},
+	EnableSwagger.Name(): {def: "false", env: []string{"ENABLE_SWAGGER"}},
+	FrontendURL.Name():   {env: []string{"FRONTEND_URL"}, setting: "frontend_url"},
+
+	SMTPHost.Name(): {env: []string{"SMTP_HOST"}, setting: "smtp_host"},
+	SMTPPort.Name(): {def: "587", env: []string{"SMTP_PORT"}, setting: "smtp_port"},
+	SMTPUser.Name(): {env: []string{"SMTP_USERNAME"}, setting: "smtp_username"},
+	SMTPPass.Name(): {env: []string{"SMTP_PASSWORD"}, setting: "smtp_password"},
+	SMTPSSLType.Name(): {env: []string{"SMTP_SSL_TYPE"}, setting: "smtp_ssl", fromSetting: func(v string) string {
+		if parseBool(v) {
+			return "ssl"
+		}
+		return "none"
+	}},
+	SystemEmail.Name():     {env: []string{"SYSTEM_EMAIL_ADDRESS"}},
+	SystemEmailName.Name(): {env: []string{"SYSTEM_EMAIL_NAME"}, setting: "system_email_name"},
+	// DKIM 签名，三项都配置时生效；私钥为 PEM 内容，环境变量中也可以是 PEM 文件路径
+	DKIMDomain.Name():     {env: []string{"DKIM_DOMAIN"}, setting: "dkim_domain"},
+	DKIMSelector.Name():   {env: []string{"DKIM_SELECTOR"}, setting: "dkim_selector"},
+	DKIMPrivateKey.Name(): {env: []string{"DKIM_PRIVATE_KEY"}, setting: "dkim_private_key", checkSetting: checkPEMSetting},
+	// 邮件投递方式：smtp（连接池）、http（HTTP 接口）、file / maildir（写入本地目录，开发测试用，仅可通过环境变量启用）
+	MailTransport.Name(): {def: "smtp", env: []string{"MAIL_TRANSPORT"}, setting: "mail_transport", checkSetting: checkMailTransportSetting},
+	MailHTTPURL.Name():   {env: []string{"MAIL_HTTP_URL"}, setting: "mail_http_url"},
+	MailHTTPToken.Name(): {env: []string{"MAIL_HTTP_TOKEN"}, setting: "mail_http_token"},
+	MailFileDir.Name():   {def: "storage/mail", env: []string{"MAIL_FILE_DIR"}},
+	SMTPPoolSize.Name():  {def: "4", env: []string{"SMTP_POOL_SIZE"}, setting: "smtp_pool_size"},
+	// 退信 / 投诉回调令牌，为空时回调接口不可用
+	MailWebhookToken.Name(): {env: []string{"MAIL_WEBHOOK_TOKEN"}, setting: "mail_webhook_token"},
+
+	RegisterCodeExpireMinutes.Name(): {def: "60", env: []string{"REGISTER_CODE_EXPIRE_MINUTES"}},
+	LoginMaxFailureCount.Name():      {def: "5", env: []string{"LOGIN_MAX_FAILURE_COUNT"}, setting: "login_max_failure"},
+	LoginLockDurationMinutes.Name():  {def: "10", env: []string{"LOGIN_LOCK_DURATION_MINUTES"}, setting: "login_lock_duration"},
+	CleanupIntervalMinutes.Name():    {def: "10", env: []string{"CLEANUP_INTERVAL_MINUTES"}},
+
+	EmailVerifyEnabled.Name(): {def: "true", env: []string{"EMAIL_VERIFY_ENABLED"}, setting: "email_verify_enabled"},
+	SMSVerifyEnabled.Name():   {def: "false", env: []string{"SMS_VERIFY_ENABLED"}, setting: "sms_verify_enabled"},
+	SMSProvider.Name():        {def: "console", env: []string{"SMS_PROVIDER"}, setting: "sms_provider"},
+	SMSAccessKey.Name():       {env: []string{"SMS_ACCESS_KEY"}, setting: "sms_access_key"},
+	SMSSecretKey.Name():       {env: []string{"SMS_SECRET_KEY"}, setting: "sms_secret_key"},
+	SMSSignName.Name():        {env: []string{"SMS_SIGN_NAME"}, setting: "sms_sign_name"},
+	SMSTemplateCode.Name():    {env: []string{"SMS_TEMPLATE_CODE"}, setting: "sms_template_code"},
+	SMSTemplates.Name():       {env: []string{"SMS_TEMPLATES"}, setting: "sms_templates"},
+	SMSAppID.Name():           {env: []string{"SMS_APP_ID"}, setting: "sms_app_id"},
+	SMSRegion.Name():          {env: []string{"SMS_REGION"}, setting: "sms_region"},
+	// 短信验证码发送配额：同一手机号每小时 / 每天、同一 IP 每天最多发送的条数
+	SMSPhoneHourlyLimit.Name(): {def: "5", env: []string{"SMS_PHONE_HOURLY_LIMIT"}, setting: "sms_phone_hourly_limit"},
+	SMSPhoneDailyLimit.Name():  {def: "10", env: []string{"SMS_PHONE_DAILY_LIMIT"}, setting: "sms_phone_daily_limit"},
+	SMSIPDailyLimit.Name():     {def: "20", env: []string{"SMS_IP_DAILY_LIMIT"}, setting: "sms_ip_daily_limit"},
+
+	RateLimitRate.Name():      {def: "100", env: []string{"RATE_LIMIT_RATE"}},
+	RateLimitBurst.Name():     {def: "200", env: []string{"RATE_LIMIT_BURST"}},
+	AuthRateLimitRate.Name():  {def: "5", env: []string{"RATE_LIMIT_AUTH_RATE"}, setting: "rate_limit_auth_rate"},
+	AuthRateLimitBurst.Name(): {def: "10", env: []string{"RATE_LIMIT_AUTH_BURST"}, setting: "rate_limit_auth_burst"},
+
+	ShutdownTimeoutSeconds.Name(): {def: "30", env: []string{"SHUTDOWN_TIMEOUT_SECONDS"}},
+
+	// 进程外插件目录，为空表示不加载进程外插件
+	PluginExternalDir.Name(): {env: []string{"PLUGIN_EXTERNAL_DIR"}},
+
+	// 实时推送的消息中转：local（进程内，单实例）、db（数据库轮询，多实例部署时使用）
+	RealtimeBroker.Name(): {def: "local", env: []string{"REALTIME_BROKER"}},
+
+	// 敏感配置加密主密钥，只能来自环境变量或 .env 文件
+	SecretMasterKey.Name():     {env: []string{"SECRET_MASTER_KEY"}},
+	SecretMasterKeyFile.Name(): {env: []string{"SECRET_MASTER_KEY_FILE"}},
+	SecretPreviousKeys.Name():  {env: []string{"SECRET_PREVIOUS_KEYS"}},
+}
+
+func specDefaultInt(name string) int {
+	v, _ := strconv.Atoi(keySpecs[name].def)
+	return v
+}
+
+func parsePositiveInt(v string, fallback int) int {
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
+
+func parseBool(v string) bool {
+	b, _ := strconv.ParseBool(strings.TrimSpace(v))
+	return b
+}
+
+// checkPEMSetting 后台只能填写 PEM 内容，不能填写服务器上的文件路径
+func checkPEMSetting(v string) error {
+	if !strings.Contains(v, "-----BEGIN") {
+		return errors.New("只能填写 PEM 格式的私钥内容")
+	}
+	return nil
+}
+
+// checkMailTransportSetting 后台只能选择不写入本地文件的投递方式
+func checkMailTransportSetting(v string) error {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "smtp", "http":
+		return nil
+	case "file", "maildir":
+		return errors.New("file 与 maildir 投递方式只能通过环境变量 MAIL_TRANSPORT 配置")
+	default:
+		return errors.New("不支持的邮件投递方式: " + v)
+	}
+}
+
+// ValidateSetting 校验 system_settings 中某一项的取值，空值表示沿用环境变量或默认值，总是合法
+func ValidateSetting(setting, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	for _, spec := range keySpecs {
+		if spec.setting == setting && spec.checkSetting != nil {
+			return spec.checkSetting(value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Layer 配置层级，数值越大优先级越高
+type Layer int
+
+const (
+	LayerDefault  Layer = iota // 内置默认值
+	LayerFile                  // .env 文件（dotenv 或 JSON 格式）
+	LayerEnv                   // 进程环境变量
+	LayerSettings              // system_settings 数据库配置
+	layerCount
+)
+
+// Change 配置变更通知
+type Change struct {
+	Keys []string // 发生变化的配置键
+	Old  Config   // 变更前的配置快照
+	New  Config   // 变更后的配置快照
+}
+
+// Has 判断给定的键中是否有任意一个发生了变化
+func (c Change) Has(keys ...Key) bool {
+	for _, k := range keys {
+		for _, changed := range c.Keys {
+			if changed == k.Name() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type subscriber struct {
+	id   uint64
+	keys []Key
+	fn   func(Change)
+}
+
+// store 分层配置存储
+type store struct {
+	mu       sync.RWMutex
+	layers   [layerCount]map[string]string
+	values   map[string]string // 合并后的生效值
+	subs     []subscriber
+	nextSub  uint64
+	filePath string
+	fileStat fileStamp
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+var defaultStore = newStore()
+
+func newStore() *store {
+	s := &store{values: make(map[string]string)}
+	defaults := make(map[string]string, len(keySpecs))
+	for name, spec := range keySpecs {
+		defaults[name] = spec.def
+	}
+	s.layers[LayerDefault] = defaults
+	for l := LayerFile; l < layerCount; l++ {
+		s.layers[l] = make(map[string]string)
+	}
+	s.values = s.merge()
+	return s
+}
+
+func (s *store) value(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[name]
+}
+
+// merge 按层级从低到高合并，空值不覆盖下层
+func (s *store) merge() map[string]string {
+	merged := make(map[string]string, len(keySpecs))
+	for l := LayerDefault; l < layerCount; l++ {
+		for name, v := range s.layers[l] {
+			if strings.TrimSpace(v) != "" {
+				merged[name] = v
+			}
+		}
+	}
+	return merged
+}
+
+// setLayer 替换某一层的值并返回变化的键
+func (s *store) setLayer(layer Layer, values map[string]string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.layers[layer] = values
+	next := s.merge()
+
+	var changed []string
+	for name := range keySpecs {
+		if next[name] != s.values[name] {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	s.values = next
+	return changed
+}
+
+func (s *store) fileStamp() fileStamp {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fileStat
+}
+
+func (s *store) setFileStamp(stamp fileStamp) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fileStat = stamp
+}
+
+func (s *store) snapshot() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make(map[string]string, len(s.values))
+	for k, v := range s.values {
+		result[k] = v
+	}
+	return result
+}
+
+func (s *store) subscribe(fn func(Change), keys []Key) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextSub++
+	id := s.nextSub
+	s.subs = append(s.subs, subscriber{id: id, keys: keys, fn: fn})
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, sub := range s.subs {
+			if sub.id == id {
+				s.subs = append(s.subs[:i], s.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (s *store) notify(change Change) {
+	s.mu.RLock()
+	subs := make([]subscriber, len(s.subs))
+	copy(subs, s.subs)
+	s.mu.RUnlock()
+
+	for _, sub := range subs {
+		if len(sub.keys) > 0 && !change.Has(sub.keys...) {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("[Config] Subscriber panic: %v", r)
+				}
+			}()
+			sub.fn(change)
+		}()
+	}
+}
+
+// ========================================
+// 层级数据来源
+// ========================================
+
+// readEnvLayer 从进程环境变量读取所有已定义的键
+func readEnvLayer() map[string]string {
+	return resolveEnvNames(func(name string) (string, bool) {
+		return os.LookupEnv(name)
+	})
+}
+
+// readFileLayer 读取 .env 文件，支持 dotenv 与 JSON 两种格式
+// JSON 格式使用小写的环境变量名作为键（如 smtp_host、db_password）
+func readFileLayer(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]string)
+	content := strings.TrimSpace(string(b))
+	if strings.HasPrefix(content, "{") {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(content), &data); err != nil {
+			return nil, fmt.Errorf("invalid JSON .env: %w", err)
+		}
+		for k, v := range data {
+			if v == nil {
+				continue
+			}
+			raw[strings.ToUpper(k)] = strings.TrimSpace(fmt.Sprint(v))
+		}
+	} else {
+		parsed, err := godotenv.Unmarshal(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("invalid .env: %w", err)
+		}
+		raw = parsed
+	}
+
+	return resolveEnvNames(func(name string) (string, bool) {
+		v, ok := raw[name]
+		return v, ok
+	}), nil
+}
+
+// resolveEnvNames 将环境变量名映射到配置键，同一键的多个别名取第一个非空值
+func resolveEnvNames(lookup func(string) (string, bool)) map[string]string {
+	result := make(map[string]string)
+	for name, spec := range keySpecs {
+		for _, envName := range spec.env {
+			if v, ok := lookup(envName); ok && strings.TrimSpace(v) != "" {
+				result[name] = v
+				break
+			}
+		}
+	}
+	return result
+}
+
+// settingsLayerFromMap 将 system_settings 键值映射到配置键
+func settingsLayerFromMap(settings map[string]string) map[string]string {
+	result := make(map[string]string)
+	for name, spec := range keySpecs {
+		if spec.setting == "" {
+			continue
+		}
+		v, ok := settings[spec.setting]
+		if !ok || strings.TrimSpace(v) == "" {
+			continue
+		}
+		if spec.checkSetting != nil {
+			if err := spec.checkSetting(v); err != nil {
+				log.Printf("[Config] Ignored setting %s: %v", spec.setting, err)
+				continue
+			}
+		}
+		if spec.fromSetting != nil {
+			v = spec.fromSetting(v)
+		}
+		result[name] = strings.TrimSpace(v)
+	}
+	return result
+}
+
+func statFile(path string) (fileStamp, bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return fileStamp{}, false
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, true
+}
@@ -12,7 +12,7 @@ var DB *sqlx.DB
 
 func InitDB() {
 	var err error
-	DB, err = sqlx.Connect(config.GlobalConfig.DBDriver, config.GlobalConfig.DBDSN)
+	DB, err = sqlx.Connect(config.Get().DBDriver, config.Get().DBDSN)
 	if err != nil {
 		log.Fatalf("Error connecting to database: %v", err)
 	}
@@ -44,7 +44,8 @@ func Migrate() {
 			group_id BIGINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '分组ID',
 			username VARCHAR(100) NOT NULL COMMENT '用户名',
 			nickname VARCHAR(100) NOT NULL DEFAULT '' COMMENT '昵称',
-			email VARCHAR(150) NOT NULL COMMENT '邮箱',
+			email VARCHAR(150) NOT NULL DEFAULT '' COMMENT '邮箱',
+			email_unique VARCHAR(150) AS (NULLIF(email, '')) VIRTUAL COMMENT '非空邮箱（唯一约束用，手机号注册的用户邮箱为空）',
 			mobile VARCHAR(50) NOT NULL DEFAULT '' COMMENT '手机',
 			avatar VARCHAR(255) NOT NULL DEFAULT '' COMMENT '头像',
 			back_ground VARCHAR(255) NOT NULL DEFAULT '' COMMENT '背景',
@@ -71,7 +72,8 @@ func Migrate() {
 			create_time BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '创建时间',
 			delete_time BIGINT UNSIGNED NULL DEFAULT NULL COMMENT '删除时间',
 			UNIQUE KEY idx_users_username (username),
-			UNIQUE KEY idx_users_email (email),
+			UNIQUE KEY idx_users_email_unique (email_unique),
+			INDEX idx_users_email (email),
 			UNIQUE KEY idx_users_api_key (apikey),
 			INDEX idx_users_mobile (mobile),
 			INDEX idx_users_status (status)
@@ -82,13 +84,15 @@ func Migrate() {
 			subject VARCHAR(255) NOT NULL COMMENT '主题',
 			content TEXT NOT NULL COMMENT '内容',
 			template_name VARCHAR(100) NOT NULL DEFAULT '' COMMENT '模板名称',
-			status TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '状态:0=失败,1=成功',
+			campaign_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '群发任务ID:0=非群发',
+			status TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '状态:0=失败,1=成功,2=跳过',
 			error_msg TEXT COMMENT '错误信息',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
 			INDEX idx_email_logs_to (to_email),
 			INDEX idx_email_logs_status_created (status, created_at),
 			INDEX idx_email_logs_template_name (template_name),
-			INDEX idx_email_logs_created_at (created_at)
+			INDEX idx_email_logs_created_at (created_at),
+			INDEX idx_email_logs_campaign (campaign_id, status)
 		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;`,
 		`CREATE TABLE IF NOT EXISTS email_templates (
 			id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
@@ -106,17 +110,19 @@ func Migrate() {
 		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;`,
 		`CREATE TABLE IF NOT EXISTS verification_codes (
 			id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
-			email VARCHAR(255) NOT NULL COMMENT '邮箱地址',
+			target VARCHAR(255) NOT NULL COMMENT '接收目标:邮箱或手机号',
+			channel VARCHAR(10) NOT NULL DEFAULT 'email' COMMENT '渠道:email=邮件,sms=短信',
 			code VARCHAR(10) NOT NULL COMMENT '验证码',
-			code_type VARCHAR(20) NOT NULL COMMENT '类型:register=注册,reset_password=重置密码',
+			code_type VARCHAR(20) NOT NULL COMMENT '类型:register=注册,login=登录,reset_password=重置密码,change_email/change_phone=换绑',
+			ip VARCHAR(50) NOT NULL DEFAULT '' COMMENT '请求IP',
 			expires_at TIMESTAMP NOT NULL COMMENT '过期时间',
 			is_used TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否已使用:0=未使用,1=已使用',
 			is_deleted TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否软删除:0=正常,1=已删除',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
-			INDEX idx_email_type (email, code_type),
-			INDEX idx_email_type_active_created (email, code_type, is_used, is_deleted, created_at),
-			INDEX idx_email_code_type_active (email, code, code_type, is_used, is_deleted),
+			INDEX idx_target_type_active_created (target, channel, code_type, is_used, is_deleted, created_at),
+			INDEX idx_channel_created (channel, created_at),
+			INDEX idx_ip_channel_created (ip, channel, created_at),
 			INDEX idx_expires_at (expires_at),
 			INDEX idx_is_deleted (is_deleted)
 		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;`,
@@ -173,10 +179,19 @@ func Migrate() {
 	}
 
 	if CheckTableExists("email_logs") {
+		if !CheckColumnExists("email_logs", "campaign_id") {
+			log.Printf("[Init] Adding missing column 'campaign_id' to 'email_logs' table...")
+			_, err := DB.Exec("ALTER TABLE email_logs ADD COLUMN campaign_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '群发任务ID:0=非群发' AFTER template_name")
+			if err != nil {
+				log.Printf("[Init] Failed to add column 'campaign_id': %v", err)
+			}
+		}
+
 		repairs := []indexRepair{
 			{"idx_email_logs_status_created", "ALTER TABLE email_logs ADD INDEX idx_email_logs_status_created (status, created_at)"},
 			{"idx_email_logs_template_name", "ALTER TABLE email_logs ADD INDEX idx_email_logs_template_name (template_name)"},
 			{"idx_email_logs_created_at", "ALTER TABLE email_logs ADD INDEX idx_email_logs_created_at (created_at)"},
+			{"idx_email_logs_campaign", "ALTER TABLE email_logs ADD INDEX idx_email_logs_campaign (campaign_id, status)"},
 		}
 
 		for _, r := range repairs {
@@ -184,10 +199,31 @@ func Migrate() {
 		}
 	}
 
+	if CheckTableExists("users") && !CheckColumnExists("users", "email_unique") {
+		// 邮箱唯一约束改到生成列上，空邮箱（手机号注册）不参与唯一校验
+		log.Printf("[Init] Moving unique email constraint of 'users' to generated column 'email_unique'...")
+		alter_sql := `ALTER TABLE users
+			MODIFY COLUMN email VARCHAR(150) NOT NULL DEFAULT '' COMMENT '邮箱',
+			ADD COLUMN email_unique VARCHAR(150) AS (NULLIF(email, '')) VIRTUAL COMMENT '非空邮箱（唯一约束用，手机号注册的用户邮箱为空）' AFTER email,
+			ADD UNIQUE KEY idx_users_email_unique (email_unique),`
+		if CheckIndexExists("users", "idx_users_email") {
+			alter_sql += `
+			DROP INDEX idx_users_email,`
+		}
+		alter_sql += `
+			ADD INDEX idx_users_email (email)`
+		if _, err := DB.Exec(alter_sql); err != nil {
+			log.Printf("[Init] Failed to add column 'email_unique': %v", err)
+		}
+	}
+
 	if CheckTableExists("verification_codes") {
+		migrateVerificationCodeTarget()
+
 		repairs := []indexRepair{
-			{"idx_email_type_active_created", "ALTER TABLE verification_codes ADD INDEX idx_email_type_active_created (email, code_type, is_used, is_deleted, created_at)"},
-			{"idx_email_code_type_active", "ALTER TABLE verification_codes ADD INDEX idx_email_code_type_active (email, code, code_type, is_used, is_deleted)"},
+			{"idx_target_type_active_created", "ALTER TABLE verification_codes ADD INDEX idx_target_type_active_created (target, channel, code_type, is_used, is_deleted, created_at)"},
+			{"idx_channel_created", "ALTER TABLE verification_codes ADD INDEX idx_channel_created (channel, created_at)"},
+			{"idx_ip_channel_created", "ALTER TABLE verification_codes ADD INDEX idx_ip_channel_created (ip, channel, created_at)"},
 		}
 
 		for _, r := range repairs {
@@ -198,6 +234,37 @@ func Migrate() {
 	log.Println("Database migration completed")
 }
 
+// migrateVerificationCodeTarget 将验证码表的 email 列改为 target + channel，手机号验证码不再借用邮箱列
+func migrateVerificationCodeTarget() {
+	if CheckColumnExists("verification_codes", "email") && !CheckColumnExists("verification_codes", "target") {
+		log.Printf("[Init] Renaming 'verification_codes.email' to 'target' and adding 'channel'...")
+		_, err := DB.Exec(`ALTER TABLE verification_codes
+			CHANGE COLUMN email target VARCHAR(255) NOT NULL COMMENT '接收目标:邮箱或手机号',
+			ADD COLUMN channel VARCHAR(10) NOT NULL DEFAULT 'email' COMMENT '渠道:email=邮件,sms=短信' AFTER target`)
+		if err != nil {
+			log.Printf("[Init] Failed to migrate 'verification_codes.email': %v", err)
+			return
+		}
+		// 此前只有换绑手机号使用短信验证码
+		if _, err := DB.Exec("UPDATE verification_codes SET channel = 'sms' WHERE code_type = 'change_phone'"); err != nil {
+			log.Printf("[Init] Failed to backfill 'verification_codes.channel': %v", err)
+		}
+	}
+	if !CheckColumnExists("verification_codes", "ip") {
+		if _, err := DB.Exec("ALTER TABLE verification_codes ADD COLUMN ip VARCHAR(50) NOT NULL DEFAULT '' COMMENT '请求IP' AFTER code_type"); err != nil {
+			log.Printf("[Init] Failed to add column 'ip' to 'verification_codes': %v", err)
+		}
+	}
+	// 旧索引建立在 email 列上，已被新的 target 索引取代
+	for _, index := range []string{"idx_email_type", "idx_email_type_active_created", "idx_email_code_type_active"} {
+		if CheckIndexExists("verification_codes", index) {
+			if _, err := DB.Exec("ALTER TABLE verification_codes DROP INDEX " + index); err != nil {
+				log.Printf("[Init] Failed to drop index '%s' on 'verification_codes': %v", index, err)
+			}
+		}
+	}
+}
+
 func CheckTableExists(tableName string) bool {
 	var count int
 	query := `SELECT COUNT(*) FROM information_schema.tables 
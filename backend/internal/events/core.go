@@ -0,0 +1,99 @@
+package events
+
+// ========================================
+// 核心事件
+// ========================================
+
+// UserRegistration 注册请求（before 钩子）
+type UserRegistration struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Mobile   string `json:"mobile"` // 手机号注册时有值，此时 Email 为空
+	IP       string `json:"ip"`
+}
+
+// UserRegisteredEvent 用户已注册
+type UserRegisteredEvent struct {
+	UserID   uint64 `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Mobile   string `json:"mobile"`
+}
+
+// UserLoginEvent 用户登录成功
+type UserLoginEvent struct {
+	UserID    uint64 `json:"user_id"`
+	Username  string `json:"username"`
+	AuthGuard string `json:"auth_guard"`
+	IP        string `json:"ip"`
+}
+
+// PaymentOrderRequest 创建支付订单请求（before 钩子）
+type PaymentOrderRequest struct {
+	UserID    uint64  `json:"user_id"`
+	GatewayID uint64  `json:"gateway_id"`
+	PayType   string  `json:"pay_type"`
+	Amount    float64 `json:"amount"`
+}
+
+// PaymentPaidEvent 订单已支付（回调入账或管理员手动完成）
+type PaymentPaidEvent struct {
+	OrderNo string  `json:"order_no"`
+	UserID  uint64  `json:"user_id"`
+	Amount  float64 `json:"amount"`
+	TradeNo string  `json:"trade_no"`
+	Manual  bool    `json:"manual"` // 管理员手动完成
+}
+
+// MoneyChangedEvent 用户余额变动
+type MoneyChangedEvent struct {
+	UserID      uint64  `json:"user_id"`
+	Amount      float64 `json:"amount"` // 正数=加款，负数=扣款
+	BeforeMoney float64 `json:"before_money"`
+	AfterMoney  float64 `json:"after_money"`
+	Memo        string  `json:"memo"`
+}
+
+// SettingsUpdatedEvent 系统配置已更新（不含配置值，敏感项不外泄）
+type SettingsUpdatedEvent struct {
+	Keys []string `json:"keys"`
+}
+
+// EmailSentEvent 邮件发送结果
+type EmailSentEvent struct {
+	To       string `json:"to"`
+	Subject  string `json:"subject"`
+	Template string `json:"template"` // 非模板邮件为空
+	Success  bool   `json:"success"`
+	Error    string `json:"error"`
+}
+
+// EmailFeedbackEvent 收到邮件服务商的退信或投诉
+type EmailFeedbackEvent struct {
+	Email      string `json:"email"`
+	Type       string `json:"type"`     // hard_bounce / soft_bounce / complaint
+	Provider   string `json:"provider"` // 回调的服务商
+	Reason     string `json:"reason"`
+	Suppressed bool   `json:"suppressed"` // 该地址是否已被抑制
+}
+
+var (
+	// UserBeforeRegister 注册前，同步订阅者可否决注册
+	UserBeforeRegister = New[UserRegistration]("user.before_register")
+	// UserRegistered 用户注册成功
+	UserRegistered = New[UserRegisteredEvent]("user.registered")
+	// UserLogin 用户登录成功
+	UserLogin = New[UserLoginEvent]("user.login")
+	// PaymentBeforeCreate 创建支付订单前，同步订阅者可否决下单
+	PaymentBeforeCreate = New[PaymentOrderRequest]("payment.before_create")
+	// PaymentPaid 订单支付成功
+	PaymentPaid = New[PaymentPaidEvent]("payment.paid")
+	// MoneyChanged 用户余额变动
+	MoneyChanged = New[MoneyChangedEvent]("money.changed")
+	// SettingsUpdated 系统配置更新
+	SettingsUpdated = New[SettingsUpdatedEvent]("settings.updated")
+	// EmailSent 邮件发送完成（含失败）
+	EmailSent = New[EmailSentEvent]("email.sent")
+	// EmailFeedback 收到退信或投诉
+	EmailFeedback = New[EmailFeedbackEvent]("email.feedback")
+)
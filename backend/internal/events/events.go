@@ -0,0 +1,227 @@
+// Package events 提供进程内的类型化事件总线
+//
+// 核心服务发布事件，插件在 Init() 中订阅：
+//   - Subscribe 同步订阅：按订阅顺序在发布方的 goroutine 中执行，返回 Reject 的错误即否决事件，
+//     后续订阅者不再执行（"before" 类事件据此拒绝注册、下单等操作）；
+//     其他错误与 panic 只记录日志，不影响发布方
+//   - SubscribeAsync 异步订阅：事件未被否决时在后台执行，错误只记录日志，不影响发布方
+//
+// 插件被禁用或重新初始化时会再次调用 Init()，应在 Shutdown() 中调用 Subscription.Unsubscribe。
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fst/backend/internal/lifecycle"
+	"log"
+	"sync"
+)
+
+// VetoError 同步订阅者否决事件时返回的错误，Message 可直接展示给用户
+type VetoError struct {
+	Event   string
+	Message string
+}
+
+func (e *VetoError) Error() string {
+	return fmt.Sprintf("event %s rejected: %s", e.Event, e.Message)
+}
+
+// Reject 供同步订阅者返回否决错误
+func Reject(message string) error {
+	return &VetoError{Message: message}
+}
+
+// Message 返回否决原因，非否决错误返回空字符串
+func Message(err error) string {
+	var veto *VetoError
+	if errors.As(err, &veto) {
+		return veto.Message
+	}
+	return ""
+}
+
+type subscriber struct {
+	id    uint64
+	sync  func(ctx context.Context, payload any) error
+	async func(ctx context.Context, payload any)
+}
+
+// Bus 事件总线
+type Bus struct {
+	mu     sync.RWMutex
+	nextID uint64
+	subs   map[string][]subscriber
+	known  map[string]bool // 已定义的事件名
+}
+
+// NewBus 创建事件总线
+func NewBus() *Bus {
+	return &Bus{subs: make(map[string][]subscriber), known: make(map[string]bool)}
+}
+
+// declare 登记事件名
+func (b *Bus) declare(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.known[name] = true
+}
+
+// Known 事件是否已在全局总线上定义
+func Known(name string) bool {
+	defaultBus.mu.RLock()
+	defer defaultBus.mu.RUnlock()
+	return defaultBus.known[name]
+}
+
+// SubscribeName 按事件名在全局总线上同步订阅，载荷为事件的原始类型
+// 供无法引用具体类型的订阅者使用（如进程外插件转发），未定义的事件返回错误
+func SubscribeName(name string, fn func(ctx context.Context, payload any) error) (*Subscription, error) {
+	if !Known(name) {
+		return nil, fmt.Errorf("events: unknown event %s", name)
+	}
+	return defaultBus.add(name, subscriber{sync: fn}), nil
+}
+
+// SubscribeNameAsync 按事件名在全局总线上异步订阅
+func SubscribeNameAsync(name string, fn func(ctx context.Context, payload any) error) (*Subscription, error) {
+	if !Known(name) {
+		return nil, fmt.Errorf("events: unknown event %s", name)
+	}
+	return defaultBus.add(name, subscriber{async: func(ctx context.Context, payload any) {
+		if err := fn(ctx, payload); err != nil {
+			log.Printf("[Events] %s async subscriber failed: %v", name, err)
+		}
+	}}), nil
+}
+
+// defaultBus 全局事件总线
+var defaultBus = NewBus()
+
+// Subscription 订阅句柄
+type Subscription struct {
+	bus   *Bus
+	event string
+	id    uint64
+}
+
+// Unsubscribe 取消订阅，可重复调用
+func (s *Subscription) Unsubscribe() {
+	if s == nil || s.bus == nil {
+		return
+	}
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	subs := s.bus.subs[s.event]
+	for i, sub := range subs {
+		if sub.id == s.id {
+			s.bus.subs[s.event] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+}
+
+func (b *Bus) add(event string, sub subscriber) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	sub.id = b.nextID
+	b.subs[event] = append(b.subs[event], sub)
+	return &Subscription{bus: b, event: event, id: sub.id}
+}
+
+// publish 依次执行同步订阅者，未被否决时启动异步订阅者
+func (b *Bus) publish(ctx context.Context, event string, payload any) error {
+	b.mu.RLock()
+	subs := append([]subscriber(nil), b.subs[event]...)
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.sync == nil {
+			continue
+		}
+		err := safeCall(ctx, sub.sync, payload)
+		if err == nil {
+			continue
+		}
+		var veto *VetoError
+		if errors.As(err, &veto) {
+			veto.Event = event
+			return veto
+		}
+		// 只有 Reject 才否决事件；普通错误与 panic 可能带有内部细节，不能展示给用户
+		log.Printf("[Events] %s subscriber failed: %v", event, err)
+	}
+
+	for _, sub := range subs {
+		if sub.async == nil {
+			continue
+		}
+		fn := sub.async
+		if !lifecycle.Go("event:"+event, func(ctx context.Context) { fn(ctx, payload) }) {
+			log.Printf("[Events] %s async subscriber skipped: shutting down", event)
+		}
+	}
+	return nil
+}
+
+func safeCall(ctx context.Context, fn func(context.Context, any) error, payload any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("subscriber panic: %v", r)
+		}
+	}()
+	return fn(ctx, payload)
+}
+
+// Event 类型化事件
+type Event[T any] struct {
+	name string
+	bus  *Bus
+}
+
+// New 在全局总线上定义事件
+func New[T any](name string) Event[T] {
+	return NewOn[T](defaultBus, name)
+}
+
+// NewOn 在指定总线上定义事件（用于测试）
+func NewOn[T any](bus *Bus, name string) Event[T] {
+	bus.declare(name)
+	return Event[T]{name: name, bus: bus}
+}
+
+// Name 事件名称
+func (e Event[T]) Name() string {
+	return e.name
+}
+
+// Subscribe 同步订阅，返回 Reject 的错误即否决事件，其他错误只记录日志
+func (e Event[T]) Subscribe(fn func(ctx context.Context, payload T) error) *Subscription {
+	return e.bus.add(e.name, subscriber{sync: func(ctx context.Context, payload any) error {
+		return fn(ctx, payload.(T))
+	}})
+}
+
+// SubscribeAsync 异步订阅，在后台执行，错误只记录日志
+func (e Event[T]) SubscribeAsync(fn func(ctx context.Context, payload T) error) *Subscription {
+	name := e.name
+	return e.bus.add(e.name, subscriber{async: func(ctx context.Context, payload any) {
+		if err := fn(ctx, payload.(T)); err != nil {
+			log.Printf("[Events] %s async subscriber failed: %v", name, err)
+		}
+	}})
+}
+
+// Publish 发布事件，被同步订阅者否决时返回 *VetoError
+func (e Event[T]) Publish(ctx context.Context, payload T) error {
+	return e.bus.publish(ctx, e.name, payload)
+}
+
+// Emit 发布已发生的事件，否决错误只记录日志（用于 "after" 类事件）
+func (e Event[T]) Emit(ctx context.Context, payload T) {
+	if err := e.Publish(ctx, payload); err != nil {
+		log.Printf("[Events] %v", err)
+	}
+}
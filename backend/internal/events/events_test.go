@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type orderEvent struct {
+	Amount float64
+}
+
+func TestSyncSubscriberVetoStopsLaterSubscribers(t *testing.T) {
+	ev := NewOn[orderEvent](NewBus(), "order.before_create")
+	var calls []string
+	ev.Subscribe(func(ctx context.Context, e orderEvent) error {
+		calls = append(calls, "first")
+		if e.Amount > 100 {
+			return Reject("金额超出限制")
+		}
+		return nil
+	})
+	ev.Subscribe(func(ctx context.Context, e orderEvent) error {
+		calls = append(calls, "second")
+		return nil
+	})
+
+	if err := ev.Publish(context.Background(), orderEvent{Amount: 10}); err != nil {
+		t.Fatalf("publish err = %v", err)
+	}
+	err := ev.Publish(context.Background(), orderEvent{Amount: 500})
+	var veto *VetoError
+	if !errors.As(err, &veto) || veto.Event != "order.before_create" || Message(err) != "金额超出限制" {
+		t.Fatalf("err = %v, want veto with message", err)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("calls = %v, second subscriber should not run after veto", calls)
+	}
+}
+
+func TestPanicAndPlainErrorsAreNotVeto(t *testing.T) {
+	ev := NewOn[orderEvent](NewBus(), "order.before_create")
+	var calls []string
+	ev.Subscribe(func(ctx context.Context, e orderEvent) error { panic("boom") })
+	ev.Subscribe(func(ctx context.Context, e orderEvent) error {
+		calls = append(calls, "plain")
+		return errors.New("dial tcp 10.0.0.5:3306: connection refused")
+	})
+	ev.Subscribe(func(ctx context.Context, e orderEvent) error {
+		calls = append(calls, "last")
+		return nil
+	})
+
+	if err := ev.Publish(context.Background(), orderEvent{}); err != nil {
+		t.Fatalf("err = %v, panic and plain errors must not veto", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("calls = %v, later subscribers should still run", calls)
+	}
+}
+
+func TestAsyncSubscriberAndUnsubscribe(t *testing.T) {
+	ev := NewOn[orderEvent](NewBus(), "order.paid")
+	got := make(chan float64, 2)
+	sub := ev.SubscribeAsync(func(ctx context.Context, e orderEvent) error {
+		got <- e.Amount
+		return nil
+	})
+
+	ev.Emit(context.Background(), orderEvent{Amount: 42})
+	select {
+	case amount := <-got:
+		if amount != 42 {
+			t.Fatalf("amount = %v", amount)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("async subscriber not called")
+	}
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	ev.Emit(context.Background(), orderEvent{Amount: 1})
+	select {
+	case amount := <-got:
+		t.Fatalf("unsubscribed handler received %v", amount)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
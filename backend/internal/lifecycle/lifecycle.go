@@ -0,0 +1,180 @@
+// Package lifecycle 集中管理进程内组件的启动与关闭
+//
+// 组件通过 Append 注册启动/停止钩子，后台任务通过 Go 启动并被跟踪。
+// 关闭时按注册的逆序执行停止钩子（HTTP 服务最后注册，因此最先停止并排空请求），
+// 随后取消根 context 通知后台任务退出，并在截止时间内等待所有任务完成。
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Hook 生命周期钩子，Start 与 Stop 均可为空
+type Hook struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Registry 生命周期注册表
+type Registry struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started bool
+	closed  bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	tasks   sync.WaitGroup
+
+	shutdownOnce sync.Once
+	shutdownCh   chan struct{}
+}
+
+var defaultRegistry = New()
+
+// New 创建生命周期注册表
+func New() *Registry {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Registry{ctx: ctx, cancel: cancel, shutdownCh: make(chan struct{})}
+}
+
+// Append 注册钩子；在 Start 之后注册的钩子只参与关闭
+func (r *Registry) Append(h Hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, h)
+}
+
+// Start 按注册顺序执行启动钩子，任一失败时逆序停止已启动的钩子
+func (r *Registry) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.started {
+		r.mu.Unlock()
+		return errors.New("lifecycle: already started")
+	}
+	r.started = true
+	hooks := append([]Hook(nil), r.hooks...)
+	r.mu.Unlock()
+
+	for i, h := range hooks {
+		if h.Start == nil {
+			continue
+		}
+		if err := h.Start(ctx); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if hooks[j].Stop != nil {
+					if stopErr := hooks[j].Stop(ctx); stopErr != nil {
+						log.Printf("[Lifecycle] Stop %s failed: %v", hooks[j].Name, stopErr)
+					}
+				}
+			}
+			return fmt.Errorf("lifecycle: start %s: %w", h.Name, err)
+		}
+	}
+	return nil
+}
+
+// Context 返回根 context，关闭时被取消
+func (r *Registry) Context() context.Context {
+	return r.ctx
+}
+
+// Go 启动一个被跟踪的后台任务，关闭时会等待其结束
+// 长期运行的任务应在 ctx.Done() 时退出；关闭开始后提交的任务会被拒绝并返回 false
+func (r *Registry) Go(name string, fn func(ctx context.Context)) bool {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		log.Printf("[Lifecycle] Task %s rejected: shutting down", name)
+		return false
+	}
+	r.tasks.Add(1)
+	r.mu.Unlock()
+
+	go func() {
+		defer r.tasks.Done()
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("[Lifecycle] Task %s panic: %v", name, rec)
+			}
+		}()
+		fn(r.ctx)
+	}()
+	return true
+}
+
+// RequestShutdown 请求关闭进程，由主函数通过 ShutdownRequested 监听
+func (r *Registry) RequestShutdown() {
+	r.shutdownOnce.Do(func() { close(r.shutdownCh) })
+}
+
+// ShutdownRequested 返回关闭请求通道
+func (r *Registry) ShutdownRequested() <-chan struct{} {
+	return r.shutdownCh
+}
+
+// Stop 逆序执行停止钩子，取消根 context 并等待后台任务结束
+// ctx 的截止时间同时约束停止钩子与任务等待，超时返回错误
+func (r *Registry) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	hooks := append([]Hook(nil), r.hooks...)
+	r.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.Stop == nil {
+			continue
+		}
+		if err := h.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+		}
+	}
+
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.tasks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("wait background tasks: %w", ctx.Err()))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ========================================
+// 全局注册表
+// ========================================
+
+// Append 向全局注册表注册钩子
+func Append(h Hook) { defaultRegistry.Append(h) }
+
+// Start 启动全局注册表
+func Start(ctx context.Context) error { return defaultRegistry.Start(ctx) }
+
+// Stop 关闭全局注册表
+func Stop(ctx context.Context) error { return defaultRegistry.Stop(ctx) }
+
+// Context 返回全局根 context
+func Context() context.Context { return defaultRegistry.Context() }
+
+// Go 在全局注册表中启动被跟踪的后台任务
+func Go(name string, fn func(ctx context.Context)) bool { return defaultRegistry.Go(name, fn) }
+
+// RequestShutdown 请求关闭进程
+func RequestShutdown() { defaultRegistry.RequestShutdown() }
+
+// ShutdownRequested 返回全局关闭请求通道
+func ShutdownRequested() <-chan struct{} { return defaultRegistry.ShutdownRequested() }
@@ -0,0 +1,78 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStopRunsHooksInReverseAndWaitsForTasks(t *testing.T) {
+	r := New()
+	var order []string
+	for _, name := range []string{"db", "plugins", "http"} {
+		name := name
+		r.Append(Hook{
+			Name:  name,
+			Start: func(context.Context) error { order = append(order, "start "+name); return nil },
+			Stop:  func(context.Context) error { order = append(order, "stop "+name); return nil },
+		})
+	}
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	finished := make(chan struct{})
+	r.Go("ticker", func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		close(finished)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := r.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Stop returned before background task finished")
+	}
+
+	want := []string{"start db", "start plugins", "start http", "stop http", "stop plugins", "stop db"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("unexpected order: %v", order)
+	}
+	if r.Go("late", func(context.Context) {}) {
+		t.Fatal("tasks submitted after Stop should be rejected")
+	}
+}
+
+func TestStopHonorsDeadline(t *testing.T) {
+	r := New()
+	block := make(chan struct{})
+	defer close(block)
+	r.Go("stuck", func(context.Context) { <-block })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := r.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+}
+
+func TestStartFailureStopsStartedHooks(t *testing.T) {
+	r := New()
+	stopped := false
+	r.Append(Hook{Name: "a", Start: func(context.Context) error { return nil }, Stop: func(context.Context) error { stopped = true; return nil }})
+	r.Append(Hook{Name: "b", Start: func(context.Context) error { return errors.New("boom") }})
+
+	if err := r.Start(context.Background()); err == nil {
+		t.Fatal("expected start error")
+	}
+	if !stopped {
+		t.Fatal("already started hooks should be stopped on failure")
+	}
+}
@@ -0,0 +1,190 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dkimHeaders 参与签名的邮件头（存在时才签名）
+var dkimHeaders = []string{
+	"From", "To", "Cc", "Reply-To", "Subject", "Date", "Message-ID",
+	"MIME-Version", "Content-Type", "List-Unsubscribe", "List-Unsubscribe-Post",
+}
+
+// DKIMSigner DKIM 签名（RFC 6376），规范化方式为 relaxed/relaxed
+type DKIMSigner struct {
+	Domain   string
+	Selector string
+	Key      crypto.Signer // *rsa.PrivateKey 或 ed25519.PrivateKey
+}
+
+// NewDKIMSigner 由 PEM 格式的私钥（PKCS#1 或 PKCS#8）创建签名器
+func NewDKIMSigner(domain, selector, keyPEM string) (*DKIMSigner, error) {
+	if domain == "" || selector == "" {
+		return nil, errors.New("dkim: domain and selector are required")
+	}
+	key, err := ParsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &DKIMSigner{Domain: domain, Selector: selector, Key: key}, nil
+}
+
+// ParsePrivateKey 解析 PEM 格式的 RSA / Ed25519 私钥
+func ParsePrivateKey(keyPEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, errors.New("dkim: private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("dkim: parse private key: %w", err)
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("dkim: unsupported key type %T", key)
+}
+
+// Sign 在邮件开头加入 DKIM-Signature 头
+func (s *DKIMSigner) Sign(message []byte) ([]byte, error) {
+	header, body := splitMessage(message)
+	bodyHash := sha256.Sum256(relaxedBody(body))
+
+	var algorithm string
+	switch s.Key.(type) {
+	case *rsa.PrivateKey:
+		algorithm = "rsa-sha256"
+	case ed25519.PrivateKey:
+		algorithm = "ed25519-sha256"
+	default:
+		return nil, fmt.Errorf("dkim: unsupported key type %T", s.Key)
+	}
+
+	fields := parseHeaderFields(header)
+	var signed []string
+	var hashInput bytes.Buffer
+	for _, name := range dkimHeaders {
+		if field, ok := lastHeaderField(fields, name); ok {
+			signed = append(signed, strings.ToLower(name))
+			hashInput.WriteString(relaxedHeader(field) + "\r\n")
+		}
+	}
+
+	value := fmt.Sprintf("v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		algorithm, s.Domain, s.Selector, time.Now().Unix(), strings.Join(signed, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]))
+	hashInput.WriteString(relaxedHeader("DKIM-Signature: " + value))
+
+	digest := sha256.Sum256(hashInput.Bytes())
+	var sig []byte
+	var err error
+	if algorithm == "rsa-sha256" {
+		sig, err = s.Key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	} else {
+		// RFC 8463：Ed25519 对 SHA-256 摘要签名
+		sig, err = s.Key.Sign(rand.Reader, digest[:], crypto.Hash(0))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dkim: sign: %w", err)
+	}
+
+	out := make([]byte, 0, len(message)+len(value)+512)
+	out = append(out, "DKIM-Signature: "+value+foldBase64(base64.StdEncoding.EncodeToString(sig))+"\r\n"...)
+	return append(out, message...), nil
+}
+
+// splitMessage 拆分邮件头与正文（头部包含末尾的 CRLF）
+func splitMessage(message []byte) (header, body []byte) {
+	if i := bytes.Index(message, []byte("\r\n\r\n")); i >= 0 {
+		return message[:i+2], message[i+4:]
+	}
+	return message, nil
+}
+
+// parseHeaderFields 按字段拆分邮件头，保留折行
+func parseHeaderFields(header []byte) []string {
+	var fields []string
+	for _, line := range strings.SplitAfter(string(header), "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1] += line
+			continue
+		}
+		fields = append(fields, line)
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSuffix(fields[i], "\r\n")
+	}
+	return fields
+}
+
+// lastHeaderField 同名头部取最后一个（RFC 6376 5.4.2）
+func lastHeaderField(fields []string, name string) (string, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if k, _, ok := strings.Cut(fields[i], ":"); ok && strings.EqualFold(strings.TrimSpace(k), name) {
+			return fields[i], true
+		}
+	}
+	return "", false
+}
+
+// relaxedHeader relaxed 头部规范化：名称小写，展开折行，连续空白压缩为一个空格，去掉冒号两侧与末尾空白
+func relaxedHeader(field string) string {
+	name, value, _ := strings.Cut(field, ":")
+	value = strings.NewReplacer("\r\n", "", "\n", "").Replace(value)
+	value = strings.Join(strings.FieldsFunc(value, isWSP), " ")
+	return strings.ToLower(strings.TrimSpace(name)) + ":" + value
+}
+
+// relaxedBody relaxed 正文规范化：行内连续空白压缩为一个空格，去掉行尾空白与末尾空行
+func relaxedBody(body []byte) []byte {
+	lines := strings.Split(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		fields := strings.FieldsFunc(line, isWSP)
+		line = strings.Join(fields, " ")
+		if len(fields) > 0 && len(lines[i]) > 0 && isWSP(rune(lines[i][0])) {
+			line = " " + line
+		}
+		lines[i] = line
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+func isWSP(r rune) bool { return r == ' ' || r == '\t' }
+
+// foldBase64 签名值按 72 字符折行，避免超出行长限制
+func foldBase64(s string) string {
+	var b strings.Builder
+	for len(s) > 72 {
+		b.WriteString(s[:72] + "\r\n ")
+		s = s[72:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
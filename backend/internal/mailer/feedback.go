@@ -0,0 +1,251 @@
+package mailer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 退信 / 投诉类型
+const (
+	FeedbackHardBounce = "hard_bounce" // 永久退信：地址不存在、域名无效等
+	FeedbackSoftBounce = "soft_bounce" // 临时退信：邮箱已满、暂时拒收等
+	FeedbackComplaint  = "complaint"   // 收件人标记为垃圾邮件
+)
+
+// Feedback 邮件服务商推送的一条退信或投诉
+type Feedback struct {
+	Email  string
+	Type   string // FeedbackHardBounce / FeedbackSoftBounce / FeedbackComplaint
+	Reason string // 服务商给出的诊断信息
+}
+
+// ErrUnknownFeedbackProvider 不支持的服务商
+var ErrUnknownFeedbackProvider = errors.New("mailer: unknown feedback provider")
+
+// FeedbackProviders 支持解析的服务商
+var FeedbackProviders = []string{"generic", "ses", "sendgrid", "mailgun", "postmark"}
+
+// ParseFeedback 解析服务商的退信 / 投诉回调内容，忽略送达、打开等其他事件
+//
+//   - generic：{"email": "...", "type": "hard_bounce|soft_bounce|complaint", "reason": "..."}，或其数组
+//   - ses：SNS 通知（notificationType / eventType 为 Bounce、Complaint）
+//   - sendgrid：Event Webhook 事件数组（bounce、spamreport）
+//   - mailgun：Webhook（failed、complained）
+//   - postmark：Bounce、SpamComplaint Webhook
+//
+// SES 的订阅确认消息不含事件，返回空列表。
+func ParseFeedback(provider string, body []byte) ([]Feedback, error) {
+	var list []Feedback
+	var err error
+	switch provider {
+	case "generic":
+		list, err = parseGenericFeedback(body)
+	case "ses":
+		list, err = parseSESFeedback(body)
+	case "sendgrid":
+		list, err = parseSendGridFeedback(body)
+	case "mailgun":
+		list, err = parseMailgunFeedback(body)
+	case "postmark":
+		list, err = parsePostmarkFeedback(body)
+	default:
+		return nil, ErrUnknownFeedbackProvider
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mailer: parse %s feedback: %w", provider, err)
+	}
+
+	result := list[:0]
+	for _, f := range list {
+		f.Email = strings.ToLower(strings.TrimSpace(f.Email))
+		if f.Email != "" && f.Type != "" {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// decodeOneOrMany 解析单个对象或对象数组
+func decodeOneOrMany[T any](body []byte) ([]T, error) {
+	body = []byte(strings.TrimSpace(string(body)))
+	if len(body) > 0 && body[0] == '[' {
+		var list []T
+		err := json.Unmarshal(body, &list)
+		return list, err
+	}
+	var one T
+	if err := json.Unmarshal(body, &one); err != nil {
+		return nil, err
+	}
+	return []T{one}, nil
+}
+
+func parseGenericFeedback(body []byte) ([]Feedback, error) {
+	events, err := decodeOneOrMany[struct {
+		Email  string `json:"email"`
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	}](body)
+	if err != nil {
+		return nil, err
+	}
+	var list []Feedback
+	for _, e := range events {
+		typ := e.Type
+		if typ == "bounce" {
+			typ = FeedbackHardBounce
+		}
+		switch typ {
+		case FeedbackHardBounce, FeedbackSoftBounce, FeedbackComplaint:
+			list = append(list, Feedback{Email: e.Email, Type: typ, Reason: e.Reason})
+		}
+	}
+	return list, nil
+}
+
+func parseSESFeedback(body []byte) ([]Feedback, error) {
+	var envelope struct {
+		Type    string `json:"Type"`
+		Message string `json:"Message"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Type == "SubscriptionConfirmation" || envelope.Type == "UnsubscribeConfirmation" {
+		return nil, nil
+	}
+	// SNS 通知的 Message 为 JSON 字符串；直接投递（如 EventBridge）时为通知本身
+	message := []byte(envelope.Message)
+	if envelope.Message == "" {
+		message = body
+	}
+
+	var n struct {
+		NotificationType string `json:"notificationType"`
+		EventType        string `json:"eventType"`
+		Bounce           struct {
+			BounceType        string `json:"bounceType"`
+			BouncedRecipients []struct {
+				EmailAddress   string `json:"emailAddress"`
+				DiagnosticCode string `json:"diagnosticCode"`
+			} `json:"bouncedRecipients"`
+		} `json:"bounce"`
+		Complaint struct {
+			ComplaintFeedbackType string `json:"complaintFeedbackType"`
+			ComplainedRecipients  []struct {
+				EmailAddress string `json:"emailAddress"`
+			} `json:"complainedRecipients"`
+		} `json:"complaint"`
+	}
+	if err := json.Unmarshal(message, &n); err != nil {
+		return nil, err
+	}
+	kind := n.NotificationType
+	if kind == "" {
+		kind = n.EventType
+	}
+
+	var list []Feedback
+	switch kind {
+	case "Bounce":
+		typ := FeedbackSoftBounce
+		if n.Bounce.BounceType == "Permanent" {
+			typ = FeedbackHardBounce
+		}
+		for _, r := range n.Bounce.BouncedRecipients {
+			list = append(list, Feedback{Email: r.EmailAddress, Type: typ, Reason: r.DiagnosticCode})
+		}
+	case "Complaint":
+		for _, r := range n.Complaint.ComplainedRecipients {
+			list = append(list, Feedback{Email: r.EmailAddress, Type: FeedbackComplaint, Reason: n.Complaint.ComplaintFeedbackType})
+		}
+	}
+	return list, nil
+}
+
+func parseSendGridFeedback(body []byte) ([]Feedback, error) {
+	events, err := decodeOneOrMany[struct {
+		Email  string `json:"email"`
+		Event  string `json:"event"`
+		Type   string `json:"type"` // bounce 事件：bounce（永久）或 blocked（临时）
+		Reason string `json:"reason"`
+	}](body)
+	if err != nil {
+		return nil, err
+	}
+	var list []Feedback
+	for _, e := range events {
+		switch e.Event {
+		case "bounce":
+			typ := FeedbackHardBounce
+			if e.Type == "blocked" {
+				typ = FeedbackSoftBounce
+			}
+			list = append(list, Feedback{Email: e.Email, Type: typ, Reason: e.Reason})
+		case "spamreport":
+			list = append(list, Feedback{Email: e.Email, Type: FeedbackComplaint})
+		}
+	}
+	return list, nil
+}
+
+func parseMailgunFeedback(body []byte) ([]Feedback, error) {
+	var payload struct {
+		EventData struct {
+			Event          string `json:"event"`
+			Severity       string `json:"severity"`
+			Recipient      string `json:"recipient"`
+			DeliveryStatus struct {
+				Message     string `json:"message"`
+				Description string `json:"description"`
+			} `json:"delivery-status"`
+		} `json:"event-data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	e := payload.EventData
+	switch e.Event {
+	case "failed":
+		typ := FeedbackSoftBounce
+		if e.Severity == "permanent" {
+			typ = FeedbackHardBounce
+		}
+		reason := e.DeliveryStatus.Description
+		if reason == "" {
+			reason = e.DeliveryStatus.Message
+		}
+		return []Feedback{{Email: e.Recipient, Type: typ, Reason: reason}}, nil
+	case "complained":
+		return []Feedback{{Email: e.Recipient, Type: FeedbackComplaint}}, nil
+	}
+	return nil, nil
+}
+
+func parsePostmarkFeedback(body []byte) ([]Feedback, error) {
+	var e struct {
+		RecordType  string `json:"RecordType"`
+		Type        string `json:"Type"`
+		Email       string `json:"Email"`
+		Description string `json:"Description"`
+		Details     string `json:"Details"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(e.Description + " " + e.Details)
+	switch {
+	case e.RecordType == "SpamComplaint" || e.Type == "SpamComplaint":
+		return []Feedback{{Email: e.Email, Type: FeedbackComplaint, Reason: reason}}, nil
+	case e.RecordType == "Bounce":
+		typ := FeedbackSoftBounce
+		switch e.Type {
+		case "HardBounce", "BadEmailAddress", "ManuallyDeactivated":
+			typ = FeedbackHardBounce
+		}
+		return []Feedback{{Email: e.Email, Type: typ, Reason: reason}}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseFeedback(t *testing.T) {
+	sesBounce, _ := json.Marshal(map[string]string{
+		"Type": "Notification",
+		"Message": `{"notificationType":"Bounce","bounce":{"bounceType":"Permanent",` +
+			`"bouncedRecipients":[{"emailAddress":"Dead@Example.com","diagnosticCode":"smtp; 550 5.1.1 user unknown"}]}}`,
+	})
+	sesComplaint, _ := json.Marshal(map[string]string{
+		"Type":    "Notification",
+		"Message": `{"notificationType":"Complaint","complaint":{"complaintFeedbackType":"abuse","complainedRecipients":[{"emailAddress":"c@example.com"}]}}`,
+	})
+
+	cases := []struct {
+		provider string
+		body     string
+		want     []Feedback
+	}{
+		{"generic", `{"email":"a@example.com","type":"bounce","reason":"gone"}`,
+			[]Feedback{{"a@example.com", FeedbackHardBounce, "gone"}}},
+		{"generic", `[{"email":"a@example.com","type":"soft_bounce"},{"email":"b@example.com","type":"delivered"}]`,
+			[]Feedback{{"a@example.com", FeedbackSoftBounce, ""}}},
+		{"ses", string(sesBounce),
+			[]Feedback{{"dead@example.com", FeedbackHardBounce, "smtp; 550 5.1.1 user unknown"}}},
+		{"ses", string(sesComplaint),
+			[]Feedback{{"c@example.com", FeedbackComplaint, "abuse"}}},
+		{"ses", `{"Type":"SubscriptionConfirmation","SubscribeURL":"https://sns.example.com/confirm"}`, nil},
+		{"ses", `{"eventType":"Bounce","bounce":{"bounceType":"Transient","bouncedRecipients":[{"emailAddress":"full@example.com"}]}}`,
+			[]Feedback{{"full@example.com", FeedbackSoftBounce, ""}}},
+		{"sendgrid", `[{"email":"a@example.com","event":"bounce","type":"bounce","reason":"550"},` +
+			`{"email":"b@example.com","event":"bounce","type":"blocked"},{"email":"c@example.com","event":"spamreport"},` +
+			`{"email":"d@example.com","event":"delivered"}]`,
+			[]Feedback{{"a@example.com", FeedbackHardBounce, "550"}, {"b@example.com", FeedbackSoftBounce, ""}, {"c@example.com", FeedbackComplaint, ""}}},
+		{"mailgun", `{"signature":{},"event-data":{"event":"failed","severity":"permanent","recipient":"a@example.com","delivery-status":{"message":"550 no such user"}}}`,
+			[]Feedback{{"a@example.com", FeedbackHardBounce, "550 no such user"}}},
+		{"mailgun", `{"event-data":{"event":"complained","recipient":"a@example.com"}}`,
+			[]Feedback{{"a@example.com", FeedbackComplaint, ""}}},
+		{"mailgun", `{"event-data":{"event":"opened","recipient":"a@example.com"}}`, nil},
+		{"postmark", `{"RecordType":"Bounce","Type":"HardBounce","Email":"a@example.com","Description":"unknown user"}`,
+			[]Feedback{{"a@example.com", FeedbackHardBounce, "unknown user"}}},
+		{"postmark", `{"RecordType":"SpamComplaint","Type":"SpamComplaint","Email":"a@example.com"}`,
+			[]Feedback{{"a@example.com", FeedbackComplaint, ""}}},
+	}
+	for _, tc := range cases {
+		got, err := ParseFeedback(tc.provider, []byte(tc.body))
+		if err != nil {
+			t.Errorf("%s %s: %v", tc.provider, tc.body, err)
+			continue
+		}
+		if len(got) == 0 && len(tc.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s %s:\n got %+v\nwant %+v", tc.provider, tc.body, got, tc.want)
+		}
+	}
+
+	if _, err := ParseFeedback("unknown", []byte(`{}`)); err != ErrUnknownFeedbackProvider {
+		t.Errorf("unknown provider = %v", err)
+	}
+	if _, err := ParseFeedback("sendgrid", []byte(`not json`)); err == nil {
+		t.Error("invalid body accepted")
+	}
+}
@@ -0,0 +1,298 @@
+package mailer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// smtpSink 本地 SMTP 收件服务，记录收到的邮件
+type smtpSink struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	messages []sinkMessage
+	conns    int // 建立过的连接数
+}
+
+type sinkMessage struct {
+	Auth string
+	From string
+	To   []string
+	Data []byte
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpSink{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *smtpSink) config() SMTPConfig {
+	host, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	return SMTPConfig{Host: host, Port: port, Username: "user", Password: "pass", Timeout: 5 * time.Second}
+}
+
+func (s *smtpSink) received() []sinkMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sinkMessage(nil), s.messages...)
+}
+
+func (s *smtpSink) connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	s.mu.Lock()
+	s.conns++
+	s.mu.Unlock()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 sink ESMTP")
+	var cur sinkMessage
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250-sink")
+			tp.PrintfLine("250 AUTH PLAIN LOGIN")
+		case "AUTH":
+			cur.Auth = arg
+			tp.PrintfLine("235 ok")
+		case "MAIL":
+			cur.From = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			cur.To = append(cur.To, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			cur.Data = data
+			s.mu.Lock()
+			s.messages = append(s.messages, cur)
+			s.mu.Unlock()
+			cur = sinkMessage{Auth: cur.Auth}
+			tp.PrintfLine("250 queued")
+		case "RSET", "NOOP":
+			tp.PrintfLine("250 ok")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 unknown")
+		}
+	}
+}
+
+func testMessage() *Message {
+	msg := &Message{
+		From:            "F.st 通知 <noreply@example.com>",
+		To:              []string{"张三 <a@example.com>", "b@example.com"},
+		Cc:              []string{"c@example.com"},
+		Bcc:             []string{"hidden@example.com", "A@example.com"},
+		ReplyTo:         []string{"support@example.com"},
+		Subject:         "订单通知",
+		Text:            "hello\nworld",
+		HTML:            "<p>hello</p>",
+		ListUnsubscribe: []string{"https://example.com/u?t=1", "mailto:unsub@example.com"},
+		Headers:         map[string]string{"x-campaign": "42"},
+	}
+	cid := msg.Embed("logo.png", "image/png", []byte("PNGDATA"))
+	msg.HTML += `<img src="` + cid + `">`
+	msg.Attach("报表.csv", "", []byte("a,b\n1,2\n"))
+	return msg
+}
+
+func TestBuild(t *testing.T) {
+	msg := testMessage()
+	data, err := msg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := parsed.Header
+
+	if h.Get("Bcc") != "" || bytes.Contains(data, []byte("hidden@example.com")) {
+		t.Error("bcc leaked into headers")
+	}
+	if to, _ := h.AddressList("To"); len(to) != 2 || to[0].Name != "张三" {
+		t.Errorf("To = %v", h.Get("To"))
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(h.Get("Subject")); subject != "订单通知" {
+		t.Errorf("Subject = %q", subject)
+	}
+	if _, err := h.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+	if id := h.Get("Message-ID"); id != msg.MessageID || !strings.HasSuffix(id, "@example.com>") {
+		t.Errorf("Message-ID = %q", id)
+	}
+	if h.Get("Reply-To") != "<support@example.com>" || h.Get("X-Campaign") != "42" {
+		t.Errorf("headers = %v", h)
+	}
+	if h.Get("List-Unsubscribe") != "<https://example.com/u?t=1>, <mailto:unsub@example.com>" ||
+		h.Get("List-Unsubscribe-Post") != "List-Unsubscribe=One-Click" {
+		t.Errorf("List-Unsubscribe = %q / %q", h.Get("List-Unsubscribe"), h.Get("List-Unsubscribe-Post"))
+	}
+
+	// mixed → [related → [alternative → [text, html], image], attachment]
+	mixed := readParts(t, h.Get("Content-Type"), parsed.Body, "multipart/mixed")
+	if len(mixed) != 2 {
+		t.Fatalf("mixed parts = %d", len(mixed))
+	}
+	related := readParts(t, mixed[0].header.Get("Content-Type"), bytes.NewReader(mixed[0].body), "multipart/related")
+	alternative := readParts(t, related[0].header.Get("Content-Type"), bytes.NewReader(related[0].body), "multipart/alternative")
+	if string(alternative[0].body) != "hello\r\nworld" || !strings.HasPrefix(alternative[0].header.Get("Content-Type"), "text/plain") {
+		t.Errorf("text part = %q", alternative[0].body)
+	}
+	if !strings.Contains(string(alternative[1].body), `<img src="cid:`) {
+		t.Errorf("html part = %q", alternative[1].body)
+	}
+
+	image := related[1]
+	if cid := image.header.Get("Content-Id"); !strings.HasSuffix(cid, "@logo.png>") || !strings.Contains(msg.HTML, strings.Trim(cid, "<>")) {
+		t.Errorf("Content-ID = %q", cid)
+	}
+	if decoded, _ := base64.StdEncoding.DecodeString(string(image.body)); string(decoded) != "PNGDATA" {
+		t.Errorf("image = %q", image.body)
+	}
+	if !strings.HasPrefix(image.header.Get("Content-Disposition"), "inline") {
+		t.Errorf("image disposition = %q", image.header.Get("Content-Disposition"))
+	}
+
+	attachment := mixed[1]
+	_, params, _ := mime.ParseMediaType(attachment.header.Get("Content-Disposition"))
+	if params["filename"] != "报表.csv" || !strings.HasPrefix(attachment.header.Get("Content-Type"), "text/csv") {
+		t.Errorf("attachment headers = %v", attachment.header)
+	}
+	if decoded, _ := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(attachment.body), "\r\n", "")); string(decoded) != "a,b\n1,2\n" {
+		t.Errorf("attachment = %q", attachment.body)
+	}
+
+	if rcpts, _ := msg.Recipients(); strings.Join(rcpts, ",") != "a@example.com,b@example.com,c@example.com,hidden@example.com" {
+		t.Errorf("Recipients = %v", rcpts)
+	}
+}
+
+func TestBuildSinglePart(t *testing.T) {
+	data, err := (&Message{From: "a@example.com", To: []string{"b@example.com"}, Subject: "hi", HTML: "<p>x</p>"}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := mail.ReadMessage(bytes.NewReader(data))
+	if ct := parsed.Header.Get("Content-Type"); ct != "text/html; charset=UTF-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	if _, err := (&Message{From: "a@example.com", Subject: "x"}).Build(); err != ErrNoRecipients {
+		t.Errorf("no recipients = %v", err)
+	}
+	injected := &Message{From: "a@example.com", To: []string{"b@example.com"}, Subject: "x", Headers: map[string]string{"X-Test": "a\r\nBcc: evil@example.com"}}
+	if _, err := injected.Build(); err == nil {
+		t.Error("header injection accepted")
+	}
+}
+
+func TestSendSMTP(t *testing.T) {
+	sink := newSMTPSink(t)
+	msg := testMessage()
+	data, err := msg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, _ := msg.FromAddress()
+	rcpts, _ := msg.Recipients()
+	if err := SendSMTP(sink.config(), from, rcpts, data); err != nil {
+		t.Fatal(err)
+	}
+
+	got := sink.received()
+	if len(got) != 1 {
+		t.Fatalf("received %d messages", len(got))
+	}
+	auth, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(got[0].Auth, "PLAIN "))
+	if string(auth) != "\x00user\x00pass" {
+		t.Errorf("auth = %q", got[0].Auth)
+	}
+	if got[0].From != "noreply@example.com" || len(got[0].To) != 4 || got[0].To[3] != "hidden@example.com" {
+		t.Errorf("envelope = %s %v", got[0].From, got[0].To)
+	}
+	if !bytes.Equal(bytes.ReplaceAll(got[0].Data, []byte("\n"), []byte("\r\n")), data) {
+		t.Error("data mismatch")
+	}
+}
+
+type mimePart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// readParts 读取 multipart 的各部分（quoted-printable 已由 multipart.Reader 解码）
+func readParts(t *testing.T, contentType string, r io.Reader, want string) []mimePart {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != want {
+		t.Fatalf("content type = %q, want %s", contentType, want)
+	}
+	mr := multipart.NewReader(bufio.NewReader(r), params["boundary"])
+	var parts []mimePart
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(p)
+		parts = append(parts, mimePart{header: p.Header, body: bytes.TrimRight(body, "\r\n")})
+	}
+}
+
+func TestLoginAuthRequiresTLS(t *testing.T) {
+	auth := LoginAuth("user", "pass")
+	if _, _, err := auth.Start(&smtp.ServerInfo{Name: "mail.example.com", TLS: false}); err == nil {
+		t.Fatal("LOGIN auth should refuse an unencrypted connection")
+	}
+	if mech, resp, err := auth.Start(&smtp.ServerInfo{Name: "mail.example.com", TLS: true}); err != nil || mech != "LOGIN" || string(resp) != "user" {
+		t.Fatalf("Start over TLS = %q %q %v", mech, resp, err)
+	}
+}
@@ -0,0 +1,358 @@
+// Package mailer 构建与发送 MIME 邮件
+//
+//   - Message 描述一封邮件：收件人（To/Cc/Bcc）、Reply-To、纯文本与 HTML 正文、附件、内嵌图片
+//   - Build 生成符合 RFC 5322 / 2045 的邮件内容，结构为
+//     multipart/mixed（附件）→ multipart/related（内嵌图片）→ multipart/alternative（纯文本 + HTML），
+//     只有一种正文且没有附件时不使用 multipart
+//   - DKIMSigner 对生成的邮件签名（rsa-sha256 / ed25519-sha256，relaxed/relaxed）
+//   - SendSMTP 通过 SMTP 投递（隐式 TLS 或 STARTTLS）
+//   - MailTransport 可替换的投递方式：SMTPPool（连接池）、HTTPTransport（HTTP 接口）、FileTransport（本地文件 / Maildir）
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Attachment 附件或内嵌图片
+type Attachment struct {
+	Filename    string
+	ContentType string // 为空时按扩展名推断
+	Data        []byte
+	ContentID   string // 内嵌图片的 Content-ID，HTML 中以 cid:xxx 引用；为空表示普通附件
+}
+
+// Message 邮件
+type Message struct {
+	From    string   // 发件人，如 "F.st <noreply@example.com>"
+	To      []string // 收件人
+	Cc      []string // 抄送
+	Bcc     []string // 密送，只出现在投递信封中，不写入邮件头
+	ReplyTo []string
+
+	Subject string
+	Text    string // 纯文本正文
+	HTML    string // HTML 正文
+
+	Attachments []Attachment
+
+	// ListUnsubscribe 退订地址（https:// 或 mailto:），https 地址同时声明 RFC 8058 一键退订
+	ListUnsubscribe []string
+	// Headers 其他邮件头
+	Headers map[string]string
+
+	MessageID string    // 为空时自动生成
+	Date      time.Time // 为空时使用当前时间
+}
+
+// ErrNoRecipients 邮件没有收件人
+var ErrNoRecipients = errors.New("mailer: no recipients")
+
+// Attach 添加附件
+func (m *Message) Attach(filename, contentType string, data []byte) {
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, ContentType: contentType, Data: data})
+}
+
+// Embed 添加内嵌图片，返回 HTML 中引用的地址（cid:xxx）
+func (m *Message) Embed(filename, contentType string, data []byte) string {
+	cid := randomHex(8) + "@" + filenameToken(filename)
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, ContentType: contentType, Data: data, ContentID: cid})
+	return "cid:" + cid
+}
+
+// Recipients 投递信封中的全部收件人地址（To + Cc + Bcc，去重）
+func (m *Message) Recipients() ([]string, error) {
+	seen := map[string]bool{}
+	var list []string
+	for _, group := range [][]string{m.To, m.Cc, m.Bcc} {
+		addrs, err := parseAddressList(group)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			key := strings.ToLower(addr.Address)
+			if !seen[key] {
+				seen[key] = true
+				list = append(list, addr.Address)
+			}
+		}
+	}
+	if len(list) == 0 {
+		return nil, ErrNoRecipients
+	}
+	return list, nil
+}
+
+// FromAddress 发件人邮箱地址（用作信封发件人）
+func (m *Message) FromAddress() (string, error) {
+	addr, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return "", fmt.Errorf("mailer: invalid from %q: %w", m.From, err)
+	}
+	return addr.Address, nil
+}
+
+// Build 生成邮件内容（CRLF 换行）；未设置的 Message-ID 与 Date 会回填到 m 中
+func (m *Message) Build() ([]byte, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: invalid from %q: %w", m.From, err)
+	}
+	if _, err := m.Recipients(); err != nil {
+		return nil, err
+	}
+	if m.Date.IsZero() {
+		m.Date = time.Now()
+	}
+	if m.MessageID == "" {
+		m.MessageID = fmt.Sprintf("<%d.%s@%s>", m.Date.UnixNano(), randomHex(8), domainOf(from.Address))
+	}
+
+	var buf bytes.Buffer
+	writeHeader := func(name, value string) error {
+		if strings.ContainsAny(name+value, "\r\n") {
+			return fmt.Errorf("mailer: header %s contains line break", name)
+		}
+		buf.WriteString(name + ": " + value + "\r\n")
+		return nil
+	}
+
+	headers := [][2]string{{"From", from.String()}}
+	for _, h := range []struct {
+		name string
+		list []string
+	}{{"To", m.To}, {"Cc", m.Cc}, {"Reply-To", m.ReplyTo}} {
+		if len(h.list) == 0 {
+			continue
+		}
+		value, err := formatAddressList(h.list)
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, [2]string{h.name, value})
+	}
+	headers = append(headers,
+		[2]string{"Subject", mime.BEncoding.Encode("UTF-8", m.Subject)},
+		[2]string{"Date", m.Date.Format(time.RFC1123Z)},
+		[2]string{"Message-ID", m.MessageID},
+		[2]string{"MIME-Version", "1.0"},
+	)
+	if len(m.ListUnsubscribe) > 0 {
+		links := make([]string, len(m.ListUnsubscribe))
+		oneClick := false
+		for i, link := range m.ListUnsubscribe {
+			links[i] = "<" + link + ">"
+			oneClick = oneClick || strings.HasPrefix(link, "https://")
+		}
+		headers = append(headers, [2]string{"List-Unsubscribe", strings.Join(links, ", ")})
+		if oneClick {
+			headers = append(headers, [2]string{"List-Unsubscribe-Post", "List-Unsubscribe=One-Click"})
+		}
+	}
+	extra := make([]string, 0, len(m.Headers))
+	for name := range m.Headers {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		headers = append(headers, [2]string{textproto.CanonicalMIMEHeaderKey(name), m.Headers[name]})
+	}
+	for _, h := range headers {
+		if err := writeHeader(h[0], h[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	body := m.bodyPart()
+	for _, name := range sortedHeaderKeys(body.header) {
+		buf.WriteString(name + ": " + body.header.Get(name) + "\r\n")
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.body)
+	return buf.Bytes(), nil
+}
+
+// part MIME 节点：头部与已编码的内容
+type part struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// bodyPart 按正文、内嵌图片与附件组合邮件主体
+func (m *Message) bodyPart() part {
+	var content part
+	switch {
+	case m.Text != "" && m.HTML != "":
+		content = multipartOf("alternative", textPart("text/plain", m.Text), textPart("text/html", m.HTML))
+	case m.HTML != "":
+		content = textPart("text/html", m.HTML)
+	default:
+		content = textPart("text/plain", m.Text)
+	}
+
+	var inline, attached []part
+	for _, a := range m.Attachments {
+		if a.ContentID != "" {
+			inline = append(inline, attachmentPart(a))
+		} else {
+			attached = append(attached, attachmentPart(a))
+		}
+	}
+	if len(inline) > 0 {
+		content = multipartOf("related", append([]part{content}, inline...)...)
+	}
+	if len(attached) > 0 {
+		content = multipartOf("mixed", append([]part{content}, attached...)...)
+	}
+	return content
+}
+
+func textPart(contentType, text string) part {
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	w.Write([]byte(normalizeNewlines(text)))
+	w.Close()
+	return part{
+		header: textproto.MIMEHeader{
+			"Content-Type":              {contentType + "; charset=UTF-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		},
+		body: buf.Bytes(),
+	}
+}
+
+func attachmentPart(a Attachment) part {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(a.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+	if a.Filename != "" {
+		params["name"] = a.Filename
+	}
+
+	disposition := "attachment"
+	header := textproto.MIMEHeader{}
+	if a.ContentID != "" {
+		disposition = "inline"
+		header.Set("Content-ID", "<"+a.ContentID+">")
+	}
+	dispositionParams := map[string]string{}
+	if a.Filename != "" {
+		dispositionParams["filename"] = a.Filename
+	}
+	header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, dispositionParams))
+	header.Set("Content-Transfer-Encoding", "base64")
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	var buf bytes.Buffer
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return part{header: header, body: buf.Bytes()}
+}
+
+func multipartOf(subtype string, parts ...part) part {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		pw, _ := w.CreatePart(p.header)
+		pw.Write(p.body)
+	}
+	w.Close()
+	return part{
+		header: textproto.MIMEHeader{"Content-Type": {fmt.Sprintf("multipart/%s; boundary=%q", subtype, w.Boundary())}},
+		body:   buf.Bytes(),
+	}
+}
+
+func parseAddressList(list []string) ([]*mail.Address, error) {
+	addrs := make([]*mail.Address, 0, len(list))
+	for _, s := range list {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("mailer: invalid address %q: %w", s, err)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+func formatAddressList(list []string) (string, error) {
+	addrs, err := parseAddressList(list)
+	if err != nil {
+		return "", err
+	}
+	out := make([]string, len(addrs))
+	for i, addr := range addrs {
+		out[i] = addr.String()
+	}
+	return strings.Join(out, ", "), nil
+}
+
+func sortedHeaderKeys(h textproto.MIMEHeader) []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeNewlines 统一为 CRLF
+func normalizeNewlines(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "\r\n")
+}
+
+func domainOf(addr string) string {
+	if i := strings.LastIndex(addr, "@"); i >= 0 && i < len(addr)-1 {
+		return addr[i+1:]
+	}
+	return "localhost"
+}
+
+// filenameToken Content-ID 中使用的文件名部分，只保留安全字符
+func filenameToken(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r < 128 && (r == '.' || r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "inline"
+	}
+	return b.String()
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
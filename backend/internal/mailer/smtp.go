@@ -0,0 +1,163 @@
+package mailer
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// SMTPConfig SMTP 服务器配置
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string // 为空或服务器不支持 AUTH 时不认证
+	Password string
+	SSL      bool // true：隐式 TLS（465）；false：服务器支持时使用 STARTTLS
+	Timeout  time.Duration
+
+	// TLSConfig 为空时按 Host 校验证书
+	TLSConfig *tls.Config
+}
+
+func (c SMTPConfig) tlsConfig() *tls.Config {
+	if c.TLSConfig != nil {
+		return c.TLSConfig
+	}
+	return &tls.Config{ServerName: c.Host}
+}
+
+func (c SMTPConfig) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return c.Timeout
+}
+
+// DialSMTP 连接 SMTP 服务器并完成 TLS 协商与认证
+func DialSMTP(cfg SMTPConfig) (*smtp.Client, error) {
+	client, _, err := dialSMTP(cfg)
+	return client, err
+}
+
+// dialSMTP 与 DialSMTP 相同，同时返回底层连接以便设置读写期限
+// 握手（问候、EHLO、STARTTLS、认证）受 Timeout 限制，返回前清除期限
+func dialSMTP(cfg SMTPConfig) (*smtp.Client, net.Conn, error) {
+	timeout := cfg.timeout()
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+	if cfg.SSL {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, cfg.tlsConfig())
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("SMTP客户端创建失败: %w", err)
+	}
+	if !cfg.SSL {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(cfg.tlsConfig()); err != nil {
+				client.Close()
+				return nil, nil, fmt.Errorf("STARTTLS 失败: %w", err)
+			}
+		}
+	}
+
+	if ok, _ := client.Extension("AUTH"); ok && cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			// PlainAuth 失败时回退到 LOGIN 认证（兼容 Yandex 等邮件服务商）
+			// 仅在加密连接（隐式 SSL 或 STARTTLS 成功）上回退，避免明文发送密码
+			if _, secure := client.TLSConnectionState(); !secure {
+				client.Close()
+				return nil, nil, fmt.Errorf("认证失败: %w", err)
+			}
+			if err = client.Auth(LoginAuth(cfg.Username, cfg.Password)); err != nil {
+				client.Close()
+				return nil, nil, fmt.Errorf("认证失败: %w", err)
+			}
+		}
+	}
+	conn.SetDeadline(time.Time{})
+	return client, conn, nil
+}
+
+// Deliver 通过已建立的连接投递一封邮件
+func Deliver(client *smtp.Client, from string, to []string, data []byte) error {
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("MAIL FROM 失败: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("RCPT TO %s 失败: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA命令失败: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("写入邮件内容失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("完成数据传输失败: %w", err)
+	}
+	return nil
+}
+
+// SendSMTP 建立连接、投递一封邮件后断开
+func SendSMTP(cfg SMTPConfig, from string, to []string, data []byte) error {
+	client, conn, err := dialSMTP(cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	conn.SetDeadline(time.Now().Add(cfg.timeout()))
+	if err := Deliver(client, from, to, data); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// loginAuth 实现 LOGIN 认证方式
+// Go 内置的 PlainAuth 在 tls.Dial 隐式 SSL 连接上会误判为非加密连接
+type loginAuth struct {
+	username, password string
+}
+
+// LoginAuth LOGIN 认证
+func LoginAuth(username, password string) smtp.Auth {
+	return &loginAuth{username, password}
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("LOGIN 认证需要加密连接")
+	}
+	return "LOGIN", []byte(a.username), nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		switch string(fromServer) {
+		case "Username:":
+			return []byte(a.username), nil
+		case "Password:":
+			return []byte(a.password), nil
+		default:
+			return nil, fmt.Errorf("unknown LOGIN challenge: %s", fromServer)
+		}
+	}
+	return nil, nil
+}
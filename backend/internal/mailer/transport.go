@@ -0,0 +1,322 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MailTransport 邮件投递方式
+//
+// Send 投递一封已生成（并签名）的邮件；实现需支持并发调用。
+// 失败时直接返回错误，不在调用方 goroutine 上等待重试，重试由队列负责。
+type MailTransport interface {
+	Name() string
+	Send(ctx context.Context, env *Envelope) error
+	Close() error
+}
+
+// Envelope 投递信封：信封发件人、全部收件人与邮件内容
+type Envelope struct {
+	From    string
+	To      []string // To + Cc + Bcc
+	Data    []byte   // Build（及 DKIM 签名）后的邮件内容
+	Message *Message // 生成 Data 的邮件，HTTP 接口用于提取主题与正文，可为空
+}
+
+// NewEnvelope 由邮件与已生成的内容创建投递信封
+func NewEnvelope(msg *Message, data []byte) (*Envelope, error) {
+	from, err := msg.FromAddress()
+	if err != nil {
+		return nil, err
+	}
+	rcpts, err := msg.Recipients()
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{From: from, To: rcpts, Data: data, Message: msg}, nil
+}
+
+// ErrTransportClosed 投递方式已关闭
+var ErrTransportClosed = errors.New("mailer: transport closed")
+
+// SMTPPool 复用 SMTP 连接的投递方式（隐式 TLS 或 STARTTLS 由 SMTPConfig.SSL 决定）
+//
+// 最多同时保持 Size 个连接，空闲超过 IdleTimeout 的连接在下次取用时关闭。
+// 复用的连接先发送 RSET 检查可用性，投递失败时关闭该连接并用新连接重试一次。
+// 每次投递为连接设置读写期限（ctx 的截止时间，未设置时为 SMTPConfig.Timeout），服务器无响应时不会一直阻塞。
+type SMTPPool struct {
+	cfg         SMTPConfig
+	idleTimeout time.Duration
+
+	slots  chan struct{} // 连接数上限
+	mu     sync.Mutex
+	idle   []*pooledClient
+	closed bool
+}
+
+type pooledClient struct {
+	client   *smtp.Client
+	conn     net.Conn
+	lastUsed time.Time
+}
+
+// NewSMTPPool 创建 SMTP 连接池；size <= 0 时为 1，idleTimeout <= 0 时为 30 秒
+func NewSMTPPool(cfg SMTPConfig, size int, idleTimeout time.Duration) *SMTPPool {
+	if size <= 0 {
+		size = 1
+	}
+	if idleTimeout <= 0 {
+		idleTimeout = 30 * time.Second
+	}
+	return &SMTPPool{cfg: cfg, idleTimeout: idleTimeout, slots: make(chan struct{}, size)}
+}
+
+// Name 投递方式名称
+func (p *SMTPPool) Name() string { return "smtp" }
+
+// Send 取用连接投递邮件，成功后连接放回池中
+func (p *SMTPPool) Send(ctx context.Context, env *Envelope) error {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.slots }()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(p.cfg.timeout())
+	}
+	pc, reused, err := p.get(deadline)
+	if err != nil {
+		return err
+	}
+	err = Deliver(pc.client, env.From, env.To, env.Data)
+	if err != nil && reused {
+		// 复用的连接可能已被服务器断开，换新连接重试一次
+		pc.client.Close()
+		if pc, err = p.dial(deadline); err != nil {
+			return err
+		}
+		err = Deliver(pc.client, env.From, env.To, env.Data)
+	}
+	if err != nil {
+		pc.client.Close()
+		return err
+	}
+	p.put(pc)
+	return nil
+}
+
+// get 取出可用的空闲连接，没有时新建，返回的连接已设置读写期限
+func (p *SMTPPool) get(deadline time.Time) (*pooledClient, bool, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, false, ErrTransportClosed
+		}
+		n := len(p.idle)
+		if n == 0 {
+			p.mu.Unlock()
+			pc, err := p.dial(deadline)
+			return pc, false, err
+		}
+		pc := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+
+		if time.Since(pc.lastUsed) > p.idleTimeout {
+			pc.client.Close()
+			continue
+		}
+		pc.conn.SetDeadline(deadline)
+		if err := pc.client.Reset(); err != nil {
+			pc.client.Close()
+			continue
+		}
+		return pc, true, nil
+	}
+}
+
+func (p *SMTPPool) dial(deadline time.Time) (*pooledClient, error) {
+	client, conn, err := dialSMTP(p.cfg)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(deadline)
+	return &pooledClient{client: client, conn: conn}, nil
+}
+
+func (p *SMTPPool) put(pc *pooledClient) {
+	pc.lastUsed = time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		pc.client.Quit()
+		return
+	}
+	pc.conn.SetDeadline(time.Time{})
+	p.idle = append(p.idle, pc)
+}
+
+// Close 关闭全部空闲连接；正在投递的连接在投递完成后关闭
+func (p *SMTPPool) Close() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle, p.closed = nil, true
+	p.mu.Unlock()
+	for _, pc := range idle {
+		pc.conn.SetDeadline(time.Now().Add(p.cfg.timeout()))
+		if err := pc.client.Quit(); err != nil {
+			pc.client.Close()
+		}
+	}
+	return nil
+}
+
+// HTTPTransport 通过 HTTP 接口投递（适配自建网关或邮件服务商的发信 API）
+//
+// 以 JSON POST 到 URL：
+//
+//	{"from": "...", "to": ["..."], "subject": "...", "text": "...", "html": "...", "raw": "<base64 MIME>"}
+//
+// raw 为完整的邮件内容（含 DKIM 签名），接口可直接转发；2xx 视为成功。
+// Token 非空时以 Authorization: Bearer 发送。
+type HTTPTransport struct {
+	URL    string
+	Token  string
+	Client *http.Client // 为空时使用 30 秒超时的默认客户端
+}
+
+type httpPayload struct {
+	From    string   `json:"from"`
+	To      []string `json:"to"`
+	Subject string   `json:"subject,omitempty"`
+	Text    string   `json:"text,omitempty"`
+	HTML    string   `json:"html,omitempty"`
+	Raw     string   `json:"raw"`
+}
+
+var defaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// Name 投递方式名称
+func (t *HTTPTransport) Name() string { return "http" }
+
+// Send 提交邮件到 HTTP 接口
+func (t *HTTPTransport) Send(ctx context.Context, env *Envelope) error {
+	payload := httpPayload{From: env.From, To: env.To, Raw: base64.StdEncoding.EncodeToString(env.Data)}
+	if env.Message != nil {
+		payload.Subject, payload.Text, payload.HTML = env.Message.Subject, env.Message.Text, env.Message.HTML
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("mailer: http transport: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.Token)
+	}
+	client := t.Client
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mailer: http transport: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("mailer: http transport: status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Close HTTP 投递无需关闭
+func (t *HTTPTransport) Close() error { return nil }
+
+// FileTransport 将邮件写入本地目录，用于开发与测试
+//
+// Maildir 为 false 时每封邮件写为 Dir 下的一个 .eml 文件；
+// 为 true 时按 Maildir 格式先写入 tmp/ 再移动到 new/，可用邮件客户端直接打开。
+// 文件开头附加 Return-Path 与 X-Envelope-To，记录投递信封（含密送收件人）。
+type FileTransport struct {
+	Dir     string
+	Maildir bool
+}
+
+// Name 投递方式名称
+func (t *FileTransport) Name() string {
+	if t.Maildir {
+		return "maildir"
+	}
+	return "file"
+}
+
+// Send 写入邮件文件
+func (t *FileTransport) Send(ctx context.Context, env *Envelope) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("Return-Path: <" + env.From + ">\r\n")
+	buf.WriteString("X-Envelope-To: " + strings.Join(env.To, ", ") + "\r\n")
+	buf.Write(env.Data)
+
+	now := time.Now()
+	if !t.Maildir {
+		if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+			return fmt.Errorf("mailer: file transport: %w", err)
+		}
+		name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405"), randomHex(4))
+		return writeFile(filepath.Join(t.Dir, name), buf.Bytes())
+	}
+
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(t.Dir, sub), 0o755); err != nil {
+			return fmt.Errorf("mailer: maildir transport: %w", err)
+		}
+	}
+	host, _ := os.Hostname()
+	host = strings.NewReplacer("/", "_", ":", "_").Replace(host)
+	name := fmt.Sprintf("%d.M%dR%s.%s", now.Unix(), now.Nanosecond()/1000, randomHex(4), host)
+	tmp := filepath.Join(t.Dir, "tmp", name)
+	if err := writeFile(tmp, buf.Bytes()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(t.Dir, "new", name)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("mailer: maildir transport: %w", err)
+	}
+	return nil
+}
+
+// Close 文件投递无需关闭
+func (t *FileTransport) Close() error { return nil }
+
+func writeFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("mailer: file transport: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testEnvelope(t *testing.T) *Envelope {
+	t.Helper()
+	msg := testMessage()
+	data, err := msg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := NewEnvelope(msg, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestSMTPPool(t *testing.T) {
+	sink := newSMTPSink(t)
+	pool := NewSMTPPool(sink.config(), 2, 0)
+	env := testEnvelope(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := pool.Send(ctx, env); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := sink.connections(); n != 1 {
+		t.Errorf("sequential sends used %d connections, want 1", n)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pool.Send(ctx, env); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := sink.connections(); n > 2 {
+		t.Errorf("pool opened %d connections, limit 2", n)
+	}
+	if got := sink.received(); len(got) != 9 || len(got[8].To) != 4 {
+		t.Fatalf("received %d messages", len(got))
+	}
+
+	// 服务器断开空闲连接后自动重连
+	pool.mu.Lock()
+	for _, pc := range pool.idle {
+		pc.client.Text.Close()
+	}
+	pool.mu.Unlock()
+	if err := pool.Send(ctx, env); err != nil {
+		t.Fatalf("send after disconnect: %v", err)
+	}
+
+	pool.Close()
+	if err := pool.Send(ctx, env); err != ErrTransportClosed {
+		t.Errorf("send after close = %v", err)
+	}
+}
+
+func TestSMTPPoolSendHonorsDeadline(t *testing.T) {
+	// 完成握手后不再响应 MAIL FROM 的服务器
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				tp := textproto.NewConn(conn)
+				tp.PrintfLine("220 stall ESMTP")
+				for {
+					line, err := tp.ReadLine()
+					if err != nil {
+						return
+					}
+					if strings.HasPrefix(strings.ToUpper(line), "EHLO") {
+						tp.PrintfLine("250 stall")
+					}
+				}
+			}()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	pool := NewSMTPPool(SMTPConfig{Host: host, Port: port, Timeout: 5 * time.Second}, 1, 0)
+	defer pool.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := pool.Send(ctx, testEnvelope(t)); err == nil {
+		t.Fatal("send to a stalled server should fail")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("send blocked for %v", elapsed)
+	}
+}
+
+func TestHTTPTransport(t *testing.T) {
+	var got httpPayload
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		if got.Subject == "fail" {
+			http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	tr := &HTTPTransport{URL: srv.URL, Token: "secret"}
+	env := testEnvelope(t)
+	if err := tr.Send(context.Background(), env); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(got.Raw)
+	if auth != "Bearer secret" || got.From != "noreply@example.com" || len(got.To) != 4 || got.Subject != "订单通知" || !bytes.Equal(raw, env.Data) {
+		t.Errorf("payload = %+v, auth = %q", got, auth)
+	}
+
+	env.Message.Subject = "fail"
+	if err := tr.Send(context.Background(), env); err == nil || !strings.Contains(err.Error(), "429: quota exceeded") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestFileTransport(t *testing.T) {
+	env := testEnvelope(t)
+	for _, maildir := range []bool{false, true} {
+		dir := t.TempDir()
+		tr := &FileTransport{Dir: dir, Maildir: maildir}
+		if err := tr.Send(context.Background(), env); err != nil {
+			t.Fatal(err)
+		}
+
+		pattern := filepath.Join(dir, "*.eml")
+		if maildir {
+			pattern = filepath.Join(dir, "new", "*")
+		}
+		files, _ := filepath.Glob(pattern)
+		if len(files) != 1 {
+			t.Fatalf("%s: files = %v", tr.Name(), files)
+		}
+		data, _ := os.ReadFile(files[0])
+		if !bytes.HasPrefix(data, []byte("Return-Path: <noreply@example.com>\r\nX-Envelope-To: a@example.com, b@example.com, c@example.com, hidden@example.com\r\n")) ||
+			!bytes.HasSuffix(data, env.Data) {
+			t.Errorf("%s: content = %q", tr.Name(), data[:120])
+		}
+		if maildir {
+			if tmp, _ := os.ReadDir(filepath.Join(dir, "tmp")); len(tmp) != 0 {
+				t.Errorf("maildir tmp not empty: %v", tmp)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mailtpl
+
+// defaultLayout 默认邮件布局，可覆盖的块：header、content、footer
+const defaultLayout = `<!DOCTYPE html>
+<html lang="zh">
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1.0">
+<title>{{.subject}}</title>
+</head>
+<body style="margin:0;padding:0;background-color:#f0f2f5;font-family:'Segoe UI','PingFang SC','Microsoft YaHei',sans-serif;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="background-color:#f0f2f5;padding:40px 0;">
+  <tr>
+    <td align="center">
+      <!-- Main Card -->
+      <table role="presentation" width="600" cellpadding="0" cellspacing="0" style="max-width:600px;width:100%;background-color:#ffffff;border-radius:16px;overflow:hidden;box-shadow:0 4px 24px rgba(0,0,0,0.08);">
+        <!-- Header -->
+        <tr>
+          <td style="background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);padding:36px 40px;text-align:center;">
+            {{block "header" .}}<h1 style="margin:0;font-size:26px;font-weight:700;color:#ffffff;letter-spacing:1px;">{{or .app_name "System"}}</h1>{{end}}
+          </td>
+        </tr>
+        <!-- Subject -->
+        <tr>
+          <td style="padding:32px 40px 0 40px;">
+            <h2 style="margin:0 0 8px 0;font-size:20px;font-weight:600;color:#1a1a2e;">{{.subject}}</h2>
+            <div style="width:48px;height:3px;background:linear-gradient(90deg,#667eea,#764ba2);border-radius:2px;"></div>
+          </td>
+        </tr>
+        <!-- Content -->
+        <tr>
+          <td style="padding:24px 40px 36px 40px;">
+            <div style="font-size:15px;line-height:1.8;color:#4a4a68;">{{block "content" .}}{{end}}</div>
+          </td>
+        </tr>
+        <!-- Divider -->
+        <tr>
+          <td style="padding:0 40px;">
+            <div style="border-top:1px solid #e8e8f0;"></div>
+          </td>
+        </tr>
+        <!-- Footer -->
+        <tr>
+          <td style="padding:24px 40px 32px 40px;text-align:center;">
+            {{block "footer" .}}<p style="margin:0 0 4px 0;font-size:12px;color:#a0a0b8;">此邮件由系统自动发送，请勿直接回复</p>
+            <p style="margin:0;font-size:12px;color:#a0a0b8;">&copy; {{.year}} {{or .app_name "System"}} · All rights reserved</p>{{end}}
+          </td>
+        </tr>
+      </table>
+    </td>
+  </tr>
+</table>
+</body>
+</html>`
+
+// builtinPartials 内置片段
+var builtinPartials = map[string]string{
+	// button 按钮链接，参数：dict "url" ... "text" ...
+	"button": `<div style="text-align:center;margin:28px 0;">` +
+		`<a href="{{.url}}" style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:16px;font-weight:600;text-decoration:none;padding:14px 48px;border-radius:10px;">{{.text}}</a>` +
+		`</div>`,
+	// code 醒目的验证码，参数为验证码本身
+	"code": `<div style="text-align:center;margin:28px 0;">` +
+		`<div style="display:inline-block;background:linear-gradient(135deg,#667eea 0%,#764ba2 100%);color:#ffffff;font-size:32px;font-weight:700;letter-spacing:8px;padding:16px 40px;border-radius:12px;">{{.}}</div>` +
+		`</div>`,
+}
@@ -0,0 +1,267 @@
+// Package mailtpl 基于 html/template 的邮件模板引擎
+//
+//   - 变量写作 {{.code}}，按所在位置（正文、属性、链接）自动转义；旧的 {code} 占位符会自动转换
+//   - 支持 if / range / with 等控制结构，可调用的函数只有 FuncMap 中列出的几个
+//   - 模板内容渲染为布局中的 content 块，可通过 {{define "footer"}}...{{end}} 覆盖布局中的块，
+//     定义 layout 块则完全替换布局
+//   - 共享片段（partial）通过 {{template "button" (dict "url" .link "text" "重置密码")}} 调用
+//   - 同时生成纯文本版本，用于 multipart/alternative
+package mailtpl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+// DefaultLayout 默认布局名称
+const DefaultLayout = "default"
+
+// maxOutput 单次渲染的最大输出，防止模板中的循环生成过大的邮件
+const maxOutput = 1 << 20
+
+// ErrOutputTooLarge 渲染结果超过 maxOutput
+var ErrOutputTooLarge = errors.New("mailtpl: rendered output too large")
+
+// Source 待渲染的模板
+type Source struct {
+	Subject string
+	Content string
+	Layout  string // 布局名称，为空时使用 DefaultLayout
+}
+
+// Message 渲染结果
+type Message struct {
+	Subject string // 主题（纯文本，已去除换行）
+	Body    string // 模板内容（不含布局）
+	HTML    string // 套用布局后的完整 HTML
+	Text    string // 由 HTML 生成的纯文本版本
+}
+
+// Engine 模板引擎，持有布局与共享片段
+type Engine struct {
+	mu       sync.RWMutex
+	layouts  map[string]string
+	partials map[string]string
+}
+
+// Default 全局引擎，已注册内置布局与片段
+var Default = New()
+
+// New 创建引擎并注册内置布局与片段
+func New() *Engine {
+	e := &Engine{layouts: map[string]string{}, partials: map[string]string{}}
+	e.RegisterLayout(DefaultLayout, defaultLayout)
+	for name, text := range builtinPartials {
+		e.RegisterPartial(name, text)
+	}
+	return e
+}
+
+// RegisterLayout 注册布局，布局中通过 {{block "content" .}}{{end}} 预留模板内容的位置
+func (e *Engine) RegisterLayout(name, text string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.layouts[name] = text
+}
+
+// RegisterPartial 注册共享片段，模板中通过 {{template "name" ...}} 调用
+func (e *Engine) RegisterPartial(name, text string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.partials[name] = text
+}
+
+// funcs 模板中可用的函数
+var funcs = template.FuncMap{
+	// dict 构造传给片段的参数：dict "url" .link "text" "重置密码"
+	"dict": func(pairs ...any) (map[string]any, error) {
+		if len(pairs)%2 != 0 {
+			return nil, errors.New("dict: odd number of arguments")
+		}
+		m := make(map[string]any, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("dict: key %v is not a string", pairs[i])
+			}
+			m[key] = pairs[i+1]
+		}
+		return m, nil
+	},
+	// default 值为空时使用默认值：{{default "用户" .nickname}}
+	"default": func(def, v any) any {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+	"upper": func(v any) string { return strings.ToUpper(toString(v)) },
+	"lower": func(v any) string { return strings.ToLower(toString(v)) },
+}
+
+func toString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// Render 渲染主题与内容并套用布局；data 中会加入 subject（渲染后的主题）
+func (e *Engine) Render(src Source, data map[string]any) (*Message, error) {
+	subject, err := renderSubject(src.Subject, data)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := e.parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]any, len(data)+1)
+	for k, v := range data {
+		vars[k] = v
+	}
+	vars["subject"] = subject
+
+	body, err := execute(t, "content", vars)
+	if err != nil {
+		return nil, err
+	}
+	htmlBody, err := execute(t, "layout", vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{Subject: subject, Body: body, HTML: htmlBody, Text: HTMLToText(htmlBody)}, nil
+}
+
+// parse 组合片段、布局与模板内容；内容最后解析，其中的 define 会覆盖布局中的同名块
+func (e *Engine) parse(src Source) (*template.Template, error) {
+	layout := src.Layout
+	if layout == "" {
+		layout = DefaultLayout
+	}
+
+	e.mu.RLock()
+	layoutText, ok := e.layouts[layout]
+	partials := make(map[string]string, len(e.partials))
+	for name, text := range e.partials {
+		partials[name] = text
+	}
+	e.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("邮件布局不存在: %s", layout)
+	}
+
+	t := template.New("layout").Funcs(funcs).Option("missingkey=zero")
+	for _, name := range sortedKeys(partials) {
+		if _, err := t.New(name).Parse(partials[name]); err != nil {
+			return nil, fmt.Errorf("片段 %s 解析失败: %w", name, err)
+		}
+	}
+	if _, err := t.Parse(layoutText); err != nil {
+		return nil, fmt.Errorf("布局 %s 解析失败: %w", layout, err)
+	}
+	if _, err := t.New("content").Parse(ConvertLegacy(src.Content)); err != nil {
+		return nil, fmt.Errorf("模板内容解析失败: %w", err)
+	}
+	return t, nil
+}
+
+// renderSubject 主题按纯文本渲染（不做 HTML 转义），并去除换行防止头部注入
+func renderSubject(subject string, data map[string]any) (string, error) {
+	t, err := texttemplate.New("subject").Funcs(texttemplate.FuncMap(funcs)).Option("missingkey=zero").Parse(ConvertLegacy(subject))
+	if err != nil {
+		return "", fmt.Errorf("主题解析失败: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&limitWriter{w: &buf}, data); err != nil {
+		return "", fmt.Errorf("主题渲染失败: %w", err)
+	}
+	s := strings.ReplaceAll(buf.String(), "<no value>", "")
+	s = strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+	return strings.TrimSpace(s), nil
+}
+
+func execute(t *template.Template, name string, data map[string]any) (string, error) {
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&limitWriter{w: &buf}, name, data); err != nil {
+		return "", fmt.Errorf("模板渲染失败: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// limitWriter 超过 maxOutput 后返回错误，终止渲染
+type limitWriter struct {
+	w *bytes.Buffer
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if l.w.Len()+len(p) > maxOutput {
+		return 0, ErrOutputTooLarge
+	}
+	return l.w.Write(p)
+}
+
+// ConvertLegacy 将旧模板的 {name} 占位符转换为 {{.name}}，已有的 {{...}} 动作保持不变
+func ConvertLegacy(text string) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	for i := 0; i < len(text); {
+		if strings.HasPrefix(text[i:], "{{") {
+			end := strings.Index(text[i+2:], "}}")
+			if end < 0 {
+				b.WriteString(text[i:])
+				break
+			}
+			b.WriteString(text[i : i+2+end+2])
+			i += 2 + end + 2
+			continue
+		}
+		if text[i] == '{' {
+			if n := identLen(text[i+1:]); n > 0 && i+1+n < len(text) && text[i+1+n] == '}' {
+				b.WriteString("{{.")
+				b.WriteString(text[i+1 : i+1+n])
+				b.WriteString("}}")
+				i += n + 2
+				continue
+			}
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	return b.String()
+}
+
+// identLen 开头的变量名长度（字母或下划线开头，后跟字母、数字、下划线）
+func identLen(s string) int {
+	n := 0
+	for n < len(s) {
+		c := s[n]
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || n > 0 && c >= '0' && c <= '9' {
+			n++
+			continue
+		}
+		break
+	}
+	return n
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
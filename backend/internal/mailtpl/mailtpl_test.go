@@ -0,0 +1,135 @@
+package mailtpl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertLegacy(t *testing.T) {
+	cases := map[string]string{
+		"Hi {name}, code {code}":               "Hi {{.name}}, code {{.code}}",
+		"{{.name}} {code}":                     "{{.name}} {{.code}}",
+		"{{if .a}}{b}{{end}}":                  "{{if .a}}{{.b}}{{end}}",
+		"a{color:red} {1x} { name } {}":        "a{color:red} {1x} { name } {}",
+		`<a href="{link}">{link}</a>{{- x -}}`: `<a href="{{.link}}">{{.link}}</a>{{- x -}}`,
+	}
+	for in, want := range cases {
+		if got := ConvertLegacy(in); got != want {
+			t.Errorf("ConvertLegacy(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	e := New()
+	msg, err := e.Render(Source{
+		Subject: "【{app_name}】Hi {{.nickname}}",
+		Content: `<p>{{.nickname}}</p><a href="{{.link}}">go</a>` +
+			`<ul>{{range .items}}<li>{{.name}} x{{.qty}}</li>{{end}}</ul>` +
+			`{{if .vip}}<p>VIP</p>{{else}}<p>normal</p>{{end}}` +
+			`{{template "code" .code}}` +
+			`{{define "footer"}}<p>bye {{$.app_name}}</p>{{end}}`,
+	}, map[string]any{
+		"app_name": "Demo",
+		"nickname": `<script>alert(1)</script>`,
+		"link":     "javascript:alert(1)",
+		"code":     "123456",
+		"items":    []any{map[string]any{"name": "A", "qty": 2}, map[string]any{"name": "B&C", "qty": 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Subject != "【Demo】Hi <script>alert(1)</script>" {
+		t.Errorf("subject = %q", msg.Subject)
+	}
+	if strings.Contains(msg.Body, "<script>") || !strings.Contains(msg.Body, "&lt;script&gt;") {
+		t.Errorf("nickname not escaped: %s", msg.Body)
+	}
+	if strings.Contains(msg.Body, "javascript:") {
+		t.Errorf("unsafe url kept: %s", msg.Body)
+	}
+	for _, want := range []string{"<li>A x2</li>", "<li>B&amp;C x1</li>", "<p>normal</p>", "123456"} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("body missing %q: %s", want, msg.Body)
+		}
+	}
+
+	// 布局：主题转义后出现在标题中，footer 块被模板覆盖
+	if !strings.Contains(msg.HTML, "<title>【Demo】Hi &lt;script&gt;") || !strings.Contains(msg.HTML, "<p>bye Demo</p>") {
+		t.Errorf("layout not applied: %s", msg.HTML)
+	}
+	if strings.Contains(msg.HTML, "请勿直接回复") {
+		t.Errorf("footer not overridden")
+	}
+
+	if strings.Contains(msg.Text, "<td") || strings.Contains(msg.Text, "<title>") {
+		t.Errorf("text contains tags: %s", msg.Text)
+	}
+	for _, want := range []string{"Demo", "- A x2", "- B&C x1", "123456", "bye Demo"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("text missing %q: %s", want, msg.Text)
+		}
+	}
+}
+
+func TestRenderLayoutOverride(t *testing.T) {
+	e := New()
+	e.RegisterLayout("plain", `<div>{{block "content" .}}{{end}}</div>`)
+	msg, err := e.Render(Source{Subject: "s", Content: "<b>{{.x}}</b>", Layout: "plain"}, map[string]any{"x": "1"})
+	if err != nil || msg.HTML != "<div><b>1</b></div>" {
+		t.Fatalf("plain layout = %q, %v", msg.HTML, err)
+	}
+
+	// 模板定义 layout 块时完全替换布局
+	msg, err = e.Render(Source{Subject: "s", Content: `{{define "layout"}}<i>{{template "content" .}}</i>{{end}}x`}, nil)
+	if err != nil || msg.HTML != "<i>x</i>" {
+		t.Fatalf("own layout = %q, %v", msg.HTML, err)
+	}
+
+	if _, err := e.Render(Source{Content: "x", Layout: "missing"}, nil); err == nil {
+		t.Fatal("expected unknown layout error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	e := New()
+	declared := ParseVariables("code, link, items")
+
+	ok := []Source{
+		{Subject: "{app_name}", Content: "{code} {{.link}}"},
+		{Subject: "s", Content: `{{range .items}}{{.name}} {{$.code}}{{end}}`},
+		{Subject: "s", Content: `{{template "button" (dict "url" .link "text" "go")}}`},
+		{Subject: "{{.subject}}", Content: `{{with .items}}{{.anything}}{{end}}`},
+	}
+	for _, src := range ok {
+		if err := e.Validate(src, append(declared, "app_name")); err != nil {
+			t.Errorf("Validate(%q) = %v", src.Content, err)
+		}
+	}
+
+	bad := map[string]Source{
+		"未声明的变量: nickname": {Subject: "s", Content: "{{.nickname}}"},
+		"未声明的变量: token":    {Subject: "{token}", Content: "x"},
+		"未声明的变量: secret":   {Subject: "s", Content: `{{range .items}}{{$.secret}}{{end}}`},
+		"解析失败":             {Subject: "s", Content: "{{if .code}}"},
+		"不存在的片段":           {Subject: "s", Content: `{{template "missing" .}}`},
+		"function":         {Subject: "s", Content: `{{exec "ls"}}`},
+	}
+	for want, src := range bad {
+		err := e.Validate(src, declared)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate(%q) = %v, want %q", src.Content, err, want)
+		}
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	got := HTMLToText(`<html><head><title>T</title><style>p{color:red}</style></head><body>` +
+		`<h2>Hello &amp; welcome</h2><p>Line  one<br>Line two</p>` +
+		`<p><a href="https://x.test/r?a=1&amp;b=2">Reset</a> <a href="https://x.test">https://x.test</a></p></body></html>`)
+	want := "Hello & welcome\n\nLine one\nLine two\n\nReset (https://x.test/r?a=1&b=2) https://x.test"
+	if got != want {
+		t.Errorf("HTMLToText = %q, want %q", got, want)
+	}
+}
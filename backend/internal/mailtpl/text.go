@@ -0,0 +1,50 @@
+package mailtpl
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// 不输出内容的元素
+	hiddenBlockRe = regexp.MustCompile(`(?is)<(head|style|script|title)\b[^>]*>.*?</(head|style|script|title)\s*>|<!--.*?-->`)
+	// 链接：文字与地址不同时在文字后附上地址
+	linkRe = regexp.MustCompile(`(?is)<a\b[^>]*?\bhref\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a\s*>`)
+	// 换行的标签
+	breakRe = regexp.MustCompile(`(?i)<br\s*/?>|</?(p|div|h[1-6]|tr|table|ul|ol|blockquote)\b[^>]*>`)
+	// 列表项
+	listItemRe = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	tagRe      = regexp.MustCompile(`(?s)<[^>]*>`)
+	spaceRe    = regexp.MustCompile(`[ \t\r\f\v]+`)
+	blankRe    = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToText 将邮件 HTML 转换为纯文本：保留段落换行与链接地址，去除样式与标签
+func HTMLToText(s string) string {
+	s = hiddenBlockRe.ReplaceAllString(s, "")
+	s = linkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := linkRe.FindStringSubmatch(m)
+		href := html.UnescapeString(sub[1])
+		text := strings.TrimSpace(tagRe.ReplaceAllString(sub[2], ""))
+		if text == "" || html.UnescapeString(text) == href {
+			return href
+		}
+		if strings.HasPrefix(href, "#") || href == "" {
+			return text
+		}
+		return text + " (" + href + ")"
+	})
+	s = breakRe.ReplaceAllString(s, "\n")
+	s = listItemRe.ReplaceAllString(s, "\n- ")
+	s = tagRe.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaceRe.ReplaceAllString(line, " "))
+	}
+	s = strings.Join(lines, "\n")
+	s = blankRe.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
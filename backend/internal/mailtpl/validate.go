@@ -0,0 +1,159 @@
+package mailtpl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"text/template/parse"
+)
+
+// Validate 检查模板能否解析与渲染，并拒绝引用未声明变量的模板；
+// declared 为模板声明的变量，subject 由引擎提供，无需声明
+func (e *Engine) Validate(src Source, declared []string) error {
+	allowed := map[string]bool{"subject": true}
+	for _, name := range declared {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = true
+		}
+	}
+
+	// 语法检查，同时收集模板中引用的变量
+	refs := map[string]bool{}
+	subjectTpl, err := texttemplate.New("subject").Funcs(texttemplate.FuncMap(funcs)).Parse(ConvertLegacy(src.Subject))
+	if err != nil {
+		return fmt.Errorf("主题解析失败: %w", err)
+	}
+	collectRefs(subjectTpl.Tree.Root, true, refs)
+
+	contentTpl, err := texttemplate.New("content").Funcs(texttemplate.FuncMap(funcs)).Parse(ConvertLegacy(src.Content))
+	if err != nil {
+		return fmt.Errorf("模板内容解析失败: %w", err)
+	}
+	for _, t := range contentTpl.Templates() {
+		if t.Tree != nil {
+			collectRefs(t.Tree.Root, true, refs)
+		}
+	}
+
+	var undeclared []string
+	for name := range refs {
+		if !allowed[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	if len(undeclared) > 0 {
+		sort.Strings(undeclared)
+		return fmt.Errorf("模板引用了未声明的变量: %s", strings.Join(undeclared, ", "))
+	}
+
+	// 引用的片段与块必须存在
+	t, err := e.parse(src)
+	if err != nil {
+		return err
+	}
+	for _, ct := range contentTpl.Templates() {
+		if ct.Tree == nil {
+			continue
+		}
+		var missing error
+		walkTemplates(ct.Tree.Root, func(name string) {
+			if missing == nil && t.Lookup(name) == nil {
+				missing = fmt.Errorf("模板引用了不存在的片段: %s", name)
+			}
+		})
+		if missing != nil {
+			return missing
+		}
+	}
+
+	// 以空数据试渲染，检查 HTML 上下文转义错误（如未闭合的属性）
+	_, err = e.Render(src, map[string]any{})
+	return err
+}
+
+// ParseVariables 解析模板的 variables 字段（逗号分隔）
+func ParseVariables(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// collectRefs 收集引用的顶层变量；root 表示当前的 . 是否为模板数据本身
+// （range / with 内部的 . 指向元素，只有 $.name 才是顶层变量）
+func collectRefs(node parse.Node, root bool, refs map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectRefs(child, root, refs)
+		}
+	case *parse.ActionNode:
+		collectRefs(n.Pipe, root, refs)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectRefs(cmd, root, refs)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectRefs(arg, root, refs)
+		}
+	case *parse.ChainNode:
+		collectRefs(n.Node, root, refs)
+	case *parse.FieldNode:
+		if root && len(n.Ident) > 0 {
+			refs[n.Ident[0]] = true
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			refs[n.Ident[1]] = true
+		}
+	case *parse.IfNode:
+		collectRefs(n.Pipe, root, refs)
+		collectRefs(n.List, root, refs)
+		collectRefs(n.ElseList, root, refs)
+	case *parse.RangeNode:
+		collectRefs(n.Pipe, root, refs)
+		collectRefs(n.List, false, refs)
+		collectRefs(n.ElseList, root, refs)
+	case *parse.WithNode:
+		collectRefs(n.Pipe, root, refs)
+		collectRefs(n.List, false, refs)
+		collectRefs(n.ElseList, root, refs)
+	case *parse.TemplateNode:
+		collectRefs(n.Pipe, root, refs)
+	}
+}
+
+// walkTemplates 遍历 {{template "name"}} 引用
+func walkTemplates(node parse.Node, fn func(name string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkTemplates(child, fn)
+		}
+	case *parse.IfNode:
+		walkTemplates(n.List, fn)
+		walkTemplates(n.ElseList, fn)
+	case *parse.RangeNode:
+		walkTemplates(n.List, fn)
+		walkTemplates(n.ElseList, fn)
+	case *parse.WithNode:
+		walkTemplates(n.List, fn)
+		walkTemplates(n.ElseList, fn)
+	case *parse.TemplateNode:
+		fn(n.Name)
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"fst/backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader 请求 ID 请求头/响应头
+	RequestIDHeader = "X-Request-ID"
+	// APIVersionHeader 客户端选择 API 版本的请求头
+	APIVersionHeader = "X-API-Version"
+	// LatestAPIVersion 当前支持的最高 API 版本
+	LatestAPIVersion = 2
+)
+
+// RequestID 为每个请求分配请求 ID：沿用上游（网关、客户端）传入的合法 X-Request-ID，否则生成新的；
+// 写入响应头与响应体的 request_id，日志中同样记录，便于排查
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request_id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(request_id) {
+			request_id = newRequestID()
+		}
+		c.Set(utils.RequestIDKey, request_id)
+		c.Header(RequestIDHeader, request_id)
+		c.Next()
+	}
+}
+
+// APIVersion 根据 X-API-Version 请求头选择错误模型，未传时为版本 1（HTTP 状态码始终 200）；
+// 传 2 时错误响应使用真实的 HTTP 状态码并附带错误码与字段详情
+func APIVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := 1
+		if value := strings.TrimSpace(c.GetHeader(APIVersionHeader)); value != "" {
+			parsed, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(value), "v"))
+			if err != nil || parsed < 1 || parsed > LatestAPIVersion {
+				utils.Fail(c, 400, "不支持的 API 版本: "+value)
+				c.Abort()
+				return
+			}
+			version = parsed
+		}
+		c.Set(utils.APIVersionKey, version)
+		c.Next()
+	}
+}
+
+// validRequestID 只接受长度适中的字母、数字、-、_、.，避免日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, ch := range id {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '_' || ch == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"fst/backend/app/models"
 	"fst/backend/utils"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func AuthMiddleware() gin.HandlerFunc {
@@ -23,14 +25,14 @@ func AuthMiddlewareForGuard(acceptGuards ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			utils.Fail(c, 401, "Authorization header is required")
+			utils.FailCode(c, 401, utils.ErrTokenMissing, "Authorization header is required")
 			c.Abort()
 			return
 		}
 
 		parts := strings.SplitN(authHeader, " ", 2)
 		if !(len(parts) == 2 && parts[0] == "Bearer") {
-			utils.Fail(c, 401, "Authorization header format must be Bearer {token}")
+			utils.FailCode(c, 401, utils.ErrTokenInvalid, "Authorization header format must be Bearer {token}")
 			c.Abort()
 			return
 		}
@@ -38,14 +40,20 @@ func AuthMiddlewareForGuard(acceptGuards ...string) gin.HandlerFunc {
 		// 依次尝试每种 guard 解析 token，匹配则通过
 		var claims *utils.Claims
 		var parseErr error
+		expired := false
 		for _, guard := range acceptGuards {
 			claims, parseErr = utils.ParseTokenForGuard(parts[1], guard)
 			if parseErr == nil {
 				break
 			}
+			expired = expired || errors.Is(parseErr, jwt.ErrTokenExpired)
 		}
 		if parseErr != nil {
-			utils.Fail(c, 401, "Invalid or expired token")
+			errCode := utils.ErrTokenInvalid
+			if expired {
+				errCode = utils.ErrTokenExpired
+			}
+			utils.FailCode(c, 401, errCode, "Invalid or expired token")
 			c.Abort()
 			return
 		}
@@ -55,9 +63,10 @@ func AuthMiddlewareForGuard(acceptGuards ...string) gin.HandlerFunc {
 		if actualGuard == "" {
 			actualGuard = utils.UserAuthGuard
 		}
-		active, err := models.IsUserSessionActive(claims.UserID, actualGuard, utils.HashToken(parts[1]))
+		tokenHash := utils.HashToken(parts[1])
+		active, err := models.IsUserSessionActive(claims.UserID, actualGuard, tokenHash)
 		if err != nil || !active {
-			utils.Fail(c, 401, "Session expired or revoked")
+			utils.FailCode(c, 401, utils.ErrSessionRevoked, "Session expired or revoked")
 			c.Abort()
 			return
 		}
@@ -68,6 +77,7 @@ func AuthMiddlewareForGuard(acceptGuards ...string) gin.HandlerFunc {
 		c.Set("userID", claims.UserID)
 		c.Set("role", claims.Role)
 		c.Set("authGuard", actualGuard)
+		c.Set("tokenHash", tokenHash)
 		c.Next()
 	}
 }
@@ -10,7 +10,7 @@ import (
 // CorsMiddleware 处理跨域请求
 func CorsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		corsOrigins := config.GlobalConfig.CorsOrigins
+		corsOrigins := config.Get().CorsOrigins
 		origin := c.GetHeader("Origin")
 
 		if origin != "" {
@@ -34,8 +34,9 @@ func CorsMiddleware() gin.HandlerFunc {
 		if reqHeaders != "" {
 			c.Header("Access-Control-Allow-Headers", reqHeaders)
 		} else {
-			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Authorization, Accept, X-Requested-With, X-Geetest-Lot-Number, X-Geetest-Captcha-Output, X-Geetest-Pass-Token, X-Geetest-Gen-Time, X-Geetest-Captcha-Id")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Authorization, Accept, X-Requested-With, X-Geetest-Lot-Number, X-Geetest-Captcha-Output, X-Geetest-Pass-Token, X-Geetest-Gen-Time, X-Geetest-Captcha-Id, X-Request-ID, X-API-Version")
 		}
+		c.Header("Access-Control-Expose-Headers", RequestIDHeader)
 
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "3600") // 预检请求缓存1小时
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"fst/backend/utils"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -27,6 +29,7 @@ var DefaultLoggerConfig = LoggerConfig{
 	SkipPaths: []string{
 		"/health",
 		"/favicon.ico",
+		"/openapi.json",
 	},
 	SkipPathPrefixes: []string{
 		"/swagger",
@@ -74,6 +77,9 @@ func LoggerMiddlewareWithConfig(config LoggerConfig) gin.HandlerFunc {
 
 		// 构建日志
 		log_line := buildLogLine(start_time, status_code, latency, client_ip, method, path)
+		if request_id := c.GetString(utils.RequestIDKey); request_id != "" {
+			log_line += " | " + request_id
+		}
 
 		// 根据状态码选择日志级别
 		if status_code >= 500 {
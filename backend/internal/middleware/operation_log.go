@@ -2,8 +2,12 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"fst/backend/app/models"
+	"fst/backend/internal/lifecycle"
+	"fst/backend/internal/queue"
 	"io"
+	"log"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -83,9 +87,7 @@ func OperationLogMiddleware(module string, action string) gin.HandlerFunc {
 		}
 
 		// 异步保存日志
-		go func() {
-			models.CreateOperationLog(log)
-		}()
+		saveOperationLog(log)
 	}
 }
 
@@ -124,12 +126,35 @@ func SimpleLogMiddleware(module string) gin.HandlerFunc {
 			Duration:   int(duration),
 		}
 
-		go func() {
-			models.CreateOperationLog(log)
-		}()
+		saveOperationLog(log)
 	}
 }
 
+// OperationLogJob 操作日志写入任务类型
+const OperationLogJob = "operation_log.write"
+
+// RegisterOperationLogJob 在队列中注册操作日志写入任务
+func RegisterOperationLogJob(m *queue.Manager) error {
+	return queue.Register(m, OperationLogJob, func(ctx context.Context, log *models.OperationLog) error {
+		return models.CreateOperationLog(log)
+	}, queue.HandlerOptions{Queue: "oplog", MaxAttempts: 3, Timeout: 30 * time.Second})
+}
+
+// saveOperationLog 后台直接写入操作日志，写入失败时交给队列重试
+func saveOperationLog(op_log *models.OperationLog) {
+	now := time.Now().Unix()
+	op_log.CreateTime = &now
+	lifecycle.Go("operation-log", func(context.Context) {
+		err := models.CreateOperationLog(op_log)
+		if err == nil {
+			return
+		}
+		if _, qerr := queue.Enqueue(OperationLogJob, op_log); qerr != nil {
+			log.Printf("[OperationLog] Failed to save operation log: %v", err)
+		}
+	})
+}
+
 func getActionByMethod(method string) string {
 	switch method {
 	case "GET":
@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"context"
+	"fst/backend/internal/config"
+	"fst/backend/internal/lifecycle"
 	"fst/backend/utils"
 	"strconv"
 	"sync"
@@ -57,14 +60,14 @@ func NewRateLimiter(config RateLimitConfig) *RateLimiter {
 		stop_ch:  make(chan struct{}),
 	}
 
-	// 启动清理协程
-	go limiter.cleanupRoutine()
+	// 启动清理协程，进程关闭时随生命周期退出
+	lifecycle.Go("ratelimit-cleanup", limiter.cleanupRoutine)
 
 	return limiter
 }
 
 // cleanupRoutine 定期清理过期的访问者记录
-func (rl *RateLimiter) cleanupRoutine() {
+func (rl *RateLimiter) cleanupRoutine(ctx context.Context) {
 	ticker := time.NewTicker(rl.config.CleanupInterval)
 	defer ticker.Stop()
 
@@ -74,6 +77,8 @@ func (rl *RateLimiter) cleanupRoutine() {
 			rl.cleanup()
 		case <-rl.stop_ch:
 			return
+		case <-ctx.Done():
+			return
 		}
 	}
 }
@@ -99,14 +104,23 @@ func (rl *RateLimiter) Stop() {
 	close(rl.stop_ch)
 }
 
+// SetLimits 在运行时调整限流速率与突发上限
+func (rl *RateLimiter) SetLimits(rate, burst int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.config.Rate = rate
+	rl.config.Burst = burst
+}
+
 // Allow 检查是否允许请求
 func (rl *RateLimiter) Allow(key string) bool {
 	rl.mu.Lock()
+	rate, burst := rl.config.Rate, rl.config.Burst
 	v, exists := rl.visitors[key]
 	if !exists {
 		v = &visitor{
 			last_seen: time.Now(),
-			tokens:    rl.config.Burst,
+			tokens:    burst,
 		}
 		rl.visitors[key] = v
 	}
@@ -120,9 +134,9 @@ func (rl *RateLimiter) Allow(key string) bool {
 	v.last_seen = now
 
 	// 令牌桶算法：根据时间间隔补充令牌
-	v.tokens += int(elapsed.Seconds() * float64(rl.config.Rate))
-	if v.tokens > rl.config.Burst {
-		v.tokens = rl.config.Burst
+	v.tokens += int(elapsed.Seconds() * float64(rate))
+	if v.tokens > burst {
+		v.tokens = burst
 	}
 
 	if v.tokens <= 0 {
@@ -133,17 +147,31 @@ func (rl *RateLimiter) Allow(key string) bool {
 	return true
 }
 
-// RateLimitMiddleware 限流中间件（使用默认配置）
+// RateLimitMiddleware 限流中间件（速率取自 RATE_LIMIT_RATE / RATE_LIMIT_BURST，支持热更新）
 func RateLimitMiddleware() gin.HandlerFunc {
-	return RateLimitMiddlewareWithConfig(DefaultRateLimitConfig)
+	cfg := DefaultRateLimitConfig
+	cfg.Rate = config.RateLimitRate.Get()
+	cfg.Burst = config.RateLimitBurst.Get()
+	return liveRateLimitMiddleware(cfg, config.RateLimitRate, config.RateLimitBurst)
 }
 
 // RateLimitMiddlewareWithConfig 使用自定义配置的限流中间件
-func RateLimitMiddlewareWithConfig(config RateLimitConfig) gin.HandlerFunc {
-	limiter := NewRateLimiter(config)
+func RateLimitMiddlewareWithConfig(cfg RateLimitConfig) gin.HandlerFunc {
+	return rateLimitHandler(NewRateLimiter(cfg), cfg.KeyFunc)
+}
+
+// liveRateLimitMiddleware 创建跟随配置变更自动调整速率的限流中间件
+func liveRateLimitMiddleware(cfg RateLimitConfig, rateKey, burstKey config.IntKey) gin.HandlerFunc {
+	limiter := NewRateLimiter(cfg)
+	config.Subscribe(func(config.Change) {
+		limiter.SetLimits(rateKey.Get(), burstKey.Get())
+	}, rateKey, burstKey)
+	return rateLimitHandler(limiter, cfg.KeyFunc)
+}
 
+func rateLimitHandler(limiter *RateLimiter, keyFunc func(*gin.Context) string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		key := config.KeyFunc(c)
+		key := keyFunc(c)
 
 		if !limiter.Allow(key) {
 			utils.Fail(c, 429, "请求过于频繁，请稍后再试")
@@ -156,31 +184,31 @@ func RateLimitMiddlewareWithConfig(config RateLimitConfig) gin.HandlerFunc {
 }
 
 // StrictRateLimitMiddleware 严格限流中间件
-// 用于登录、注册等敏感接口
+// 用于登录、注册等敏感接口，速率可通过 rate_limit_auth_rate / rate_limit_auth_burst 热更新
 func StrictRateLimitMiddleware() gin.HandlerFunc {
-	config := RateLimitConfig{
-		Rate:            5,   // 每秒5个请求
-		Burst:           10,  // 突发上限10
+	cfg := RateLimitConfig{
+		Rate:            config.AuthRateLimitRate.Get(),  // 默认每秒5个请求
+		Burst:           config.AuthRateLimitBurst.Get(), // 默认突发上限10
 		KeyFunc:         DefaultKeyFunc,
 		CleanupInterval: time.Minute,
 	}
-	return RateLimitMiddlewareWithConfig(config)
+	return liveRateLimitMiddleware(cfg, config.AuthRateLimitRate, config.AuthRateLimitBurst)
 }
 
 // IPRateLimitMiddleware 基于IP的限流中间件
 func IPRateLimitMiddleware(rate, burst int) gin.HandlerFunc {
-	config := RateLimitConfig{
+	cfg := RateLimitConfig{
 		Rate:            rate,
 		Burst:           burst,
 		KeyFunc:         DefaultKeyFunc,
 		CleanupInterval: time.Minute,
 	}
-	return RateLimitMiddlewareWithConfig(config)
+	return RateLimitMiddlewareWithConfig(cfg)
 }
 
 // UserRateLimitMiddleware 基于用户ID的限流中间件
 func UserRateLimitMiddleware(rate, burst int) gin.HandlerFunc {
-	config := RateLimitConfig{
+	cfg := RateLimitConfig{
 		Rate: rate,
 		Burst: burst,
 		KeyFunc: func(c *gin.Context) string {
@@ -191,12 +219,12 @@ func UserRateLimitMiddleware(rate, burst int) gin.HandlerFunc {
 		},
 		CleanupInterval: time.Minute,
 	}
-	return RateLimitMiddlewareWithConfig(config)
+	return RateLimitMiddlewareWithConfig(cfg)
 }
 
 // PathRateLimitMiddleware 基于路径的限流中间件
 func PathRateLimitMiddleware(rate, burst int) gin.HandlerFunc {
-	config := RateLimitConfig{
+	cfg := RateLimitConfig{
 		Rate: rate,
 		Burst: burst,
 		KeyFunc: func(c *gin.Context) string {
@@ -204,5 +232,5 @@ func PathRateLimitMiddleware(rate, burst int) gin.HandlerFunc {
 		},
 		CleanupInterval: time.Minute,
 	}
-	return RateLimitMiddlewareWithConfig(config)
+	return RateLimitMiddlewareWithConfig(cfg)
 }
@@ -0,0 +1,226 @@
+// Package clientgen 根据 OpenAPI 文档生成 Go 与 TypeScript 客户端
+//
+// 生成的客户端按本项目的接口约定工作：
+//   - 响应统一为 {code, message, data}，code 为 200 时返回 data，否则返回业务错误
+//   - 请求携带 Authorization: Bearer <accessToken>；响应 code 为 401 时使用 refreshToken
+//     调用刷新接口，成功后重试一次原请求
+//
+// 由 backend/cmd/gen_client.go 调用。
+package clientgen
+
+import (
+	"fst/backend/internal/openapi"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// RefreshPath 刷新令牌接口
+const RefreshPath = "/api/v1/public/refresh-token"
+
+// Header 生成文件的头部说明
+const Header = "Code generated by go run backend/cmd/gen_client.go. DO NOT EDIT."
+
+// model 与目标语言无关的接口模型
+type model struct {
+	doc   *openapi.Document
+	names map[string]string // components 键 → 类型名
+	types []string          // components 键，按类型名排序
+	ops   []*operation
+}
+
+type operation struct {
+	ID          string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	PathParams  []*param
+	Query       []*param
+	Body        *openapi.Schema // 只支持 JSON 请求体
+	Data        *openapi.Schema // 响应 data 的结构，nil 表示未声明
+	Raw         bool            // 响应不是 JSON（如文件下载），返回原始内容
+}
+
+type param struct {
+	Name        string
+	Description string
+	Required    bool
+	Schema      *openapi.Schema
+}
+
+// newModel 整理文档中的接口，不支持的接口（非 JSON 请求体）跳过并返回名称
+func newModel(doc *openapi.Document) (*model, []string) {
+	m := &model{doc: doc, names: typeNames(doc.Components.Schemas)}
+	for key := range doc.Components.Schemas {
+		m.types = append(m.types, key)
+	}
+	sort.Slice(m.types, func(i, j int) bool { return m.names[m.types[i]] < m.names[m.types[j]] })
+
+	var skipped []string
+	for p, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			o := &operation{
+				ID:          op.OperationID,
+				Method:      method,
+				Path:        p,
+				Summary:     op.Summary,
+				Description: op.Description,
+			}
+			for _, prm := range op.Parameters {
+				pa := &param{Name: prm.Name, Description: prm.Description, Required: prm.Required, Schema: prm.Schema}
+				switch prm.In {
+				case "path":
+					o.PathParams = append(o.PathParams, pa)
+				case "query":
+					o.Query = append(o.Query, pa)
+				}
+			}
+			if op.RequestBody != nil {
+				media, ok := op.RequestBody.Content["application/json"]
+				if !ok {
+					skipped = append(skipped, method+" "+p)
+					continue
+				}
+				o.Body = media.Schema
+				if o.Body == nil {
+					o.Body = &openapi.Schema{}
+				}
+			}
+			if resp := op.Responses["200"]; resp != nil && len(resp.Content) > 0 {
+				if media, ok := resp.Content["application/json"]; ok {
+					o.Data = m.envelopeData(media.Schema)
+				} else {
+					o.Raw = true
+				}
+			}
+			sortPathParams(o)
+			m.ops = append(m.ops, o)
+		}
+	}
+	sort.Slice(m.ops, func(i, j int) bool { return m.ops[i].ID < m.ops[j].ID })
+	sort.Strings(skipped)
+	return m, skipped
+}
+
+// envelopeData 取出 utils.Response{data=X} 中的 X
+func (m *model) envelopeData(s *openapi.Schema) *openapi.Schema {
+	if s == nil {
+		return nil
+	}
+	for _, part := range s.AllOf {
+		if part.Ref == "" && part.Properties["data"] != nil {
+			return part.Properties["data"]
+		}
+	}
+	return nil
+}
+
+// resolve 取出引用指向的结构
+func (m *model) resolve(s *openapi.Schema) *openapi.Schema {
+	for s != nil && s.Ref != "" {
+		s = m.doc.Components.Schemas[refKey(s.Ref)]
+	}
+	return s
+}
+
+// sortPathParams 补全文档中未声明的路径参数（按字符串处理），并按在路径中出现的顺序排列
+func sortPathParams(o *operation) {
+	declared := make(map[string]bool, len(o.PathParams))
+	for _, p := range o.PathParams {
+		declared[p.Name] = true
+	}
+	pathTemplate(o.Path, func(name string) string {
+		if !declared[name] {
+			declared[name] = true
+			o.PathParams = append(o.PathParams, &param{Name: name, Required: true, Schema: &openapi.Schema{Type: "string"}})
+		}
+		return ""
+	})
+
+	index := func(name string) int { return strings.Index(o.Path, "{"+name+"}") }
+	sort.SliceStable(o.PathParams, func(i, j int) bool {
+		return index(o.PathParams[i].Name) < index(o.PathParams[j].Name)
+	})
+}
+
+func refKey(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// runtimeNames 客户端运行时已使用的类型名
+var runtimeNames = map[string]bool{
+	"Client": true, "Error": true, "ApiClient": true, "ApiError": true, "Envelope": true,
+	"Tokens": true, "TokenStore": true, "ClientOptions": true, "QueryValue": true,
+}
+
+// typeNames 类型名取 components 键中的类型部分（admin.LoginRequest → LoginRequest），
+// 重名或与运行时类型同名时加包名前缀（AdminLoginRequest）
+func typeNames(schemas map[string]*openapi.Schema) map[string]string {
+	count := make(map[string]int)
+	for key := range schemas {
+		count[shortName(key)]++
+	}
+	names := make(map[string]string, len(schemas))
+	for key := range schemas {
+		name := shortName(key)
+		if count[name] > 1 || runtimeNames[name] {
+			name = pascal(key)
+		}
+		names[key] = name
+	}
+	return names
+}
+
+func shortName(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+	return pascal(key)
+}
+
+// pascal 将 user_id、email-logs、admin.Request 转换为 UserId、EmailLogs、AdminRequest
+func pascal(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if b.Len() == 0 || unicode.IsDigit(rune(b.String()[0])) {
+		return "X" + b.String()
+	}
+	return b.String()
+}
+
+func camel(s string) string {
+	p := pascal(s)
+	return strings.ToLower(p[:1]) + p[1:]
+}
+
+// comment 注释内容，多行说明合并为一行
+func comment(op *operation) string {
+	text := op.Summary
+	if text == "" {
+		text = op.Description
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// pathTemplate 将 /items/{id} 中的参数替换为 format(name) 的结果
+func pathTemplate(p string, format func(name string) string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(p, "{")
+		if start < 0 {
+			b.WriteString(p)
+			return b.String()
+		}
+		end := strings.Index(p[start:], "}")
+		if end < 0 {
+			b.WriteString(p)
+			return b.String()
+		}
+		b.WriteString(p[:start])
+		b.WriteString(format(p[start+1 : start+end]))
+		p = p[start+end+1:]
+	}
+}
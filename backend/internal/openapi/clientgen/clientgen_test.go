@@ -0,0 +1,253 @@
+package clientgen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fst/backend/internal/openapi"
+)
+
+func envelope(data *openapi.Schema) map[string]*openapi.Response {
+	schema := &openapi.Schema{AllOf: []*openapi.Schema{
+		{Ref: "#/components/schemas/utils.Response"},
+		{Type: "object", Properties: map[string]*openapi.Schema{"data": data}},
+	}}
+	return map[string]*openapi.Response{
+		"200": {Description: "OK", Content: map[string]*openapi.MediaType{"application/json": {Schema: schema}}},
+	}
+}
+
+func testDoc() *openapi.Document {
+	ref := func(key string) *openapi.Schema { return &openapi.Schema{Ref: "#/components/schemas/" + key} }
+	return &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: "Test API", Version: "1.0"},
+		Paths: map[string]*openapi.PathItem{
+			"/api/v1/public/login": {Post: &openapi.Operation{
+				OperationID: "publicAuthLogin",
+				Summary:     "用户登录",
+				RequestBody: &openapi.RequestBody{Content: map[string]*openapi.MediaType{
+					"application/json": {Schema: ref("public.LoginRequest")},
+				}},
+				Responses: envelope(ref("services.Session")),
+			}},
+			"/api/v1/items/{id}": {Get: &openapi.Operation{
+				OperationID: "userItemGet",
+				Parameters: []*openapi.Parameter{
+					{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}},
+					{Name: "with_files", In: "query", Schema: &openapi.Schema{Type: "boolean"}},
+				},
+				Responses: envelope(ref("models.Item")),
+			}},
+			"/api/v1/items": {Get: &openapi.Operation{
+				OperationID: "userItemList",
+				Responses:   envelope(&openapi.Schema{Type: "array", Items: ref("models.Item")}),
+			}},
+			"/api/v1/files/{path}": {Get: &openapi.Operation{
+				OperationID: "userFileDownload",
+				Responses: map[string]*openapi.Response{"200": {Description: "OK", Content: map[string]*openapi.MediaType{
+					"application/octet-stream": {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+				}}},
+			}},
+			"/api/v1/upload": {Post: &openapi.Operation{
+				OperationID: "userFileUpload",
+				RequestBody: &openapi.RequestBody{Content: map[string]*openapi.MediaType{
+					"multipart/form-data": {Schema: &openapi.Schema{Type: "object"}},
+				}},
+				Responses: map[string]*openapi.Response{"200": {Description: "OK"}},
+			}},
+		},
+		Components: openapi.Components{Schemas: map[string]*openapi.Schema{
+			"utils.Response": {Type: "object", Properties: map[string]*openapi.Schema{
+				"code":    {Type: "integer"},
+				"message": {Type: "string"},
+				"data":    {},
+			}},
+			"public.LoginRequest": {Type: "object", Required: []string{"username"}, Properties: map[string]*openapi.Schema{
+				"username": {Type: "string", Description: "用户名"},
+				"password": {Type: "string"},
+			}},
+			"services.Session": {Type: "object", Properties: map[string]*openapi.Schema{
+				"accessToken":  {Type: "string"},
+				"refreshToken": {Type: "string"},
+			}},
+			"models.Item": {Type: "object", Description: "Item 物品", Properties: map[string]*openapi.Schema{
+				"id":         {Type: "integer", Format: "int64"},
+				"name":       {Type: "string"},
+				"tags":       {Type: "array", Items: &openapi.Schema{Type: "string"}},
+				"created_at": {Type: "string", Format: "date-time"},
+			}},
+		}},
+	}
+}
+
+// runtimeTest 在生成的包中运行：验证 data 解包、业务错误与 401 刷新后重试
+const runtimeTest = `package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func reply(w http.ResponseWriter, code int, message string, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"code": code, "message": message, "data": data})
+}
+
+func TestClient(t *testing.T) {
+	var refreshes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/public/refresh-token":
+			var req map[string]string
+			json.NewDecoder(r.Body).Decode(&req)
+			if req["refreshToken"] != "r1" || req["authGuard"] != "user" {
+				reply(w, 401, "invalid refresh token", nil)
+				return
+			}
+			refreshes.Add(1)
+			reply(w, 200, "ok", map[string]any{"accessToken": "a2", "refreshToken": "r2", "expiresAt": 1})
+		case "/api/v1/items/7":
+			if r.Header.Get("Authorization") != "Bearer a2" {
+				reply(w, 401, "token expired", nil)
+				return
+			}
+			if r.URL.Query().Get("with_files") != "true" {
+				reply(w, 400, "missing query", nil)
+				return
+			}
+			reply(w, 200, "ok", map[string]any{"id": 7, "name": "seven", "tags": []string{"a"}})
+		case "/api/v1/items":
+			if r.Header.Get("X-API-Version") != "2" {
+				reply(w, 400, "missing version", nil)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]any{"code": 403, "message": "forbidden", "data": map[string]any{"error_code": "forbidden"}, "request_id": "req-1"})
+		case "/api/v1/files/a b.txt":
+			w.Write([]byte("content"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.SetTokens("a1", "r1")
+	var saved string
+	c.OnTokenRefresh = func(accessToken, refreshToken string, expiresAt int64) { saved = refreshToken }
+
+	withFiles := true
+	item, err := c.UserItemGet(context.Background(), 7, &UserItemGetQuery{WithFiles: &withFiles})
+	if err != nil {
+		t.Fatalf("UserItemGet: %v", err)
+	}
+	if item.Id != 7 || item.Name != "seven" || len(item.Tags) != 1 {
+		t.Fatalf("item = %+v", item)
+	}
+	if refreshes.Load() != 1 || saved != "r2" {
+		t.Fatalf("refreshes = %d, saved = %q", refreshes.Load(), saved)
+	}
+	if access, refresh := c.Tokens(); access != "a2" || refresh != "r2" {
+		t.Fatalf("tokens = %s, %s", access, refresh)
+	}
+
+	c.APIVersion = 2
+	_, err = c.UserItemList(context.Background())
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 403 || apiErr.Status != 403 || apiErr.ErrorCode() != "forbidden" || apiErr.RequestID != "req-1" {
+		t.Fatalf("UserItemList err = %#v", err)
+	}
+	c.APIVersion = 0
+
+	content, err := c.UserFileDownload(context.Background(), "a b.txt")
+	if err != nil || string(content) != "content" {
+		t.Fatalf("UserFileDownload = %q, %v", content, err)
+	}
+
+	// 刷新令牌失效时返回原始的 401 错误
+	c.SetTokens("expired", "bad")
+	if _, err := c.UserItemGet(context.Background(), 7, nil); !errors.As(err, &apiErr) || apiErr.Code != 401 {
+		t.Fatalf("expired err = %v", err)
+	}
+}
+`
+
+func TestGo(t *testing.T) {
+	src, skipped, err := Go(testDoc(), "apiclient")
+	if err != nil {
+		t.Fatalf("Go: %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != "POST /api/v1/upload" {
+		t.Fatalf("skipped = %v", skipped)
+	}
+	for _, want := range []string{
+		"func (c *Client) PublicAuthLogin(ctx context.Context, body LoginRequest) (*Session, error)",
+		"func (c *Client) UserItemGet(ctx context.Context, id int64, query *UserItemGetQuery) (*Item, error)",
+		"func (c *Client) UserItemList(ctx context.Context) ([]Item, error)",
+		"func (c *Client) UserFileDownload(ctx context.Context, pathParam string) ([]byte, error)",
+		"// Item 物品",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("生成的 Go 客户端缺少 %q", want)
+		}
+	}
+	if testing.Short() {
+		return
+	}
+
+	// 在独立模块中编译并运行生成的客户端
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module apiclient\n\ngo 1.24\n",
+		"client.go":      string(src),
+		"client_test.go": runtimeTest,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("go", "test", "-count=1", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("生成的客户端测试失败: %v\n%s", err, out)
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	src, skipped := TypeScript(testDoc())
+	if len(skipped) != 1 {
+		t.Fatalf("skipped = %v", skipped)
+	}
+	for _, want := range []string{
+		"export interface Item {",
+		"  created_at?: string",
+		"  tags?: string[]",
+		"export interface LoginRequest {",
+		"  username: string",
+		"publicAuthLogin(body: LoginRequest): Promise<Session>",
+		"userItemGet(id: number, query?: { with_files?: boolean }): Promise<Item>",
+		"`/api/v1/items/${encodeURIComponent(String(id))}`",
+		"userItemList(): Promise<Item[]>",
+		"userFileDownload(path: string): Promise<Blob>",
+		"'" + RefreshPath + "'",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("生成的 TypeScript 客户端缺少 %q", want)
+		}
+	}
+	if strings.Contains(string(src), "userFileUpload") {
+		t.Error("multipart 接口应跳过")
+	}
+}
@@ -0,0 +1,502 @@
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"fst/backend/internal/openapi"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Go 生成单文件 Go 客户端包，只依赖标准库
+func Go(doc *openapi.Document, pkg string) ([]byte, []string, error) {
+	m, skipped := newModel(doc)
+	g := &goGen{model: m}
+
+	fmt.Fprintf(&g.buf, "// %s\n\n", Header)
+	fmt.Fprintf(&g.buf, "// Package %s %s 客户端\n", pkg, doc.Info.Title)
+	fmt.Fprintf(&g.buf, "package %s\n\n", pkg)
+	g.buf.WriteString(goImports)
+	g.buf.WriteString(strings.ReplaceAll(goRuntime, "{{refreshPath}}", strconv.Quote(RefreshPath)))
+
+	for _, key := range m.types {
+		g.typeDecl(key)
+	}
+	for _, op := range m.ops {
+		g.method(op)
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return g.buf.Bytes(), skipped, fmt.Errorf("格式化生成的 Go 代码失败: %v", err)
+	}
+	return src, skipped, nil
+}
+
+type goGen struct {
+	*model
+	buf bytes.Buffer
+}
+
+// typeDecl 结构体声明，字段按 JSON 名称排序
+func (g *goGen) typeDecl(key string) {
+	s := g.doc.Components.Schemas[key]
+	name := g.names[key]
+	g.buf.WriteString("\n")
+	if s.Description != "" {
+		writeGoComment(&g.buf, "", s.Description)
+	} else {
+		fmt.Fprintf(&g.buf, "// %s %s\n", name, key)
+	}
+	if len(s.Properties) == 0 {
+		fmt.Fprintf(&g.buf, "type %s map[string]any\n", name)
+		return
+	}
+
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	fields := make([]string, 0, len(s.Properties))
+	for f := range s.Properties {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	fmt.Fprintf(&g.buf, "type %s struct {\n", name)
+	used := make(map[string]bool)
+	for _, f := range fields {
+		prop := s.Properties[f]
+		field := pascal(f)
+		for used[field] {
+			field += "_"
+		}
+		used[field] = true
+
+		if desc := propertyDescription(prop); desc != "" {
+			writeGoComment(&g.buf, "\t", desc)
+		}
+		tag := f
+		if !required[f] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s `json:%q`\n", field, g.goType(prop), tag)
+	}
+	g.buf.WriteString("}\n")
+}
+
+// method 接口方法：路径参数按顺序传入，查询参数使用 <方法名>Query 结构体
+func (g *goGen) method(op *operation) {
+	name := pascal(op.ID)
+	args := []string{"ctx context.Context"}
+	used := map[string]bool{"ctx": true, "query": true, "body": true, "out": true, "path": true}
+	pathArgs := make(map[string]string)
+	for _, p := range op.PathParams {
+		arg := goIdent(camel(p.Name), used)
+		pathArgs[p.Name] = arg
+		args = append(args, arg+" "+g.scalarType(p.Schema))
+	}
+	if len(op.Query) > 0 {
+		args = append(args, "query *"+name+"Query")
+	}
+	if op.Body != nil {
+		args = append(args, "body "+g.goType(op.Body))
+	}
+
+	result := "json.RawMessage"
+	switch {
+	case op.Raw:
+		result = "[]byte"
+	case op.Data != nil:
+		result = g.goType(op.Data)
+		if isStructRef(g.model, op.Data) {
+			result = "*" + result
+		}
+	}
+
+	var parts []string
+	literal := pathTemplate(op.Path, func(param string) string {
+		if pathArgs[param] == "" {
+			return "{" + param + "}"
+		}
+		return "\x00" + param + "\x00"
+	})
+	for i, part := range strings.Split(literal, "\x00") {
+		switch {
+		case i%2 == 1:
+			parts = append(parts, g.pathValue(pathArgs[part], op, part))
+		case part != "":
+			parts = append(parts, strconv.Quote(part))
+		}
+	}
+	path := strings.Join(parts, " + ")
+	query := "nil"
+	if len(op.Query) > 0 {
+		query = "query.values()"
+	}
+	body := "nil"
+	if op.Body != nil {
+		body = "body"
+	}
+
+	g.buf.WriteString("\n")
+	if c := comment(op); c != "" {
+		fmt.Fprintf(&g.buf, "// %s %s\n//\n", name, c)
+	}
+	fmt.Fprintf(&g.buf, "// %s %s\n", op.Method, op.Path)
+	fmt.Fprintf(&g.buf, "func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(args, ", "), result)
+	switch {
+	case op.Raw:
+		fmt.Fprintf(&g.buf, "\treturn c.doRaw(ctx, %q, %s, %s, %s)\n", op.Method, path, query, body)
+	case strings.HasPrefix(result, "*"):
+		fmt.Fprintf(&g.buf, "\tout := new(%s)\n", strings.TrimPrefix(result, "*"))
+		fmt.Fprintf(&g.buf, "\tif err := c.do(ctx, %q, %s, %s, %s, out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn out, nil\n", op.Method, path, query, body)
+	default:
+		fmt.Fprintf(&g.buf, "\tvar out %s\n", result)
+		fmt.Fprintf(&g.buf, "\terr := c.do(ctx, %q, %s, %s, %s, &out)\n\treturn out, err\n", op.Method, path, query, body)
+	}
+	g.buf.WriteString("}\n")
+
+	if len(op.Query) > 0 {
+		g.queryType(name, op.Query)
+	}
+}
+
+// queryType 查询参数结构体；可选参数使用指针，区分未设置与零值
+func (g *goGen) queryType(name string, params []*param) {
+	fmt.Fprintf(&g.buf, "\n// %sQuery %s 的查询参数\n", name, name)
+	fmt.Fprintf(&g.buf, "type %sQuery struct {\n", name)
+	fields := make([]string, len(params))
+	used := make(map[string]bool)
+	for i, p := range params {
+		fields[i] = pascal(p.Name)
+		for used[fields[i]] {
+			fields[i] += "_"
+		}
+		used[fields[i]] = true
+		if p.Description != "" {
+			writeGoComment(&g.buf, "\t", p.Description)
+		}
+		typ := g.scalarType(p.Schema)
+		if !p.Required {
+			typ = "*" + typ
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s\n", fields[i], typ)
+	}
+	g.buf.WriteString("}\n\n")
+
+	fmt.Fprintf(&g.buf, "func (q *%sQuery) values() url.Values {\n\tv := url.Values{}\n\tif q == nil {\n\t\treturn v\n\t}\n", name)
+	for i, p := range params {
+		if p.Required {
+			fmt.Fprintf(&g.buf, "\tv.Set(%q, fmt.Sprint(q.%s))\n", p.Name, fields[i])
+		} else {
+			fmt.Fprintf(&g.buf, "\tif q.%s != nil {\n\t\tv.Set(%q, fmt.Sprint(*q.%s))\n\t}\n", fields[i], p.Name, fields[i])
+		}
+	}
+	g.buf.WriteString("\treturn v\n}\n")
+}
+
+func (g *goGen) pathValue(arg string, op *operation, name string) string {
+	for _, p := range op.PathParams {
+		if p.Name == name && g.scalarType(p.Schema) != "string" {
+			return "url.PathEscape(fmt.Sprint(" + arg + "))"
+		}
+	}
+	return "url.PathEscape(" + arg + ")"
+}
+
+// scalarType 路径与查询参数的类型
+func (g *goGen) scalarType(s *openapi.Schema) string {
+	switch t := g.goType(s); t {
+	case "int64", "float64", "bool", "string":
+		return t
+	}
+	return "string"
+}
+
+// goType Schema 对应的 Go 类型
+func (g *goGen) goType(s *openapi.Schema) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		return g.names[refKey(s.Ref)]
+	}
+	if len(s.AllOf) == 1 {
+		return g.goType(s.AllOf[0])
+	}
+	if len(s.AllOf) > 1 {
+		return "json.RawMessage"
+	}
+	switch s.Type {
+	case "string":
+		if s.Format == "byte" {
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(s.Items)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + g.goType(s.AdditionalProperties)
+		}
+		return "map[string]any"
+	}
+	return "any"
+}
+
+func isStructRef(m *model, s *openapi.Schema) bool {
+	if s.Ref == "" {
+		return false
+	}
+	resolved := m.resolve(s)
+	return resolved != nil && len(resolved.Properties) > 0
+}
+
+func propertyDescription(s *openapi.Schema) string {
+	if s.Description != "" {
+		return s.Description
+	}
+	if len(s.AllOf) == 1 {
+		return s.AllOf[0].Description
+	}
+	return ""
+}
+
+// goIdent 参数名，避开关键字与已使用的名称
+func goIdent(name string, used map[string]bool) string {
+	if token.IsKeyword(name) || used[name] {
+		name += "Param"
+	}
+	for used[name] {
+		name += "_"
+	}
+	used[name] = true
+	return name
+}
+
+func writeGoComment(buf *bytes.Buffer, indent, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(buf, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+const goImports = `import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+`
+
+// goRuntime 客户端运行时：信封解析与令牌刷新
+const goRuntime = `
+// refreshPath 刷新令牌接口
+const refreshPath = {{refreshPath}}
+
+// Client API 客户端，可并发使用
+type Client struct {
+	// BaseURL 服务地址，如 http://localhost:8080
+	BaseURL    string
+	HTTPClient *http.Client
+	// AuthGuard 刷新令牌时的登录端：user / admin
+	AuthGuard string
+	// OnTokenRefresh 令牌刷新成功后调用，可用于持久化新令牌
+	OnTokenRefresh func(accessToken, refreshToken string, expiresAt int64)
+	// APIVersion 非 0 时发送 X-API-Version 请求头；2 表示错误响应使用真实的 HTTP 状态码并附带错误码
+	APIVersion int
+
+	mu           sync.Mutex
+	refreshMu    sync.Mutex
+	accessToken  string
+	refreshToken string
+}
+
+// New 创建客户端
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+		AuthGuard:  "user",
+	}
+}
+
+// SetTokens 设置访问令牌与刷新令牌（登录接口返回的 accessToken、refreshToken）
+func (c *Client) SetTokens(accessToken, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken, c.refreshToken = accessToken, refreshToken
+}
+
+// Tokens 当前的访问令牌与刷新令牌
+func (c *Client) Tokens() (accessToken, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.accessToken, c.refreshToken
+}
+
+// Error 业务错误：响应 code 不为 200
+type Error struct {
+	Status    int             // HTTP 状态码
+	Code      int             ` + "`json:\"code\"`" + `
+	Message   string          ` + "`json:\"message\"`" + `
+	Data      json.RawMessage ` + "`json:\"data\"`" + `
+	RequestID string          ` + "`json:\"request_id\"`" + `
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.Code, e.Message)
+}
+
+// ErrorCode 稳定的错误码（如 validation_failed），API 版本 2 下才有
+func (e *Error) ErrorCode() string {
+	var data struct {
+		ErrorCode string ` + "`json:\"error_code\"`" + `
+	}
+	json.Unmarshal(e.Data, &data)
+	return data.ErrorCode
+}
+
+// do 发送 JSON 请求，将响应中的 data 解析到 out
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	data, err := c.send(ctx, method, path, query, body, true)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// doRaw 发送请求并返回原始响应内容（文件下载等非 JSON 接口）
+func (c *Client) doRaw(ctx context.Context, method, path string, query url.Values, body any) ([]byte, error) {
+	resp, err := c.request(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		var apiErr Error
+		if json.Unmarshal(content, &apiErr) == nil && apiErr.Code != 0 && apiErr.Code != 200 {
+			apiErr.Status = resp.StatusCode
+			return nil, &apiErr
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &Error{Status: resp.StatusCode, Code: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	}
+	return content, nil
+}
+
+// send 发送请求并解析 {code, message, data}；code 为 401 时刷新令牌后重试一次
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body any, retry bool) (json.RawMessage, error) {
+	accessToken, _ := c.Tokens()
+	resp, err := c.request(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var env Error
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, &Error{Status: resp.StatusCode, Code: resp.StatusCode, Message: fmt.Sprintf("解析响应失败: %v", err)}
+	}
+	env.Status = resp.StatusCode
+	if env.Code == 200 {
+		return env.Data, nil
+	}
+	if (env.Code == 401 || resp.StatusCode == http.StatusUnauthorized) && retry && path != refreshPath && c.refresh(ctx, accessToken) {
+		return c.send(ctx, method, path, query, body, false)
+	}
+	return nil, &env
+}
+
+func (c *Client) request(ctx context.Context, method, path string, query url.Values, body any) (*http.Response, error) {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.APIVersion != 0 {
+		req.Header.Set("X-API-Version", strconv.Itoa(c.APIVersion))
+	}
+	if accessToken, _ := c.Tokens(); accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// refresh 使用刷新令牌换取新令牌；并发请求同时过期时只刷新一次
+func (c *Client) refresh(ctx context.Context, expired string) bool {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	accessToken, refreshToken := c.Tokens()
+	if accessToken != expired {
+		// 其他请求已完成刷新
+		return accessToken != ""
+	}
+	if refreshToken == "" {
+		return false
+	}
+
+	data, err := c.send(ctx, http.MethodPost, refreshPath, nil, map[string]string{
+		"refreshToken": refreshToken,
+		"authGuard":    c.AuthGuard,
+	}, false)
+	if err != nil {
+		return false
+	}
+	var tokens struct {
+		AccessToken  string ` + "`json:\"accessToken\"`" + `
+		RefreshToken string ` + "`json:\"refreshToken\"`" + `
+		ExpiresAt    int64  ` + "`json:\"expiresAt\"`" + `
+	}
+	if json.Unmarshal(data, &tokens) != nil || tokens.AccessToken == "" {
+		return false
+	}
+	c.SetTokens(tokens.AccessToken, tokens.RefreshToken)
+	if c.OnTokenRefresh != nil {
+		c.OnTokenRefresh(tokens.AccessToken, tokens.RefreshToken, tokens.ExpiresAt)
+	}
+	return true
+}
+`
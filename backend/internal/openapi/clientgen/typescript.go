@@ -0,0 +1,420 @@
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"fst/backend/internal/openapi"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TypeScript 生成单文件 TypeScript 客户端，基于 fetch，不依赖第三方库
+func TypeScript(doc *openapi.Document) ([]byte, []string) {
+	m, skipped := newModel(doc)
+	g := &tsGen{model: m}
+
+	g.buf.WriteString("/* eslint-disable */\n")
+	fmt.Fprintf(&g.buf, "// %s\n", Header)
+	fmt.Fprintf(&g.buf, "// %s 客户端\n", doc.Info.Title)
+	for _, key := range m.types {
+		g.typeDecl(key)
+	}
+	g.buf.WriteString(strings.ReplaceAll(tsRuntime, "{{refreshPath}}", "'"+RefreshPath+"'"))
+	for _, op := range m.ops {
+		g.method(op)
+	}
+	g.buf.WriteString("}\n")
+	return g.buf.Bytes(), skipped
+}
+
+type tsGen struct {
+	*model
+	buf bytes.Buffer
+}
+
+func (g *tsGen) typeDecl(key string) {
+	s := g.doc.Components.Schemas[key]
+	name := g.names[key]
+	g.buf.WriteString("\n")
+	if s.Description != "" {
+		writeTSDoc(&g.buf, "", s.Description)
+	}
+	if len(s.Properties) == 0 {
+		fmt.Fprintf(&g.buf, "export type %s = Record<string, unknown>\n", name)
+		return
+	}
+	fmt.Fprintf(&g.buf, "export interface %s {\n", name)
+	g.properties(s, "  ")
+	g.buf.WriteString("}\n")
+}
+
+// properties 对象属性，按名称排序
+func (g *tsGen) properties(s *openapi.Schema, indent string) {
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop := s.Properties[name]
+		if desc := propertyDescription(prop); desc != "" {
+			writeTSDoc(&g.buf, indent, desc)
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		fmt.Fprintf(&g.buf, "%s%s%s: %s\n", indent, tsKey(name), optional, g.tsType(prop))
+	}
+}
+
+// method 接口方法：路径参数按顺序传入，其后为查询参数对象与请求体
+func (g *tsGen) method(op *operation) {
+	used := map[string]bool{"query": true, "body": true}
+	var args []string
+	pathArgs := make(map[string]string)
+	for _, p := range op.PathParams {
+		arg := tsIdent(camel(p.Name), used)
+		pathArgs[p.Name] = arg
+		args = append(args, arg+": "+g.tsType(p.Schema))
+	}
+	if len(op.Query) > 0 {
+		var fields []string
+		optional := "?"
+		for _, p := range op.Query {
+			mark := "?"
+			if p.Required {
+				mark, optional = "", ""
+			}
+			fields = append(fields, tsKey(p.Name)+mark+": "+g.tsType(p.Schema))
+		}
+		args = append(args, "query"+optional+": { "+strings.Join(fields, ", ")+" }")
+	}
+	if op.Body != nil {
+		args = append(args, "body: "+g.tsType(op.Body))
+	}
+
+	result := "unknown"
+	switch {
+	case op.Raw:
+		result = "Blob"
+	case op.Data != nil:
+		result = g.tsType(op.Data)
+	}
+
+	path := pathTemplate(op.Path, func(param string) string {
+		if arg := pathArgs[param]; arg != "" {
+			return "${encodeURIComponent(String(" + arg + "))}"
+		}
+		return "{" + param + "}"
+	})
+	query, body := "undefined", "undefined"
+	if len(op.Query) > 0 {
+		query = "query"
+	}
+	if op.Body != nil {
+		body = "body"
+	}
+	raw := ""
+	if op.Raw {
+		raw = ", true"
+	}
+
+	g.buf.WriteString("\n")
+	doc := op.Method + " " + op.Path
+	if c := comment(op); c != "" {
+		doc = c + "\n" + doc
+	}
+	writeTSDoc(&g.buf, "  ", doc)
+	fmt.Fprintf(&g.buf, "  %s(%s): Promise<%s> {\n", op.ID, strings.Join(args, ", "), result)
+	fmt.Fprintf(&g.buf, "    return this.request<%s>('%s', `%s`, %s, %s%s)\n", result, op.Method, path, query, body, raw)
+	g.buf.WriteString("  }\n")
+}
+
+// tsType Schema 对应的 TypeScript 类型
+func (g *tsGen) tsType(s *openapi.Schema) string {
+	if s == nil {
+		return "unknown"
+	}
+	t := g.baseType(s)
+	if s.Nullable {
+		t += " | null"
+	}
+	return t
+}
+
+func (g *tsGen) baseType(s *openapi.Schema) string {
+	if s.Ref != "" {
+		return g.names[refKey(s.Ref)]
+	}
+	if len(s.AllOf) > 0 {
+		parts := make([]string, len(s.AllOf))
+		for i, part := range s.AllOf {
+			parts[i] = g.tsType(part)
+		}
+		return strings.Join(parts, " & ")
+	}
+	switch s.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := g.tsType(s.Items)
+		if !tsSimpleType.MatchString(item) {
+			return "Array<" + item + ">"
+		}
+		return item + "[]"
+	case "object":
+		if len(s.Properties) > 0 {
+			var fields []string
+			required := make(map[string]bool, len(s.Required))
+			for _, r := range s.Required {
+				required[r] = true
+			}
+			names := make([]string, 0, len(s.Properties))
+			for name := range s.Properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				mark := "?"
+				if required[name] {
+					mark = ""
+				}
+				fields = append(fields, tsKey(name)+mark+": "+g.tsType(s.Properties[name]))
+			}
+			return "{ " + strings.Join(fields, ", ") + " }"
+		}
+		if s.AdditionalProperties != nil {
+			return "Record<string, " + g.tsType(s.AdditionalProperties) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+var (
+	tsSimpleType = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\[\])*$`)
+	tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+)
+
+// tsReserved 不能作为参数名的保留字
+var tsReserved = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"let": true, "static": true, "yield": true, "await": true, "implements": true,
+	"interface": true, "package": true, "private": true, "protected": true, "public": true,
+}
+
+func tsIdent(name string, used map[string]bool) string {
+	if tsReserved[name] || used[name] {
+		name += "Param"
+	}
+	for used[name] {
+		name += "_"
+	}
+	used[name] = true
+	return name
+}
+
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return "'" + strings.ReplaceAll(name, "'", "\\'") + "'"
+}
+
+func writeTSDoc(buf *bytes.Buffer, indent, text string) {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(text, "*/", "* /")), "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s * %s\n", indent, strings.TrimSpace(line))
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// tsRuntime 客户端运行时：信封解析与令牌刷新（类定义的开头，接口方法追加在其后）
+const tsRuntime = `
+const REFRESH_PATH = {{refreshPath}}
+
+/** 接口返回的 {code, message, data} */
+export interface Envelope<T = unknown> {
+  code: number
+  message: string
+  data: T
+  request_id?: string
+}
+
+/** 业务错误：响应 code 不为 200 */
+export class ApiError extends Error {
+  readonly code: number
+  readonly status: number
+  readonly data: unknown
+  readonly requestId?: string
+
+  constructor(code: number, message: string, status: number, data: unknown = null, requestId?: string) {
+    super(message)
+    this.name = 'ApiError'
+    this.code = code
+    this.status = status
+    this.data = data
+    this.requestId = requestId
+  }
+
+  /** 稳定的错误码（如 validation_failed），API 版本 2 下才有 */
+  get errorCode(): string | undefined {
+    return (this.data as { error_code?: string } | null)?.error_code
+  }
+}
+
+/** 登录与刷新接口返回的令牌 */
+export interface Tokens {
+  accessToken: string
+  refreshToken: string
+  expiresAt?: number
+}
+
+/** 令牌存储，浏览器中可基于 localStorage 实现 */
+export interface TokenStore {
+  get: () => Tokens | null
+  set: (tokens: Tokens) => void
+}
+
+/** 内存中的令牌存储 */
+export function memoryTokenStore(initial: Tokens | null = null): TokenStore {
+  let tokens = initial
+  return {
+    get: () => tokens,
+    set: (value) => {
+      tokens = value
+    },
+  }
+}
+
+export interface ClientOptions {
+  /** 服务地址，默认为当前站点 */
+  baseURL?: string
+  /** 刷新令牌时的登录端 */
+  authGuard?: 'user' | 'admin'
+  tokenStore?: TokenStore
+  fetch?: typeof fetch
+  /** 每个请求附加的请求头，如 { 'X-API-Version': '2' } 使用真实的 HTTP 状态码 */
+  headers?: () => Record<string, string>
+  /** 令牌刷新失败（需要重新登录）时调用 */
+  onUnauthorized?: () => void
+}
+
+type QueryValue = string | number | boolean | null | undefined
+
+function buildQuery(query?: object): string {
+  if (!query)
+    return ''
+  const params = new URLSearchParams()
+  for (const [key, value] of Object.entries(query as Record<string, QueryValue>)) {
+    if (value !== undefined && value !== null)
+      params.append(key, String(value))
+  }
+  const text = params.toString()
+  return text ? ` + "`?${text}`" + ` : ''
+}
+
+export class ApiClient {
+  private readonly baseURL: string
+  private readonly authGuard: 'user' | 'admin'
+  private readonly tokenStore: TokenStore
+  private readonly fetchImpl: typeof fetch
+  private readonly options: ClientOptions
+  private refreshing: Promise<boolean> | null = null
+
+  constructor(options: ClientOptions = {}) {
+    this.options = options
+    this.baseURL = (options.baseURL ?? '').replace(/\/+$/, '')
+    this.authGuard = options.authGuard ?? 'user'
+    this.tokenStore = options.tokenStore ?? memoryTokenStore()
+    this.fetchImpl = options.fetch ?? globalThis.fetch.bind(globalThis)
+  }
+
+  /** 发送请求，返回响应中的 data；code 为 401 时刷新令牌后重试一次 */
+  async request<T>(method: string, path: string, query?: object, body?: unknown, raw = false): Promise<T> {
+    return this.send<T>(method, path, query, body, raw, true)
+  }
+
+  private async send<T>(method: string, path: string, query: object | undefined, body: unknown, raw: boolean, retry: boolean): Promise<T> {
+    const tokens = this.tokenStore.get()
+    const headers: Record<string, string> = { ...this.options.headers?.() }
+    if (tokens?.accessToken)
+      headers.Authorization = ` + "`Bearer ${tokens.accessToken}`" + `
+    if (body !== undefined)
+      headers['Content-Type'] = 'application/json'
+
+    const response = await this.fetchImpl(this.baseURL + path + buildQuery(query), {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    })
+
+    const isJSON = (response.headers.get('Content-Type') ?? '').includes('application/json')
+    if (raw && response.ok && !isJSON)
+      return await response.blob() as T
+
+    let payload: Envelope<T>
+    try {
+      payload = await response.json() as Envelope<T>
+    }
+    catch {
+      throw new ApiError(response.status, response.statusText || '解析响应失败', response.status)
+    }
+    if (payload.code === 200)
+      return payload.data
+
+    if ((payload.code === 401 || response.status === 401) && retry && path !== REFRESH_PATH) {
+      if (await this.refresh(tokens?.accessToken))
+        return this.send<T>(method, path, query, body, raw, false)
+    }
+    throw new ApiError(payload.code, payload.message, response.status, payload.data, payload.request_id)
+  }
+
+  /** 刷新令牌；并发请求同时过期时共用一次刷新 */
+  private refresh(expired?: string): Promise<boolean> {
+    if (!this.refreshing) {
+      this.refreshing = this.doRefresh(expired).finally(() => {
+        this.refreshing = null
+      })
+    }
+    return this.refreshing
+  }
+
+  private async doRefresh(expired?: string): Promise<boolean> {
+    const tokens = this.tokenStore.get()
+    if (tokens?.accessToken && tokens.accessToken !== expired)
+      return true
+    if (tokens?.refreshToken) {
+      try {
+        const data = await this.send<Tokens>('POST', REFRESH_PATH, undefined, { refreshToken: tokens.refreshToken, authGuard: this.authGuard }, false, false)
+        if (data?.accessToken) {
+          this.tokenStore.set({ accessToken: data.accessToken, refreshToken: data.refreshToken, expiresAt: data.expiresAt })
+          return true
+        }
+      }
+      catch {}
+    }
+    this.options.onUnauthorized?.()
+    return false
+  }
+`
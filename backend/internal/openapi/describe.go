@@ -0,0 +1,140 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// BearerAuth 登录鉴权方式的名称，Auth 引用，由 routes.setupOpenAPI 声明
+const BearerAuth = "BearerAuth"
+
+// Op 创建接口说明，配合 Route / Describe 在注册路由时登记；tags 为接口分组
+// 以下方法均返回副本，可以从同一个 Operation 派生多个接口
+func Op(summary string, tags ...string) Operation {
+	return Operation{Summary: summary, Tags: tags}
+}
+
+// Notes 追加详细说明，多次调用按行拼接
+func (op Operation) Notes(desc string) Operation {
+	if op.Description != "" {
+		op.Description += "\n"
+	}
+	op.Description += desc
+	return op
+}
+
+// Auth 需要登录（BearerAuth）
+func (op Operation) Auth() Operation {
+	op.Security = append(op.Security[:len(op.Security):len(op.Security)], map[string][]string{BearerAuth: {}})
+	return op
+}
+
+// Path 路径参数，typ 为 int、string 等基础类型名
+func (op Operation) Path(name, typ, desc string) Operation {
+	return op.param("path", name, typ, desc, true)
+}
+
+// Query 查询参数，typ 为 int、string 等基础类型名
+func (op Operation) Query(name, typ, desc string) Operation {
+	return op.param("query", name, typ, desc, false)
+}
+
+// Paged 分页参数 page、page_size
+func (op Operation) Paged() Operation {
+	return op.Query("page", "int", "页码").Default(1).Query("page_size", "int", "每页数量").Default(20)
+}
+
+// Default 设置上一个参数的默认值
+func (op Operation) Default(value interface{}) Operation {
+	return op.last(func(p *Parameter) {
+		schema := *p.Schema
+		schema.Default = value
+		p.Schema = &schema
+	})
+}
+
+// Required 将上一个参数标记为必填
+func (op Operation) Required() Operation {
+	return op.last(func(p *Parameter) { p.Required = true })
+}
+
+// Body JSON 请求体，value 为请求结构体（或 map 等）的零值，生成文档时通过反射解析
+func (op Operation) Body(value interface{}, desc string) Operation {
+	op.RequestBody = &RequestBody{
+		Description: desc,
+		Required:    true,
+		Content:     jsonContent(&Schema{goType: reflect.TypeOf(value)}),
+	}
+	return op
+}
+
+// Returns 成功响应，value 为统一响应结构中数据字段的值，nil 表示不声明数据结构
+func (op Operation) Returns(value interface{}) Operation {
+	return op.respond(http.StatusOK, &Response{Description: "OK", Content: jsonContent(dataSchema(value))})
+}
+
+// Fails 可能返回的错误状态码，响应体同为统一响应结构
+func (op Operation) Fails(codes ...int) Operation {
+	for _, code := range codes {
+		op = op.respond(code, &Response{Description: http.StatusText(code), Content: jsonContent(dataSchema(nil))})
+	}
+	return op
+}
+
+// Produces 非 JSON 的成功响应，如 text/event-stream
+func (op Operation) Produces(mime, desc string) Operation {
+	return op.respond(http.StatusOK, &Response{
+		Description: desc,
+		Content:     map[string]*MediaType{mime: {Schema: &Schema{Type: "string"}}},
+	})
+}
+
+func (op Operation) param(in, name, typ, desc string, required bool) Operation {
+	schema := basicSchemas[typ]
+	op.Parameters = append(op.Parameters[:len(op.Parameters):len(op.Parameters)], &Parameter{
+		Name:        name,
+		In:          in,
+		Description: desc,
+		Required:    required,
+		Schema:      &schema,
+	})
+	return op
+}
+
+// last 修改上一个参数，复制后替换，不影响派生出当前接口的 Operation
+func (op Operation) last(modify func(*Parameter)) Operation {
+	n := len(op.Parameters)
+	if n == 0 {
+		return op
+	}
+	params := append([]*Parameter(nil), op.Parameters...)
+	p := *params[n-1]
+	modify(&p)
+	params[n-1] = &p
+	op.Parameters = params
+	return op
+}
+
+func (op Operation) respond(code int, resp *Response) Operation {
+	responses := make(map[string]*Response, len(op.Responses)+1)
+	for k, v := range op.Responses {
+		responses[k] = v
+	}
+	responses[strconv.Itoa(code)] = resp
+	op.Responses = responses
+	return op
+}
+
+// dataSchema 统一响应结构中的数据，value 为 nil 时只有响应结构本身
+func dataSchema(value interface{}) *Schema {
+	schema := &Schema{enveloped: true}
+	if value != nil {
+		schema.goType = reflect.TypeOf(value)
+	}
+	return schema
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
@@ -0,0 +1,306 @@
+// Package openapi 在运行时生成 OpenAPI 3 接口文档
+//
+// 接口列表取自 gin 实际注册的路由，说明由注册路由时调用 Route / Describe 在代码中提供：
+//
+//	users.GET("/:id", ctrl.Detail)
+//	openapi.Route(users, "GET", "/:id", openapi.Op("获取用户详情", "Admin-用户管理").Auth().
+//		Path("id", "int", "用户ID").Returns(models.User{}))
+//
+// 请求体与响应数据通过反射生成 Schema，不需要 swag 命令、生成的 docs.go 或解析源码。
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Registry 文档元数据登记处
+type Registry struct {
+	mu        sync.Mutex
+	info      Info
+	schemes   map[string]*SecurityScheme
+	envelope  *envelope
+	described map[string]*Operation // "METHOD /path/{param}"
+}
+
+// envelope 统一响应结构，Returns 声明的数据放在 field 字段中
+type envelope struct {
+	t     reflect.Type
+	field string
+}
+
+// NewRegistry 创建登记处
+func NewRegistry() *Registry {
+	return &Registry{
+		info:      Info{Title: "API", Version: "1.0"},
+		schemes:   make(map[string]*SecurityScheme),
+		described: make(map[string]*Operation),
+	}
+}
+
+// Default 全局登记处
+var Default = NewRegistry()
+
+// SetInfo 设置文档基本信息
+func SetInfo(info Info) { Default.SetInfo(info) }
+
+// AddSecurityScheme 声明鉴权方式，Operation.Auth 引用 BearerAuth
+func AddSecurityScheme(name string, scheme SecurityScheme) {
+	Default.AddSecurityScheme(name, scheme)
+}
+
+// SetEnvelope 设置统一响应结构（如 utils.Response），field 为数据字段的 JSON 名称
+func SetEnvelope(value interface{}, field string) { Default.SetEnvelope(value, field) }
+
+// Describe 声明接口，path 可使用 gin 格式（:id）
+func Describe(method, path string, op Operation) { Default.Describe(method, path, op) }
+
+// Route 声明 group 下的接口，relativePath 与注册路由时一致
+func Route(group *gin.RouterGroup, method, relativePath string, op Operation) {
+	Default.Describe(method, path.Join(group.BasePath(), relativePath), op)
+}
+
+// Handler 返回 /openapi.json 处理器
+func Handler(engine *gin.Engine) gin.HandlerFunc { return Default.Handler(engine) }
+
+// BuildEngine 为 engine 中 /api/ 下的路由生成文档（客户端生成等离线场景使用）
+func BuildEngine(engine *gin.Engine) *Document { return Default.BuildEngine(engine) }
+
+func (r *Registry) SetInfo(info Info) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.info = info
+}
+
+func (r *Registry) AddSecurityScheme(name string, scheme SecurityScheme) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemes[name] = &scheme
+}
+
+func (r *Registry) SetEnvelope(value interface{}, field string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.envelope = &envelope{t: reflect.TypeOf(value), field: field}
+}
+
+func (r *Registry) Describe(method, routePath string, op Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.described[strings.ToUpper(method)+" "+specPath(routePath)] = &op
+}
+
+// Handler 首次请求时生成文档并缓存
+func (r *Registry) Handler(engine *gin.Engine) gin.HandlerFunc {
+	var once sync.Once
+	var body []byte
+	return func(c *gin.Context) {
+		once.Do(func() {
+			body, _ = json.Marshal(r.BuildEngine(engine))
+		})
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+// BuildEngine 为 engine 中 /api/ 下的路由生成文档
+func (r *Registry) BuildEngine(engine *gin.Engine) *Document {
+	var routes gin.RoutesInfo
+	for _, route := range engine.Routes() {
+		if strings.HasPrefix(route.Path, "/api/") {
+			routes = append(routes, route)
+		}
+	}
+	return r.Build(routes)
+}
+
+// Build 为给定路由生成文档
+// 没有声明的路由也会列出（只有路径参数与默认响应），便于发现遗漏的说明。
+func (r *Registry) Build(routes gin.RoutesInfo) *Document {
+	r.mu.Lock()
+	described := make(map[string]*Operation, len(r.described))
+	for k, v := range r.described {
+		described[k] = v
+	}
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    r.info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			SecuritySchemes: make(map[string]*SecurityScheme, len(r.schemes)),
+		},
+	}
+	for k, v := range r.schemes {
+		doc.Components.SecuritySchemes[k] = v
+	}
+	res := newResolver(r.envelope)
+	r.mu.Unlock()
+
+	sorted := append(gin.RoutesInfo(nil), routes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	ids := make(map[string]bool)
+	tags := make(map[string]bool)
+	for _, route := range sorted {
+		p := specPath(route.Path)
+		item := doc.Paths[p]
+		if item == nil {
+			item = &PathItem{}
+			doc.Paths[p] = item
+		}
+		slot := item.operation(route.Method)
+		if slot == nil {
+			continue
+		}
+
+		key := handlerName(route.Handler)
+		op := &Operation{}
+		if d, ok := described[route.Method+" "+p]; ok {
+			op = res.operation(d)
+		}
+		if op.Responses == nil {
+			op.Responses = make(map[string]*Response)
+		}
+		if len(op.Responses) == 0 {
+			op.Responses["200"] = &Response{Description: "OK"}
+		}
+		op.Parameters = withPathParams(op.Parameters, p)
+		op.OperationID = uniqueID(ids, op.OperationID, operationID(key, route.Method, p))
+		for _, tag := range op.Tags {
+			tags[tag] = true
+		}
+		*slot = op
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	if len(res.schemas) > 0 {
+		doc.Components.Schemas = res.schemas
+	}
+	return doc
+}
+
+// specPath 将 gin 路径参数 :id、*path 转换为 {id}、{path}
+func specPath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// withPathParams 补全声明中缺少的路径参数，并去掉路径中不存在的参数
+func withPathParams(params []*Parameter, p string) []*Parameter {
+	names := make(map[string]bool)
+	for _, s := range strings.Split(p, "/") {
+		if strings.HasPrefix(s, "{") {
+			names[strings.Trim(s, "{}")] = true
+		}
+	}
+
+	result := make([]*Parameter, 0, len(params)+len(names))
+	seen := make(map[string]bool)
+	for _, param := range params {
+		if param.In == "path" {
+			if !names[param.Name] {
+				continue
+			}
+			seen[param.Name] = true
+		}
+		result = append(result, param)
+	}
+	var missing []string
+	for name := range names {
+		if !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		result = append(result, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	return result
+}
+
+// handlerName 将 gin 的处理器名称（完整包路径，方法值带 -fm 后缀）转换为 pkg.(*Type).Method 格式
+func handlerName(name string) string {
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// operationID 默认接口 ID：控制器方法为 <包名><类型名去掉 Controller><方法名>，如 adminJobList；
+// 匿名函数等按请求方法与路径生成，如 getAdminJobsById
+func operationID(key, method, p string) string {
+	if pkg, rest, ok := strings.Cut(key, "."); ok && !strings.Contains(rest, ".func") {
+		parts := strings.Split(strings.NewReplacer("(*", "", ")", "").Replace(rest), ".")
+		if len(parts) == 2 {
+			return lowerFirst(pkg) + upperFirst(strings.TrimSuffix(parts[0], "Controller")) + upperFirst(parts[1])
+		}
+	}
+
+	id := strings.ToLower(method)
+	for _, s := range strings.Split(strings.TrimPrefix(p, "/api/v1"), "/") {
+		if strings.HasPrefix(s, "{") {
+			id += "By" + upperFirst(camel(strings.Trim(s, "{}")))
+		} else if s != "" {
+			id += upperFirst(camel(s))
+		}
+	}
+	return id
+}
+
+// uniqueID 接口 ID 重复时追加序号（同一处理器注册到多个路径）
+func uniqueID(ids map[string]bool, id, fallback string) string {
+	if id == "" {
+		id = fallback
+	}
+	candidate := id
+	for i := 2; ids[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", id, i)
+	}
+	ids[candidate] = true
+	return candidate
+}
+
+// camel 将 email-logs、trade_no 转换为 emailLogs、tradeNo
+func camel(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	for i := 1; i < len(parts); i++ {
+		parts[i] = upperFirst(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username" binding:"required" example:"admin"`
+	Password string `json:"password" binding:"required,min=6"`
+	Remember *bool  `json:"remember,omitempty"`
+	internal string
+	Ignored  string `json:"-"`
+	Page
+}
+
+type Page struct {
+	Page int `json:"page"`
+}
+
+type testController struct{}
+
+func (c *testController) Login(ctx *gin.Context) {}
+func (c *testController) Get(ctx *gin.Context)   {}
+
+type Envelope struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+func TestBuild(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := &testController{}
+	router := gin.New()
+	router.POST("/api/v1/login", ctrl.Login)
+	router.GET("/api/v1/items/:id", ctrl.Get)
+	router.GET("/api/v1/items/:id/files/*path", func(c *gin.Context) {})
+	router.PUT("/api/v1/plugins/ext/items/:id", func(c *gin.Context) {})
+	router.GET("/health", func(c *gin.Context) {})
+
+	r := NewRegistry()
+	r.SetEnvelope(Envelope{}, "data")
+	r.Describe("POST", "/api/v1/login", Op("用户登录", "Public-认证").
+		Body(LoginRequest{}, "登录信息").Returns([]LoginRequest{}).Fails(http.StatusBadRequest))
+	r.Describe("GET", "/api/v1/items/:id", Op("获取详情").Auth().
+		Path("id", "int", "ID").Path("gone", "int", "已不存在的参数").
+		Query("status", "int", "状态").Default(1).Returns(nil))
+	r.Describe("PUT", "/api/v1/plugins/ext/items/:id", Operation{Summary: "插件接口", Tags: []string{"Plugin-ext"}})
+	router.GET("/openapi.json", r.Handler(router))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	var doc Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.0.3" || doc.Paths["/health"] != nil || doc.Paths["/openapi.json"] != nil {
+		t.Fatalf("unexpected paths: %v", doc.Paths)
+	}
+
+	login := doc.Paths["/api/v1/login"].Post
+	if login == nil || login.Summary != "用户登录" || login.OperationID != "openapiTestLogin" || login.Tags[0] != "Public-认证" {
+		t.Fatalf("login = %+v", login)
+	}
+	body := login.RequestBody.Content["application/json"].Schema
+	if body.Ref != "#/components/schemas/openapi.LoginRequest" || !login.RequestBody.Required {
+		t.Fatalf("login body = %+v", body)
+	}
+	ok := login.Responses["200"].Content["application/json"].Schema
+	if len(ok.AllOf) != 2 || ok.AllOf[0].Ref != "#/components/schemas/openapi.Envelope" || ok.AllOf[1].Properties["data"].Type != "array" {
+		t.Fatalf("login response = %+v", ok)
+	}
+	if login.Responses["400"].Description != "Bad Request" {
+		t.Fatalf("400 = %+v", login.Responses["400"])
+	}
+
+	schema := doc.Components.Schemas["openapi.LoginRequest"]
+	if schema == nil {
+		t.Fatalf("LoginRequest schema = %+v", schema)
+	}
+	for _, name := range []string{"username", "password", "remember", "page"} {
+		if schema.Properties[name] == nil {
+			t.Fatalf("missing property %s: %v", name, schema.Properties)
+		}
+	}
+	if len(schema.Properties) != 4 || schema.Properties["username"].Example != "admin" {
+		t.Fatalf("properties = %v", schema.Properties)
+	}
+	if len(schema.Required) != 2 {
+		t.Fatalf("required = %v", schema.Required)
+	}
+
+	get := doc.Paths["/api/v1/items/{id}"].Get
+	if len(get.Security) != 1 || len(get.Parameters) != 2 || get.Responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/openapi.Envelope" {
+		t.Fatalf("get = %+v", get)
+	}
+	status := get.Parameters[1]
+	if status.In != "query" || status.Schema.Type != "integer" || status.Schema.Default != float64(1) {
+		t.Fatalf("status param = %+v", status.Schema)
+	}
+
+	// 没有声明的路由补全路径参数，按路径生成 ID
+	files := doc.Paths["/api/v1/items/{id}/files/{path}"].Get
+	if files == nil || len(files.Parameters) != 2 || files.OperationID != "getItemsByIdFilesByPath" || files.Responses["200"] == nil {
+		t.Fatalf("files = %+v", files)
+	}
+
+	plugin := doc.Paths["/api/v1/plugins/ext/items/{id}"].Put
+	if plugin == nil || plugin.Summary != "插件接口" || len(plugin.Parameters) != 1 {
+		t.Fatalf("plugin = %+v", plugin)
+	}
+}
+
+func TestOperationBuilderCopies(t *testing.T) {
+	base := Op("列表", "Admin").Auth().Query("status", "int", "状态")
+	paged := base.Paged()
+	required := base.Required().Returns(nil)
+
+	if len(base.Parameters) != 1 || base.Parameters[0].Required || base.Responses != nil {
+		t.Fatalf("base modified: %+v", base)
+	}
+	if len(paged.Parameters) != 3 || paged.Parameters[2].Schema.Default != 20 {
+		t.Fatalf("paged = %+v", paged.Parameters)
+	}
+	if !required.Parameters[0].Required || required.Responses["200"] == nil {
+		t.Fatalf("required = %+v", required)
+	}
+}
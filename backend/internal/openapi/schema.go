@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"path"
+	"reflect"
+	"strings"
+)
+
+const refPrefix = "#/components/schemas/"
+
+// resolver 将 Go 类型转换为 Schema
+// 结构体统一放入 components，名称为 "<包名>.<类型名>"。
+type resolver struct {
+	envelope *envelope
+	schemas  map[string]*Schema
+}
+
+func newResolver(env *envelope) *resolver {
+	return &resolver{envelope: env, schemas: make(map[string]*Schema)}
+}
+
+// basicSchemas Go 内置类型及参数声明中使用的类型名
+var basicSchemas = map[string]Schema{
+	"string":  {Type: "string"},
+	"bool":    {Type: "boolean"},
+	"boolean": {Type: "boolean"},
+	"int":     {Type: "integer"},
+	"int8":    {Type: "integer", Format: "int32"},
+	"int16":   {Type: "integer", Format: "int32"},
+	"int32":   {Type: "integer", Format: "int32"},
+	"int64":   {Type: "integer", Format: "int64"},
+	"uint":    {Type: "integer"},
+	"uint8":   {Type: "integer", Format: "int32"},
+	"uint16":  {Type: "integer", Format: "int32"},
+	"uint32":  {Type: "integer", Format: "int64"},
+	"uint64":  {Type: "integer", Format: "int64"},
+	"integer": {Type: "integer"},
+	"float32": {Type: "number", Format: "float"},
+	"float64": {Type: "number", Format: "double"},
+	"number":  {Type: "number"},
+}
+
+// wellKnown 标准库中有固定 JSON 表示的类型
+var wellKnown = map[string]Schema{
+	"time.Time":            {Type: "string", Format: "date-time"},
+	"time.Duration":        {Type: "integer", Format: "int64"},
+	"json.RawMessage":      {},
+	"json.Number":          {Type: "number"},
+	"sql.NullString":       {Type: "string", Nullable: true},
+	"sql.NullInt64":        {Type: "integer", Format: "int64", Nullable: true},
+	"sql.NullBool":         {Type: "boolean", Nullable: true},
+	"sql.NullTime":         {Type: "string", Format: "date-time", Nullable: true},
+	"multipart.FileHeader": {Type: "string", Format: "binary"},
+}
+
+// operation 复制声明的接口，并将其中按 Go 类型声明的 Schema 解析为实际结构
+func (r *resolver) operation(d *Operation) *Operation {
+	op := *d
+	op.Parameters = make([]*Parameter, 0, len(d.Parameters))
+	for _, p := range d.Parameters {
+		clone := *p
+		clone.Schema = r.resolve(p.Schema)
+		op.Parameters = append(op.Parameters, &clone)
+	}
+	if d.RequestBody != nil {
+		body := *d.RequestBody
+		body.Content = r.content(d.RequestBody.Content)
+		op.RequestBody = &body
+	}
+	op.Responses = make(map[string]*Response, len(d.Responses))
+	for code, resp := range d.Responses {
+		clone := *resp
+		clone.Content = r.content(resp.Content)
+		op.Responses[code] = &clone
+	}
+	return &op
+}
+
+func (r *resolver) content(content map[string]*MediaType) map[string]*MediaType {
+	if content == nil {
+		return nil
+	}
+	result := make(map[string]*MediaType, len(content))
+	for mime, media := range content {
+		result[mime] = &MediaType{Schema: r.resolve(media.Schema)}
+	}
+	return result
+}
+
+// resolve 解析 Body / Returns 等以 Go 值声明的 Schema；enveloped 的数据放入统一响应结构
+func (r *resolver) resolve(s *Schema) *Schema {
+	if s == nil || s.goType == nil && !s.enveloped {
+		return s
+	}
+	var data *Schema
+	if s.goType != nil {
+		data = r.reflect(s.goType)
+	}
+	if !s.enveloped || r.envelope == nil {
+		if data == nil {
+			return &Schema{}
+		}
+		return data
+	}
+	wrapper := r.reflect(r.envelope.t)
+	if data == nil {
+		return wrapper
+	}
+	return &Schema{AllOf: []*Schema{wrapper, {
+		Type:       "object",
+		Properties: map[string]*Schema{r.envelope.field: data},
+	}}}
+}
+
+// reflect 通过反射解析类型
+func (r *resolver) reflect(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	key := ""
+	if t.Name() != "" && t.PkgPath() != "" {
+		key = path.Base(t.PkgPath()) + "." + t.Name()
+		if known, ok := wellKnown[key]; ok {
+			return &known
+		}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if key != "" {
+			if _, ok := r.schemas[key]; !ok {
+				schema := &Schema{}
+				r.schemas[key] = schema
+				*schema = *r.reflectFields(t)
+			}
+			return &Schema{Ref: refPrefix + key}
+		}
+		return r.reflectFields(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.reflect(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.reflect(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	}
+	if basic, ok := basicSchemas[t.Kind().String()]; ok {
+		return &basic
+	}
+	return &Schema{}
+}
+
+func (r *resolver) reflectFields(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, skip := jsonFieldName(field.Tag)
+		if skip || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			r.embed(schema, r.reflect(field.Type))
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = describe(r.reflect(field.Type), "", field.Tag)
+		if isRequired(field.Tag) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// embed 将嵌入结构体的字段合并到外层
+func (r *resolver) embed(dst, embedded *Schema) {
+	if embedded.Ref != "" {
+		embedded = r.schemas[strings.TrimPrefix(embedded.Ref, refPrefix)]
+	}
+	if embedded == nil {
+		return
+	}
+	for name, prop := range embedded.Properties {
+		if _, ok := dst.Properties[name]; !ok {
+			dst.Properties[name] = prop
+		}
+	}
+	dst.Required = append(dst.Required, embedded.Required...)
+}
+
+// jsonFieldName 返回 json 标签中的字段名，标签为 "-" 时跳过
+func jsonFieldName(tag reflect.StructTag) (name string, skip bool) {
+	value, ok := tag.Lookup("json")
+	if !ok {
+		return "", false
+	}
+	name, _, _ = strings.Cut(value, ",")
+	return name, name == "-"
+}
+
+func isRequired(tag reflect.StructTag) bool {
+	for _, rule := range strings.Split(tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// describe 为字段附加说明与示例；$ref 不能带其他属性，通过 allOf 包装
+func describe(schema *Schema, desc string, tag reflect.StructTag) *Schema {
+	example := tag.Get("example")
+	if desc == "" && example == "" {
+		return schema
+	}
+	if schema.Ref != "" {
+		schema = &Schema{AllOf: []*Schema{schema}}
+	}
+	schema.Description = desc
+	if example != "" {
+		schema.Example = example
+	}
+	return schema
+}
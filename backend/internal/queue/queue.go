@@ -0,0 +1,407 @@
+// Package queue 提供持久化的后台任务队列
+//
+// 任务入队即写入存储（数据库），由各实例的 worker 按队列并发度领取执行：
+//   - 处理函数按任务类型注册，Register 支持带类型的 payload（JSON 序列化）
+//   - 失败后按指数退避重试，超过最大次数进入死信（dead）状态，可由管理员手动重试
+//   - 执行中的任务带锁定期限，进程崩溃后锁过期会被其他 worker 重新领取
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// 任务状态
+const (
+	StatusPending = "pending" // 等待执行（含等待重试）
+	StatusRunning = "running" // 执行中
+	StatusDone    = "done"    // 执行成功
+	StatusDead    = "dead"    // 重试耗尽（死信）
+)
+
+const (
+	defaultQueue        = "default"
+	defaultMaxAttempts  = 5
+	defaultTimeout      = 2 * time.Minute
+	defaultPollInterval = 2 * time.Second
+)
+
+var (
+	// ErrNoHandler 任务类型未注册处理函数
+	ErrNoHandler = errors.New("queue: no handler registered for job type")
+	// ErrNotStarted 队列未初始化
+	ErrNotStarted = errors.New("queue: manager not initialized")
+	// ErrLockLost 任务锁已失效（锁过期后被其他 worker 重新领取），本次执行结果不再写回
+	ErrLockLost = errors.New("queue: job lock lost")
+)
+
+// Job 队列任务
+type Job struct {
+	ID          uint64
+	Queue       string
+	Type        string
+	Payload     []byte
+	Status      string
+	Attempts    int // 已执行次数（领取时递增）
+	MaxAttempts int
+	LastError   string
+	RunAt       time.Time
+	LockedBy    string // 领取该任务的执行实例
+}
+
+// Store 任务持久化
+type Store interface {
+	// Enqueue 写入任务并回填 ID
+	Enqueue(job *Job) error
+	// Claim 领取一个可执行任务并标记为 running（attempts+1），无任务时返回 nil
+	// 可执行：pending 且 run_at 已到，或 running 但锁定已过期（执行实例崩溃）
+	Claim(queue, owner string, now, lockUntil time.Time) (*Job, error)
+	// Complete、Retry、Bury 仅在任务仍为 running 且由 job.LockedBy 持有锁时生效，否则返回 ErrLockLost
+
+	// Complete 标记成功
+	Complete(job *Job) error
+	// Retry 标记失败并在 runAt 重新执行
+	Retry(job *Job, runAt time.Time, errMsg string) error
+	// Bury 标记为死信
+	Bury(job *Job, errMsg string) error
+}
+
+// HandlerFunc 任务处理函数
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+// HandlerOptions 处理函数选项
+type HandlerOptions struct {
+	Queue       string        // 所属队列，默认 default
+	MaxAttempts int           // 最大执行次数，默认 5
+	Timeout     time.Duration // 单次执行超时，默认 2 分钟
+}
+
+// QueueConfig 队列配置
+type QueueConfig struct {
+	Concurrency  int           // 每个实例的并发 worker 数，默认 1
+	PollInterval time.Duration // 空闲时轮询间隔，默认 2 秒
+}
+
+type handler struct {
+	fn   HandlerFunc
+	opts HandlerOptions
+}
+
+type queueState struct {
+	cfg  QueueConfig
+	wake chan struct{}
+}
+
+// Manager 队列管理器
+type Manager struct {
+	store Store
+	owner string
+
+	// Backoff 返回第 attempt 次失败后的重试间隔
+	Backoff func(attempt int) time.Duration
+
+	mu       sync.RWMutex
+	queues   map[string]*queueState
+	handlers map[string]handler
+	ctx      context.Context
+	cancel   context.CancelFunc
+	started  bool
+	wg       sync.WaitGroup
+}
+
+// New 创建队列管理器，owner 为当前实例标识（写入任务锁）
+func New(store Store, owner string) *Manager {
+	return &Manager{
+		store:    store,
+		owner:    owner,
+		Backoff:  ExponentialBackoff(10*time.Second, time.Hour),
+		queues:   make(map[string]*queueState),
+		handlers: make(map[string]handler),
+	}
+}
+
+// ExponentialBackoff 指数退避（带 ±10% 抖动）：base, 2*base, 4*base ... 不超过 max
+func ExponentialBackoff(base, max time.Duration) func(int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		jitter := time.Duration(rand.Int63n(int64(d)/5+1)) - d/10
+		return d + jitter
+	}
+}
+
+// DefineQueue 设置队列并发度，需在 Start 之前调用
+func (m *Manager) DefineQueue(name string, cfg QueueConfig) {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queues[name] = &queueState{cfg: cfg, wake: make(chan struct{}, 1)}
+}
+
+// Handle 注册任务类型的处理函数，未定义的队列按默认配置创建
+func (m *Manager) Handle(jobType string, fn HandlerFunc, opts HandlerOptions) error {
+	if opts.Queue == "" {
+		opts.Queue = defaultQueue
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+
+	m.mu.Lock()
+	if _, exists := m.handlers[jobType]; exists {
+		m.mu.Unlock()
+		return fmt.Errorf("queue: handler for %s already registered", jobType)
+	}
+	m.handlers[jobType] = handler{fn: fn, opts: opts}
+	_, defined := m.queues[opts.Queue]
+	m.mu.Unlock()
+
+	if !defined {
+		m.DefineQueue(opts.Queue, QueueConfig{})
+	}
+	return nil
+}
+
+// Register 注册带类型 payload 的处理函数
+func Register[T any](m *Manager, jobType string, fn func(ctx context.Context, payload T) error, opts HandlerOptions) error {
+	return m.Handle(jobType, func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("queue: decode payload: %w", err)
+		}
+		return fn(ctx, payload)
+	}, opts)
+}
+
+// EnqueueOption 入队选项
+type EnqueueOption func(*Job)
+
+// Delay 延迟执行
+func Delay(d time.Duration) EnqueueOption {
+	return func(j *Job) { j.RunAt = j.RunAt.Add(d) }
+}
+
+// Enqueue 将任务写入队列，payload 以 JSON 序列化
+func (m *Manager) Enqueue(jobType string, payload any, opts ...EnqueueOption) (uint64, error) {
+	m.mu.RLock()
+	h, ok := m.handlers[jobType]
+	m.mu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrNoHandler, jobType)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("queue: encode payload: %w", err)
+	}
+	job := &Job{
+		Queue:       h.opts.Queue,
+		Type:        jobType,
+		Payload:     data,
+		Status:      StatusPending,
+		MaxAttempts: h.opts.MaxAttempts,
+		RunAt:       time.Now(),
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if err := m.store.Enqueue(job); err != nil {
+		return 0, err
+	}
+	m.wakeQueue(job.Queue)
+	return job.ID, nil
+}
+
+// Wake 唤醒队列立即领取任务（如管理员重试死信后）
+func (m *Manager) Wake(queue string) {
+	m.wakeQueue(queue)
+}
+
+func (m *Manager) wakeQueue(name string) {
+	m.mu.RLock()
+	q := m.queues[name]
+	m.mu.RUnlock()
+	if q == nil {
+		return
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start 为每个队列启动 worker
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started {
+		return errors.New("queue: already started")
+	}
+	m.ctx, m.cancel = context.WithCancel(ctx)
+	m.started = true
+	for name, q := range m.queues {
+		for i := 0; i < q.cfg.Concurrency; i++ {
+			m.wg.Add(1)
+			go m.worker(name, q)
+		}
+	}
+	return nil
+}
+
+// Stop 停止领取新任务并等待执行中的任务完成
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.started {
+		m.mu.Unlock()
+		return nil
+	}
+	m.cancel()
+	m.started = false
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Manager) worker(name string, q *queueState) {
+	defer m.wg.Done()
+	for {
+		if m.ctx.Err() != nil {
+			return
+		}
+
+		now := time.Now()
+		job, err := m.store.Claim(name, m.owner, now, now.Add(m.maxTimeout(name)+time.Minute))
+		if err != nil {
+			log.Printf("[Queue] Claim from %s failed: %v", name, err)
+		}
+		if job != nil {
+			m.process(job)
+			continue
+		}
+
+		timer := time.NewTimer(q.cfg.PollInterval)
+		select {
+		case <-m.ctx.Done():
+			timer.Stop()
+			return
+		case <-q.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// maxTimeout 队列内处理函数的最长超时，用于计算锁定期限
+func (m *Manager) maxTimeout(queue string) time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	timeout := defaultTimeout
+	for _, h := range m.handlers {
+		if h.opts.Queue == queue && h.opts.Timeout > timeout {
+			timeout = h.opts.Timeout
+		}
+	}
+	return timeout
+}
+
+func (m *Manager) process(job *Job) {
+	m.mu.RLock()
+	h, ok := m.handlers[job.Type]
+	m.mu.RUnlock()
+	if !ok {
+		if err := m.store.Bury(job, ErrNoHandler.Error()); err != nil {
+			log.Printf("[Queue] Bury job %d failed: %v", job.ID, err)
+		}
+		return
+	}
+
+	// 关闭时不取消执行中的任务，由生命周期截止时间兜底
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(m.ctx), h.opts.Timeout)
+	err := safeRun(ctx, h.fn, job)
+	cancel()
+
+	switch {
+	case err == nil:
+		err = m.store.Complete(job)
+	case job.Attempts >= job.MaxAttempts:
+		log.Printf("[Queue] Job %d (%s) moved to dead letter after %d attempts: %v", job.ID, job.Type, job.Attempts, err)
+		err = m.store.Bury(job, err.Error())
+	default:
+		err = m.store.Retry(job, time.Now().Add(m.Backoff(job.Attempts)), err.Error())
+	}
+	if errors.Is(err, ErrLockLost) {
+		log.Printf("[Queue] Job %d (%s) lock lost, result discarded", job.ID, job.Type)
+	} else if err != nil {
+		log.Printf("[Queue] Update job %d failed: %v", job.ID, err)
+	}
+}
+
+func safeRun(ctx context.Context, fn HandlerFunc, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx, job)
+}
+
+// ========================================
+// 全局队列
+// ========================================
+
+var (
+	defaultMu      sync.RWMutex
+	defaultManager *Manager
+)
+
+// SetDefault 设置全局队列管理器
+func SetDefault(m *Manager) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultManager = m
+}
+
+// Default 返回全局队列管理器，未初始化时为 nil
+func Default() *Manager {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultManager
+}
+
+// Enqueue 向全局队列写入任务
+func Enqueue(jobType string, payload any, opts ...EnqueueOption) (uint64, error) {
+	m := Default()
+	if m == nil {
+		return 0, ErrNotStarted
+	}
+	return m.Enqueue(jobType, payload, opts...)
+}
@@ -0,0 +1,221 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memStore 内存队列存储
+type memStore struct {
+	mu     sync.Mutex
+	nextID uint64
+	jobs   map[uint64]*Job
+	locks  map[uint64]time.Time
+}
+
+func newMemStore() *memStore {
+	return &memStore{jobs: make(map[uint64]*Job), locks: make(map[uint64]time.Time)}
+}
+
+func (s *memStore) Enqueue(job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	job.ID = s.nextID
+	cp := *job
+	s.jobs[job.ID] = &cp
+	return nil
+}
+
+func (s *memStore) Claim(queue, owner string, now, lockUntil time.Time) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := uint64(1); id <= s.nextID; id++ {
+		job := s.jobs[id]
+		if job == nil || job.Queue != queue {
+			continue
+		}
+		ready := job.Status == StatusPending && !job.RunAt.After(now)
+		expired := job.Status == StatusRunning && s.locks[id].Before(now)
+		if !ready && !expired {
+			continue
+		}
+		job.Status = StatusRunning
+		job.Attempts++
+		job.LockedBy = owner
+		s.locks[id] = lockUntil
+		cp := *job
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (s *memStore) finish(job *Job, status string, runAt time.Time, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.jobs[job.ID]
+	if stored.Status != StatusRunning || stored.LockedBy != job.LockedBy {
+		return ErrLockLost
+	}
+	stored.Status = status
+	stored.LockedBy = ""
+	stored.LastError = errMsg
+	if !runAt.IsZero() {
+		stored.RunAt = runAt
+	}
+	return nil
+}
+
+func (s *memStore) Complete(job *Job) error { return s.finish(job, StatusDone, time.Time{}, "") }
+func (s *memStore) Retry(job *Job, runAt time.Time, errMsg string) error {
+	return s.finish(job, StatusPending, runAt, errMsg)
+}
+func (s *memStore) Bury(job *Job, errMsg string) error {
+	return s.finish(job, StatusDead, time.Time{}, errMsg)
+}
+
+func (s *memStore) get(id uint64) Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.jobs[id]
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func TestTypedHandlerRuns(t *testing.T) {
+	store := newMemStore()
+	m := New(store, "test")
+	got := make(chan string, 1)
+	if err := Register(m, "greet", func(ctx context.Context, p greeting) error {
+		got <- p.Name
+		return nil
+	}, HandlerOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop(context.Background())
+
+	id, err := m.Enqueue("greet", greeting{Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case name := <-got:
+		if name != "alice" {
+			t.Fatalf("payload = %q, want alice", name)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("handler not called")
+	}
+	waitFor(t, func() bool { return store.get(id).Status == StatusDone })
+
+	if _, err := m.Enqueue("unknown", nil); !errors.Is(err, ErrNoHandler) {
+		t.Fatalf("enqueue unknown type: err = %v, want ErrNoHandler", err)
+	}
+}
+
+func TestRetryThenDeadLetter(t *testing.T) {
+	store := newMemStore()
+	m := New(store, "test")
+	m.Backoff = func(int) time.Duration { return 0 }
+	var calls atomic.Int32
+	m.Handle("flaky", func(ctx context.Context, job *Job) error {
+		calls.Add(1)
+		return errors.New("boom")
+	}, HandlerOptions{MaxAttempts: 3})
+	m.DefineQueue(defaultQueue, QueueConfig{PollInterval: 10 * time.Millisecond})
+	m.Start(context.Background())
+	defer m.Stop(context.Background())
+
+	id, _ := m.Enqueue("flaky", nil)
+	waitFor(t, func() bool { return store.get(id).Status == StatusDead })
+
+	job := store.get(id)
+	if calls.Load() != 3 || job.Attempts != 3 {
+		t.Fatalf("calls = %d, attempts = %d, want 3", calls.Load(), job.Attempts)
+	}
+	if job.LastError != "boom" {
+		t.Fatalf("last error = %q", job.LastError)
+	}
+}
+
+func TestStaleWorkerDoesNotOverwriteReclaimedJob(t *testing.T) {
+	store := newMemStore()
+	m := New(store, "stale")
+	m.ctx = context.Background() // 不启动 worker，直接调用 process
+	m.Handle("slow", func(ctx context.Context, job *Job) error { return nil }, HandlerOptions{})
+	id, _ := m.Enqueue("slow", nil)
+
+	job, _ := store.Claim(defaultQueue, "stale", time.Now(), time.Now())
+	// 锁过期后被其他实例重新领取
+	if _, err := store.Claim(defaultQueue, "other", time.Now().Add(time.Second), time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	m.process(job)
+	if got := store.get(id); got.Status != StatusRunning || got.LockedBy != "other" {
+		t.Fatalf("status = %s, locked by = %q, want running by other", got.Status, got.LockedBy)
+	}
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	store := newMemStore()
+	m := New(store, "test")
+	m.DefineQueue("slow", QueueConfig{Concurrency: 2, PollInterval: 10 * time.Millisecond})
+	var active, peak, done atomic.Int32
+	m.Handle("sleep", func(ctx context.Context, job *Job) error {
+		n := active.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		active.Add(-1)
+		done.Add(1)
+		return nil
+	}, HandlerOptions{Queue: "slow"})
+	m.Start(context.Background())
+	defer m.Stop(context.Background())
+
+	for i := 0; i < 6; i++ {
+		m.Enqueue("sleep", nil)
+	}
+	waitFor(t, func() bool { return done.Load() == 6 })
+	if peak.Load() > 2 {
+		t.Fatalf("peak concurrency = %d, want <= 2", peak.Load())
+	}
+}
+
+func TestBackoffGrowsAndCaps(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 10*time.Second)
+	within := func(d, want time.Duration) bool {
+		return d >= want-want/10 && d <= want+want/10
+	}
+	cases := map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 10: 10 * time.Second}
+	for attempt, want := range cases {
+		if d := backoff(attempt); !within(d, want) {
+			t.Errorf("backoff(%d) = %v, want ~%v", attempt, d, want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package realtime
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	defaultLocalBuffer  = 1024
+	defaultPollInterval = time.Second
+	defaultPollLimit    = 500
+	defaultPollLookback = 100 // 每次轮询重新读取的已读 ID 范围
+)
+
+// ========================================
+// LocalBroker 进程内投递
+// ========================================
+
+// LocalBroker 进程内投递，仅本实例的订阅者能收到消息
+type LocalBroker struct {
+	ch chan *Message
+}
+
+// NewLocalBroker 创建进程内 Broker，buffer 为未投递消息的缓冲数（默认 1024）
+func NewLocalBroker(buffer int) *LocalBroker {
+	if buffer <= 0 {
+		buffer = defaultLocalBuffer
+	}
+	return &LocalBroker{ch: make(chan *Message, buffer)}
+}
+
+// Publish 写入缓冲区，已满时返回 ErrBrokerFull 而不阻塞发布方
+func (b *LocalBroker) Publish(ctx context.Context, msg *Message) error {
+	select {
+	case b.ch <- msg:
+		return nil
+	default:
+		return ErrBrokerFull
+	}
+}
+
+// Run 从缓冲区取出消息投递
+func (b *LocalBroker) Run(ctx context.Context, deliver func(*Message)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-b.ch:
+			deliver(msg)
+		}
+	}
+}
+
+// ========================================
+// PollingBroker 共享存储轮询
+// ========================================
+
+// Store 消息持久化，由共享存储（如数据库）实现
+type Store interface {
+	// Append 写入消息并回填 ID，ID 按分配顺序递增（如自增主键）；
+	// 并发写入时较小的 ID 可能晚于较大的 ID 提交，由 PollingBroker 回看处理
+	Append(msg *Message) error
+	// After 按 ID 升序返回大于 id 的消息，最多 limit 条
+	After(id uint64, limit int) ([]*Message, error)
+	// LastID 当前最大的消息 ID
+	LastID() (uint64, error)
+}
+
+// PollingBroker 消息写入共享存储，各实例轮询读取新消息，实现跨实例扇出
+// 只投递启动之后发布的消息。每次轮询从已读最大 ID 往回 defaultPollLookback 个 ID 开始读取并按 ID 去重，
+// 晚于更大 ID 提交的消息只要仍在这个范围内就会投递；超出范围的属于尽力而为的实时提示，可能被跳过。
+// 过期消息的清理由调用方负责
+type PollingBroker struct {
+	store    Store
+	interval time.Duration
+	wake     chan struct{}
+}
+
+// NewPollingBroker 创建轮询 Broker，interval 为轮询间隔（默认 1 秒）
+func NewPollingBroker(store Store, interval time.Duration) *PollingBroker {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	return &PollingBroker{store: store, interval: interval, wake: make(chan struct{}, 1)}
+}
+
+// Publish 写入存储，并唤醒本实例立即读取
+func (b *PollingBroker) Publish(ctx context.Context, msg *Message) error {
+	if err := b.store.Append(msg); err != nil {
+		return err
+	}
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run 从启动时的最大 ID 开始轮询新消息
+func (b *PollingBroker) Run(ctx context.Context, deliver func(*Message)) error {
+	start, err := b.store.LastID()
+	if err != nil {
+		return err
+	}
+	high := start                      // 已投递的最大 ID
+	delivered := make(map[uint64]bool) // 回看范围内已投递的 ID
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-b.wake:
+		}
+
+		from := start
+		if high > start+defaultPollLookback {
+			from = high - defaultPollLookback
+		}
+		for {
+			msgs, err := b.store.After(from, defaultPollLimit)
+			if err != nil {
+				log.Printf("[Realtime] 读取消息失败: %v", err)
+				break
+			}
+			for _, msg := range msgs {
+				from = msg.ID
+				if delivered[msg.ID] {
+					continue
+				}
+				delivered[msg.ID] = true
+				if msg.ID > high {
+					high = msg.ID
+				}
+				deliver(msg)
+			}
+			if len(msgs) < defaultPollLimit {
+				break
+			}
+		}
+
+		// 已移出回看范围的 ID 不会再被读到
+		for id := range delivered {
+			if id+defaultPollLookback <= high {
+				delete(delivered, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Package realtime 提供按主题订阅的实时消息推送中心
+//
+// 发布的消息先交给 Broker，再由 Broker 投递到每个实例的 Hub，Hub 分发给订阅了该主题的本地连接：
+//   - LocalBroker：进程内直接投递，适用于单实例部署
+//   - PollingBroker：消息写入共享存储（数据库），各实例轮询读取，多实例部署时扇出到所有实例
+//
+// 订阅者的缓冲区写满时（客户端读取过慢）会被断开，客户端重连后应重新拉取最新状态。
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+const (
+	// TopicBroadcast 全体在线用户
+	TopicBroadcast = "broadcast"
+
+	defaultBufferSize = 32
+)
+
+var (
+	// ErrBrokerFull 本地投递缓冲区已满
+	ErrBrokerFull = errors.New("realtime: broker buffer full")
+	// ErrHubClosed Hub 已关闭
+	ErrHubClosed = errors.New("realtime: hub closed")
+)
+
+// UserTopic 用户私有主题
+func UserTopic(userID uint64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// Message 推送消息
+type Message struct {
+	ID    uint64          `json:"id,omitempty"` // Broker 分配的序号，LocalBroker 为 0
+	Topic string          `json:"topic"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// Broker 消息中转，负责把任一实例发布的消息投递到所有实例
+type Broker interface {
+	// Publish 发布消息
+	Publish(ctx context.Context, msg *Message) error
+	// Run 接收消息并调用 deliver，阻塞到 ctx 取消
+	Run(ctx context.Context, deliver func(*Message)) error
+}
+
+// Subscriber 一个本地连接的订阅
+type Subscriber struct {
+	ch     chan *Message
+	topics []string
+	done   chan struct{}
+	once   sync.Once
+}
+
+// C 接收消息的通道
+func (s *Subscriber) C() <-chan *Message { return s.ch }
+
+// Done 订阅被关闭时（Hub 关闭或缓冲区写满）关闭
+func (s *Subscriber) Done() <-chan struct{} { return s.done }
+
+func (s *Subscriber) close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Hub 本实例的订阅中心
+type Hub struct {
+	broker Broker
+
+	// BufferSize 每个订阅者的缓冲消息数，默认 32
+	BufferSize int
+
+	mu     sync.RWMutex
+	topics map[string]map[*Subscriber]struct{}
+	closed bool
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewHub 创建订阅中心，broker 为空时使用 LocalBroker
+func NewHub(broker Broker) *Hub {
+	if broker == nil {
+		broker = NewLocalBroker(0)
+	}
+	return &Hub{
+		broker:     broker,
+		BufferSize: defaultBufferSize,
+		topics:     make(map[string]map[*Subscriber]struct{}),
+	}
+}
+
+// Publish 向主题发布事件，data 以 JSON 序列化
+func (h *Hub) Publish(ctx context.Context, topic, event string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("realtime: encode data: %w", err)
+	}
+	return h.broker.Publish(ctx, &Message{Topic: topic, Event: event, Data: raw})
+}
+
+// Subscribe 订阅一个或多个主题
+func (h *Hub) Subscribe(topics ...string) (*Subscriber, error) {
+	size := h.BufferSize
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	s := &Subscriber{ch: make(chan *Message, size), topics: topics, done: make(chan struct{})}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+	for _, topic := range topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = make(map[*Subscriber]struct{})
+		}
+		h.topics[topic][s] = struct{}{}
+	}
+	return s, nil
+}
+
+// Unsubscribe 取消订阅，可重复调用
+func (h *Hub) Unsubscribe(s *Subscriber) {
+	h.mu.Lock()
+	h.remove(s)
+	h.mu.Unlock()
+	s.close()
+}
+
+// remove 从主题中移除订阅者，调用方持有写锁
+func (h *Hub) remove(s *Subscriber) {
+	for _, topic := range s.topics {
+		delete(h.topics[topic], s)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+}
+
+// Count 当前订阅者数
+func (h *Hub) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	seen := make(map[*Subscriber]struct{})
+	for _, subs := range h.topics {
+		for s := range subs {
+			seen[s] = struct{}{}
+		}
+	}
+	return len(seen)
+}
+
+// dispatch 把消息分发给订阅了该主题的本地连接，缓冲区已满的订阅者被断开
+func (h *Hub) dispatch(msg *Message) {
+	var slow []*Subscriber
+	h.mu.RLock()
+	for s := range h.topics[msg.Topic] {
+		select {
+		case s.ch <- msg:
+		default:
+			slow = append(slow, s)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, s := range slow {
+		log.Printf("[Realtime] 订阅者缓冲区已满，断开连接: topic=%s", msg.Topic)
+		h.Unsubscribe(s)
+	}
+}
+
+// Start 启动 Broker 接收消息
+func (h *Hub) Start(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.cancel != nil {
+		return errors.New("realtime: already started")
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	h.cancel = cancel
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		if err := h.broker.Run(runCtx, h.dispatch); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("[Realtime] Broker stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Close 断开所有订阅者，之后的订阅返回 ErrHubClosed，可重复调用
+// 在 HTTP 服务关闭时调用，使长连接请求尽快结束
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	var subs []*Subscriber
+	for _, set := range h.topics {
+		for s := range set {
+			subs = append(subs, s)
+		}
+	}
+	h.topics = make(map[string]map[*Subscriber]struct{})
+	h.mu.Unlock()
+
+	for _, s := range subs {
+		s.close()
+	}
+}
+
+// Stop 断开所有订阅者并停止 Broker
+func (h *Hub) Stop(ctx context.Context) error {
+	h.Close()
+
+	h.mu.Lock()
+	cancel := h.cancel
+	h.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,202 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receive 在超时前读取一条消息
+func receive(t *testing.T, s *Subscriber) *Message {
+	t.Helper()
+	select {
+	case msg := <-s.C():
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+		return nil
+	}
+}
+
+func TestHubDeliversByTopic(t *testing.T) {
+	hub := NewHub(nil)
+	if err := hub.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer hub.Stop(context.Background())
+
+	alice, _ := hub.Subscribe(UserTopic(1), TopicBroadcast)
+	bob, _ := hub.Subscribe(UserTopic(2), TopicBroadcast)
+
+	if err := hub.Publish(context.Background(), UserTopic(1), "payment.paid", map[string]string{"order_no": "A1"}); err != nil {
+		t.Fatal(err)
+	}
+	if msg := receive(t, alice); msg.Event != "payment.paid" || string(msg.Data) != `{"order_no":"A1"}` {
+		t.Fatalf("alice got %+v", msg)
+	}
+
+	hub.Publish(context.Background(), TopicBroadcast, "settings.updated", nil)
+	for _, s := range []*Subscriber{alice, bob} {
+		if msg := receive(t, s); msg.Event != "settings.updated" {
+			t.Fatalf("got %+v, want settings.updated", msg)
+		}
+	}
+	select {
+	case msg := <-bob.C():
+		t.Fatalf("bob should not receive user:1 message, got %+v", msg)
+	default:
+	}
+
+	hub.Unsubscribe(alice)
+	hub.Unsubscribe(alice)
+	if got := hub.Count(); got != 1 {
+		t.Fatalf("Count() = %d, want 1", got)
+	}
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	hub := NewHub(nil)
+	hub.BufferSize = 1
+	s, _ := hub.Subscribe(UserTopic(1))
+
+	hub.dispatch(&Message{Topic: UserTopic(1), Event: "a"})
+	hub.dispatch(&Message{Topic: UserTopic(1), Event: "b"})
+
+	select {
+	case <-s.Done():
+	default:
+		t.Fatal("slow subscriber should be closed")
+	}
+	if hub.Count() != 0 {
+		t.Fatal("slow subscriber should be removed")
+	}
+}
+
+func TestHubCloseEndsSubscribers(t *testing.T) {
+	hub := NewHub(nil)
+	s, _ := hub.Subscribe(UserTopic(1))
+	hub.Close()
+
+	select {
+	case <-s.Done():
+	default:
+		t.Fatal("subscriber should be closed")
+	}
+	if _, err := hub.Subscribe(UserTopic(1)); err != ErrHubClosed {
+		t.Fatalf("Subscribe after Close err = %v, want ErrHubClosed", err)
+	}
+}
+
+// memStore 内存消息存储，模拟多个实例共享的数据库
+type memStore struct {
+	mu      sync.Mutex
+	msgs    []*Message
+	pending map[uint64]bool // 已分配 ID 但尚未提交的消息
+}
+
+func (s *memStore) Append(msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg.ID = uint64(len(s.msgs) + 1)
+	s.msgs = append(s.msgs, msg)
+	return nil
+}
+
+// reserve 分配 ID 但暂不提交，模拟并发事务中较小 ID 晚提交
+func (s *memStore) reserve(msg *Message) {
+	s.Append(msg)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending == nil {
+		s.pending = make(map[uint64]bool)
+	}
+	s.pending[msg.ID] = true
+}
+
+func (s *memStore) commit(msg *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, msg.ID)
+}
+
+func (s *memStore) After(id uint64, limit int) ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*Message
+	for _, msg := range s.msgs {
+		if msg.ID > id && !s.pending[msg.ID] && len(result) < limit {
+			result = append(result, msg)
+		}
+	}
+	return result, nil
+}
+
+func (s *memStore) LastID() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return uint64(len(s.msgs)), nil
+}
+
+func TestPollingBrokerFansOutAcrossHubs(t *testing.T) {
+	store := &memStore{}
+	store.Append(&Message{Topic: UserTopic(1), Event: "old"})
+
+	hubA := NewHub(NewPollingBroker(store, 10*time.Millisecond))
+	hubB := NewHub(NewPollingBroker(store, 10*time.Millisecond))
+	subA, _ := hubA.Subscribe(UserTopic(1))
+	subB, _ := hubB.Subscribe(UserTopic(1))
+	for _, hub := range []*Hub{hubA, hubB} {
+		hub.Start(context.Background())
+		defer hub.Stop(context.Background())
+	}
+	// 等待两个实例读取启动时的 LastID
+	time.Sleep(30 * time.Millisecond)
+
+	if err := hubA.Publish(context.Background(), UserTopic(1), "new", 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []*Subscriber{subA, subB} {
+		if msg := receive(t, s); msg.Event != "new" {
+			t.Fatalf("got %q, want new (messages before start must be skipped)", msg.Event)
+		}
+	}
+}
+
+func TestPollingBrokerDeliversLateCommittedMessages(t *testing.T) {
+	store := &memStore{}
+	broker := NewPollingBroker(store, 10*time.Millisecond)
+	got := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go broker.Run(ctx, func(msg *Message) { got <- msg.Event })
+	time.Sleep(30 * time.Millisecond)
+
+	late := &Message{Topic: UserTopic(1), Event: "late"}
+	store.reserve(late)
+	broker.Publish(ctx, &Message{Topic: UserTopic(1), Event: "early"})
+	next := func() string {
+		t.Helper()
+		select {
+		case event := <-got:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for message")
+			return ""
+		}
+	}
+	if event := next(); event != "early" {
+		t.Fatalf("got %q, want early", event)
+	}
+
+	// 较小的 ID 在更大的 ID 被读取之后才提交，仍然投递且只投递一次
+	store.commit(late)
+	if event := next(); event != "late" {
+		t.Fatalf("got %q, want late", event)
+	}
+	select {
+	case event := <-got:
+		t.Fatalf("duplicate delivery of %q", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
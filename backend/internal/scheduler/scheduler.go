@@ -0,0 +1,403 @@
+// Package scheduler 提供集群内唯一执行的定时任务调度
+//
+// 每个实例都会按 cron 表达式计算触发时间，但执行前必须通过 Store 获取数据库租约：
+// 同一触发时间点只有一个实例能拿到租约，因此多副本部署时任务不会重复执行。
+// 每次执行都会记录运行历史（耗时、错误），并支持暂停与手动触发。
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 触发方式
+const (
+	TriggerSchedule = "schedule" // 定时触发
+	TriggerManual   = "manual"   // 管理员手动触发
+)
+
+// 运行状态
+const (
+	RunStatusRunning = "running"
+	RunStatusSuccess = "success"
+	RunStatusFailed  = "failed"
+)
+
+// 默认单次执行超时，同时决定租约时长
+const defaultTimeout = 10 * time.Minute
+
+var (
+	// ErrJobNotFound 任务不存在
+	ErrJobNotFound = errors.New("scheduler: job not found")
+	// ErrJobRunning 任务正在执行
+	ErrJobRunning = errors.New("scheduler: job is already running")
+	// ErrLeaseHeld 其他实例持有租约
+	ErrLeaseHeld = errors.New("scheduler: job is running on another instance")
+)
+
+// Job 定时任务定义
+type Job struct {
+	Name        string
+	Description string
+	Spec        string        // cron 表达式，见 ParseSchedule
+	Timeout     time.Duration // 单次执行超时，默认 10 分钟
+	Run         func(ctx context.Context) error
+}
+
+func (j Job) timeout() time.Duration {
+	if j.Timeout > 0 {
+		return j.Timeout
+	}
+	return defaultTimeout
+}
+
+// RunRecord 单次运行记录
+type RunRecord struct {
+	ID         uint64
+	Job        string
+	Instance   string
+	Trigger    string
+	Status     string
+	Error      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Duration   time.Duration
+}
+
+// Store 调度状态持久化
+type Store interface {
+	// EnsureJob 登记任务元数据（不存在则创建，不修改暂停状态）
+	EnsureJob(job Job) error
+	// AcquireLease 尝试获取执行租约，租约有效期至 until
+	// scheduledAt 非零表示定时触发：任务暂停或该时间点已被其他实例执行时返回 false
+	AcquireLease(name, owner string, scheduledAt, until time.Time) (bool, error)
+	// ReleaseLease 释放租约
+	ReleaseLease(name, owner string) error
+	// SetPaused 设置暂停状态
+	SetPaused(name string, paused bool) error
+	// StartRun 写入运行记录并回填 ID
+	StartRun(rec *RunRecord) error
+	// FinishRun 更新运行结果
+	FinishRun(rec *RunRecord) error
+}
+
+// JobInfo 任务在当前实例中的状态
+type JobInfo struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Spec        string    `json:"spec"`
+	NextRun     time.Time `json:"next_run"`
+	Running     bool      `json:"running"` // 是否正在本实例执行
+}
+
+type entry struct {
+	job      Job
+	schedule Schedule
+
+	mu      sync.Mutex
+	next    time.Time
+	running bool
+}
+
+// Scheduler 定时任务调度器
+type Scheduler struct {
+	store    Store
+	instance string
+	now      func() time.Time
+
+	mu      sync.RWMutex
+	jobs    map[string]*entry
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool
+	wg      sync.WaitGroup
+}
+
+// New 创建调度器，instance 为当前实例标识（用于租约归属与运行记录）
+func New(store Store, instance string) *Scheduler {
+	if instance == "" {
+		instance = DefaultInstanceID()
+	}
+	return &Scheduler{
+		store:    store,
+		instance: instance,
+		now:      time.Now,
+		jobs:     make(map[string]*entry),
+	}
+}
+
+// DefaultInstanceID 使用主机名与进程号作为实例标识
+func DefaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// Instance 返回当前实例标识
+func (s *Scheduler) Instance() string {
+	return s.instance
+}
+
+// Register 注册任务；调度器已启动时立即开始调度
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("scheduler: job name and run func are required")
+	}
+	schedule, err := ParseSchedule(job.Spec)
+	if err != nil {
+		return fmt.Errorf("scheduler: job %s: %w", job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("scheduler: job %s already registered", job.Name)
+	}
+	if err := s.store.EnsureJob(job); err != nil {
+		return fmt.Errorf("scheduler: register job %s: %w", job.Name, err)
+	}
+
+	e := &entry{job: job, schedule: schedule}
+	s.jobs[job.Name] = e
+	if s.started {
+		s.startLoop(e)
+	}
+	return nil
+}
+
+// Start 启动所有已注册任务的调度循环
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return errors.New("scheduler: already started")
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.started = true
+	for _, e := range s.jobs {
+		s.startLoop(e)
+	}
+	return nil
+}
+
+// Stop 停止调度并等待正在执行的任务结束
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return nil
+	}
+	s.cancel()
+	s.started = false
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Jobs 返回所有任务的本地状态，按名称排序
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]JobInfo, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		e.mu.Lock()
+		infos = append(infos, JobInfo{
+			Name:        e.job.Name,
+			Description: e.job.Description,
+			Spec:        e.job.Spec,
+			NextRun:     e.next,
+			Running:     e.running,
+		})
+		e.mu.Unlock()
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Pause 暂停任务（集群内生效，手动触发不受影响）
+func (s *Scheduler) Pause(name string) error {
+	return s.setPaused(name, true)
+}
+
+// Resume 恢复任务
+func (s *Scheduler) Resume(name string) error {
+	return s.setPaused(name, false)
+}
+
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	if s.lookup(name) == nil {
+		return ErrJobNotFound
+	}
+	return s.store.SetPaused(name, paused)
+}
+
+// Trigger 立即在本实例执行一次任务；获取租约后异步执行并立即返回
+func (s *Scheduler) Trigger(name string) error {
+	e := s.lookup(name)
+	if e == nil {
+		return ErrJobNotFound
+	}
+
+	s.mu.RLock()
+	ctx := s.ctx
+	s.mu.RUnlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	release, err := s.acquire(e, time.Time{})
+	if err != nil {
+		return err
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer release()
+		s.run(ctx, e, TriggerManual)
+	}()
+	return nil
+}
+
+func (s *Scheduler) lookup(name string) *entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.jobs[name]
+}
+
+// startLoop 需持有 s.mu
+func (s *Scheduler) startLoop(e *entry) {
+	ctx := s.ctx
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		var prev time.Time
+		for {
+			// 定时器可能略早于触发时间唤醒，从上一次触发点之后计算避免重复
+			from := s.now()
+			if from.Before(prev) {
+				from = prev
+			}
+			next := e.schedule.Next(from)
+			if next.IsZero() {
+				log.Printf("[Scheduler] Job %s has no upcoming run, stopped", e.job.Name)
+				return
+			}
+			e.mu.Lock()
+			e.next = next
+			e.mu.Unlock()
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			prev = next
+
+			release, err := s.acquire(e, next)
+			if err != nil {
+				if !errors.Is(err, ErrLeaseHeld) && !errors.Is(err, ErrJobRunning) {
+					log.Printf("[Scheduler] Job %s acquire lease failed: %v", e.job.Name, err)
+				}
+				continue
+			}
+			s.run(ctx, e, TriggerSchedule)
+			release()
+		}
+	}()
+}
+
+// acquire 标记本地运行并获取集群租约，返回释放函数
+func (s *Scheduler) acquire(e *entry, scheduledAt time.Time) (func(), error) {
+	e.mu.Lock()
+	if e.running {
+		e.mu.Unlock()
+		return nil, ErrJobRunning
+	}
+	e.running = true
+	e.mu.Unlock()
+
+	clearRunning := func() {
+		e.mu.Lock()
+		e.running = false
+		e.mu.Unlock()
+	}
+
+	ok, err := s.store.AcquireLease(e.job.Name, s.instance, scheduledAt, s.now().Add(e.job.timeout()+time.Minute))
+	if err != nil || !ok {
+		clearRunning()
+		if err == nil {
+			err = ErrLeaseHeld
+		}
+		return nil, err
+	}
+
+	return func() {
+		if err := s.store.ReleaseLease(e.job.Name, s.instance); err != nil {
+			log.Printf("[Scheduler] Job %s release lease failed: %v", e.job.Name, err)
+		}
+		clearRunning()
+	}, nil
+}
+
+// run 执行任务并记录运行历史
+func (s *Scheduler) run(ctx context.Context, e *entry, trigger string) {
+	rec := &RunRecord{
+		Job:       e.job.Name,
+		Instance:  s.instance,
+		Trigger:   trigger,
+		Status:    RunStatusRunning,
+		StartedAt: s.now(),
+	}
+	if err := s.store.StartRun(rec); err != nil {
+		log.Printf("[Scheduler] Job %s record run failed: %v", e.job.Name, err)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, e.job.timeout())
+	err := safeRun(runCtx, e.job.Run)
+	cancel()
+
+	rec.FinishedAt = s.now()
+	rec.Duration = rec.FinishedAt.Sub(rec.StartedAt)
+	rec.Status = RunStatusSuccess
+	if err != nil {
+		rec.Status = RunStatusFailed
+		rec.Error = err.Error()
+		log.Printf("[Scheduler] Job %s failed after %s: %v", e.job.Name, rec.Duration, err)
+	}
+	if err := s.store.FinishRun(rec); err != nil {
+		log.Printf("[Scheduler] Job %s record result failed: %v", e.job.Name, err)
+	}
+}
+
+func safeRun(ctx context.Context, fn func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
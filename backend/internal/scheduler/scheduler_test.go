@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseScheduleNext(t *testing.T) {
+	base := time.Date(2026, 3, 13, 10, 7, 30, 0, time.UTC) // 周五
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 13, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 13, 10, 15, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 3, 14, 3, 0, 0, 0, time.UTC)},
+		{"30 9 * * MON-FRI", time.Date(2026, 3, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 JAN *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 15 * 0", time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 13, 11, 0, 0, 0, time.UTC)},
+		{"@every 10m", time.Date(2026, 3, 13, 10, 10, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		s, err := ParseSchedule(c.spec)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q): %v", c.spec, err)
+		}
+		if got := s.Next(base); !got.Equal(c.want) {
+			t.Errorf("%q.Next() = %v, want %v", c.spec, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "@every 1ms", "@sometimes"} {
+		if _, err := ParseSchedule(bad); err == nil {
+			t.Errorf("ParseSchedule(%q) should fail", bad)
+		}
+	}
+}
+
+// memStore 内存实现的 Store，模拟数据库租约语义
+type memStore struct {
+	mu        sync.Mutex
+	paused    map[string]bool
+	owner     map[string]string
+	until     map[string]time.Time
+	scheduled map[string]time.Time
+	runs      []RunRecord
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		paused:    map[string]bool{},
+		owner:     map[string]string{},
+		until:     map[string]time.Time{},
+		scheduled: map[string]time.Time{},
+	}
+}
+
+func (m *memStore) EnsureJob(Job) error { return nil }
+
+func (m *memStore) AcquireLease(name, owner string, scheduledAt, until time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.owner[name] != "" && m.until[name].After(time.Now()) {
+		return false, nil
+	}
+	if !scheduledAt.IsZero() {
+		if m.paused[name] || !m.scheduled[name].Before(scheduledAt) {
+			return false, nil
+		}
+		m.scheduled[name] = scheduledAt
+	}
+	m.owner[name], m.until[name] = owner, until
+	return true, nil
+}
+
+func (m *memStore) ReleaseLease(name, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.owner[name] == owner {
+		m.owner[name] = ""
+	}
+	return nil
+}
+
+func (m *memStore) SetPaused(name string, paused bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paused[name] = paused
+	return nil
+}
+
+func (m *memStore) StartRun(rec *RunRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec.ID = uint64(len(m.runs) + 1)
+	m.runs = append(m.runs, *rec)
+	return nil
+}
+
+func (m *memStore) FinishRun(rec *RunRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[rec.ID-1] = *rec
+	return nil
+}
+
+func TestScheduledTickRunsOncePerCluster(t *testing.T) {
+	store := newMemStore()
+	job := Job{Name: "cleanup", Spec: "* * * * *", Run: func(context.Context) error { return nil }}
+
+	a, b := New(store, "a"), New(store, "b")
+	if err := a.Register(job); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Register(job); err != nil {
+		t.Fatal(err)
+	}
+
+	tick := time.Date(2026, 1, 1, 0, 1, 0, 0, time.UTC)
+	release, err := a.acquire(a.lookup("cleanup"), tick)
+	if err != nil {
+		t.Fatalf("first instance should acquire lease: %v", err)
+	}
+	if _, err := b.acquire(b.lookup("cleanup"), tick); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("second instance should be rejected while lease is held, got %v", err)
+	}
+	release()
+
+	// 租约释放后，同一触发时间点也不会被再次执行
+	if _, err := b.acquire(b.lookup("cleanup"), tick); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("same tick must not run twice, got %v", err)
+	}
+	release, err = b.acquire(b.lookup("cleanup"), tick.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("next tick should be acquirable: %v", err)
+	}
+	release()
+
+	if err := a.Pause("cleanup"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.acquire(a.lookup("cleanup"), tick.Add(2*time.Minute)); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("paused job should not be scheduled, got %v", err)
+	}
+}
+
+func TestTriggerRecordsHistory(t *testing.T) {
+	store := newMemStore()
+	s := New(store, "a")
+	done := make(chan struct{})
+	err := s.Register(Job{Name: "fail", Spec: "@daily", Run: func(context.Context) error {
+		defer close(done)
+		return errors.New("smtp down")
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Pause("fail"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Trigger("fail"); err != nil {
+		t.Fatalf("manual trigger should ignore pause: %v", err)
+	}
+	<-done
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Trigger("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if len(store.runs) != 1 {
+		t.Fatalf("expected 1 run record, got %d", len(store.runs))
+	}
+	run := store.runs[0]
+	if run.Status != RunStatusFailed || run.Error != "smtp down" || run.Trigger != TriggerManual {
+		t.Fatalf("unexpected run record: %+v", run)
+	}
+}
@@ -0,0 +1,270 @@
+// Package secrets 提供敏感配置的静态加密（信封加密）
+//
+// 每个值使用随机生成的数据密钥（DEK）以 AES-256-GCM 加密，
+// 数据密钥再由主密钥（KEK）加密后与密文一起存储：
+//
+//	enc:v1:<主密钥ID>:<base64(加密后的数据密钥)>:<base64(nonce+密文)>
+//
+// 主密钥来自 SECRET_MASTER_KEY 或 SECRET_MASTER_KEY_FILE，
+// 轮换时将旧密钥放入 SECRET_PREVIOUS_KEYS（逗号分隔），仍可解密旧数据。
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"fst/backend/internal/config"
+)
+
+// Prefix 已加密值的前缀
+const Prefix = "enc:v1:"
+
+var (
+	// ErrNoMasterKey 未配置主密钥
+	ErrNoMasterKey = errors.New("secrets: master key not configured")
+	// ErrUnknownKey 密文使用的主密钥不在当前密钥环中
+	ErrUnknownKey = errors.New("secrets: ciphertext was encrypted with an unknown master key")
+	// ErrMalformed 密文格式错误
+	ErrMalformed = errors.New("secrets: malformed ciphertext")
+)
+
+// Keyring 主密钥环：当前密钥用于加密，历史密钥仅用于解密
+type Keyring struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+var (
+	mu      sync.RWMutex
+	keyring *Keyring
+)
+
+// Init 从配置加载主密钥环，未配置主密钥时加密功能处于关闭状态
+func Init() error {
+	current := strings.TrimSpace(config.SecretMasterKey.Get())
+	if current == "" {
+		if path := strings.TrimSpace(config.SecretMasterKeyFile.Get()); path != "" {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("secrets: read master key file: %w", err)
+			}
+			current = strings.TrimSpace(string(b))
+		}
+	}
+
+	var previous []string
+	for _, k := range strings.Split(config.SecretPreviousKeys.Get(), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			previous = append(previous, k)
+		}
+	}
+
+	if current == "" {
+		if len(previous) > 0 {
+			return errors.New("secrets: SECRET_PREVIOUS_KEYS is set but SECRET_MASTER_KEY is missing")
+		}
+		SetKeyring(nil)
+		return nil
+	}
+
+	kr, err := NewKeyring(current, previous...)
+	if err != nil {
+		return err
+	}
+	SetKeyring(kr)
+	return nil
+}
+
+// NewKeyring 创建密钥环，密钥为 32 字节的 base64 或 hex 编码
+func NewKeyring(current string, previous ...string) (*Keyring, error) {
+	kr := &Keyring{keys: make(map[string][]byte)}
+	for i, encoded := range append([]string{current}, previous...) {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		id := keyID(key)
+		if i == 0 {
+			kr.currentID = id
+		}
+		kr.keys[id] = key
+	}
+	return kr, nil
+}
+
+// SetKeyring 替换全局密钥环，传入 nil 表示关闭加密
+func SetKeyring(kr *Keyring) {
+	mu.Lock()
+	defer mu.Unlock()
+	keyring = kr
+}
+
+func currentKeyring() *Keyring {
+	mu.RLock()
+	defer mu.RUnlock()
+	return keyring
+}
+
+// Enabled 是否已配置主密钥
+func Enabled() bool {
+	return currentKeyring() != nil
+}
+
+// IsEncrypted 判断值是否为密文
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// IsCurrent 判断密文是否由当前主密钥加密
+func IsCurrent(value string) bool {
+	kr := currentKeyring()
+	if kr == nil || !IsEncrypted(value) {
+		return false
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, Prefix), ":")
+	return id == kr.currentID
+}
+
+// Encrypt 加密明文；空值或已加密的值原样返回
+// 未配置主密钥时：非生产环境原样返回明文，生产环境拒绝写入并返回 ErrNoMasterKey，
+// 避免明文落库后下次启动被 CheckSecretsAtRest 拦截
+func Encrypt(plaintext string) (string, error) {
+	if plaintext == "" || IsEncrypted(plaintext) {
+		return plaintext, nil
+	}
+	kr := currentKeyring()
+	if kr == nil {
+		if config.IsProductionMode() {
+			return "", ErrNoMasterKey
+		}
+		return plaintext, nil
+	}
+	return kr.Encrypt(plaintext)
+}
+
+// Decrypt 解密密文；非密文原样返回
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	kr := currentKeyring()
+	if kr == nil {
+		return "", ErrNoMasterKey
+	}
+	return kr.Decrypt(value)
+}
+
+// Encrypt 使用当前主密钥加密
+func (kr *Keyring) Encrypt(plaintext string) (string, error) {
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(kr.keys[kr.currentID], dek, []byte(kr.currentID))
+	if err != nil {
+		return "", err
+	}
+	data, err := seal(dek, []byte(plaintext), []byte(Prefix))
+	if err != nil {
+		return "", err
+	}
+
+	return Prefix + kr.currentID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(data), nil
+}
+
+// Decrypt 使用密钥环中对应的主密钥解密
+func (kr *Keyring) Decrypt(value string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(value, Prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	kek, ok := kr.keys[parts[0]]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	data, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dek, err := open(kek, wrapped, []byte(parts[0]))
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dek, data, []byte(Prefix))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(key, ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, body := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, body, aad)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: decrypt failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	if b, err := hex.DecodeString(encoded); err == nil && len(b) == 32 {
+		return b, nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(encoded); err == nil && len(b) == 32 {
+			return b, nil
+		}
+	}
+	return nil, errors.New("secrets: master key must be 32 bytes encoded as base64 or hex")
+}
+
+// keyID 主密钥标识，取 SHA-256 前 8 字节
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,99 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"fst/backend/internal/config"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune(b)), 32)))
+}
+
+func useKeyring(t *testing.T, kr *Keyring) {
+	t.Helper()
+	old := currentKeyring()
+	SetKeyring(kr)
+	t.Cleanup(func() { SetKeyring(old) })
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	kr, err := NewKeyring(testKey('a'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	useKeyring(t, kr)
+
+	enc, err := Encrypt("smtp-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(enc) || strings.Contains(enc, "smtp-password") {
+		t.Fatalf("value should be encrypted, got %q", enc)
+	}
+	again, _ := Encrypt(enc)
+	if again != enc {
+		t.Fatal("encrypting a ciphertext should be a no-op")
+	}
+	dec, err := Decrypt(enc)
+	if err != nil || dec != "smtp-password" {
+		t.Fatalf("Decrypt() = %q, %v", dec, err)
+	}
+	if empty, _ := Encrypt(""); empty != "" {
+		t.Fatalf("empty value should stay empty, got %q", empty)
+	}
+}
+
+func TestRotationKeepsOldCiphertextReadable(t *testing.T) {
+	oldRing, _ := NewKeyring(testKey('a'))
+	useKeyring(t, oldRing)
+	enc, _ := Encrypt("gateway-key")
+
+	newRing, err := NewKeyring(testKey('b'), testKey('a'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetKeyring(newRing)
+	if IsCurrent(enc) {
+		t.Fatal("old ciphertext should not be reported as current")
+	}
+	dec, err := Decrypt(enc)
+	if err != nil || dec != "gateway-key" {
+		t.Fatalf("Decrypt() with previous key = %q, %v", dec, err)
+	}
+
+	onlyNew, _ := NewKeyring(testKey('b'))
+	SetKeyring(onlyNew)
+	if _, err := Decrypt(enc); err != ErrUnknownKey {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestDisabledPassthrough(t *testing.T) {
+	useKeyring(t, nil)
+
+	if v, _ := Encrypt("plain"); v != "plain" {
+		t.Fatalf("Encrypt without key should passthrough, got %q", v)
+	}
+	if _, err := Decrypt(Prefix + "x:y:z"); err != ErrNoMasterKey {
+		t.Fatalf("expected ErrNoMasterKey, got %v", err)
+	}
+	if _, err := NewKeyring("short"); err == nil {
+		t.Fatal("invalid key length should be rejected")
+	}
+}
+
+func TestEncryptRejectsPlaintextInProductionWithoutKey(t *testing.T) {
+	useKeyring(t, nil)
+	old := config.Get()
+	config.Set(&config.Config{AppMode: "production"})
+	t.Cleanup(func() { config.Set(old) })
+
+	if _, err := Encrypt("plain"); err != ErrNoMasterKey {
+		t.Fatalf("expected ErrNoMasterKey in production without key, got %v", err)
+	}
+	if v, err := Encrypt(""); err != nil || v != "" {
+		t.Fatalf("empty value should passthrough, got %q, %v", v, err)
+	}
+}
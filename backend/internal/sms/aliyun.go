@@ -0,0 +1,142 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	aliyunEndpoint = "https://dysmsapi.aliyuncs.com/"
+	aliyunVersion  = "2017-05-25"
+	aliyunRegion   = "cn-hangzhou"
+)
+
+// AliyunClient 阿里云短信（dysmsapi SendSms）
+type AliyunClient struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	Region          string       // 为空时使用 cn-hangzhou
+	Endpoint        string       // 为空时使用 https://dysmsapi.aliyuncs.com/
+	HTTPClient      *http.Client // 为空时使用 15 秒超时的默认客户端
+}
+
+type aliyunResponse struct {
+	Code      string `json:"Code"`
+	Message   string `json:"Message"`
+	BizID     string `json:"BizId"`
+	RequestID string `json:"RequestId"`
+}
+
+// Name 服务商名称
+func (c *AliyunClient) Name() string { return "aliyun" }
+
+// Send 发送模板短信，返回 BizId（回执 ID）
+func (c *AliyunClient) Send(ctx context.Context, msg *Message) (string, error) {
+	if msg.Template == "" {
+		return "", ErrMissingTemplate
+	}
+	template_param := make(map[string]string, len(msg.ParamNames))
+	for i, name := range msg.ParamNames {
+		if i < len(msg.Params) {
+			template_param[name] = msg.Params[i]
+		}
+	}
+	param_json, err := json.Marshal(template_param)
+	if err != nil {
+		return "", err
+	}
+	region := c.Region
+	if region == "" {
+		region = aliyunRegion
+	}
+
+	params := url.Values{}
+	params.Set("Action", "SendSms")
+	params.Set("Version", aliyunVersion)
+	params.Set("Format", "JSON")
+	params.Set("RegionId", region)
+	params.Set("AccessKeyId", c.AccessKeyID)
+	params.Set("SignatureMethod", "HMAC-SHA1")
+	params.Set("SignatureVersion", "1.0")
+	params.Set("SignatureNonce", nonce())
+	params.Set("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05Z"))
+	params.Set("PhoneNumbers", strings.TrimPrefix(msg.Phone, "+"))
+	params.Set("SignName", msg.SignName)
+	params.Set("TemplateCode", msg.Template)
+	params.Set("TemplateParam", string(param_json))
+	signature := SignAliyunRPC(http.MethodPost, params, c.AccessKeySecret)
+	form := canonicalAliyunQuery(params) + "&Signature=" + aliyunPercentEncode(signature)
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = aliyunEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form))
+	if err != nil {
+		return "", fmt.Errorf("sms: aliyun: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body, err := do(c.HTTPClient, req)
+	if err != nil {
+		return "", fmt.Errorf("sms: aliyun: %w", err)
+	}
+
+	var resp aliyunResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("sms: aliyun: invalid response: %s", snippet(body))
+	}
+	if resp.Code != "OK" {
+		return "", &Error{Provider: "aliyun", Code: resp.Code, Message: resp.Message, RequestID: resp.RequestID}
+	}
+	return resp.BizID, nil
+}
+
+// SignAliyunRPC 计算阿里云 RPC 风格接口的签名（SignatureVersion 1.0，HMAC-SHA1）
+// params 中的 Signature 不参与签名；返回 Base64 编码的签名
+func SignAliyunRPC(method string, params url.Values, access_key_secret string) string {
+	string_to_sign := method + "&" + aliyunPercentEncode("/") + "&" + aliyunPercentEncode(canonicalAliyunQuery(params))
+	mac := hmac.New(sha1.New, []byte(access_key_secret+"&"))
+	mac.Write([]byte(string_to_sign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// canonicalAliyunQuery 除 Signature 外的参数按名称排序，以阿里云规则编码后拼接
+func canonicalAliyunQuery(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k != "Signature" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, aliyunPercentEncode(k)+"="+aliyunPercentEncode(params.Get(k)))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// aliyunPercentEncode RFC 3986 编码：空格为 %20，* 为 %2A，~ 不编码
+func aliyunPercentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
+
+func nonce() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,93 @@
+// Package sms 对接短信服务商的发送接口（自行实现请求签名，不依赖官方 SDK）
+//
+//   - AliyunClient：阿里云短信 SendSms，RPC 签名 v1（HMAC-SHA1）
+//   - TencentClient：腾讯云短信 SendSms（2021-01-11），TC3-HMAC-SHA256 签名
+//
+// 只发送模板短信，签名与模板需先在服务商控制台审核通过。
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Message 一条模板短信
+type Message struct {
+	Phone    string // 手机号，可带 + 与国家码；不带时阿里云按国内号码发送，腾讯云补 +86
+	SignName string // 短信签名
+	Template string // 模板 ID / 模板 Code
+	// Params 模板参数（按模板变量顺序）
+	// 阿里云模板变量按名称填充，参数名取自 ParamNames；腾讯云模板变量按 {1}、{2}… 顺序填充
+	Params     []string
+	ParamNames []string
+}
+
+// CodeMessage 验证码短信：阿里云模板变量为 ${code}，腾讯云模板变量为 {1}=验证码、{2}=有效分钟数
+func CodeMessage(phone, sign_name, template, code string, expire_minutes int) *Message {
+	return &Message{
+		Phone:      phone,
+		SignName:   sign_name,
+		Template:   template,
+		Params:     []string{code, fmt.Sprint(expire_minutes)},
+		ParamNames: []string{"code"},
+	}
+}
+
+// Client 短信服务商客户端，实现需支持并发调用
+type Client interface {
+	// Name 服务商名称
+	Name() string
+	// Send 发送模板短信，成功时返回服务商的回执 ID
+	Send(ctx context.Context, msg *Message) (string, error)
+}
+
+// Error 服务商返回的业务错误
+type Error struct {
+	Provider  string
+	Code      string
+	Message   string
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("sms: %s: %s: %s (request_id=%s)", e.Provider, e.Code, e.Message, e.RequestID)
+}
+
+// ErrMissingTemplate 未指定模板
+var ErrMissingTemplate = errors.New("sms: template is empty")
+
+var defaultHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// do 发送请求并读取响应体，非 2xx 且响应体为空时返回错误，其余交由调用方解析
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 && len(strings.TrimSpace(string(body))) == 0 {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// snippet 截取响应体用于错误信息
+func snippet(body []byte) string {
+	s := strings.TrimSpace(string(body))
+	if len(s) > 256 {
+		s = s[:256]
+	}
+	return s
+}
@@ -0,0 +1,154 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+// 阿里云文档中的签名示例
+func TestSignAliyunRPC(t *testing.T) {
+	params := url.Values{}
+	params.Set("AccessKeyId", "testid")
+	params.Set("Action", "DescribeRegions")
+	params.Set("Format", "XML")
+	params.Set("SignatureMethod", "HMAC-SHA1")
+	params.Set("SignatureNonce", "3ee8c1b8-83d3-44af-a94f-4e0ad82fd6cf")
+	params.Set("SignatureVersion", "1.0")
+	params.Set("Timestamp", "2016-02-23T12:46:24Z")
+	params.Set("Version", "2014-05-26")
+
+	if got, want := SignAliyunRPC(http.MethodGet, params, "testsecret"), "OLeaidS1JvxuMvnyHOwuJ+uX5qY="; got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+	params.Set("Signature", "ignored")
+	if got := SignAliyunRPC(http.MethodGet, params, "testsecret"); got != "OLeaidS1JvxuMvnyHOwuJ+uX5qY=" {
+		t.Errorf("Signature param should not be signed, got %s", got)
+	}
+}
+
+// 腾讯云文档中的签名示例
+func TestSignTencentTC3(t *testing.T) {
+	payload := []byte(`{"Limit": 1, "Filters": [{"Values": ["\u672a\u547d\u540d"], "Name": "instance-name"}]}`)
+	got := SignTencentTC3("AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE", "Gu5t9xGARNpq86cd98joQYCN3EXAMPLE",
+		"cvm", "cvm.tencentcloudapi.com", 1551113065, payload)
+	want := "TC3-HMAC-SHA256 Credential=AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE/2019-02-25/cvm/tc3_request, " +
+		"SignedHeaders=content-type;host, Signature=72e494ea809ad7a8c8f7a4507b9bddcbaa8e581f516e8da2f66e2c5a96525168"
+	if got != want {
+		t.Errorf("authorization =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAliyunClientSend(t *testing.T) {
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		form = r.PostForm
+		if form.Get("Signature") != SignAliyunRPC(http.MethodPost, form, "secret") {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"Code":"SignatureDoesNotMatch","Message":"bad signature","RequestId":"r-0"}`)
+			return
+		}
+		if form.Get("PhoneNumbers") == "13900000000" {
+			io.WriteString(w, `{"Code":"isv.BUSINESS_LIMIT_CONTROL","Message":"limit","RequestId":"r-2"}`)
+			return
+		}
+		io.WriteString(w, `{"Code":"OK","Message":"OK","BizId":"biz-1","RequestId":"r-1"}`)
+	}))
+	defer srv.Close()
+
+	client := &AliyunClient{AccessKeyID: "id", AccessKeySecret: "secret", Endpoint: srv.URL}
+	biz_id, err := client.Send(context.Background(), CodeMessage("+8613800000000", "F.st", "SMS_1", "123456", 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if biz_id != "biz-1" {
+		t.Errorf("biz_id = %q", biz_id)
+	}
+	checks := map[string]string{
+		"Action":        "SendSms",
+		"AccessKeyId":   "id",
+		"RegionId":      "cn-hangzhou",
+		"PhoneNumbers":  "8613800000000",
+		"SignName":      "F.st",
+		"TemplateCode":  "SMS_1",
+		"TemplateParam": `{"code":"123456"}`,
+	}
+	for k, want := range checks {
+		if got := form.Get(k); got != want {
+			t.Errorf("%s = %q, want %q", k, got, want)
+		}
+	}
+
+	_, err = client.Send(context.Background(), CodeMessage("13900000000", "F.st", "SMS_1", "123456", 10))
+	var sms_err *Error
+	if !errors.As(err, &sms_err) || sms_err.Code != "isv.BUSINESS_LIMIT_CONTROL" || sms_err.RequestID != "r-2" {
+		t.Errorf("err = %v, want isv.BUSINESS_LIMIT_CONTROL", err)
+	}
+
+	bad := &AliyunClient{AccessKeyID: "id", AccessKeySecret: "wrong", Endpoint: srv.URL}
+	if _, err := bad.Send(context.Background(), CodeMessage("13800000000", "F.st", "SMS_1", "1", 10)); !errors.As(err, &sms_err) || sms_err.Code != "SignatureDoesNotMatch" {
+		t.Errorf("wrong secret err = %v", err)
+	}
+	if _, err := client.Send(context.Background(), CodeMessage("13800000000", "F.st", "", "1", 10)); !errors.Is(err, ErrMissingTemplate) {
+		t.Errorf("empty template err = %v", err)
+	}
+}
+
+func TestTencentClientSend(t *testing.T) {
+	var req tencentRequest
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		payload, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-TC-Timestamp"), 10, 64)
+		if r.Header.Get("Authorization") != SignTencentTC3("id", "secret", "sms", r.Host, timestamp, payload) {
+			io.WriteString(w, `{"Response":{"Error":{"Code":"AuthFailure.SignatureFailure","Message":"bad signature"},"RequestId":"r-0"}}`)
+			return
+		}
+		if err := json.Unmarshal(payload, &req); err != nil {
+			t.Error(err)
+		}
+		if req.PhoneNumberSet[0] == "+8613900000000" {
+			io.WriteString(w, `{"Response":{"SendStatusSet":[{"SerialNo":"","Code":"LimitExceeded.PhoneNumberDailyLimit","Message":"limit"}],"RequestId":"r-2"}}`)
+			return
+		}
+		io.WriteString(w, `{"Response":{"SendStatusSet":[{"SerialNo":"serial-1","Code":"Ok","Message":"send success"}],"RequestId":"r-1"}}`)
+	}))
+	defer srv.Close()
+
+	client := &TencentClient{SecretID: "id", SecretKey: "secret", AppID: "1400000000", Endpoint: srv.URL}
+	serial_no, err := client.Send(context.Background(), CodeMessage("13800000000", "F.st", "100", "123456", 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial_no != "serial-1" {
+		t.Errorf("serial_no = %q", serial_no)
+	}
+	if req.PhoneNumberSet[0] != "+8613800000000" || req.SmsSdkAppID != "1400000000" || req.TemplateID != "100" {
+		t.Errorf("request = %+v", req)
+	}
+	if len(req.TemplateParamSet) != 2 || req.TemplateParamSet[0] != "123456" || req.TemplateParamSet[1] != "5" {
+		t.Errorf("TemplateParamSet = %v", req.TemplateParamSet)
+	}
+	if headers.Get("X-TC-Action") != "SendSms" || headers.Get("X-TC-Version") != "2021-01-11" || headers.Get("X-TC-Region") != "ap-guangzhou" {
+		t.Errorf("headers = %v", headers)
+	}
+
+	var sms_err *Error
+	if _, err := client.Send(context.Background(), CodeMessage("13900000000", "F.st", "100", "1", 5)); !errors.As(err, &sms_err) || sms_err.Code != "LimitExceeded.PhoneNumberDailyLimit" {
+		t.Errorf("err = %v, want LimitExceeded.PhoneNumberDailyLimit", err)
+	}
+	bad := &TencentClient{SecretID: "id", SecretKey: "wrong", AppID: "1400000000", Endpoint: srv.URL}
+	if _, err := bad.Send(context.Background(), CodeMessage("13800000000", "F.st", "100", "1", 5)); !errors.As(err, &sms_err) || sms_err.Code != "AuthFailure.SignatureFailure" {
+		t.Errorf("wrong secret err = %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tencentEndpoint    = "https://sms.tencentcloudapi.com/"
+	tencentService     = "sms"
+	tencentVersion     = "2021-01-11"
+	tencentRegion      = "ap-guangzhou"
+	tencentContentType = "application/json; charset=utf-8"
+)
+
+// TencentClient 腾讯云短信（SendSms 2021-01-11）
+type TencentClient struct {
+	SecretID   string
+	SecretKey  string
+	AppID      string       // 短信应用 SdkAppId
+	Region     string       // 为空时使用 ap-guangzhou
+	Endpoint   string       // 为空时使用 https://sms.tencentcloudapi.com/
+	HTTPClient *http.Client // 为空时使用 15 秒超时的默认客户端
+}
+
+type tencentRequest struct {
+	PhoneNumberSet   []string `json:"PhoneNumberSet"`
+	SmsSdkAppID      string   `json:"SmsSdkAppId"`
+	SignName         string   `json:"SignName"`
+	TemplateID       string   `json:"TemplateId"`
+	TemplateParamSet []string `json:"TemplateParamSet"`
+}
+
+type tencentResponse struct {
+	Response struct {
+		Error *struct {
+			Code    string `json:"Code"`
+			Message string `json:"Message"`
+		} `json:"Error"`
+		SendStatusSet []struct {
+			SerialNo string `json:"SerialNo"`
+			Code     string `json:"Code"`
+			Message  string `json:"Message"`
+		} `json:"SendStatusSet"`
+		RequestID string `json:"RequestId"`
+	} `json:"Response"`
+}
+
+// Name 服务商名称
+func (c *TencentClient) Name() string { return "tencent" }
+
+// Send 发送模板短信，返回 SerialNo（回执 ID）
+func (c *TencentClient) Send(ctx context.Context, msg *Message) (string, error) {
+	if msg.Template == "" {
+		return "", ErrMissingTemplate
+	}
+	phone := msg.Phone
+	if !strings.HasPrefix(phone, "+") {
+		phone = "+86" + phone
+	}
+	params := msg.Params
+	if params == nil {
+		params = []string{}
+	}
+	payload, err := json.Marshal(tencentRequest{
+		PhoneNumberSet:   []string{phone},
+		SmsSdkAppID:      c.AppID,
+		SignName:         msg.SignName,
+		TemplateID:       msg.Template,
+		TemplateParamSet: params,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = tencentEndpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("sms: tencent: %w", err)
+	}
+	region := c.Region
+	if region == "" {
+		region = tencentRegion
+	}
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("sms: tencent: %w", err)
+	}
+	req.Header.Set("Content-Type", tencentContentType)
+	req.Header.Set("Authorization", SignTencentTC3(c.SecretID, c.SecretKey, tencentService, u.Host, timestamp, payload))
+	req.Header.Set("X-TC-Action", "SendSms")
+	req.Header.Set("X-TC-Version", tencentVersion)
+	req.Header.Set("X-TC-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-TC-Region", region)
+	body, err := do(c.HTTPClient, req)
+	if err != nil {
+		return "", fmt.Errorf("sms: tencent: %w", err)
+	}
+
+	var resp tencentResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("sms: tencent: invalid response: %s", snippet(body))
+	}
+	r := resp.Response
+	if r.Error != nil {
+		return "", &Error{Provider: "tencent", Code: r.Error.Code, Message: r.Error.Message, RequestID: r.RequestID}
+	}
+	if len(r.SendStatusSet) == 0 {
+		return "", fmt.Errorf("sms: tencent: empty SendStatusSet (request_id=%s)", r.RequestID)
+	}
+	status := r.SendStatusSet[0]
+	if !strings.EqualFold(status.Code, "Ok") {
+		return "", &Error{Provider: "tencent", Code: status.Code, Message: status.Message, RequestID: r.RequestID}
+	}
+	return status.SerialNo, nil
+}
+
+// SignTencentTC3 计算腾讯云 API 3.0 的 TC3-HMAC-SHA256 签名，返回 Authorization 头
+// 签名的请求头为 content-type（application/json; charset=utf-8）与 host，请求方法为 POST
+func SignTencentTC3(secret_id, secret_key, service, host string, timestamp int64, payload []byte) string {
+	const algorithm = "TC3-HMAC-SHA256"
+	const signed_headers = "content-type;host"
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+
+	canonical_request := strings.Join([]string{
+		http.MethodPost,
+		"/",
+		"",
+		"content-type:" + tencentContentType + "\nhost:" + host + "\n",
+		signed_headers,
+		sha256Hex(payload),
+	}, "\n")
+	scope := date + "/" + service + "/tc3_request"
+	string_to_sign := strings.Join([]string{
+		algorithm,
+		strconv.FormatInt(timestamp, 10),
+		scope,
+		sha256Hex([]byte(canonical_request)),
+	}, "\n")
+
+	secret_date := hmacSHA256([]byte("TC3"+secret_key), date)
+	secret_service := hmacSHA256(secret_date, service)
+	secret_signing := hmacSHA256(secret_service, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secret_signing, string_to_sign))
+
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, secret_id, scope, signed_headers, signature)
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,113 @@
+// Package pluginsdk 进程外插件协议与开发包
+//
+// 进程外插件是独立的可执行文件，由主程序启动并监管：
+//  1. 主程序通过环境变量传入协议版本与调用令牌后启动插件进程
+//  2. 插件监听本地端口，在标准输出打印握手行 "FST_PLUGIN|<协议版本>|<地址>"
+//  3. 主程序读取清单（名称、版本、依赖、配置项、路由、订阅的事件），
+//     之后按插件生命周期调用 configure / init / shutdown
+//  4. 插件路由的请求由主程序鉴权后转发到插件，核心事件以 JSON 推送给插件
+//
+// 所有调用都携带 "Authorization: Bearer <令牌>"，响应统一为 {code, message, data}。
+// 插件使用 Serve 即可实现协议，不需要依赖主程序的其他代码。
+package pluginsdk
+
+import "encoding/json"
+
+// ProtocolVersion 当前协议版本，握手时版本不一致的插件拒绝加载
+const ProtocolVersion = 1
+
+// 主程序传给插件进程的环境变量
+const (
+	EnvProtocol = "FST_PLUGIN_PROTOCOL" // 协议版本
+	EnvToken    = "FST_PLUGIN_TOKEN"    // 调用令牌，插件据此校验请求来自主程序
+)
+
+// HandshakePrefix 握手行前缀
+const HandshakePrefix = "FST_PLUGIN"
+
+// 插件提供的协议接口
+const (
+	PathManifest  = "/fst/v1/manifest"  // GET  清单
+	PathConfigure = "/fst/v1/configure" // POST 应用配置，请求体为配置 map
+	PathInit      = "/fst/v1/init"      // POST 初始化
+	PathShutdown  = "/fst/v1/shutdown"  // POST 关闭，插件处理完成后应退出进程
+	PathHealth    = "/fst/v1/health"    // GET  健康检查
+	PathEvents    = "/fst/v1/events"    // POST 事件推送，请求体为 Event
+	PathHTTP      = "/fst/v1/http"      // *    路由转发前缀：/fst/v1/http/<group><path>
+)
+
+// 转发请求时附带的用户信息（主程序会清除客户端伪造的同名请求头）
+const (
+	HeaderUserID    = "X-Fst-User-Id"
+	HeaderUsername  = "X-Fst-Username"
+	HeaderRole      = "X-Fst-Role"
+	HeaderAuthGuard = "X-Fst-Auth-Guard"
+)
+
+// 路由分组，与主程序的 RouteGroups 对应
+const (
+	GroupPublic = "public" // 无需登录
+	GroupUser   = "user"   // 用户或管理员登录
+	GroupAdmin  = "admin"  // 仅管理员
+)
+
+// 配置项类型，与主程序 plugins.Field* 一致
+const (
+	FieldString = "string"
+	FieldInt    = "int"
+	FieldFloat  = "float"
+	FieldBool   = "bool"
+)
+
+// Manifest 插件清单
+type Manifest struct {
+	Protocol     int                 `json:"protocol"`
+	Name         string              `json:"name"`
+	Version      string              `json:"version"`
+	Description  string              `json:"description"`
+	Priority     int                 `json:"priority"`
+	Dependencies []string            `json:"dependencies"`
+	ConfigSchema []ConfigField       `json:"config_schema"`
+	Routes       []Route             `json:"routes"`
+	Events       []EventSubscription `json:"events"`
+}
+
+// ConfigField 配置项声明，字段含义同主程序 plugins.ConfigField
+type ConfigField struct {
+	Key         string      `json:"key"`
+	Type        string      `json:"type"`
+	Default     interface{} `json:"default,omitempty"`
+	Required    bool        `json:"required"`
+	Secret      bool        `json:"secret"`
+	Options     []string    `json:"options,omitempty"`
+	Description string      `json:"description"`
+}
+
+// Route 插件路由声明，实际路径为 /api/v1/plugins/<插件名>[/user|/admin]<path>
+type Route struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`                  // 支持 gin 路径参数，如 /items/:id
+	Group       string `json:"group"`                 // public / user / admin，默认 public
+	Summary     string `json:"summary,omitempty"`     // 接口文档中的标题
+	Description string `json:"description,omitempty"` // 接口文档中的说明
+}
+
+// EventSubscription 事件订阅声明
+// Sync 为 true 时主程序同步等待插件处理，before 类事件中返回错误即否决
+type EventSubscription struct {
+	Name string `json:"name"`
+	Sync bool   `json:"sync"`
+}
+
+// Event 推送给插件的事件
+type Event struct {
+	Name    string          `json:"name"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Response 协议接口的统一响应，code 非 200 表示失败（事件推送中即否决）
+type Response struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
@@ -0,0 +1,155 @@
+package pluginsdk
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Plugin 插件实现，未设置的回调视为成功
+type Plugin struct {
+	Manifest Manifest
+
+	Configure func(config map[string]interface{}) error
+	Init      func() error
+	Shutdown  func() error
+	Health    func(ctx context.Context) error
+	OnEvent   func(ctx context.Context, event Event) error
+
+	// Handler 处理转发的路由请求，请求路径为 /<group><path>，如 /user/items/5
+	Handler http.Handler
+}
+
+// User 转发请求中的登录用户
+type User struct {
+	ID        uint64
+	Username  string
+	Role      string
+	AuthGuard string
+}
+
+// UserFromRequest 读取主程序附带的登录用户，public 路由返回 false
+func UserFromRequest(r *http.Request) (User, bool) {
+	id, err := strconv.ParseUint(r.Header.Get(HeaderUserID), 10, 64)
+	if err != nil || id == 0 {
+		return User{}, false
+	}
+	return User{
+		ID:        id,
+		Username:  r.Header.Get(HeaderUsername),
+		Role:      r.Header.Get(HeaderRole),
+		AuthGuard: r.Header.Get(HeaderAuthGuard),
+	}, true
+}
+
+// Serve 监听本地端口、打印握手行并处理主程序调用，收到 shutdown 后返回
+// 必须由主程序启动（依赖 EnvProtocol / EnvToken 环境变量）
+func Serve(p *Plugin) error {
+	if os.Getenv(EnvProtocol) != strconv.Itoa(ProtocolVersion) {
+		return fmt.Errorf("pluginsdk: 该程序是插件，需由主程序启动（协议版本 %d）", ProtocolVersion)
+	}
+	token := os.Getenv(EnvToken)
+	if token == "" {
+		return errors.New("pluginsdk: 缺少调用令牌")
+	}
+	p.Manifest.Protocol = ProtocolVersion
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	srv := &http.Server{Handler: p.handler(token, done), ReadHeaderTimeout: 10 * time.Second}
+	go srv.Serve(ln)
+
+	fmt.Printf("%s|%d|%s\n", HandshakePrefix, ProtocolVersion, ln.Addr().String())
+
+	<-done
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+func (p *Plugin) handler(token string, done chan struct{}) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+PathManifest, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, Response{Code: 200, Data: mustJSON(p.Manifest)})
+	})
+	mux.HandleFunc("POST "+PathConfigure, func(w http.ResponseWriter, r *http.Request) {
+		var config map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			writeResult(w, fmt.Errorf("配置格式错误: %v", err))
+			return
+		}
+		writeResult(w, call(p.Configure == nil, func() error { return p.Configure(config) }))
+	})
+	mux.HandleFunc("POST "+PathInit, func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, call(p.Init == nil, p.Init))
+	})
+	mux.HandleFunc("POST "+PathShutdown, func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, call(p.Shutdown == nil, p.Shutdown))
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+	})
+	mux.HandleFunc("GET "+PathHealth, func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, call(p.Health == nil, func() error { return p.Health(r.Context()) }))
+	})
+	mux.HandleFunc("POST "+PathEvents, func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			writeResult(w, fmt.Errorf("事件格式错误: %v", err))
+			return
+		}
+		writeResult(w, call(p.OnEvent == nil, func() error { return p.OnEvent(r.Context(), event) }))
+	})
+	if p.Handler != nil {
+		mux.Handle(PathHTTP+"/", http.StripPrefix(PathHTTP, p.Handler))
+	}
+
+	expected := "Bearer " + token
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(expected)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, Response{Code: 401, Message: "invalid plugin token"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func call(skip bool, fn func() error) error {
+	if skip {
+		return nil
+	}
+	return fn()
+}
+
+func writeResult(w http.ResponseWriter, err error) {
+	if err != nil {
+		writeJSON(w, Response{Code: 500, Message: strings.TrimSpace(err.Error())})
+		return
+	}
+	writeJSON(w, Response{Code: 200, Message: "ok"})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func mustJSON(v interface{}) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
@@ -5,11 +5,12 @@ import (
 	"fst/backend/app/controllers/admin"
 	"fst/backend/app/controllers/public"
 	"fst/backend/app/controllers/user"
-	_ "fst/backend/docs"
+	"fst/backend/app/models"
+	"fst/backend/app/services"
 	"fst/backend/internal/config"
 	"fst/backend/internal/middleware"
-
-	// "fst/backend/utils"
+	"fst/backend/internal/openapi"
+	"fst/backend/utils"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -24,17 +25,27 @@ var (
 	publicAuthCtrl            *public.AuthController
 	publicSettingsCtrl        *public.SettingsController
 	publicPaymentCallbackCtrl *public.PaymentCallbackController
+	publicEmailWebhookCtrl    *public.EmailWebhookController
 	userProfileCtrl           *user.ProfileController
 	userPaymentCtrl           *user.PaymentController
+	userNotificationCtrl      *user.NotificationController
+	userRealtimeCtrl          *user.RealtimeController
 	systemCtrl                *controllers.SystemController
 	adminUserCtrl             *admin.UserController
 	adminLogCtrl              *admin.LogController
 	adminEmailTplCtrl         *admin.EmailTemplateController
 	adminEmailLogCtrl         *admin.EmailLogController
+	adminSMSLogCtrl           *admin.SMSLogController
+	adminEmailSuppressionCtrl *admin.EmailSuppressionController
+	adminEmailCampaignCtrl    *admin.EmailCampaignController
+	adminNotificationCtrl     *admin.NotificationController
 	adminSettingsCtrl         *admin.SettingsController
 	adminDebugCtrl            *admin.DebugController
 	adminMoneyScoreCtrl       *admin.UserMoneyScoreController
 	adminPaymentCtrl          *admin.PaymentController
+	adminJobCtrl              *admin.JobController
+	adminQueueCtrl            *admin.QueueController
+	adminPluginCtrl           *admin.PluginController
 )
 
 // initControllers 初始化所有控制器
@@ -42,17 +53,27 @@ func initControllers() {
 	publicAuthCtrl = public.NewAuthController()
 	publicSettingsCtrl = public.NewSettingsController()
 	publicPaymentCallbackCtrl = public.NewPaymentCallbackController()
+	publicEmailWebhookCtrl = public.NewEmailWebhookController()
 	userProfileCtrl = user.NewProfileController()
 	userPaymentCtrl = user.NewPaymentController()
+	userNotificationCtrl = user.NewNotificationController()
+	userRealtimeCtrl = user.NewRealtimeController()
 	systemCtrl = &controllers.SystemController{}
 	adminUserCtrl = admin.NewUserController()
 	adminLogCtrl = admin.NewLogController()
 	adminEmailTplCtrl = admin.NewEmailTemplateController()
 	adminEmailLogCtrl = admin.NewEmailLogController()
+	adminSMSLogCtrl = admin.NewSMSLogController()
+	adminEmailSuppressionCtrl = admin.NewEmailSuppressionController()
+	adminEmailCampaignCtrl = admin.NewEmailCampaignController()
+	adminNotificationCtrl = admin.NewNotificationController()
 	adminSettingsCtrl = admin.NewSettingsController()
 	adminDebugCtrl = admin.NewDebugController()
 	adminMoneyScoreCtrl = admin.NewUserMoneyScoreController()
 	adminPaymentCtrl = admin.NewPaymentController()
+	adminJobCtrl = admin.NewJobController()
+	adminQueueCtrl = admin.NewQueueController()
+	adminPluginCtrl = admin.NewPluginController()
 }
 
 func SetupRoutes(router *gin.Engine) {
@@ -62,10 +83,12 @@ func SetupRoutes(router *gin.Engine) {
 	initControllers()
 
 	// ========================================
-	// Swagger 文档
+	// 接口文档：运行时从路由与注册时声明的接口说明生成 OpenAPI 3，Swagger UI 读取 /openapi.json
 	// ========================================
-	if config.GlobalConfig.EnableSwagger {
-		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if config.Get().EnableSwagger {
+		setupOpenAPI()
+		router.GET("/openapi.json", openapi.Handler(router))
+		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json")))
 	}
 
 	// ========================================
@@ -86,6 +109,7 @@ func SetupRoutes(router *gin.Engine) {
 				publicAuthCtrl.RegisterRoutes(publicGroup)
 				publicSettingsCtrl.RegisterRoutes(publicGroup)
 				publicPaymentCallbackCtrl.RegisterRoutes(publicGroup)
+				publicEmailWebhookCtrl.RegisterRoutes(publicGroup)
 			}
 
 			// ----------------------------------------
@@ -96,6 +120,8 @@ func SetupRoutes(router *gin.Engine) {
 			{
 				userProfileCtrl.RegisterRoutes(userGroup)
 				userPaymentCtrl.RegisterRoutes(userGroup)
+				userNotificationCtrl.RegisterRoutes(userGroup)
+				userRealtimeCtrl.RegisterRoutes(userGroup)
 			}
 
 			// ----------------------------------------
@@ -105,6 +131,8 @@ func SetupRoutes(router *gin.Engine) {
 			system.Use(middleware.AuthMiddlewareForGuard("user", "admin"))
 			{
 				system.GET("/cleanup-status", systemCtrl.GetCleanupStatus)
+				openapi.Route(system, "GET", "/cleanup-status", openapi.Op("获取清理任务状态", "系统管理").
+					Notes("返回验证码清理任务的运行状态、间隔、上次/下次执行时间").Returns(nil))
 			}
 
 			// ----------------------------------------
@@ -122,48 +150,194 @@ func SetupRoutes(router *gin.Engine) {
 				users.Use(middleware.SimpleLogMiddleware("用户管理"))
 				{
 					users.GET("", adminUserCtrl.List)
+					openapi.Route(users, "GET", "", openapi.Op("获取用户列表", "Admin-用户管理").Notes("获取所有用户列表（分页）").Auth().
+						Paged().Query("keyword", "string", "搜索关键词").Query("status", "int", "状态").Returns(nil))
 					users.GET("/:id", adminUserCtrl.Detail)
+					openapi.Route(users, "GET", "/:id", openapi.Op("获取用户详情", "Admin-用户管理").Notes("根据ID获取用户详情").Auth().
+						Path("id", "int", "用户ID").Returns(nil))
 					users.POST("", adminUserCtrl.Create)
+					openapi.Route(users, "POST", "", openapi.Op("创建用户", "Admin-用户管理").Notes("管理员创建新用户").Auth().
+						Body(services.UserCreateRequest{}, "用户信息").Returns(nil))
 					users.POST("/batch-simple", adminUserCtrl.BatchGetSimpleInfo) // 批量获取用户简要信息
+					openapi.Route(users, "POST", "/batch-simple", openapi.Op("批量获取用户简要信息", "Admin-用户管理").
+						Notes("根据用户ID列表批量获取用户简要信息（用户名、昵称等），用于日志等场景显示用户名").Auth().
+						Body(map[string][]uint64{}, "用户ID列表 {ids: [1, 2, 3]}").Returns(nil))
 					users.PUT("/:id", adminUserCtrl.Update)
+					openapi.Route(users, "PUT", "/:id", openapi.Op("更新用户", "Admin-用户管理").Notes("更新用户信息").Auth().
+						Path("id", "int", "用户ID").Body(services.UserUpdateRequest{}, "用户信息").Returns(nil))
 					users.DELETE("/:id", adminUserCtrl.Delete)
+					openapi.Route(users, "DELETE", "/:id", openapi.Op("删除用户", "Admin-用户管理").Notes("删除指定用户").Auth().
+						Path("id", "int", "用户ID").Returns(nil))
 					users.PUT("/:id/status", adminUserCtrl.UpdateStatus)
+					openapi.Route(users, "PUT", "/:id/status", openapi.Op("更新用户状态", "Admin-用户管理").Notes("启用/禁用用户").
+						Auth().Path("id", "int", "用户ID").Body(map[string]int{}, "状态 {status: 0|1}").Returns(nil))
 					users.PUT("/:id/password", adminUserCtrl.ResetPassword)
+					openapi.Route(users, "PUT", "/:id/password", openapi.Op("重置用户密码", "Admin-用户管理").Notes("管理员重置用户密码").
+						Auth().Path("id", "int", "用户ID").Body(map[string]string{}, "新密码 {password: \"xxx\"}").
+						Returns(nil))
 					users.GET("/lookup", adminUserCtrl.LookupUser)
+					openapi.Route(users, "GET", "/lookup", openapi.Op("按标识查找用户", "Admin-用户管理").
+						Notes("通过 ID、用户名或邮箱查找用户").Auth().Query("keyword", "string", "用户标识（ID/用户名/邮箱）").Required().
+						Returns(nil))
 					users.POST("/:id/login-as", adminUserCtrl.LoginToUser)
+					openapi.Route(users, "POST", "/:id/login-as", openapi.Op("管理员登录指定用户", "Admin-用户管理").
+						Notes("管理员可以生成任意用户的 JWT token 进行调试").Auth().Path("id", "int", "用户ID").Returns(nil))
 					users.POST("/:id/reset-apikey", adminUserCtrl.ResetApiKey)
+					openapi.Route(users, "POST", "/:id/reset-apikey", openapi.Op("重置用户 API Key", "Admin-用户管理").
+						Notes("管理员重置指定用户的 API 密钥").Auth().Path("id", "int", "用户ID").Returns(nil))
 				}
 
 				// ----- 操作日志 -----
 				logs := adminGroup.Group("/logs")
 				{
 					logs.GET("", adminLogCtrl.List)
+					openapi.Route(logs, "GET", "", openapi.Op("获取操作日志列表", "Admin-操作日志").
+						Notes("获取操作日志列表（分页），仅支持简单分页浏览").Auth().Paged().Returns(nil))
 					logs.POST("/clean", adminLogCtrl.Clean)
+					openapi.Route(logs, "POST", "/clean", openapi.Op("清理操作日志", "Admin-操作日志").
+						Notes("清理指定时间之前的操作日志，用于控制日志数量").Auth().
+						Body(map[string]int64{}, "清理参数 {before_time: timestamp}").Returns(nil))
 				}
 
 				// ----- 邮件发件测试 -----
 				adminGroup.POST("/email-send-test", adminEmailTplCtrl.SendTest)
+				openapi.Route(adminGroup, "POST", "/email-send-test", openapi.Op("发件测试", "Admin-邮件模板").
+					Notes("发送测试邮件，验证SMTP配置是否正常，支持选择模板发送").Auth().Body(admin.EmailSendTestRequest{}, "测试邮件参数").
+					Returns(nil))
 
 				// ----- 邮件模板 -----
 				emailTemplates := adminGroup.Group("/email-templates")
 				{
 					emailTemplates.GET("", adminEmailTplCtrl.List)
+					openapi.Route(emailTemplates, "GET", "", openapi.Op("获取邮件模板列表", "Admin-邮件模板").Notes("获取所有邮件模板列表").
+						Auth().Returns(nil))
 					emailTemplates.GET("/:id", adminEmailTplCtrl.Detail)
+					openapi.Route(emailTemplates, "GET", "/:id", openapi.Op("获取邮件模板详情", "Admin-邮件模板").
+						Notes("根据ID获取邮件模板详情").Auth().Path("id", "int", "模板ID").Returns(nil))
 					emailTemplates.PUT("/:id", adminEmailTplCtrl.Update)
+					openapi.Route(emailTemplates, "PUT", "/:id", openapi.Op("更新邮件模板", "Admin-邮件模板").Notes("更新邮件模板内容").
+						Auth().Path("id", "int", "模板ID").Body(admin.EmailTemplateUpdateRequest{}, "更新信息").Returns(nil))
 					emailTemplates.POST("/:id/preview", adminEmailTplCtrl.Preview)
+					openapi.Route(emailTemplates, "POST", "/:id/preview", openapi.Op("预览邮件模板", "Admin-邮件模板").
+						Notes("预览邮件模板渲染效果").Auth().Path("id", "int", "模板ID").Body(admin.EmailPreviewRequest{}, "预览参数").
+						Returns(nil))
 					emailTemplates.POST("/:id/reset", adminEmailTplCtrl.Reset)
+					openapi.Route(emailTemplates, "POST", "/:id/reset", openapi.Op("重置邮件模板", "Admin-邮件模板").
+						Notes("重置邮件模板为系统默认模板").Auth().Path("id", "int", "模板ID").Returns(nil))
+					emailTemplates.GET("/:id/revisions", adminEmailTplCtrl.Revisions)
+					openapi.Route(emailTemplates, "GET", "/:id/revisions", openapi.Op("获取邮件模板版本列表", "Admin-邮件模板").
+						Notes("获取模板的修改记录（不含内容），新版本在前").Auth().Path("id", "int", "模板ID").
+						Returns([]models.EmailTemplateRevision{}))
+					emailTemplates.GET("/:id/revisions/:version", adminEmailTplCtrl.RevisionDetail)
+					openapi.Route(emailTemplates, "GET", "/:id/revisions/:version", openapi.Op("获取邮件模板版本详情", "Admin-邮件模板").
+						Auth().Path("id", "int", "模板ID").Path("version", "int", "版本号").
+						Returns(models.EmailTemplateRevision{}))
+					emailTemplates.GET("/:id/diff", adminEmailTplCtrl.Diff)
+					openapi.Route(emailTemplates, "GET", "/:id/diff", openapi.Op("对比邮件模板版本", "Admin-邮件模板").
+						Notes("逐行对比两个版本的主题与内容，不传 to 时与模板当前内容对比").Auth().Path("id", "int", "模板ID").
+						Query("from", "int", "旧版本号").Required().Query("to", "int", "新版本号").
+						Returns(services.TemplateDiff{}))
+					emailTemplates.POST("/:id/rollback", adminEmailTplCtrl.Rollback)
+					openapi.Route(emailTemplates, "POST", "/:id/rollback", openapi.Op("回滚邮件模板", "Admin-邮件模板").
+						Notes("将模板恢复为指定版本的内容，作为新版本保存").Auth().Path("id", "int", "模板ID").
+						Body(admin.EmailTemplateRollbackRequest{}, "目标版本").Returns(nil))
 				}
 
 				// ----- 邮件发送记录 -----
 				emailLogs := adminGroup.Group("/email-logs")
 				{
 					emailLogs.GET("", adminEmailLogCtrl.List)
+					openapi.Route(emailLogs, "GET", "", openapi.Op("获取邮件发送记录列表", "Admin-邮件日志").
+						Notes("分页获取邮件发送记录，支持按收件人、模板名、状态筛选").Auth().Paged().Query("to_email", "string", "收件人邮箱（模糊）").
+						Query("template_name", "string", "模板名称").Query("campaign_id", "int", "群发任务ID").
+						Query("status", "int", "状态: -1=全部, 0=失败, 1=成功, 2=跳过").Default(-1).
+						Query("start_time", "string", "开始时间 (YYYY-MM-DD HH:MM:SS)").
+						Query("end_time", "string", "结束时间 (YYYY-MM-DD HH:MM:SS)").Returns(nil))
 					emailLogs.GET("/stats", adminEmailLogCtrl.Stats)
+					openapi.Route(emailLogs, "GET", "/stats", openapi.Op("邮件发送统计", "Admin-邮件日志").
+						Notes("获取邮件发送总数、成功数、失败数、跳过数").Auth().Returns(nil))
 					emailLogs.GET("/template-names", adminEmailLogCtrl.TemplateNames)
+					openapi.Route(emailLogs, "GET", "/template-names", openapi.Op("获取邮件模板名列表", "Admin-邮件日志").
+						Notes("获取邮件日志中出现的所有模板名，用于筛选").Auth().Returns(nil))
 					emailLogs.GET("/:id", adminEmailLogCtrl.Detail)
+					openapi.Route(emailLogs, "GET", "/:id", openapi.Op("获取邮件发送记录详情", "Admin-邮件日志").
+						Notes("根据 ID 获取邮件日志详情，包含邮件内容").Auth().Path("id", "int", "日志ID").Returns(nil))
 					emailLogs.POST("/clean", adminEmailLogCtrl.Clean)
+					openapi.Route(emailLogs, "POST", "/clean", openapi.Op("清理邮件发送记录", "Admin-邮件日志").
+						Notes("删除指定日期之前的邮件日志").Auth().
+						Body(map[string]string{}, "清理参数 {before: '2025-01-01 00:00:00'}").Returns(nil))
+				}
+
+				// ----- 短信发送记录 -----
+				smsLogs := adminGroup.Group("/sms-logs")
+				{
+					smsLogs.GET("", adminSMSLogCtrl.List)
+					openapi.Route(smsLogs, "GET", "", openapi.Op("获取短信发送记录列表", "Admin-短信日志").
+						Notes("分页获取短信发送记录，支持按手机号、用途、服务商、状态筛选（不记录验证码内容）").Auth().Paged().
+						Query("phone", "string", "手机号（模糊）").
+						Query("purpose", "string", "用途: register, login, change_phone").
+						Query("provider", "string", "服务商: console, aliyun, tencent").
+						Query("status", "int", "状态: -1=全部, 0=失败, 1=成功").Default(-1).
+						Query("start_time", "string", "开始时间 (YYYY-MM-DD HH:MM:SS)").
+						Query("end_time", "string", "结束时间 (YYYY-MM-DD HH:MM:SS)").Returns(nil))
+					smsLogs.GET("/stats", adminSMSLogCtrl.Stats)
+					openapi.Route(smsLogs, "GET", "/stats", openapi.Op("短信发送统计", "Admin-短信日志").
+						Notes("获取短信发送总数、成功数、失败数").Auth().Returns(nil))
+					smsLogs.POST("/clean", adminSMSLogCtrl.Clean)
+					openapi.Route(smsLogs, "POST", "/clean", openapi.Op("清理短信发送记录", "Admin-短信日志").
+						Notes("删除指定日期之前的短信日志").Auth().
+						Body(map[string]string{}, "清理参数 {before: '2025-01-01 00:00:00'}").Returns(nil))
+				}
+
+				// ----- 邮件抑制列表（退信 / 投诉） -----
+				emailSuppressions := adminGroup.Group("/email-suppressions")
+				{
+					emailSuppressions.GET("", adminEmailSuppressionCtrl.List)
+					openapi.Route(emailSuppressions, "GET", "", openapi.Op("获取邮件抑制列表", "Admin-邮件日志").
+						Notes("分页获取因退信、投诉记录的地址，已抑制的地址不再发送邮件").Auth().Paged().Query("email", "string", "邮箱（模糊）").
+						Query("reason", "string", "原因: hard_bounce, soft_bounce, complaint").
+						Query("suppressed", "int", "状态: -1=全部, 0=仅记录, 1=已抑制").Default(-1).Returns(nil))
+					emailSuppressions.DELETE("/:id", adminEmailSuppressionCtrl.Delete)
+					openapi.Route(emailSuppressions, "DELETE", "/:id", openapi.Op("移除邮件抑制记录", "Admin-邮件日志").
+						Notes("删除抑制记录，该地址恢复接收邮件，退信次数清零").Auth().Path("id", "int", "记录ID").Returns(nil))
+				}
+
+				// ----- 邮件群发 -----
+				emailCampaigns := adminGroup.Group("/email-campaigns")
+				{
+					emailCampaigns.GET("", adminEmailCampaignCtrl.List)
+					openapi.Route(emailCampaigns, "GET", "", openapi.Op("获取邮件群发任务列表", "Admin-邮件群发").
+						Notes("分页获取群发任务及发送进度").Auth().Paged().Query("name", "string", "任务名称（模糊）").
+						Query("status", "string", "状态: scheduled, running, paused, completed, cancelled").Returns(nil))
+					emailCampaigns.POST("", adminEmailCampaignCtrl.Create)
+					openapi.Route(emailCampaigns, "POST", "", openapi.Op("创建邮件群发任务", "Admin-邮件群发").
+						Notes("选择模板与收件人筛选条件创建群发，可设置计划时间与每分钟发送数").Auth().Body(services.EmailCampaignRequest{}, "群发参数").
+						Returns(models.EmailCampaign{}))
+					emailCampaigns.POST("/preview", adminEmailCampaignCtrl.Preview)
+					openapi.Route(emailCampaigns, "POST", "/preview", openapi.Op("预览群发收件人数", "Admin-邮件群发").
+						Notes("按筛选条件统计当前匹配的收件人数（未删除且填写了邮箱的用户）").Auth().
+						Body(admin.EmailCampaignPreviewRequest{}, "筛选条件").Returns(nil))
+					emailCampaigns.GET("/:id", adminEmailCampaignCtrl.Detail)
+					openapi.Route(emailCampaigns, "GET", "/:id", openapi.Op("获取邮件群发任务详情", "Admin-邮件群发").
+						Notes("获取群发任务的模板、筛选条件与发送进度，每个收件人的结果见邮件日志 campaign_id").Auth().Path("id", "int", "任务ID").
+						Returns(models.EmailCampaign{}))
+					emailCampaigns.POST("/:id/pause", adminEmailCampaignCtrl.Pause)
+					openapi.Route(emailCampaigns, "POST", "/:id/pause", openapi.Op("暂停邮件群发", "Admin-邮件群发").
+						Notes("暂停等待中或发送中的群发任务，恢复后从上次处理到的用户继续").Auth().Path("id", "int", "任务ID").Returns(nil))
+					emailCampaigns.POST("/:id/resume", adminEmailCampaignCtrl.Resume)
+					openapi.Route(emailCampaigns, "POST", "/:id/resume", openapi.Op("恢复邮件群发", "Admin-邮件群发").
+						Notes("恢复已暂停的群发任务").Auth().Path("id", "int", "任务ID").Returns(nil))
+					emailCampaigns.POST("/:id/cancel", adminEmailCampaignCtrl.Cancel)
+					openapi.Route(emailCampaigns, "POST", "/:id/cancel", openapi.Op("取消邮件群发", "Admin-邮件群发").
+						Notes("取消未结束的群发任务，已发送的邮件不受影响").Auth().Path("id", "int", "任务ID").Returns(nil))
 				}
 
+				// ----- 站内通知 -----
+				adminGroup.POST("/notifications/broadcast", adminNotificationCtrl.Broadcast)
+				openapi.Route(adminGroup, "POST", "/notifications/broadcast", openapi.Op("广播站内通知", "Admin-站内通知").
+					Notes("向指定用户或全部用户发送系统通知；可传单语言 title/content，或多语言 title_i18n/content_i18n（优先）").Auth().
+					Body(services.NotificationBroadcast{}, "通知内容").Returns(nil))
+
 				// ----- 余额/积分管理 -----
 				adminMoneyScoreCtrl.RegisterRoutes(adminGroup)
 
@@ -173,6 +347,15 @@ func SetupRoutes(router *gin.Engine) {
 				// ----- 支付订单管理 -----
 				adminPaymentCtrl.RegisterPaymentRoutes(adminGroup)
 
+				// ----- 定时任务 -----
+				adminJobCtrl.RegisterRoutes(adminGroup)
+
+				// ----- 任务队列 -----
+				adminQueueCtrl.RegisterRoutes(adminGroup)
+
+				// ----- 插件管理 -----
+				adminPluginCtrl.RegisterRoutes(adminGroup)
+
 				// ----- 调试工具 -----
 				adminDebugCtrl.RegisterRoutes(adminGroup)
 			}
@@ -204,3 +387,19 @@ func SetupRoutes(router *gin.Engine) {
 		}
 	}
 }
+
+// setupOpenAPI 接口文档基本信息
+func setupOpenAPI() {
+	openapi.SetInfo(openapi.Info{
+		Title:       "FST Platform API",
+		Description: "FST Platform 后端 API 接口文档",
+		Version:     "1.0",
+	})
+	openapi.AddSecurityScheme(openapi.BearerAuth, openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        "Authorization",
+		Description: "JWT认证令牌，格式: Bearer {token}",
+	})
+	openapi.SetEnvelope(utils.Response{}, "data")
+}
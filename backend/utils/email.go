@@ -1,185 +1,214 @@
 package utils
 
 import (
-	"crypto/tls"
-	"encoding/base64"
+	"context"
 	"fmt"
 	"fst/backend/internal/config"
+	"fst/backend/internal/mailer"
 	"log"
-	"net"
-	"net/smtp"
+	"net/mail"
+	"os"
 	"strings"
+	"sync"
 	"time"
-	"unicode/utf8"
 )
 
-// EmailMessage 邮件内容
+// EmailMessage 邮件内容（单个收件人的简单邮件；需要抄送、附件等时使用 mailer.Message）
 type EmailMessage struct {
 	To      string
 	Subject string
-	Body    string
+	Body    string // HTML 内容
+	Text    string // 纯文本版本，非空时以 multipart/alternative 发送
 }
 
-func encodeRFC2047IfNeeded(s string) string {
-	if s == "" {
-		return s
-	}
-	if utf8.ValidString(s) {
-		allASCII := true
-		for i := 0; i < len(s); i++ {
-			if s[i] >= 128 {
-				allASCII = false
-				break
-			}
-		}
-		if allASCII {
-			return s
-		}
-	}
-	encoded := base64.StdEncoding.EncodeToString([]byte(s))
-	return fmt.Sprintf("=?UTF-8?B?%s?=", encoded)
-}
-
-// SendEmail 发送邮件 (支持 SSL)
-func SendEmail(msg EmailMessage) error {
-	cfg := config.GlobalConfig
-	if cfg.SMTPHost == "" {
-		return fmt.Errorf("SMTP host not configured")
-	}
-
-	// 使用 SYSTEM_EMAIL_ADDRESS 作为发信人邮箱地址，如果为空则使用 SMTPUser
+// DefaultFrom 默认发件人：SYSTEM_EMAIL_ADDRESS（为空时使用 SMTP 用户名），名称为 SYSTEM_EMAIL_NAME（为空时使用 AppName）
+func DefaultFrom() string {
+	cfg := config.Get()
 	fromEmail := cfg.SystemEmail
 	if fromEmail == "" {
 		fromEmail = cfg.SMTPUser
 	}
-	// 使用 SYSTEM_EMAIL_NAME 作为发信人名称，如果为空则使用 AppName
 	emailName := cfg.SystemEmailName
 	if emailName == "" {
 		emailName = cfg.AppName
 	}
+	return (&mail.Address{Name: emailName, Address: fromEmail}).String()
+}
 
-	fromHeader := fmt.Sprintf("%s <%s>", encodeRFC2047IfNeeded(emailName), fromEmail)
-	subjectHeader := encodeRFC2047IfNeeded(msg.Subject)
-
-	message := ""
-	message += fmt.Sprintf("From: %s\r\n", fromHeader)
-	message += fmt.Sprintf("To: %s\r\n", msg.To)
-	message += fmt.Sprintf("Subject: %s\r\n", subjectHeader)
-	message += "MIME-Version: 1.0\r\n"
-	message += "Content-Type: text/html; charset=UTF-8\r\n"
-	message += "\r\n" + msg.Body
-
-	auth := smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPass, cfg.SMTPHost)
+// SendEmail 发送邮件 (支持 SSL)
+func SendEmail(msg EmailMessage) error {
+	return SendMessage(&mailer.Message{
+		To:      []string{msg.To},
+		Subject: msg.Subject,
+		HTML:    msg.Body,
+		Text:    msg.Text,
+	})
+}
 
-	// 重试机制：最多尝试3次，处理 EOF 等瞬时错误
-	var err error
-	for attempt := 1; attempt <= 3; attempt++ {
-		if cfg.SMTPSSL {
-			err = sendEmailSSL(cfg.SMTPHost, cfg.SMTPPort, fromEmail, msg.To, message, auth)
-		} else {
-			addr := net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort)
-			err = smtp.SendMail(addr, auth, fromEmail, []string{msg.To}, []byte(message))
-		}
-		if err == nil {
-			return nil
-		}
-		log.Printf("[Email] 第 %d 次发送失败: %v", attempt, err)
-		if attempt < 3 {
-			time.Sleep(time.Duration(attempt) * 2 * time.Second)
-		}
+// SendMessage 通过当前投递方式发送 MIME 邮件；From 为空时使用 DefaultFrom，配置了 DKIM 时签名
+// 失败时直接返回错误，不在当前 goroutine 上等待重试；需要重试的调用方通过队列发送
+func SendMessage(msg *mailer.Message) error {
+	transport, err := MailTransport()
+	if err != nil {
+		return err
+	}
+	if msg.From == "" {
+		msg.From = DefaultFrom()
+	}
+	data, err := BuildMessage(msg)
+	if err != nil {
+		return err
+	}
+	env, err := mailer.NewEnvelope(msg, data)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("发送邮件失败（已重试3次）: %w", err)
+	ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+	defer cancel()
+	if err := transport.Send(ctx, env); err != nil {
+		return fmt.Errorf("发送邮件失败（%s）: %w", transport.Name(), err)
+	}
+	return nil
 }
 
-// loginAuth 实现 LOGIN 认证方式（兼容 Yandex 等邮件服务商）
-// Go 内置的 PlainAuth 在 tls.Dial 隐式 SSL 连接上会误判为非加密连接
-type loginAuth struct {
-	username, password string
-}
+// mailSendTimeout 单封邮件投递（含等待连接池空闲连接）的超时时间
+const mailSendTimeout = 2 * time.Minute
 
-func LoginAuth(username, password string) smtp.Auth {
-	return &loginAuth{username, password}
-}
+var (
+	transportMu     sync.Mutex
+	transportKey    string // 创建当前投递方式所用的配置，配置变化时重建
+	transportCached mailer.MailTransport
+)
 
-func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
-	return "LOGIN", []byte(a.username), nil
-}
+// MailTransport 按当前配置（mail_transport 等）返回邮件投递方式
+// 配置变化时创建新的投递方式并关闭旧的（SMTP 连接池中正在使用的连接在投递完成后关闭）
+func MailTransport() (mailer.MailTransport, error) {
+	cfg := config.Get()
+	kind := cfg.MailTransport
+	if kind == "" {
+		kind = "smtp"
+	}
+	var key string
+	switch kind {
+	case "smtp":
+		key = strings.Join([]string{kind, cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass, fmt.Sprint(cfg.SMTPSSL, cfg.SMTPPoolSize)}, "|")
+	case "http":
+		key = strings.Join([]string{kind, cfg.MailHTTPURL, cfg.MailHTTPToken}, "|")
+	case "file", "maildir":
+		// 写入本地目录仅用于开发测试，且只能通过环境变量 MAIL_TRANSPORT 启用
+		if config.IsProductionMode() {
+			return nil, fmt.Errorf("生产环境不支持邮件投递方式: %s", kind)
+		}
+		key = kind + "|" + cfg.MailFileDir
+	default:
+		return nil, fmt.Errorf("不支持的邮件投递方式: %s", kind)
+	}
 
-func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
-	if more {
-		switch string(fromServer) {
-		case "Username:":
-			return []byte(a.username), nil
-		case "Password:":
-			return []byte(a.password), nil
-		default:
-			return nil, fmt.Errorf("unknown LOGIN challenge: %s", fromServer)
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	if key == transportKey && transportCached != nil {
+		return transportCached, nil
+	}
+
+	var transport mailer.MailTransport
+	switch kind {
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP host not configured")
+		}
+		transport = mailer.NewSMTPPool(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUser,
+			Password: cfg.SMTPPass,
+			SSL:      cfg.SMTPSSL,
+		}, cfg.SMTPPoolSize, 0)
+	case "http":
+		if cfg.MailHTTPURL == "" {
+			return nil, fmt.Errorf("邮件 HTTP 投递地址未配置")
 		}
+		transport = &mailer.HTTPTransport{URL: cfg.MailHTTPURL, Token: cfg.MailHTTPToken}
+	default:
+		dir := cfg.MailFileDir
+		if dir == "" {
+			dir = "storage/mail"
+		}
+		transport = &mailer.FileTransport{Dir: dir, Maildir: kind == "maildir"}
+	}
+
+	if transportCached != nil {
+		transportCached.Close()
 	}
-	return nil, nil
+	transportKey, transportCached = key, transport
+	log.Printf("[Email] 使用投递方式: %s", transport.Name())
+	return transport, nil
 }
 
-func sendEmailSSL(host, port, from, to, message string, auth smtp.Auth) error {
-	addr := net.JoinHostPort(host, port)
-	tlsconfig := &tls.Config{
-		ServerName:         host,
+// CloseMailTransport 关闭当前投递方式（释放 SMTP 连接池），进程退出时调用
+func CloseMailTransport(context.Context) error {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	if transportCached == nil {
+		return nil
 	}
+	err := transportCached.Close()
+	transportKey, transportCached = "", nil
+	return err
+}
 
-	log.Printf("[Email] 连接 %s (TLS)...", addr)
-	dialer := &net.Dialer{Timeout: 30 * time.Second}
-	conn, err := tls.DialWithDialer(dialer, "tcp", addr, tlsconfig)
+// BuildMessage 生成邮件内容，配置了 DKIM 时附加签名
+func BuildMessage(msg *mailer.Message) ([]byte, error) {
+	data, err := msg.Build()
 	if err != nil {
-		return fmt.Errorf("TLS连接失败: %w", err)
+		return nil, err
 	}
-
-	client, err := smtp.NewClient(conn, host)
+	signer, err := dkimSigner()
 	if err != nil {
-		conn.Close()
-		return fmt.Errorf("SMTP客户端创建失败: %w", err)
-	}
-	defer client.Close()
-
-	log.Printf("[Email] EHLO 成功，开始认证...")
-	if err = client.Auth(auth); err != nil {
-		log.Printf("[Email] AUTH 失败: %v, 尝试 LOGIN 方式...", err)
-		// PlainAuth 失败时回退到 LOGIN 认证
-		loginA := LoginAuth(
-			config.GlobalConfig.SMTPUser,
-			config.GlobalConfig.SMTPPass,
-		)
-		if err = client.Auth(loginA); err != nil {
-			return fmt.Errorf("认证失败: %w", err)
-		}
+		return nil, err
 	}
-	log.Printf("[Email] 认证成功")
-
-	if err = client.Mail(from); err != nil {
-		return fmt.Errorf("MAIL FROM 失败: %w", err)
+	if signer == nil {
+		return data, nil
 	}
+	return signer.Sign(data)
+}
 
-	if err = client.Rcpt(to); err != nil {
-		return fmt.Errorf("RCPT TO 失败: %w", err)
-	}
+var (
+	dkimMu     sync.Mutex
+	dkimKey    string // 已解析的配置（domain|selector|key），配置变化时重新解析
+	dkimCached *mailer.DKIMSigner
+)
 
-	log.Printf("[Email] 开始写入邮件数据 (%d bytes)...", len(message))
-	w, err := client.Data()
-	if err != nil {
-		return fmt.Errorf("DATA命令失败: %w", err)
+// dkimSigner 按当前配置返回 DKIM 签名器，未配置时返回 nil
+func dkimSigner() (*mailer.DKIMSigner, error) {
+	cfg := config.Get()
+	if cfg.DKIMDomain == "" || cfg.DKIMSelector == "" || cfg.DKIMPrivateKey == "" {
+		return nil, nil
 	}
 
-	_, err = w.Write([]byte(message))
-	if err != nil {
-		return fmt.Errorf("写入邮件内容失败: %w", err)
+	dkimMu.Lock()
+	defer dkimMu.Unlock()
+	key := cfg.DKIMDomain + "|" + cfg.DKIMSelector + "|" + cfg.DKIMPrivateKey
+	if key == dkimKey && dkimCached != nil {
+		return dkimCached, nil
 	}
 
-	err = w.Close()
+	// 私钥可以是 PEM 内容（换行可写作 \n），也可以是 PEM 文件路径
+	// 后台设置只接受 PEM 内容（见 config.ValidateSetting），文件路径只可能来自环境变量
+	pem := strings.ReplaceAll(cfg.DKIMPrivateKey, `\n`, "\n")
+	if !strings.Contains(pem, "-----BEGIN") {
+		content, err := os.ReadFile(pem)
+		if err != nil {
+			return nil, fmt.Errorf("读取 DKIM 私钥失败: %w", err)
+		}
+		pem = string(content)
+	}
+	signer, err := mailer.NewDKIMSigner(cfg.DKIMDomain, cfg.DKIMSelector, pem)
 	if err != nil {
-		return fmt.Errorf("完成数据传输失败: %w", err)
+		return nil, err
 	}
-
-	log.Printf("[Email] 邮件发送成功")
-	return client.Quit()
+	dkimKey, dkimCached = key, signer
+	return signer, nil
 }
 
 // ReplaceTemplateVars 替换模板变量
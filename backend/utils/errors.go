@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ========================================
+// 错误模型（API 版本 2）
+//
+// 版本 1（默认）：HTTP 状态码始终为 200，错误码只在 JSON 的 code 中
+// 版本 2（请求头 X-API-Version: 2）：HTTP 状态码与 code 一致，data 中附带稳定的
+// 错误码与字段校验详情，便于代理、监控与重试识别失败请求
+// ========================================
+
+const (
+	// APIVersionKey 上下文中当前请求的 API 版本（由 middleware.APIVersion 设置）
+	APIVersionKey = "api_version"
+	// RequestIDKey 上下文中的请求 ID（由 middleware.RequestID 设置）
+	RequestIDKey = "request_id"
+)
+
+// 稳定的错误码，客户端按错误码而不是提示文字判断错误类型
+const (
+	ErrBadRequest         = "bad_request"
+	ErrValidationFailed   = "validation_failed"
+	ErrUnauthorized       = "unauthorized"
+	ErrTokenMissing       = "token_missing"
+	ErrTokenInvalid       = "token_invalid"
+	ErrTokenExpired       = "token_expired"
+	ErrSessionRevoked     = "session_revoked"
+	ErrForbidden          = "forbidden"
+	ErrNotFound           = "not_found"
+	ErrMethodNotAllowed   = "method_not_allowed"
+	ErrConflict           = "conflict"
+	ErrTooManyRequests    = "too_many_requests"
+	ErrInternal           = "internal_error"
+	ErrBadGateway         = "bad_gateway"
+	ErrServiceUnavailable = "service_unavailable"
+)
+
+// defaultErrorCodes 未指定错误码时按 code 取默认值
+var defaultErrorCodes = map[int]string{
+	400: ErrBadRequest,
+	401: ErrUnauthorized,
+	403: ErrForbidden,
+	404: ErrNotFound,
+	405: ErrMethodNotAllowed,
+	409: ErrConflict,
+	422: ErrValidationFailed,
+	429: ErrTooManyRequests,
+	500: ErrInternal,
+	502: ErrBadGateway,
+	503: ErrServiceUnavailable,
+}
+
+// ErrorData 版本 2 错误响应的 data
+type ErrorData struct {
+	ErrorCode string       `json:"error_code"`        // 稳定的错误码，如 validation_failed
+	Details   []FieldError `json:"details,omitempty"` // 字段校验详情
+}
+
+// FieldError 字段校验错误
+type FieldError struct {
+	Field   string `json:"field"`           // 字段名（JSON 名称）
+	Rule    string `json:"rule"`            // 未通过的规则，如 required、min
+	Param   string `json:"param,omitempty"` // 规则参数，如 min=6 中的 6
+	Message string `json:"message"`
+}
+
+// ErrorCode 按 code 返回默认错误码
+func ErrorCode(code int) string {
+	if errCode, ok := defaultErrorCodes[code]; ok {
+		return errCode
+	}
+	if code >= 500 {
+		return ErrInternal
+	}
+	return ErrBadRequest
+}
+
+// UseStatusCodes 当前请求是否使用版本 2 的错误模型
+func UseStatusCodes(c *gin.Context) bool {
+	return c.GetInt(APIVersionKey) >= 2
+}
+
+// FailCode 返回错误并指定稳定的错误码（版本 1 下与 Fail 相同）
+func FailCode(c *gin.Context, code int, errCode, message string) {
+	fail(c, code, message, ErrorData{ErrorCode: errCode})
+}
+
+// FailBind 返回请求参数绑定错误，版本 2 下附带字段校验详情；
+// req 为绑定的目标结构体，用于取字段的 JSON 名称
+//
+//	if err := c.ShouldBindJSON(&req); err != nil {
+//		utils.FailBind(c, err, &req)
+//		return
+//	}
+func FailBind(c *gin.Context, err error, req any) {
+	details := BindErrorDetails(err, req)
+	errCode := ErrBadRequest
+	if len(details) > 0 {
+		errCode = ErrValidationFailed
+	}
+	message := err.Error()
+	if UseStatusCodes(c) && len(details) > 0 {
+		message = details[0].Message
+	}
+	fail(c, 400, message, ErrorData{ErrorCode: errCode, Details: details})
+}
+
+func fail(c *gin.Context, code int, message string, data ErrorData) {
+	if !UseStatusCodes(c) {
+		c.JSON(200, Response{Code: code, Message: message, RequestID: c.GetString(RequestIDKey)})
+		return
+	}
+	if data.ErrorCode == "" {
+		data.ErrorCode = ErrorCode(code)
+	}
+	c.JSON(httpStatus(code), Response{Code: code, Message: message, Data: data, RequestID: c.GetString(RequestIDKey)})
+}
+
+// httpStatus 业务码即 HTTP 状态码（400~599），其他值按 500 处理
+func httpStatus(code int) int {
+	if code >= 400 && code < 600 {
+		return code
+	}
+	return http.StatusInternalServerError
+}
+
+// BindErrorDetails 将 gin 绑定错误转换为字段校验详情
+func BindErrorDetails(err error, req any) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		details := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			field := jsonPath(req, fe.StructNamespace())
+			details = append(details, FieldError{
+				Field:   field,
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: ruleMessage(field, fe.Tag(), fe.Param()),
+			})
+		}
+		return details
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: fmt.Sprintf("%s 应为 %s 类型", typeErr.Field, typeErr.Type.String()),
+		}}
+	}
+	return nil
+}
+
+// jsonPath 将 LoginRequest.Profile.NickName 转换为 profile.nick_name 形式的 JSON 路径
+func jsonPath(req any, namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 1 {
+		parts = parts[1:] // 去掉结构体名
+	}
+
+	t := reflect.TypeOf(req)
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		name, index, _ := strings.Cut(part, "[")
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		var field reflect.StructField
+		var ok bool
+		if t != nil && t.Kind() == reflect.Struct {
+			field, ok = t.FieldByName(name)
+		}
+		if ok {
+			if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" && tag != "-" {
+				name = tag
+			} else if tag, _, _ := strings.Cut(field.Tag.Get("form"), ","); tag != "" && tag != "-" {
+				name = tag
+			}
+			t = field.Type
+		} else {
+			t = nil
+		}
+		if index != "" {
+			name += "[" + index
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ".")
+}
+
+// ruleMessage 常见校验规则的提示
+func ruleMessage(field, rule, param string) string {
+	switch rule {
+	case "required":
+		return field + " 不能为空"
+	case "min":
+		return fmt.Sprintf("%s 不能小于 %s", field, param)
+	case "max":
+		return fmt.Sprintf("%s 不能大于 %s", field, param)
+	case "len":
+		return fmt.Sprintf("%s 长度必须为 %s", field, param)
+	case "email":
+		return field + " 不是有效的邮箱地址"
+	case "oneof":
+		return fmt.Sprintf("%s 必须是 [%s] 之一", field, param)
+	case "gt", "gte", "lt", "lte":
+		return fmt.Sprintf("%s 不满足 %s=%s", field, rule, param)
+	}
+	if param != "" {
+		return fmt.Sprintf("%s 未通过 %s=%s 校验", field, rule, param)
+	}
+	return fmt.Sprintf("%s 未通过 %s 校验", field, rule)
+}
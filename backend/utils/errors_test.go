@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bindProfile struct {
+	NickName string `json:"nick_name" binding:"required"`
+}
+
+type bindRequest struct {
+	Email    string        `json:"email" binding:"required,email"`
+	Password string        `json:"password" binding:"required,min=6"`
+	Age      int           `json:"age"`
+	Profile  bindProfile   `json:"profile"`
+	Tags     []bindProfile `json:"tags" binding:"dive"`
+}
+
+func serve(version int, handler gin.HandlerFunc, body string) (*httptest.ResponseRecorder, map[string]any) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/", func(c *gin.Context) {
+		c.Set(RequestIDKey, "req-1")
+		if version > 0 {
+			c.Set(APIVersionKey, version)
+		}
+		handler(c)
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	var resp map[string]any
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w, resp
+}
+
+func TestFailVersions(t *testing.T) {
+	forbidden := func(c *gin.Context) { Fail(c, 403, "无权限") }
+
+	// 版本 1：HTTP 200，data 为 null
+	w, resp := serve(0, forbidden, "")
+	if w.Code != 200 || resp["code"] != float64(403) || resp["data"] != nil || resp["request_id"] != "req-1" {
+		t.Fatalf("v1 = %d %v", w.Code, resp)
+	}
+
+	// 版本 2：HTTP 状态码与 code 一致，data 中带错误码
+	w, resp = serve(2, forbidden, "")
+	data, _ := resp["data"].(map[string]any)
+	if w.Code != 403 || resp["code"] != float64(403) || data["error_code"] != ErrForbidden {
+		t.Fatalf("v2 = %d %v", w.Code, resp)
+	}
+
+	w, resp = serve(2, func(c *gin.Context) { FailCode(c, 401, ErrTokenExpired, "expired") }, "")
+	data, _ = resp["data"].(map[string]any)
+	if w.Code != 401 || data["error_code"] != ErrTokenExpired {
+		t.Fatalf("FailCode = %d %v", w.Code, resp)
+	}
+
+	// 非 HTTP 状态码的业务码按 500 返回
+	w, _ = serve(2, func(c *gin.Context) { Fail(c, 1001, "unknown") }, "")
+	if w.Code != 500 {
+		t.Fatalf("status = %d", w.Code)
+	}
+
+	w, resp = serve(2, func(c *gin.Context) { Success(c, "ok") }, "")
+	if w.Code != 200 || resp["data"] != "ok" || resp["request_id"] != "req-1" {
+		t.Fatalf("success = %d %v", w.Code, resp)
+	}
+}
+
+func TestFailBind(t *testing.T) {
+	bind := func(c *gin.Context) {
+		var req bindRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			FailBind(c, err, &req)
+		}
+	}
+
+	body := `{"email":"bad","password":"123","tags":[{"nick_name":""}]}`
+	w, resp := serve(0, bind, body)
+	if w.Code != 200 || resp["code"] != float64(400) || resp["data"] != nil {
+		t.Fatalf("v1 = %d %v", w.Code, resp)
+	}
+
+	w, resp = serve(2, bind, body)
+	if w.Code != 400 {
+		t.Fatalf("v2 status = %d", w.Code)
+	}
+	data, _ := resp["data"].(map[string]any)
+	if data["error_code"] != ErrValidationFailed {
+		t.Fatalf("v2 data = %v", data)
+	}
+	got := map[string]string{}
+	for _, item := range data["details"].([]any) {
+		d := item.(map[string]any)
+		got[d["field"].(string)] = d["rule"].(string)
+	}
+	want := map[string]string{"email": "email", "password": "min", "profile.nick_name": "required", "tags[0].nick_name": "required"}
+	for field, rule := range want {
+		if got[field] != rule {
+			t.Errorf("details[%s] = %q, want %q (all: %v)", field, got[field], rule, got)
+		}
+	}
+
+	w, resp = serve(2, bind, `{"age":"x"}`)
+	data, _ = resp["data"].(map[string]any)
+	details, _ := data["details"].([]any)
+	if w.Code != 400 || len(details) != 1 || details[0].(map[string]any)["field"] != "age" {
+		t.Fatalf("type error = %d %v", w.Code, resp)
+	}
+}
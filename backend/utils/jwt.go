@@ -35,10 +35,10 @@ const (
 )
 
 func getJWTSecretByGuard(authGuard string) string {
-	if authGuard == AdminAuthGuard && config.GlobalConfig.AdminJWTSecret != "" {
-		return config.GlobalConfig.AdminJWTSecret
+	if authGuard == AdminAuthGuard && config.Get().AdminJWTSecret != "" {
+		return config.Get().AdminJWTSecret
 	}
-	return config.GlobalConfig.JWTSecret
+	return config.Get().JWTSecret
 }
 
 func jwtSigningKeyByGuard(authGuard string) jwt.Keyfunc {
@@ -54,7 +54,7 @@ func jwtSigningKey(token *jwt.Token) (interface{}, error) {
 	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
 		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 	}
-	return []byte(config.GlobalConfig.JWTSecret), nil
+	return []byte(config.Get().JWTSecret), nil
 }
 
 func GenerateToken(userID uint64, role string) (string, error) {
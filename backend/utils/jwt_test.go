@@ -9,10 +9,10 @@ import (
 )
 
 func useTestJWTConfig() func() {
-	old := config.GlobalConfig
-	config.GlobalConfig = &config.Config{JWTSecret: "unit-test-secret"}
+	old := config.Get()
+	config.Set(&config.Config{JWTSecret: "unit-test-secret"})
 	return func() {
-		config.GlobalConfig = old
+		config.Set(old)
 	}
 }
 
@@ -54,7 +54,7 @@ func TestTokenTypeSeparationLegacyTokens(t *testing.T) {
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
 		},
 	}
-	legacyAccessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, legacyAccessClaims).SignedString([]byte(config.GlobalConfig.JWTSecret))
+	legacyAccessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, legacyAccessClaims).SignedString([]byte(config.Get().JWTSecret))
 	if err != nil {
 		t.Fatalf("failed to sign legacy access token: %v", err)
 	}
@@ -65,7 +65,7 @@ func TestTokenTypeSeparationLegacyTokens(t *testing.T) {
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
 		},
 	}
-	legacyRefreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, legacyRefreshClaims).SignedString([]byte(config.GlobalConfig.JWTSecret))
+	legacyRefreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, legacyRefreshClaims).SignedString([]byte(config.Get().JWTSecret))
 	if err != nil {
 		t.Fatalf("failed to sign legacy refresh token: %v", err)
 	}
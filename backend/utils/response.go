@@ -3,31 +3,31 @@ package utils
 import "github.com/gin-gonic/gin"
 
 type Response struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    any    `json:"data"`
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Data      any    `json:"data"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func Success(c *gin.Context, data any) {
 	c.JSON(200, Response{
-		Code:    200,
-		Message: "OK",
-		Data:    data,
+		Code:      200,
+		Message:   "OK",
+		Data:      data,
+		RequestID: c.GetString(RequestIDKey),
 	})
 }
 
 func SuccessMsg(c *gin.Context, message string, data any) {
 	c.JSON(200, Response{
-		Code:    200,
-		Message: message,
-		Data:    data,
+		Code:      200,
+		Message:   message,
+		Data:      data,
+		RequestID: c.GetString(RequestIDKey),
 	})
 }
 
+// Fail 返回错误；API 版本 2 下 HTTP 状态码与 code 一致并附带错误码（见 errors.go）
 func Fail(c *gin.Context, code int, message string) {
-	c.JSON(200, Response{
-		Code:    code,
-		Message: message,
-		Data:    nil,
-	})
+	fail(c, code, message, ErrorData{})
 }
@@ -123,6 +123,37 @@ func IsDigit(str string) bool {
 	return true
 }
 
+// NormalizeMobile 规范化手机号，返回规范化结果与是否合法
+// 去除空格、短横线和括号；中国大陆号码统一为 11 位本地号码（+86 前缀会被去掉），
+// 其他国家/地区号码需带 + 前缀，保留为 +国家码号码 的形式
+func NormalizeMobile(mobile string) (string, bool) {
+	mobile = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(mobile))
+
+	if !strings.HasPrefix(mobile, "+") {
+		if len(mobile) == 11 && mobile[0] == '1' && IsDigit(mobile) {
+			return mobile, true
+		}
+		return "", false
+	}
+	digits := mobile[1:]
+	if len(digits) < 6 || len(digits) > 15 || !IsDigit(digits) {
+		return "", false
+	}
+	if local := strings.TrimPrefix(digits, "86"); local != digits {
+		if len(local) == 11 && local[0] == '1' {
+			return local, true
+		}
+		return "", false
+	}
+	return mobile, true
+}
+
 // ValidatePort 检测端口合法性(返回true表示合法，false表示不合法)
 func ValidatePort(port int) bool {
 	return port >= 1 && port <= 65535
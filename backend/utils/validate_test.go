@@ -0,0 +1,32 @@
+package utils
+
+import "testing"
+
+func TestNormalizeMobile(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"13800138000", "13800138000", true},
+		{" 138-0013-8000 ", "13800138000", true},
+		{"+86 138 0013 8000", "13800138000", true},
+		{"+8613800138000", "13800138000", true},
+		{"+1 (415) 555-2671", "+14155552671", true},
+		{"+852 6123 4567", "+85261234567", true},
+		{"", "", false},
+		{"1380013800", "", false},
+		{"23800138000", "", false},
+		{"4155552671", "", false},
+		{"+86 10 1234 5678", "", false},
+		{"+12345", "", false},
+		{"+1234567890123456", "", false},
+		{"138a0013800", "", false},
+	}
+	for _, tc := range cases {
+		got, ok := NormalizeMobile(tc.in)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("NormalizeMobile(%q) = %q, %v; want %q, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/**
+ * 管理端 API 服务 - 邮件群发
+ * 按模板与用户筛选条件群发，支持计划时间、限速、暂停与取消
+ */
+import { request } from '@/service/http'
+
+const BASE_URL = '/api/v1/admin/email-campaigns'
+
+/** 收件人筛选条件，空值表示不限 */
+export interface EmailCampaignFilter {
+  /** 用户状态：1=启用, 0=禁用 */
+  status?: number
+  levels?: number[]
+  group_ids?: number[]
+  /** user / admin */
+  role?: string
+  /** 注册时间范围（时间戳，含） */
+  registered_from?: number
+  registered_to?: number
+}
+
+export interface EmailCampaign {
+  id: number
+  name: string
+  template_name: string
+  lang: string
+  vars: Record<string, any>
+  filter: EmailCampaignFilter
+  /** scheduled / running / paused / completed / cancelled */
+  status: string
+  scheduled_at: number
+  /** 每分钟最多发送数，0=不限 */
+  rate_per_minute: number
+  last_user_id: number
+  max_user_id: number
+  total: number
+  sent: number
+  failed: number
+  skipped: number
+  created_by: number
+  created_by_name: string
+  start_time: number
+  finish_time: number
+  create_time: number
+  update_time: number
+}
+
+export interface EmailCampaignCreateParams {
+  name: string
+  template_name: string
+  lang?: string
+  vars?: Record<string, any>
+  filter?: EmailCampaignFilter
+  /** 计划发送时间（时间戳），0 表示立即 */
+  scheduled_at?: number
+  rate_per_minute?: number
+}
+
+export const adminEmailCampaignApi = {
+  /**
+   * 获取群发任务列表（分页）
+   */
+  list(params?: { page?: number; page_size?: number; name?: string; status?: string }) {
+    return request.Get<Service.ResponseResult<{ list: EmailCampaign[]; total: number; page: number; page_size: number }>>(BASE_URL, { params })
+  },
+
+  /**
+   * 获取群发任务详情
+   */
+  detail(id: number) {
+    return request.Get<Service.ResponseResult<EmailCampaign>>(`${BASE_URL}/${id}`)
+  },
+
+  /**
+   * 预览筛选条件匹配的收件人数
+   */
+  preview(filter: EmailCampaignFilter) {
+    return request.Post<Service.ResponseResult<{ count: number }>>(`${BASE_URL}/preview`, { filter })
+  },
+
+  /**
+   * 创建群发任务
+   */
+  create(data: EmailCampaignCreateParams) {
+    return request.Post<Service.ResponseResult<EmailCampaign>>(BASE_URL, data)
+  },
+
+  /**
+   * 暂停群发
+   */
+  pause(id: number) {
+    return request.Post<Service.ResponseResult<EmailCampaign>>(`${BASE_URL}/${id}/pause`)
+  },
+
+  /**
+   * 恢复群发
+   */
+  resume(id: number) {
+    return request.Post<Service.ResponseResult<EmailCampaign>>(`${BASE_URL}/${id}/resume`)
+  },
+
+  /**
+   * 取消群发
+   */
+  cancel(id: number) {
+    return request.Post<Service.ResponseResult<EmailCampaign>>(`${BASE_URL}/${id}/cancel`)
+  },
+}
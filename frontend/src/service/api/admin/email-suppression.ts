@@ -0,0 +1,37 @@
+/**
+ * 管理端 API 服务 - 邮件抑制列表
+ * 退信、投诉回调记录的地址；已抑制的地址不再发送邮件，删除记录后恢复发送
+ */
+import { request } from '@/service/http'
+
+const BASE_URL = '/api/v1/admin/email-suppressions'
+
+export interface EmailSuppression {
+  id: number
+  email: string
+  /** hard_bounce / soft_bounce / complaint */
+  reason: string
+  source: string
+  detail: string
+  bounce_count: number
+  suppressed: boolean
+  create_time: number
+  update_time: number
+}
+
+export const adminEmailSuppressionApi = {
+  /**
+   * 获取抑制列表（分页）
+   * @param params.suppressed -1=全部, 0=仅记录, 1=已抑制
+   */
+  list(params?: { page?: number; page_size?: number; email?: string; reason?: string; suppressed?: number }) {
+    return request.Get<Service.ResponseResult<{ list: EmailSuppression[]; total: number; page: number; page_size: number }>>(BASE_URL, { params })
+  },
+
+  /**
+   * 移除抑制记录
+   */
+  remove(id: number) {
+    return request.Delete<Service.ResponseResult<null>>(`${BASE_URL}/${id}`)
+  },
+}
@@ -21,6 +21,34 @@ export interface EmailTemplate {
   updated_at: string
 }
 
+export interface EmailTemplateRevision {
+  id: number
+  template_id: number
+  version: number
+  subject: string
+  content?: string
+  description: string
+  status: number
+  /** initial / builtin / edit / reset / rollback */
+  source: string
+  source_ref: number
+  author_id: number
+  author_name: string
+  create_time: number
+}
+
+export interface EmailTemplateDiffLine {
+  op: 'equal' | 'add' | 'remove'
+  text: string
+}
+
+export interface EmailTemplateDiff {
+  from: number
+  to: number
+  subject: EmailTemplateDiffLine[]
+  content: EmailTemplateDiffLine[]
+}
+
 export const adminEmailTemplateApi = {
   list() {
     return request.Get<Service.ResponseResult<EmailTemplate[]>>(BASE_URL)
@@ -36,18 +64,35 @@ export const adminEmailTemplateApi = {
     description?: string
     status?: number
   }) {
-    return request.Put<Service.ResponseResult<{ message: string }>>(`${BASE_URL}/${id}`, data)
+    return request.Put<Service.ResponseResult<{ message: string; version?: number }>>(`${BASE_URL}/${id}`, data)
   },
 
   preview(id: number, data: {
     content: string
     vars?: Record<string, any>
   }) {
-    return request.Post<Service.ResponseResult<{ subject: string; content: string; wrapped: string }>>(`${BASE_URL}/${id}/preview`, data)
+    return request.Post<Service.ResponseResult<{ subject: string; content: string; wrapped: string; text: string }>>(`${BASE_URL}/${id}/preview`, data)
   },
 
   reset(id: number) {
-    return request.Post<Service.ResponseResult<{ message: string }>>(`${BASE_URL}/${id}/reset`, {})
+    return request.Post<Service.ResponseResult<{ message: string; version?: number }>>(`${BASE_URL}/${id}/reset`, {})
+  },
+
+  revisions(id: number) {
+    return request.Get<Service.ResponseResult<EmailTemplateRevision[]>>(`${BASE_URL}/${id}/revisions`)
+  },
+
+  revision(id: number, version: number) {
+    return request.Get<Service.ResponseResult<EmailTemplateRevision>>(`${BASE_URL}/${id}/revisions/${version}`)
+  },
+
+  /** to 不传时与模板当前内容对比 */
+  diff(id: number, from: number, to?: number) {
+    return request.Get<Service.ResponseResult<EmailTemplateDiff>>(`${BASE_URL}/${id}/diff`, { params: { from, to } })
+  },
+
+  rollback(id: number, version: number) {
+    return request.Post<Service.ResponseResult<{ message: string; version?: number }>>(`${BASE_URL}/${id}/rollback`, { version })
   },
 
   sendTest(data: { to: string; subject?: string; content?: string; template_id?: number }) {
@@ -37,4 +37,8 @@ export const adminApi = {
   log: createLazyModule(() => import('./log').then(m => m.adminLogApi)),
   settings: createLazyModule(() => import('./settings').then(m => m.adminSettingsApi)),
   dashboard: createLazyModule(() => import('./dashboard').then(m => m.adminDashboardApi)),
+  emailSuppression: createLazyModule(() => import('./email-suppression').then(m => m.adminEmailSuppressionApi)),
+  emailCampaign: createLazyModule(() => import('./email-campaign').then(m => m.adminEmailCampaignApi)),
+  notification: createLazyModule(() => import('./notification').then(m => m.adminNotificationApi)),
+  smsLog: createLazyModule(() => import('./sms-log').then(m => m.adminSMSLogApi)),
 }
@@ -0,0 +1,28 @@
+/**
+ * 管理端 API 服务 - 站内通知
+ * 向指定用户或全部用户广播系统通知
+ */
+import { request } from '@/service/http'
+
+const BASE_URL = '/api/v1/admin/notifications'
+
+export interface NotificationBroadcastParams {
+  /** 单语言标题，与 title_i18n 二选一 */
+  title?: string
+  /** 多语言标题，如 { zhCN: '系统维护', enUS: 'Maintenance' }，优先于 title */
+  title_i18n?: Record<string, string>
+  content?: string
+  content_i18n?: Record<string, string>
+  link?: string
+  /** 接收用户 ID，为空时发送给全部用户 */
+  user_ids?: number[]
+}
+
+export const adminNotificationApi = {
+  /**
+   * 广播站内通知，返回发送人数
+   */
+  broadcast(data: NotificationBroadcastParams) {
+    return request.Post<Service.ResponseResult<{ count: number }>>(`${BASE_URL}/broadcast`, data)
+  },
+}
@@ -0,0 +1,56 @@
+/**
+ * 管理端 API 服务 - 短信发送记录
+ * 每次发送验证码短信记录一条，不包含验证码内容
+ */
+import { request } from '@/service/http'
+
+const BASE_URL = '/api/v1/admin/sms-logs'
+
+export interface SMSLog {
+  id: number
+  phone: string
+  /** register / login / change_phone */
+  purpose: string
+  /** console / aliyun / tencent */
+  provider: string
+  template: string
+  /** 0=失败, 1=成功 */
+  status: number
+  /** 服务商回执 ID（阿里云 BizId / 腾讯云 SerialNo） */
+  request_id: string
+  error_msg: string
+  created_at: string
+}
+
+export const adminSMSLogApi = {
+  /**
+   * 获取短信发送记录（分页）
+   * @param params.status -1=全部, 0=失败, 1=成功
+   */
+  list(params?: {
+    page?: number
+    page_size?: number
+    phone?: string
+    purpose?: string
+    provider?: string
+    status?: number
+    start_time?: string
+    end_time?: string
+  }) {
+    return request.Get<Service.ResponseResult<{ list: SMSLog[]; total: number; page: number; page_size: number }>>(BASE_URL, { params })
+  },
+
+  /**
+   * 发送统计
+   */
+  stats() {
+    return request.Get<Service.ResponseResult<{ total: number; success: number; fail: number }>>(`${BASE_URL}/stats`)
+  },
+
+  /**
+   * 删除指定时间之前的记录
+   */
+  clean(before: string) {
+    return request.Post<Service.ResponseResult<{ affected: number }>>(`${BASE_URL}/clean`, { before })
+  },
+}
@@ -49,6 +49,30 @@ export function fetchResetPasswordConfirm(data: any) {
   return request.Post<Service.ResponseResult<any>>('/api/v1/public/reset-password', data)
 }
 
+/** 发送短信验证码（注册 / 登录 / 找回密码） */
+export function fetchSendSMSCode(data: { phone: string, purpose: 'register' | 'login' | 'reset_password' }) {
+  return request.Post<Service.ResponseResult<any>>('/api/v1/public/sms/send-code', data)
+}
+
+/** 手机号注册 */
+export function fetchRegisterByPhone(data: { username: string, password: string, phone: string, code: string }) {
+  return request.Post<Service.ResponseResult<any>>('/api/v1/public/register/phone', data)
+}
+
+/** 短信验证码登录 */
+export function fetchLoginBySMS(data: { phone: string, code: string, authGuard?: 'user' | 'admin' }) {
+  const methodInstance = request.Post<Service.ResponseResult<Api.Login.Info>>('/api/v1/public/login/sms', data)
+  methodInstance.meta = {
+    authRole: null,
+  }
+  return methodInstance
+}
+
+/** 短信验证码重置密码 */
+export function fetchResetPasswordBySMS(data: { phone: string, code: string, new_password: string }) {
+  return request.Post<Service.ResponseResult<any>>('/api/v1/public/reset-password/sms', data)
+}
+
 /** 获取用户信息 */
 export function fetchUserProfile() {
   return request.Get<Service.ResponseResult<any>>('/api/v1/user/profile')
@@ -0,0 +1,55 @@
+import { request } from '../../http'
+
+// ========================================
+// 类型定义
+// ========================================
+
+/** 站内通知，title / content 为多语言 JSON（用 parseMemo 解析），或请求时传 lang 由服务端解析 */
+export interface UserNotification {
+  id: number
+  user_id: number
+  /** system / recharge / balance / login / password */
+  type: string
+  title: string
+  content: string
+  link: string
+  is_read: boolean
+  read_time: number
+  create_time: number
+}
+
+interface NotificationListResponse {
+  list: UserNotification[]
+  total: number
+  page: number
+  page_size: number
+}
+
+// ========================================
+// 用户端站内通知 API
+// ========================================
+
+/** 获取通知列表，is_read：-1=全部, 0=未读, 1=已读 */
+export function fetchNotifications(params: { page?: number, page_size?: number, type?: string, is_read?: number, lang?: string }) {
+  return request.Get<Service.ResponseResult<NotificationListResponse>>('/api/v1/user/notifications', { params })
+}
+
+/** 获取未读通知数 */
+export function fetchUnreadNotificationCount() {
+  return request.Get<Service.ResponseResult<{ count: number }>>('/api/v1/user/notifications/unread-count')
+}
+
+/** 标记通知已读 */
+export function markNotificationRead(id: number) {
+  return request.Post<Service.ResponseResult<null>>(`/api/v1/user/notifications/${id}/read`)
+}
+
+/** 全部标记已读 */
+export function markAllNotificationsRead() {
+  return request.Post<Service.ResponseResult<{ affected: number }>>('/api/v1/user/notifications/read-all')
+}
+
+/** 删除通知 */
+export function deleteNotification(id: number) {
+  return request.Delete<Service.ResponseResult<null>>(`/api/v1/user/notifications/${id}`)
+}
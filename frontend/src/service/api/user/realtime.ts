@@ -0,0 +1,98 @@
+import { authStorage } from '@/utils'
+
+// ========================================
+// 类型定义
+// ========================================
+
+/** 服务端推送的事件 */
+export type RealtimeEvent = 'ready' | 'payment.paid' | 'notification.new' | 'settings.updated' | 'session.revoked'
+
+export type RealtimeHandlers = Partial<Record<RealtimeEvent, (data: any) => void>> & {
+  /** 连接返回 401（令牌失效），不再自动重连 */
+  unauthorized?: () => void
+}
+
+// ========================================
+// 用户端实时推送（SSE）
+// ========================================
+
+const STREAM_URL = `${__URL_MAP__.url.path}/api/v1/user/stream`
+const RETRY_MIN = 1000
+const RETRY_MAX = 30000
+
+/**
+ * 连接实时推送，返回断开函数
+ * 原生 EventSource 不能携带 Authorization 头，这里用 fetch 读取事件流；断线后按指数退避自动重连，
+ * 每次重连读取最新的 accessToken。收到 session.revoked 后服务端会关闭连接，
+ * 调用方应通过普通接口确认登录状态（令牌失效时由请求层统一刷新或退出登录）。
+ */
+export function connectRealtime(handlers: RealtimeHandlers) {
+  let controller: AbortController | null = null
+  let stopped = false
+  let retry = RETRY_MIN
+  let timer: ReturnType<typeof setTimeout> | undefined
+
+  const dispatch = (block: string) => {
+    let event = 'message'
+    const data: string[] = []
+    for (const line of block.split('\n')) {
+      if (line.startsWith('event:'))
+        event = line.slice(6).trim()
+      else if (line.startsWith('data:'))
+        data.push(line.slice(5).replace(/^ /, ''))
+    }
+    if (!data.length)
+      return
+    let payload: any = data.join('\n')
+    try {
+      payload = JSON.parse(payload)
+    }
+    catch {}
+    handlers[event as RealtimeEvent]?.(payload)
+  }
+
+  const connect = async () => {
+    controller = new AbortController()
+    try {
+      const response = await fetch(STREAM_URL, {
+        headers: { Authorization: `Bearer ${authStorage.get('accessToken')}`, Accept: 'text/event-stream' },
+        signal: controller.signal,
+      })
+      if (response.status === 401) {
+        stopped = true
+        handlers.unauthorized?.()
+        return
+      }
+      if (!response.ok || !response.body)
+        throw new Error(`stream status ${response.status}`)
+
+      retry = RETRY_MIN
+      const reader = response.body.pipeThrough(new TextDecoderStream()).getReader()
+      let buffer = ''
+      while (true) {
+        const { value, done } = await reader.read()
+        if (done)
+          break
+        buffer += value
+        let index = buffer.indexOf('\n\n')
+        while (index >= 0) {
+          dispatch(buffer.slice(0, index))
+          buffer = buffer.slice(index + 2)
+          index = buffer.indexOf('\n\n')
+        }
+      }
+    }
+    catch {}
+    if (!stopped) {
+      timer = setTimeout(connect, retry)
+      retry = Math.min(retry * 2, RETRY_MAX)
+    }
+  }
+
+  connect()
+  return () => {
+    stopped = true
+    clearTimeout(timer)
+    controller?.abort()
+  }
+}